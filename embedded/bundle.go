@@ -0,0 +1,572 @@
+package embedded
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BundleEnv points to a vendored node_modules bundle to install from
+	BundleEnv = "BEDROCK_MODULES_BUNDLE"
+
+	// bundleFileName is the name of an imported bundle in the vendor
+	// directory, and of a bundle shipped next to the bedrock binary
+	bundleFileName = "bedrock-modules.tar.gz"
+
+	// bundleManifest is the first entry of every bundle
+	bundleManifest = "bedrock-bundle.json"
+
+	bundleFormat = 1
+)
+
+// BundleManifest describes the contents of a vendored node_modules bundle
+type BundleManifest struct {
+	Format           int               `json:"format"`
+	DependenciesHash string            `json:"dependenciesHash"` // package.json and postinstall.js the bundle was installed from
+	CreatedAt        time.Time         `json:"createdAt"`
+	Files            map[string]string `json:"files"` // Path -> SHA-256 of every regular file
+}
+
+// Bundle is a vendored bundle found on disk
+type Bundle struct {
+	Path   string
+	SHA256 string // Expected checksum of the tarball, from its .sha256 file
+}
+
+// dependenciesHash identifies the dependency set: a bundle can replace
+// `npm install` only if it was built from the same package.json and
+// postinstall patches
+func dependenciesHash() (string, error) {
+	hasher := sha256.New()
+	for _, name := range []string{"modules/package.json", "modules/postinstall.js"} {
+		data, err := ModulesFS.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		hasher.Write(data)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// vendorDir is where imported bundles are kept. It sits next to the
+// modules cache so cleaning the cache keeps the bundle for reinstalling.
+func vendorDir() (string, error) {
+	cache, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(cache), "vendor"), nil
+}
+
+// FindBundle returns the vendored bundle SetupModules would install from:
+// $BEDROCK_MODULES_BUNDLE, then an imported bundle, then one shipped next
+// to the bedrock binary
+func FindBundle() (*Bundle, bool) {
+	var candidates []string
+
+	if path := os.Getenv(BundleEnv); path != "" {
+		candidates = append(candidates, path)
+	}
+	if dir, err := vendorDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, bundleFileName))
+	}
+	if exe, err := os.Executable(); err == nil {
+		candidates = append(candidates, filepath.Join(filepath.Dir(exe), bundleFileName))
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		bundle := &Bundle{Path: path}
+		if sum, err := readChecksumFile(path + ".sha256"); err == nil {
+			bundle.SHA256 = sum
+		}
+		return bundle, true
+	}
+	return nil, false
+}
+
+// ExportBundle packs the installed node_modules into a gzipped tarball at
+// path and writes its checksum to path.sha256. The checksum is returned.
+func ExportBundle(path string) (string, error) {
+	cache, err := SetupModules()
+	if err != nil {
+		return "", err
+	}
+
+	depsHash, err := dependenciesHash()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash dependencies: %w", err)
+	}
+
+	nodeModules := filepath.Join(cache, "node_modules")
+	manifest := BundleManifest{
+		Format:           bundleFormat,
+		DependenciesHash: depsHash,
+		CreatedAt:        time.Now().UTC(),
+		Files:            make(map[string]string),
+	}
+
+	var paths []string
+	err = filepath.Walk(nodeModules, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(cache, p)
+		if err != nil {
+			return err
+		}
+		// Command shims link up into their packages; nothing runs them
+		if info.IsDir() && info.Name() == ".bin" {
+			return filepath.SkipDir
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if !validLinkname(link) {
+				return fmt.Errorf("link %s -> %s leaves its directory and cannot be bundled", rel, link)
+			}
+		}
+		paths = append(paths, rel)
+		if info.Mode().IsRegular() {
+			sum, err := fileSHA256(p)
+			if err != nil {
+				return err
+			}
+			manifest.Files[filepath.ToSlash(rel)] = sum
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read node_modules: %w", err)
+	}
+	sort.Strings(paths)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(file, hasher))
+	tw := tar.NewWriter(gz)
+
+	// The manifest goes first so files can be checked while extracting
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    bundleManifest,
+		Mode:    0644,
+		Size:    int64(len(manifestData)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return "", fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return "", fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	for _, rel := range paths {
+		if err := addToTar(tw, cache, rel); err != nil {
+			return "", fmt.Errorf("failed to add %s to bundle: %w", rel, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return "", fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if err := writeChecksumFile(path+".sha256", sum, filepath.Base(path)); err != nil {
+		return "", err
+	}
+	return sum, nil
+}
+
+// ImportBundle verifies a bundle, keeps a copy in the vendor directory and
+// reinstalls the modules cache from it. The tarball must match expectedSum,
+// or the checksum in path.sha256 when expectedSum is empty.
+func ImportBundle(path, expectedSum string) (*BundleManifest, error) {
+	if expectedSum == "" {
+		sum, err := readChecksumFile(path + ".sha256")
+		if err != nil {
+			return nil, fmt.Errorf("no checksum for %s: pass --sha256 or provide %s.sha256", path, filepath.Base(path))
+		}
+		expectedSum = sum
+	}
+
+	// Verify fully before touching the existing install
+	manifest, err := verifyBundle(path, expectedSum)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := vendorDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create vendor directory: %w", err)
+	}
+
+	dest := filepath.Join(dir, bundleFileName)
+	if src, err := filepath.Abs(path); err != nil || src != dest {
+		if err := copyFile(path, dest); err != nil {
+			return nil, fmt.Errorf("failed to store bundle: %w", err)
+		}
+	}
+	if err := writeChecksumFile(dest+".sha256", expectedSum, bundleFileName); err != nil {
+		return nil, err
+	}
+
+	// Force a reinstall, which now prefers the imported bundle
+	if err := CleanCache(); err != nil {
+		return nil, err
+	}
+	setupMu.Lock()
+	setupDone = false
+	setupMu.Unlock()
+
+	if _, err := SetupModules(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// verifyBundle checks a bundle's checksum, format, dependency set and file
+// hashes without installing it
+func verifyBundle(path, expectedSum string) (*BundleManifest, error) {
+	tmp, err := os.MkdirTemp("", "bedrock-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	return extractBundle(path, expectedSum, tmp)
+}
+
+// installBundle extracts a verified bundle's node_modules into cache
+func installBundle(bundle *Bundle, cache string) error {
+	if bundle.SHA256 == "" {
+		return fmt.Errorf("bundle %s has no %s.sha256 checksum file", bundle.Path, filepath.Base(bundle.Path))
+	}
+
+	staging, err := os.MkdirTemp(cache, ".bundle-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if _, err := extractBundle(bundle.Path, bundle.SHA256, staging); err != nil {
+		return err
+	}
+
+	if err := os.Rename(filepath.Join(staging, "node_modules"), filepath.Join(cache, "node_modules")); err != nil {
+		return fmt.Errorf("failed to install node_modules: %w", err)
+	}
+	return nil
+}
+
+// errStaleBundle is returned for a bundle built from other dependencies
+type errStaleBundle struct {
+	path string
+}
+
+func (e *errStaleBundle) Error() string {
+	return fmt.Sprintf("bundle %s was built for a different version of the modules", e.path)
+}
+
+// extractBundle extracts the bundle at path into dest, checking the
+// tarball against expectedSum and every file against the manifest
+func extractBundle(path, expectedSum, dest string) (*BundleManifest, error) {
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if !strings.EqualFold(sum, expectedSum) {
+		return nil, fmt.Errorf("bundle checksum mismatch: expected %s, got %s", expectedSum, sum)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	// Manifest first
+	header, err := tr.Next()
+	if err != nil || header.Name != bundleManifest {
+		return nil, fmt.Errorf("invalid bundle: missing %s", bundleManifest)
+	}
+	var manifest BundleManifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.Format != bundleFormat {
+		return nil, fmt.Errorf("unsupported bundle format %d", manifest.Format)
+	}
+	depsHash, err := dependenciesHash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash dependencies: %w", err)
+	}
+	if manifest.DependenciesHash != depsHash {
+		return nil, &errStaleBundle{path: path}
+	}
+
+	seen := make(map[string]bool, len(manifest.Files))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		name := filepath.ToSlash(filepath.Clean(header.Name))
+		if name != "node_modules" && !strings.HasPrefix(name, "node_modules/") {
+			return nil, fmt.Errorf("invalid bundle entry %s", header.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		if err := checkEntryPath(dest, target); err != nil {
+			return nil, fmt.Errorf("invalid bundle entry %s: %w", name, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			want, ok := manifest.Files[name]
+			if !ok {
+				return nil, fmt.Errorf("bundle entry %s is not in the manifest", name)
+			}
+			got, err := writeFile(target, tr, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract %s: %w", name, err)
+			}
+			if got != want {
+				return nil, fmt.Errorf("bundle file %s is corrupted (checksum mismatch)", name)
+			}
+			seen[name] = true
+		case tar.TypeSymlink:
+			// Links may only point down from their own directory
+			if !validLinkname(header.Linkname) {
+				return nil, fmt.Errorf("invalid bundle link %s -> %s", name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported bundle entry %s", name)
+		}
+	}
+
+	if len(seen) != len(manifest.Files) {
+		return nil, fmt.Errorf("bundle is incomplete: %d of %d files present", len(seen), len(manifest.Files))
+	}
+
+	return &manifest, nil
+}
+
+// validLinkname reports whether a symlink target stays below the link's
+// directory: relative and without ".." segments
+func validLinkname(link string) bool {
+	if link == "" || filepath.IsAbs(link) || strings.HasPrefix(link, "/") {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(link), "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// checkEntryPath makes sure extracting to target cannot leave
+// dest/node_modules: no directory on the way may be a symlink, the
+// parent must resolve inside node_modules and an existing target must not
+// be a link that would be written through.
+func checkEntryPath(dest, target string) error {
+	root := filepath.Join(dest, "node_modules")
+	if target == root {
+		return nil
+	}
+
+	rel, err := filepath.Rel(root, filepath.Dir(target))
+	if err != nil {
+		return err
+	}
+	dir := root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part != "." {
+			dir = filepath.Join(dir, part)
+		}
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			break // Created below; a new directory cannot be a link
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a link", dir)
+		}
+	}
+
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%s is already a link", target)
+	}
+
+	parent, err := filepath.EvalSymlinks(filepath.Dir(target))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	if parent != realRoot && !strings.HasPrefix(parent, realRoot+string(filepath.Separator)) {
+		return fmt.Errorf("%s resolves outside node_modules", filepath.Dir(target))
+	}
+	return nil
+}
+
+func addToTar(tw *tar.Writer, base, rel string) error {
+	path := filepath.Join(base, rel)
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(rel)
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// writeFile writes r to path and returns the SHA-256 of what was written
+func writeFile(path string, r io.Reader, perm os.FileMode) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm|0200)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hasher), r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// readChecksumFile reads a sha256sum-style file ("<hex>  <name>")
+func readChecksumFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid checksum file %s", path)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+func writeChecksumFile(path, sum, name string) error {
+	if err := os.WriteFile(path, []byte(sum+"  "+name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write checksum: %w", err)
+	}
+	return nil
+}
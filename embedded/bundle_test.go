@@ -0,0 +1,146 @@
+package embedded
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type tarEntry struct {
+	name string
+	link string // Symlink target; a regular file when empty
+	body string
+}
+
+// writeBundle writes a bundle with a valid manifest listing every regular
+// file and returns its path and checksum
+func writeBundle(t *testing.T, entries []tarEntry) (string, string) {
+	t.Helper()
+	depsHash, err := dependenciesHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := BundleManifest{Format: bundleFormat, DependenciesHash: depsHash, Files: map[string]string{}}
+	for _, e := range entries {
+		if e.link == "" {
+			sum := sha256.Sum256([]byte(e.body))
+			manifest.Files[e.name] = hex.EncodeToString(sum[:])
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	data, _ := json.Marshal(manifest)
+	tw.WriteHeader(&tar.Header{Name: bundleManifest, Mode: 0644, Size: int64(len(data))})
+	tw.Write(data)
+	for _, e := range entries {
+		if e.link != "" {
+			tw.WriteHeader(&tar.Header{Name: e.name, Typeflag: tar.TypeSymlink, Linkname: e.link})
+			continue
+		}
+		tw.WriteHeader(&tar.Header{Name: e.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(e.body))})
+		tw.Write([]byte(e.body))
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	sum, err := fileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, sum
+}
+
+func TestExtractBundle(t *testing.T) {
+	path, sum := writeBundle(t, []tarEntry{
+		{name: "node_modules/pkg/index.js", body: "module.exports = 1;\n"},
+		{name: "node_modules/pkg/main.js", link: "index.js"},
+	})
+	dest := t.TempDir()
+	if _, err := extractBundle(path, sum, dest); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "node_modules", "pkg", "main.js"))
+	if err != nil || string(data) != "module.exports = 1;\n" {
+		t.Errorf("main.js = %q, %v", data, err)
+	}
+}
+
+func TestExtractBundleRejectsEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{
+			name: "chained parent links",
+			entries: []tarEntry{
+				{name: "node_modules/a/b", link: ".."},
+				{name: "node_modules/a/b/c", link: ".."},
+				{name: "node_modules/a/b/c/d", link: ".."},
+				{name: "node_modules/a/b/c/d/x", body: "escaped"},
+			},
+		},
+		{
+			name: "parent segment",
+			entries: []tarEntry{
+				{name: "node_modules/a/up", link: "sub/../../.."},
+			},
+		},
+		{
+			name: "absolute link",
+			entries: []tarEntry{
+				{name: "node_modules/a/abs", link: "/tmp"},
+			},
+		},
+		{
+			name: "file through a link",
+			entries: []tarEntry{
+				{name: "node_modules/a/self", link: "."},
+				{name: "node_modules/a/self/x", body: "through a link"},
+			},
+		},
+		{
+			name: "file over a link",
+			entries: []tarEntry{
+				{name: "node_modules/a/x", link: "y"},
+				{name: "node_modules/a/x", body: "over a link"},
+			},
+		},
+		{
+			name: "outside node_modules",
+			entries: []tarEntry{
+				{name: "../x", body: "escaped"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, sum := writeBundle(t, tt.entries)
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "staging")
+			if err := os.Mkdir(dest, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := extractBundle(path, sum, dest)
+			if err == nil || !strings.Contains(err.Error(), "invalid bundle") {
+				t.Errorf("error = %v, want an invalid bundle", err)
+			}
+			if _, err := os.Stat(filepath.Join(parent, "x")); err == nil {
+				t.Error("a file was written outside the extraction directory")
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"embed"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,7 +13,7 @@ import (
 	"sync"
 )
 
-//go:embed modules/deploy/deploy.js modules/call/call.js modules/faucet/faucet.js modules/modify/modify.js modules/delete/delete.js modules/user_delete/user_delete.js modules/clawback/clawback.js modules/package.json modules/postinstall.js
+//go:embed modules/deploy/deploy.js modules/call/call.js modules/faucet/faucet.js modules/modify/modify.js modules/delete/delete.js modules/user_delete/user_delete.js modules/clawback/clawback.js modules/sign/sign.js modules/worker/worker.js modules/package.json modules/postinstall.js
 var ModulesFS embed.FS
 
 var (
@@ -112,6 +113,20 @@ func getModulesVersion() (string, error) {
 	}
 	hasher.Write(clawbackJS)
 
+	// Hash sign.js
+	signJS, err := ModulesFS.ReadFile("modules/sign/sign.js")
+	if err != nil {
+		return "", err
+	}
+	hasher.Write(signJS)
+
+	// Hash worker.js
+	workerJS, err := ModulesFS.ReadFile("modules/worker/worker.js")
+	if err != nil {
+		return "", err
+	}
+	hasher.Write(workerJS)
+
 	// Hash postinstall.js
 	postinstallJS, err := ModulesFS.ReadFile("modules/postinstall.js")
 	if err != nil {
@@ -285,17 +300,58 @@ func SetupModules() (string, error) {
 			return "", fmt.Errorf("failed to write clawback.js: %w", err)
 		}
 
-		// Install npm dependencies
-		fmt.Println("⚡ First run detected - installing JavaScript dependencies...")
-		fmt.Printf("   Cache location: %s\n", cache)
+		// Extract sign.js
+		signJS, err := ModulesFS.ReadFile("modules/sign/sign.js")
+		if err != nil {
+			return "", fmt.Errorf("failed to read sign.js: %w", err)
+		}
+
+		signPath := filepath.Join(cache, "sign.js")
+		if err := os.WriteFile(signPath, signJS, 0755); err != nil {
+			return "", fmt.Errorf("failed to write sign.js: %w", err)
+		}
+
+		// Extract worker.js
+		workerJS, err := ModulesFS.ReadFile("modules/worker/worker.js")
+		if err != nil {
+			return "", fmt.Errorf("failed to read worker.js: %w", err)
+		}
 
-		cmd := exec.Command("npm", "install", "--silent", "--no-progress")
-		cmd.Dir = cache
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		workerPath := filepath.Join(cache, "worker.js")
+		if err := os.WriteFile(workerPath, workerJS, 0755); err != nil {
+			return "", fmt.Errorf("failed to write worker.js: %w", err)
+		}
 
-		if err := cmd.Run(); err != nil {
-			return "", fmt.Errorf("failed to install npm dependencies: %w", err)
+		// Install dependencies, preferring a vendored bundle over npm
+		installed := false
+		if bundle, ok := FindBundle(); ok {
+			fmt.Println("⚡ First run detected - installing JavaScript dependencies from bundle...")
+			fmt.Printf("   Bundle: %s\n", bundle.Path)
+
+			if err := installBundle(bundle, cache); err != nil {
+				var stale *errStaleBundle
+				if !errors.As(err, &stale) {
+					// A corrupted or tampered bundle must never be used
+					return "", fmt.Errorf("failed to install modules bundle: %w", err)
+				}
+				fmt.Printf("   ⚠ %v, falling back to npm\n", err)
+			} else {
+				installed = true
+			}
+		}
+
+		if !installed {
+			fmt.Println("⚡ First run detected - installing JavaScript dependencies...")
+			fmt.Printf("   Cache location: %s\n", cache)
+
+			cmd := exec.Command("npm", "install", "--silent", "--no-progress")
+			cmd.Dir = cache
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+
+			if err := cmd.Run(); err != nil {
+				return "", fmt.Errorf("failed to install npm dependencies (for offline installs, see 'bedrock modules import'): %w", err)
+			}
 		}
 
 		fmt.Println("✓ Dependencies installed successfully")
@@ -333,7 +389,7 @@ func GetModulePath(moduleName string) (string, error) {
 }
 
 // CleanCache removes the entire bedrock cache directory, forcing a fresh
-// reinstall of JS modules on next use
+// reinstall of JS modules on next use. Imported bundles are kept.
 func CleanCache() error {
 	cache, err := getCacheDir()
 	if err != nil {
@@ -14,30 +14,33 @@
  *   "function_name": "register",
  *   "network_url": "wss://alphanet.xrpl.org",
  *   "wallet_seed": "sXXX...",
+ *   "unsigned": true (optional, emit the unsigned transaction instead of signing it),
+ *   "account": "rXXX..." (required with unsigned),
+ *   "last_ledger_sequence": 123 (optional, set on the signed transaction),
+ *   "sequence": 7 (optional, autofilled by the caller; with a numeric computation_allowance
+ *                  the transaction is then built without connecting to network_url),
+ *   "ticket_sequence": 45 (optional, use a Ticket instead of the account Sequence),
+ *   "amount": "1000000" | {currency, issuer, value} | {mpt_issuance_id, value} (optional, sent with the call),
+ *   "simulate": true (optional, run the call through the simulate RPC without submitting;
+ *                     needs only "account" when no wallet_seed is given),
  *   "abi_path": "/path/to/abi.json" (optional),
  *   "parameters": {"name": "test", "duration": 31536000} (optional),
- *   "computation_allowance": "1000000" (optional),
+ *   "computation_allowance": "1000000" or "auto" (optional, simulated when "auto"),
  *   "fee": "1000000" (optional),
  *   "verbose": true (optional)
  * }
  *
- * Output JSON format:
+ * Output JSON format (the caller submits and tracks the signed transaction):
  * {
  *   "success": true,
  *   "data": {
- *     "txHash": "...",
- *     "returnCode": 0,
- *     "returnValue": "...",
- *     "gasUsed": 12345,
- *     "validated": true
+ *     "signedTx": { "txBlob": "...", "hash": "...", "lastLedgerSequence": 123 }
  *   }
  * }
  */
 
 const xrpl = require('@transia/xrpl');
 const fs = require('fs');
-const http = require('http');
-const https = require('https');
 
 /**
  * Build Parameters array from ABI and provided values
@@ -140,6 +143,61 @@ function formatParameterValue(type, value) {
   }
 }
 
+const DEFAULT_ALLOWANCE = 1000000;
+
+/**
+ * Estimate the computation allowance by simulating the call.
+ * Falls back to the default when the node does not support simulate.
+ */
+async function estimateAllowance(client, tx, margin, log) {
+  try {
+    const sim = await client.request({
+      command: 'simulate',
+      tx_json: { ...tx, ComputationAllowance: DEFAULT_ALLOWANCE * 10 },
+    });
+    const gasUsed = sim.result?.meta?.GasUsed;
+    if (gasUsed !== undefined && gasUsed > 0) {
+      const allowance = Math.ceil(gasUsed * margin);
+      log(`Simulated gas usage: ${gasUsed}, allowance: ${allowance}`);
+      return allowance;
+    }
+  } catch (error) {
+    log(`Simulation unavailable (${error.message}), using default allowance`);
+  }
+  return DEFAULT_ALLOWANCE;
+}
+
+/**
+ * Run the call through the simulate RPC. Nothing is signed or committed;
+ * the result carries the metadata the call would produce.
+ */
+async function simulateCall(client, tx, log) {
+  const simTx = { ...tx };
+  delete simTx.LastLedgerSequence;
+
+  const sim = await client.request({ command: 'simulate', tx_json: simTx });
+  const meta = sim.result?.meta;
+
+  log(`\nSimulated result: ${sim.result?.engine_result}`);
+  if (meta?.GasUsed !== undefined) {
+    log(`  Gas Used: ${meta.GasUsed}`);
+  }
+
+  return {
+    success: true,
+    data: {
+      txHash: sim.result?.tx_json?.hash || sim.result?.hash || '',
+      returnCode: meta?.WasmReturnCode,
+      returnValue: meta?.ReturnValue,
+      gasUsed: meta?.GasUsed,
+      validated: false,
+      transactionResult: meta?.TransactionResult || sim.result?.engine_result,
+      meta: meta,
+      simulated: true,
+    },
+  };
+}
+
 /**
  * Call a contract function
  */
@@ -154,27 +212,53 @@ async function callContract(config) {
     computation_allowance,
     fee,
     verbose,
+    unsigned,
+    last_ledger_sequence,
+    ticket_sequence,
+    simulate,
+    amount,
+    account,
+    sequence,
   } = config;
 
   const log = verbose ? console.error.bind(console) : () => {};
 
   log('Calling smart contract on XRPL...\n');
 
-  const client = new xrpl.Client(network_url);
-  client.apiVersion = 1;
+  // With the Sequence autofilled by the caller there is nothing to look up
+  const offline = sequence !== undefined && sequence !== null && !simulate;
+  const client = offline ? null : new xrpl.Client(network_url);
+  const disconnect = async () => {
+    if (client && client.isConnected()) {
+      await client.disconnect();
+    }
+  };
 
   try {
-    await client.connect();
-    log('✓ Connected to network');
+    if (client) {
+      client.apiVersion = 1;
+      await client.connect();
+      log('✓ Connected to network');
+    }
 
-    // Create or restore wallet
+    // Create or restore wallet (unsigned transactions only need the address)
     const algorithm = config.algorithm === 'ed25519' ? undefined : xrpl.ECDSA.secp256k1;
-    const wallet = wallet_seed
-      ? (algorithm ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm }) : xrpl.Wallet.fromSeed(wallet_seed))
-      : (algorithm ? xrpl.Wallet.generate(algorithm) : xrpl.Wallet.generate());
+    let wallet = null;
+    if (simulate && !wallet_seed) {
+      if (!account) {
+        throw new Error('account or wallet_seed is required to simulate a call');
+      }
+    } else if (!unsigned) {
+      wallet = wallet_seed
+        ? (algorithm ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm }) : xrpl.Wallet.fromSeed(wallet_seed))
+        : (algorithm ? xrpl.Wallet.generate(algorithm) : xrpl.Wallet.generate());
+    } else if (!account) {
+      throw new Error('account is required when building an unsigned transaction');
+    }
+    const address = wallet ? wallet.address : account;
 
     log('\nWallet:');
-    log('  Address:', wallet.address);
+    log('  Address:', address);
 
     log(`\nContract: ${contract_account}`);
     log(`Function: ${function_name}`);
@@ -223,11 +307,13 @@ async function callContract(config) {
     }
 
     // Check balance
-    const balance = await client.getXrpBalance(wallet.address);
-    log(`\nWallet balance: ${balance} XRP`);
+    if (client) {
+      const balance = await client.getXrpBalance(address);
+      log(`\nWallet balance: ${balance} XRP`);
 
-    if (parseFloat(balance) === 0) {
-      log('Warning: Wallet not funded, call will likely fail');
+      if (parseFloat(balance) === 0) {
+        log('Warning: Wallet not funded, call will likely fail');
+      }
     }
 
     // Create ContractCall transaction
@@ -238,159 +324,84 @@ async function callContract(config) {
       .toUpperCase();
 
     // Manual construction: autofill tries to simulate ContractCall which nodes may not support
-    const accountInfo = await client.request({
-      command: 'account_info',
-      account: wallet.address,
-    });
+    const accountSequence = offline
+      ? sequence
+      : (await client.request({ command: 'account_info', account: address })).result.account_data.Sequence;
 
     const tx = {
       TransactionType: 'ContractCall',
-      Account: wallet.address,
+      Account: address,
       ContractAccount: contract_account,
       FunctionName: functionNameHex,
       Parameters: Parameters,
-      ComputationAllowance: parseInt(computation_allowance || '1000000'),
+      ComputationAllowance: DEFAULT_ALLOWANCE,
       Fee: fee || '1000000',
-      Sequence: accountInfo.result.account_data.Sequence,
-      SigningPubKey: wallet.publicKey,
+      Sequence: accountSequence,
       NetworkID: config.network_id,
     };
+    if (tx.Parameters === undefined) {
+      delete tx.Parameters;
+    }
 
-    const signed = wallet.sign(tx);
-
-    log('Transaction ID:', signed.hash);
-
-    const isLocal = network_url.includes('localhost') || network_url.includes('127.0.0.1');
-
-    let txResult = null;
-
-    if (isLocal) {
-      // Local nodes: use HTTP RPC to avoid WebSocket hangs under emulation
-      await client.disconnect();
-
-      const rpcUrl = network_url
-        .replace('ws://', 'http://').replace('wss://', 'https://')
-        .replace('localhost:6006', 'localhost:5005');
-
-      const submitResult = await httpRPC(rpcUrl, 'submit', { tx_blob: signed.tx_blob }, 120000);
+    // Value sent along with the call to a payable entry point
+    if (amount !== undefined && amount !== null) {
+      tx.Amount = amount;
+      log(`Amount: ${JSON.stringify(amount)}`);
+    }
 
-      log('Submit response:', JSON.stringify(submitResult).substring(0, 200));
+    if (last_ledger_sequence) {
+      tx.LastLedgerSequence = last_ledger_sequence;
+    }
 
-      if (submitResult.engine_result &&
-          submitResult.engine_result !== 'tesSUCCESS' &&
-          !submitResult.engine_result.startsWith('tes')) {
-        throw new Error(`Transaction rejected: ${submitResult.engine_result} - ${submitResult.engine_result_message}`);
-      }
+    // A Ticket replaces the account Sequence so calls can run concurrently
+    if (ticket_sequence) {
+      tx.Sequence = 0;
+      tx.TicketSequence = ticket_sequence;
+    }
 
-      // Wait for validation by polling tx via HTTP RPC
-      for (let attempt = 0; attempt < 60; attempt++) {
-        await new Promise(r => setTimeout(r, 1000));
-        try {
-          const txRes = await httpRPC(rpcUrl, 'tx', { transaction: signed.hash }, 10000);
-          if (txRes.validated) {
-            txResult = txRes;
-            break;
-          }
-        } catch (e) {
-          // Transaction not yet found, keep waiting
-        }
+    if (!computation_allowance || computation_allowance === 'auto') {
+      if (!client) {
+        throw new Error('computation_allowance must be set when the sequence is given');
       }
+      tx.ComputationAllowance = await estimateAllowance(client, tx, config.allowance_margin || 1.25, log);
     } else {
-      // Remote networks: use WebSocket submit and wait
-      const submitResult = await client.request({
-        command: 'submit',
-        tx_blob: signed.tx_blob,
-      });
-
-      log('Submit response:', JSON.stringify(submitResult.result).substring(0, 200));
-
-      if (submitResult.result.engine_result &&
-          submitResult.result.engine_result !== 'tesSUCCESS' &&
-          !submitResult.result.engine_result.startsWith('tes')) {
-        throw new Error(`Transaction rejected: ${submitResult.result.engine_result} - ${submitResult.result.engine_result_message}`);
-      }
-
-      // Poll for validation via WebSocket
-      for (let attempt = 0; attempt < 60; attempt++) {
-        await new Promise(r => setTimeout(r, 1000));
-        try {
-          const txRes = await client.request({
-            command: 'tx',
-            transaction: signed.hash,
-          });
-          if (txRes.result.validated) {
-            txResult = txRes.result;
-            break;
-          }
-        } catch (e) {
-          // Transaction not yet found, keep waiting
-        }
-      }
-
-      await client.disconnect();
+      tx.ComputationAllowance = parseInt(computation_allowance);
     }
 
-    if (!txResult) {
-      throw new Error('Transaction was not validated within 60 seconds');
+    // Simulate mode: apply the call to a scratch copy of the open ledger
+    if (simulate) {
+      const simResult = await simulateCall(client, tx, log);
+      await disconnect();
+      console.log(JSON.stringify(simResult));
+      return simResult;
     }
 
-    log('\n✓ Contract function called successfully!');
-
-    const meta = txResult.meta;
-
-    log('\nTransaction Status:');
-    log(`  Result: ${meta?.TransactionResult || 'N/A'}`);
-    log(`  Validated: ${txResult.validated ? 'Yes' : 'No'}`);
-
-    if (meta?.WasmReturnCode !== undefined) {
-      log('\nContract Return Code:');
-      log(`  Value: ${meta.WasmReturnCode}`);
-      log(`  Status: ${meta.WasmReturnCode === 0 ? 'SUCCESS' : 'ERROR'}`);
+    // Unsigned mode: hand the autofilled transaction back for offline signing
+    if (unsigned) {
+      await disconnect();
+      const unsignedResult = { success: true, data: { unsignedTx: tx } };
+      console.log(JSON.stringify(unsignedResult));
+      return unsignedResult;
     }
 
-    if (meta?.ReturnValue) {
-      log('\nContract Return Data:');
-      log(`  Hex: ${meta.ReturnValue}`);
-      log(`  Decimal: ${parseInt(meta.ReturnValue, 16)}`);
+    tx.SigningPubKey = wallet.publicKey;
 
-      // Try to decode based on ABI return type
-      if (functionDef?.returns) {
-        log(`  Type: ${functionDef.returns.type}`);
-        log(`  Description: ${functionDef.returns.description || 'N/A'}`);
-      }
-    }
+    const signed = wallet.sign(tx);
 
-    if (meta?.GasUsed !== undefined) {
-      log('\nGas/Computation Used:');
-      log(`  Gas Used: ${meta.GasUsed}`);
-      log(`  Allowance: ${tx.ComputationAllowance}`);
-      const percentage = (
-        (meta.GasUsed / parseInt(tx.ComputationAllowance)) *
-        100
-      ).toFixed(2);
-      log(`  Percentage: ${percentage}%`);
-    }
+    log('Transaction ID:', signed.hash);
 
-    // Output call result as clean JSON to stdout
-    const callResult = {
+    // Submission and tracking are done by the caller
+    await disconnect();
+    const signedResult = {
       success: true,
       data: {
-        txHash: signed.hash,
-        returnCode: meta?.WasmReturnCode,
-        returnValue: meta?.ReturnValue,
-        gasUsed: meta?.GasUsed,
-        validated: txResult.validated,
-        transactionResult: meta?.TransactionResult,
-        meta: meta,
+        signedTx: { txBlob: signed.tx_blob, hash: signed.hash, lastLedgerSequence: tx.LastLedgerSequence },
       },
     };
-
-    console.log(JSON.stringify(callResult));
-    return callResult;
+    console.log(JSON.stringify(signedResult));
+    return signedResult;
   } catch (error) {
-    if (client.isConnected()) {
-      await client.disconnect();
-    }
+    await disconnect();
 
     // Output error as JSON
     const errorResult = {
@@ -404,47 +415,6 @@ async function callContract(config) {
   }
 }
 
-/**
- * Send a JSON-RPC request via HTTP
- */
-function httpRPC(rpcUrl, method, params, timeout) {
-  return new Promise((resolve, reject) => {
-    const url = new URL(rpcUrl);
-    const protocol = url.protocol === 'https:' ? https : http;
-    const postData = JSON.stringify({ method, params: [params] });
-    const options = {
-      hostname: url.hostname,
-      port: url.port,
-      path: url.pathname,
-      method: 'POST',
-      headers: {
-        'Content-Type': 'application/json',
-        'Content-Length': Buffer.byteLength(postData),
-      },
-    };
-    const req = protocol.request(options, (res) => {
-      let data = '';
-      res.on('data', (chunk) => { data += chunk; });
-      res.on('end', () => {
-        try {
-          const parsed = JSON.parse(data);
-          if (parsed.result && parsed.result.status === 'error') {
-            reject(new Error(`${parsed.result.error}: ${parsed.result.error_message}`));
-          } else {
-            resolve(parsed.result);
-          }
-        } catch (e) {
-          reject(new Error(`Failed to parse RPC response: ${e.message}`));
-        }
-      });
-    });
-    req.on('error', (err) => reject(err));
-    req.setTimeout(timeout || 30000, () => { req.destroy(); reject(new Error('RPC request timeout')); });
-    req.write(postData);
-    req.end();
-  });
-}
-
 // CLI interface
 if (require.main === module) {
   const args = process.argv.slice(2);
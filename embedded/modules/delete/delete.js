@@ -19,6 +19,11 @@ async function deleteContract(config) {
     algorithm,
     fee,
     verbose,
+    unsigned,
+    sign_only,
+    last_ledger_sequence,
+    account,
+    signers_count,
   } = config;
 
   const log = verbose ? console.error.bind(console) : () => {};
@@ -32,23 +37,63 @@ async function deleteContract(config) {
     await client.connect();
     log('Connected to network');
 
+    // Unsigned transactions only need the signing account's address
     const algo = algorithm === 'ed25519' ? undefined : xrpl.ECDSA.secp256k1;
-    const wallet = algo
-      ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm: algo })
-      : xrpl.Wallet.fromSeed(wallet_seed);
+    let wallet = null;
+    if (!unsigned) {
+      wallet = algo
+        ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm: algo })
+        : xrpl.Wallet.fromSeed(wallet_seed);
+    } else if (!account) {
+      throw new Error('account is required when building an unsigned transaction');
+    }
+    const address = wallet ? wallet.address : account;
 
     const tx = {
       TransactionType: 'ContractDelete',
-      Account: wallet.address,
+      Account: address,
       ContractAccount: contract_account,
       Fee: fee || '1000000',
     };
 
     const prepared = await client.autofill(tx);
+
+    if (last_ledger_sequence) {
+      prepared.LastLedgerSequence = last_ledger_sequence;
+    }
+
+    // Unsigned mode: hand the autofilled transaction back for offline signing.
+    // LastLedgerSequence is dropped since offline signing can take longer than it allows.
+    if (unsigned) {
+      delete prepared.LastLedgerSequence;
+
+      // Multisig-ready: empty SigningPubKey and a fee covering every signature
+      if (signers_count > 0) {
+        prepared.SigningPubKey = '';
+        prepared.Fee = (BigInt(prepared.Fee) * BigInt(signers_count + 1)).toString();
+      }
+
+      await client.disconnect();
+      console.log(JSON.stringify({ success: true, data: { unsignedTx: prepared } }));
+      return;
+    }
+
     const signed = wallet.sign(prepared);
 
     log('Transaction ID:', signed.hash);
 
+    // Sign-only mode: submission and tracking are done by the caller
+    if (sign_only) {
+      await client.disconnect();
+      console.log(JSON.stringify({
+        success: true,
+        data: {
+          signedTx: { txBlob: signed.tx_blob, hash: signed.hash, lastLedgerSequence: prepared.LastLedgerSequence },
+        },
+      }));
+      return;
+    }
+
     const result = await client.submitAndWait(signed.tx_blob);
 
     await client.disconnect();
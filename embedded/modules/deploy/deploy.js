@@ -14,30 +14,39 @@
  *   "abi_path": "/path/to/abi.json",
  *   "network_url": "wss://alphanet.xrpl.org",
  *   "wallet_seed": "sXXX..." (optional),
- *   "faucet_url": "https://faucet..." (optional),
+ *   "unsigned": true (optional, emit the unsigned transaction instead of signing it),
+ *   "account": "rXXX..." (required with unsigned),
+ *   "last_ledger_sequence": 123 (optional, set on the signed transaction),
+ *   "sequence": 7 (optional, autofilled by the caller; the transaction is then built
+ *                  without connecting to network_url),
  *   "fee": "100000000" (optional, default 100 XRP),
  *   "verbose": true (optional)
  * }
  *
- * Output JSON format:
+ * Output JSON format (the caller submits and tracks the signed transaction):
  * {
  *   "success": true,
  *   "data": {
- *     "txHash": "...",
+ *     "signedTx": { "txBlob": "...", "hash": "...", "lastLedgerSequence": 123 },
  *     "walletAddress": "...",
- *     "walletSeed": "...",
- *     "contractAccount": "...",
- *     "contractIndex": "...",
- *     "validated": true
+ *     "walletSeed": "..."
  *   }
  * }
+ *
+ * Progress events are printed to stdout as NDJSON before the result line:
+ *   {"type":"progress","event":"phase","phase":"connect","message":"..."}
  */
 
 const xrpl = require('@transia/xrpl');
 const fs = require('fs');
 const path = require('path');
-const http = require('http');
-const https = require('https');
+
+/**
+ * Emit a progress event as an NDJSON line on stdout
+ */
+function progress(event, fields = {}) {
+  console.log(JSON.stringify({ type: 'progress', event, ...fields }));
+}
 
 /**
  * Extract exported function names from WASM binary
@@ -152,7 +161,6 @@ async function deployContract(config) {
     abi_path,
     network_url,
     wallet_seed,
-    faucet_url,
     fee,
     verbose,
     immutable,
@@ -162,28 +170,50 @@ async function deployContract(config) {
     reuse_code,
     params,
     owner,
+    unsigned,
+    last_ledger_sequence,
+    account,
+    sequence,
   } = config;
 
   const log = verbose ? console.error.bind(console) : () => {};
 
   log('Deploying contract to XRPL...\n');
 
-  const client = new xrpl.Client(network_url);
-  client.apiVersion = 1;
+  // With the Sequence autofilled by the caller there is nothing to look up
+  const offline = sequence !== undefined && sequence !== null;
+  const client = offline ? null : new xrpl.Client(network_url);
+  const disconnect = async () => {
+    if (client && client.isConnected()) {
+      await client.disconnect();
+    }
+  };
 
   try {
-    await client.connect();
-    log('✓ Connected to network');
+    if (client) {
+      client.apiVersion = 1;
+      progress('phase', { phase: 'connect', message: `Connecting to ${network_url}` });
+      await client.connect();
+      log('✓ Connected to network');
+    }
 
-    // Create or restore wallet
+    // Create or restore wallet (unsigned transactions only need the address)
     const algorithm = config.algorithm === 'ed25519' ? undefined : xrpl.ECDSA.secp256k1;
-    const wallet = wallet_seed
-      ? (algorithm ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm }) : xrpl.Wallet.fromSeed(wallet_seed))
-      : (algorithm ? xrpl.Wallet.generate(algorithm) : xrpl.Wallet.generate());
+    let wallet = null;
+    if (!unsigned) {
+      wallet = wallet_seed
+        ? (algorithm ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm }) : xrpl.Wallet.fromSeed(wallet_seed))
+        : (algorithm ? xrpl.Wallet.generate(algorithm) : xrpl.Wallet.generate());
+    } else if (!account) {
+      throw new Error('account is required when building an unsigned transaction');
+    }
+    const address = wallet ? wallet.address : account;
 
     log('\nWallet:');
-    log('  Address:', wallet.address);
-    log('  Seed:', wallet.seed);
+    log('  Address:', address);
+    if (wallet) {
+      log('  Seed:', wallet.seed);
+    }
 
     // Read WASM file (unless reusing existing code by hash)
     let wasmHex;
@@ -192,6 +222,7 @@ async function deployContract(config) {
     if (reuse_code) {
       log(`\nReusing existing ContractSource by hash: ${reuse_code}`);
     } else {
+      progress('phase', { phase: 'code', message: 'Loading contract code' });
       if (!fs.existsSync(wasm_path)) {
         throw new Error(`WASM file not found: ${wasm_path}`);
       }
@@ -253,86 +284,30 @@ async function deployContract(config) {
       Functions = buildFunctionsFromABI(abi, functionNames);
     }
 
-    // Check balance and auto-fund if needed
-    let balance = 0;
-    try {
-      balance = await client.getXrpBalance(wallet.address);
-    } catch (e) {
-      // Account not found -- needs funding
-      balance = 0;
-    }
-    log(`\nWallet balance: ${balance} XRP`);
-
-    if (parseFloat(balance) === 0 && faucet_url) {
-      log('Wallet not funded, requesting funds...');
-      const isLocal = network_url.includes('localhost') || network_url.includes('127.0.0.1');
-      if (isLocal) {
-        const GENESIS_SEED = 'snoPBrXtMeMyMHUVTgbuqAfg1SUTb';
-        const genesisWallet = xrpl.Wallet.fromSeed(GENESIS_SEED, { algorithm: xrpl.ECDSA.secp256k1 });
-        const payment = {
-          TransactionType: 'Payment',
-          Account: genesisWallet.address,
-          Destination: wallet.address,
-          Amount: '1000000000',
-        };
-        const prepared = await client.autofill(payment);
-        const signed = genesisWallet.sign(prepared);
-        const fundResult = await client.submitAndWait(signed.tx_blob);
-        if (fundResult.result.meta.TransactionResult !== 'tesSUCCESS') {
-          throw new Error(`Funding failed: ${fundResult.result.meta.TransactionResult}`);
-        }
-        log('Funded from local genesis account');
-      } else {
-        // Use external faucet
-        const https = require('https');
-        const http = require('http');
-        await new Promise((resolve, reject) => {
-          const url = new URL(faucet_url);
-          const protocol = url.protocol === 'https:' ? https : http;
-          const postData = JSON.stringify({ destination: wallet.address });
-          const options = {
-            hostname: url.hostname,
-            port: url.port,
-            path: url.pathname + url.search,
-            method: 'POST',
-            headers: {
-              'Content-Type': 'application/json',
-              'Content-Length': Buffer.byteLength(postData),
-            },
-          };
-          const req = protocol.request(options, (res) => {
-            let data = '';
-            res.on('data', (chunk) => { data += chunk; });
-            res.on('end', () => {
-              if (res.statusCode === 200 || res.statusCode === 201) {
-                resolve(data);
-              } else {
-                reject(new Error(`Faucet request failed: ${res.statusCode} ${data}`));
-              }
-            });
-          });
-          req.on('error', (err) => reject(err));
-          req.write(postData);
-          req.end();
-        });
-        log('Funded from external faucet');
+    // Funding is done by the caller before the module runs
+    if (client) {
+      let balance = 0;
+      try {
+        balance = await client.getXrpBalance(address);
+      } catch (e) {
+        // Account not found
+        balance = 0;
+      }
+      log(`\nWallet balance: ${balance} XRP`);
+
+      if (parseFloat(balance) === 0) {
+        log('Warning: Wallet not funded, deployment will likely fail');
       }
-      // Wait a moment for ledger to close
-      await new Promise(r => setTimeout(r, 2000));
-      balance = await client.getXrpBalance(wallet.address);
-      log(`Updated balance: ${balance} XRP`);
-    } else if (parseFloat(balance) === 0) {
-      log('Warning: Wallet not funded and no faucet URL provided, deployment will likely fail');
     }
 
-    // Create ContractCreate transaction via manual construction + HTTP RPC
+    // Create ContractCreate transaction via manual construction
     // (client.autofill may not support ContractCreate on xrpld 3.2.0)
     log('\nSubmitting contract creation transaction...');
+    progress('phase', { phase: 'prepare', message: 'Preparing ContractCreate transaction' });
 
-    const accountInfo = await client.request({
-      command: 'account_info',
-      account: wallet.address,
-    });
+    const accountSequence = offline
+      ? sequence
+      : (await client.request({ command: 'account_info', account: address })).result.account_data.Sequence;
 
     // Compute deploy flags
     let deployFlags = 0;
@@ -343,13 +318,16 @@ async function deployContract(config) {
 
     const tx = {
       TransactionType: 'ContractCreate',
-      Account: wallet.address,
+      Account: address,
       Fee: fee || '100000000', // 100 XRP default
-      Sequence: accountInfo.result.account_data.Sequence,
-      SigningPubKey: wallet.publicKey,
+      Sequence: accountSequence,
       NetworkID: config.network_id,
     };
 
+    if (wallet) {
+      tx.SigningPubKey = wallet.publicKey;
+    }
+
     // Use ContractHash (reuse existing code) or ContractCode (new WASM)
     if (reuse_code) {
       tx.ContractHash = reuse_code;
@@ -378,126 +356,37 @@ async function deployContract(config) {
       }
     }
 
-    const signed = wallet.sign(tx);
-
-    log('Transaction ID:', signed.hash);
-
-    const isLocal = network_url.includes('localhost') || network_url.includes('127.0.0.1');
-
-    let txResult = null;
-
-    if (isLocal) {
-      // Local nodes: use HTTP RPC to avoid WebSocket hangs under emulation
-      await client.disconnect();
-
-      const rpcUrl = network_url
-        .replace('ws://', 'http://').replace('wss://', 'https://')
-        .replace('localhost:6006', 'localhost:5005');
-
-      const submitResult = await httpRPC(rpcUrl, 'submit', { tx_blob: signed.tx_blob }, 120000);
-
-      log('Submit response:', JSON.stringify(submitResult).substring(0, 200));
-
-      if (submitResult.engine_result &&
-          submitResult.engine_result !== 'tesSUCCESS' &&
-          !submitResult.engine_result.startsWith('tes')) {
-        throw new Error(`Transaction rejected: ${submitResult.engine_result} - ${submitResult.engine_result_message}`);
-      }
-
-      // Wait for validation by polling tx via HTTP RPC
-      for (let attempt = 0; attempt < 60; attempt++) {
-        await new Promise(r => setTimeout(r, 1000));
-        try {
-          const txRes = await httpRPC(rpcUrl, 'tx', { transaction: signed.hash }, 10000);
-          if (txRes.validated) {
-            txResult = txRes;
-            break;
-          }
-        } catch (e) {
-          // Transaction not yet found, keep waiting
-        }
-      }
-    } else {
-      // Remote networks: use WebSocket submit and wait
-      const submitResult = await client.request({
-        command: 'submit',
-        tx_blob: signed.tx_blob,
-      });
-
-      log('Submit response:', JSON.stringify(submitResult.result).substring(0, 200));
-
-      if (submitResult.result.engine_result &&
-          submitResult.result.engine_result !== 'tesSUCCESS' &&
-          !submitResult.result.engine_result.startsWith('tes')) {
-        throw new Error(`Transaction rejected: ${submitResult.result.engine_result} - ${submitResult.result.engine_result_message}`);
-      }
-
-      // Poll for validation via WebSocket
-      for (let attempt = 0; attempt < 60; attempt++) {
-        await new Promise(r => setTimeout(r, 1000));
-        try {
-          const txRes = await client.request({
-            command: 'tx',
-            transaction: signed.hash,
-          });
-          if (txRes.result.validated) {
-            txResult = txRes.result;
-            break;
-          }
-        } catch (e) {
-          // Transaction not yet found, keep waiting
-        }
-      }
-
-      await client.disconnect();
+    if (last_ledger_sequence) {
+      tx.LastLedgerSequence = last_ledger_sequence;
     }
 
-    if (!txResult) {
-      throw new Error('Transaction was not validated within 60 seconds');
+    // Unsigned mode: hand the autofilled transaction back for offline signing
+    if (unsigned) {
+      await disconnect();
+      const unsignedResult = { success: true, data: { unsignedTx: tx } };
+      console.log(JSON.stringify(unsignedResult));
+      return unsignedResult;
     }
 
-    log('\n✓ Contract deployed successfully!');
-
-    // Extract contract account from result
-    const meta = txResult.meta;
-    let contractAccount = null;
-    let contractIndex = null;
-
-    if (meta?.AffectedNodes) {
-      for (const node of meta.AffectedNodes) {
-        if (node.CreatedNode?.LedgerEntryType === 'Contract') {
-          contractIndex = node.CreatedNode.LedgerIndex;
-          contractAccount = node.CreatedNode.NewFields?.ContractAccount;
-          log('\nContract Ledger Index:', contractIndex);
-          if (contractAccount) {
-            log('Contract Account:', contractAccount);
-          }
-        }
-      }
-    }
+    progress('phase', { phase: 'sign', message: 'Signing transaction' });
+    const signed = wallet.sign(tx);
 
-    log('\n✓ Done');
+    log('Transaction ID:', signed.hash);
 
-    // Output deployment info as clean JSON to stdout
-    const deploymentInfo = {
+    // Submission and tracking are done by the caller
+    await disconnect();
+    const signedResult = {
       success: true,
       data: {
-        txHash: signed.hash,
+        signedTx: { txBlob: signed.tx_blob, hash: signed.hash, lastLedgerSequence: tx.LastLedgerSequence },
         walletAddress: wallet.address,
         walletSeed: wallet.seed,
-        contractAccount: contractAccount,
-        contractIndex: contractIndex,
-        validated: txResult.validated,
-        meta: meta,
       },
     };
-
-    console.log(JSON.stringify(deploymentInfo));
-    return deploymentInfo;
+    console.log(JSON.stringify(signedResult));
+    return signedResult;
   } catch (error) {
-    if (client.isConnected()) {
-      await client.disconnect();
-    }
+    await disconnect();
 
     // Output error as JSON
     const errorResult = {
@@ -511,47 +400,6 @@ async function deployContract(config) {
   }
 }
 
-/**
- * Send a JSON-RPC request via HTTP
- */
-function httpRPC(rpcUrl, method, params, timeout) {
-  return new Promise((resolve, reject) => {
-    const url = new URL(rpcUrl);
-    const protocol = url.protocol === 'https:' ? https : http;
-    const postData = JSON.stringify({ method, params: [params] });
-    const options = {
-      hostname: url.hostname,
-      port: url.port,
-      path: url.pathname,
-      method: 'POST',
-      headers: {
-        'Content-Type': 'application/json',
-        'Content-Length': Buffer.byteLength(postData),
-      },
-    };
-    const req = protocol.request(options, (res) => {
-      let data = '';
-      res.on('data', (chunk) => { data += chunk; });
-      res.on('end', () => {
-        try {
-          const parsed = JSON.parse(data);
-          if (parsed.result && parsed.result.status === 'error') {
-            reject(new Error(`${parsed.result.error}: ${parsed.result.error_message}`));
-          } else {
-            resolve(parsed.result);
-          }
-        } catch (e) {
-          reject(new Error(`Failed to parse RPC response: ${e.message}`));
-        }
-      });
-    });
-    req.on('error', (err) => reject(err));
-    req.setTimeout(timeout || 30000, () => { req.destroy(); reject(new Error('RPC request timeout')); });
-    req.write(postData);
-    req.end();
-  });
-}
-
 // CLI interface
 if (require.main === module) {
   const args = process.argv.slice(2);
@@ -566,7 +414,6 @@ The config JSON file should contain:
   "abi_path": "/path/to/abi.json",
   "network_url": "wss://alphanet.xrpl.org",
   "wallet_seed": "sXXX..." (optional),
-  "faucet_url": "https://faucet..." (optional),
   "fee": "100000000" (optional),
   "verbose": true (optional)
 }
@@ -13,7 +13,9 @@
  *   "wallet_seed": "sXXX..." (optional),
  *   "wallet_address": "rXXX..." (optional),
  *   "network_url": "wss://..." (optional, for balance check),
- *   "is_local": false (optional, if true uses genesis account for funding),
+ *   "is_local": false (optional, if true signs a funding payment from the genesis account),
+ *   "sign_only": true (required with is_local, the caller submits the signed payment),
+ *   "last_ledger_sequence": 123 (optional, set on the signed payment),
  *   "verbose": true (optional)
  * }
  *
@@ -28,6 +30,9 @@
  *     "faucetAmount": "1000"
  *   }
  * }
+ *
+ * Progress events are printed to stdout as NDJSON before the result line:
+ *   {"type":"progress","event":"phase","phase":"fund","message":"..."}
  */
 
 const xrpl = require('@transia/xrpl');
@@ -35,6 +40,13 @@ const fs = require('fs');
 const https = require('https');
 const http = require('http');
 
+/**
+ * Emit a progress event as an NDJSON line on stdout
+ */
+function progress(event, fields = {}) {
+  console.log(JSON.stringify({ type: 'progress', event, ...fields }));
+}
+
 // Genesis account seed for local development
 const GENESIS_SEED = 'snoPBrXtMeMyMHUVTgbuqAfg1SUTb';
 // Default amount to fund in drops (1000 XRP)
@@ -44,8 +56,16 @@ const LOCAL_FAUCET_AMOUNT = '1000000000';
  * Request funds from XRPL faucet
  */
 async function requestFaucet(config) {
-  const { faucet_url, wallet_seed, wallet_address, network_url, is_local, verbose } =
-    config;
+  const {
+    faucet_url,
+    wallet_seed,
+    wallet_address,
+    network_url,
+    is_local,
+    sign_only,
+    last_ledger_sequence,
+    verbose,
+  } = config;
 
   const log = verbose ? console.error.bind(console) : () => {};
 
@@ -73,25 +93,38 @@ async function requestFaucet(config) {
       log('  Seed:', wallet.seed);
     }
 
-    let faucetResult;
-
-    // Use local genesis funding or external faucet
+    // Local funding is a genesis payment; the caller submits and tracks it
     if (is_local) {
-      log('\nFunding from local genesis account...');
-      log('  Network URL:', network_url);
-      faucetResult = await fundFromGenesis(network_url, address, log);
-      log('✓ Local funding successful');
-    } else {
-      log('\nRequesting funds from faucet...');
-      log('  Faucet URL:', faucet_url);
-      faucetResult = await makeFaucetRequest(faucet_url, address);
-      log('✓ Faucet request successful');
+      if (!sign_only) {
+        throw new Error('local funding is only signed here; set sign_only and submit the result');
+      }
+      log('\nSigning funding payment from local genesis account...');
+      progress('phase', { phase: 'sign', message: 'Signing funding payment from genesis account' });
+      const signed = await signGenesisPayment(network_url, address, last_ledger_sequence);
+      const result = {
+        success: true,
+        data: {
+          signedTx: signed,
+          walletAddress: address,
+          walletSeed: wallet ? wallet.seed : '',
+          faucetAmount: String(Number(LOCAL_FAUCET_AMOUNT) / 1000000),
+        },
+      };
+      console.log(JSON.stringify(result));
+      return result;
     }
 
+    log('\nRequesting funds from faucet...');
+    log('  Faucet URL:', faucet_url);
+    progress('phase', { phase: 'fund', message: `Requesting funds from ${faucet_url}` });
+    const faucetResult = await makeFaucetRequest(faucet_url, address);
+    log('✓ Faucet request successful');
+
     // Get balance if network URL provided
     let balance = null;
     if (network_url) {
       log('\nChecking balance...');
+      progress('phase', { phase: 'balance', message: 'Checking balance' });
       const client = new xrpl.Client(network_url);
       client.apiVersion = 1;
       await client.connect();
@@ -127,38 +160,31 @@ async function requestFaucet(config) {
 }
 
 /**
- * Fund an address from the local genesis account
+ * Build and sign a funding payment from the local genesis account
  */
-async function fundFromGenesis(networkUrl, destinationAddress, log) {
+async function signGenesisPayment(networkUrl, destinationAddress, lastLedgerSequence) {
   const client = new xrpl.Client(networkUrl);
   client.apiVersion = 1;
   await client.connect();
 
   try {
-    // Create genesis wallet
     const genesisWallet = xrpl.Wallet.fromSeed(GENESIS_SEED, { algorithm: xrpl.ECDSA.secp256k1 });
-    log('  Genesis address:', genesisWallet.address);
 
-    // Prepare Payment transaction
-    const payment = {
+    const prepared = await client.autofill({
       TransactionType: 'Payment',
       Account: genesisWallet.address,
       Destination: destinationAddress,
       Amount: LOCAL_FAUCET_AMOUNT,
-    };
-
-    // Autofill, sign, and submit
-    const prepared = await client.autofill(payment);
-    const signed = genesisWallet.sign(prepared);
-    const result = await client.submitAndWait(signed.tx_blob);
-
-    if (result.result.meta.TransactionResult !== 'tesSUCCESS') {
-      throw new Error(`Transaction failed: ${result.result.meta.TransactionResult}`);
+    });
+    if (lastLedgerSequence) {
+      prepared.LastLedgerSequence = lastLedgerSequence;
     }
 
+    const signed = genesisWallet.sign(prepared);
     return {
-      txHash: result.result.hash,
-      amount: String(Number(LOCAL_FAUCET_AMOUNT) / 1000000), // Convert drops to XRP
+      txBlob: signed.tx_blob,
+      hash: signed.hash,
+      lastLedgerSequence: prepared.LastLedgerSequence,
     };
   } finally {
     await client.disconnect();
@@ -6,11 +6,24 @@
  * Handles modifying deployed contracts via ContractModify transaction.
  *
  * Usage: node modify.js <config-json-path>
+ *
+ * Progress events are printed to stdout as NDJSON before the result line:
+ *   {"type":"progress","event":"phase","phase":"connect","message":"..."}
+ *   {"type":"progress","event":"submitted","txHash":"..."}
+ *   {"type":"progress","event":"waiting","txHash":"..."}
+ *   {"type":"progress","event":"validated","txHash":"...","result":"tesSUCCESS","ledgerIndex":123}
  */
 
 const xrpl = require('@transia/xrpl');
 const fs = require('fs');
 
+/**
+ * Emit a progress event as an NDJSON line on stdout
+ */
+function progress(event, fields = {}) {
+  console.log(JSON.stringify({ type: 'progress', event, ...fields }));
+}
+
 function buildFunctionsFromABI(abi, exportedFunctions) {
   const functions = [];
 
@@ -49,6 +62,11 @@ async function modifyContract(config) {
     abi_path,
     fee,
     verbose,
+    unsigned,
+    sign_only,
+    last_ledger_sequence,
+    account,
+    signers_count,
     owner,
     contract_hash,
     immutable,
@@ -65,17 +83,25 @@ async function modifyContract(config) {
   client.apiVersion = 1;
 
   try {
+    progress('phase', { phase: 'connect', message: `Connecting to ${network_url}` });
     await client.connect();
     log('Connected to network');
 
+    // Unsigned transactions only need the signing account's address
     const algo = algorithm === 'ed25519' ? undefined : xrpl.ECDSA.secp256k1;
-    const wallet = algo
-      ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm: algo })
-      : xrpl.Wallet.fromSeed(wallet_seed);
+    let wallet = null;
+    if (!unsigned) {
+      wallet = algo
+        ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm: algo })
+        : xrpl.Wallet.fromSeed(wallet_seed);
+    } else if (!account) {
+      throw new Error('account is required when building an unsigned transaction');
+    }
+    const address = wallet ? wallet.address : account;
 
     const tx = {
       TransactionType: 'ContractModify',
-      Account: wallet.address,
+      Account: address,
       ContractAccount: contract_account,
       Fee: fee || '10000000',
     };
@@ -117,12 +143,54 @@ async function modifyContract(config) {
       log(`Setting flags: 0x${modifyFlags.toString(16)}`);
     }
 
+    progress('phase', { phase: 'prepare', message: 'Preparing ContractModify transaction' });
     const prepared = await client.autofill(tx);
+
+    if (last_ledger_sequence) {
+      prepared.LastLedgerSequence = last_ledger_sequence;
+    }
+
+    // Unsigned mode: hand the autofilled transaction back for offline signing.
+    // LastLedgerSequence is dropped since offline signing can take longer than it allows.
+    if (unsigned) {
+      delete prepared.LastLedgerSequence;
+
+      // Multisig-ready: empty SigningPubKey and a fee covering every signature
+      if (signers_count > 0) {
+        prepared.SigningPubKey = '';
+        prepared.Fee = (BigInt(prepared.Fee) * BigInt(signers_count + 1)).toString();
+      }
+
+      await client.disconnect();
+      console.log(JSON.stringify({ success: true, data: { unsignedTx: prepared } }));
+      return;
+    }
+
+    progress('phase', { phase: 'sign', message: 'Signing transaction' });
     const signed = wallet.sign(prepared);
 
     log('Transaction ID:', signed.hash);
 
+    // Sign-only mode: submission and tracking are done by the caller
+    if (sign_only) {
+      await client.disconnect();
+      console.log(JSON.stringify({
+        success: true,
+        data: {
+          signedTx: { txBlob: signed.tx_blob, hash: signed.hash, lastLedgerSequence: prepared.LastLedgerSequence },
+        },
+      }));
+      return;
+    }
+
+    progress('submitted', { txHash: signed.hash });
+    progress('waiting', { txHash: signed.hash });
     const result = await client.submitAndWait(signed.tx_blob);
+    progress('validated', {
+      txHash: signed.hash,
+      result: result.result.meta?.TransactionResult,
+      ledgerIndex: result.result.ledger_index,
+    });
 
     await client.disconnect();
 
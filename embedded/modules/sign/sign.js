@@ -0,0 +1,109 @@
+#!/usr/bin/env node
+
+/**
+ * XRPL Transaction Signing Module
+ *
+ * Signs a prepared transaction offline. No network connection is made, so
+ * this module can run on an air-gapped host.
+ *
+ * Modes:
+ *   sign      - single signature (default)
+ *   multisign - add this wallet's Signer entry to a multisig transaction
+ *   combine   - merge Signers from several multi-signed blobs
+ *
+ * Usage: node sign.js <config-json-path>
+ */
+
+const xrpl = require('@transia/xrpl');
+const fs = require('fs');
+
+async function signTransaction(config) {
+  const {
+    mode,
+    tx_json,
+    tx_blobs,
+    wallet_seed,
+    algorithm,
+    verbose,
+  } = config;
+
+  const log = verbose ? console.error.bind(console) : () => {};
+
+  try {
+    let txBlob;
+
+    if (mode === 'combine') {
+      // Merge the Signers of several multi-signed copies of the same transaction
+      if (!tx_blobs || tx_blobs.length === 0) {
+        throw new Error('tx_blobs is required to combine signatures');
+      }
+      txBlob = xrpl.multisign(tx_blobs);
+      log(`Combined ${tx_blobs.length} signature(s)`);
+    } else {
+      if (!tx_json) {
+        throw new Error('tx_json is required');
+      }
+
+      const algo = algorithm === 'ed25519' ? undefined : xrpl.ECDSA.secp256k1;
+      const wallet = algo
+        ? xrpl.Wallet.fromSeed(wallet_seed, { algorithm: algo })
+        : xrpl.Wallet.fromSeed(wallet_seed);
+
+      if (mode === 'multisign') {
+        // Every signer signs the same transaction: no Signers yet and an empty SigningPubKey
+        const { Signers, ...shared } = tx_json;
+        const signed = wallet.sign({ ...shared, SigningPubKey: '' }, true);
+        txBlob = signed.tx_blob;
+        log(`Added signature from ${wallet.address}`);
+      } else {
+        if (tx_json.Account && tx_json.Account !== wallet.address) {
+          log(`Warning: signing for ${tx_json.Account} with key of ${wallet.address} (regular key?)`);
+        }
+        txBlob = wallet.sign(tx_json).tx_blob;
+      }
+    }
+
+    const hash = xrpl.hashes.hashSignedTx(txBlob);
+
+    log('Transaction ID:', hash);
+
+    console.log(JSON.stringify({
+      success: true,
+      data: {
+        txBlob,
+        hash,
+        txJson: xrpl.decode(txBlob),
+      },
+    }));
+  } catch (error) {
+    console.log(JSON.stringify({
+      success: false,
+      error: error.message,
+      details: error.data ? JSON.stringify(error.data) : error.stack,
+    }));
+    process.exit(1);
+  }
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  if (args.length < 1) {
+    console.error('Usage: node sign.js <config-json-path>');
+    process.exit(1);
+  }
+
+  const configPath = args[0];
+  if (!fs.existsSync(configPath)) {
+    console.log(JSON.stringify({
+      success: false,
+      error: `Config file not found: ${configPath}`,
+      details: 'Please provide a valid config JSON file path',
+    }));
+    process.exit(1);
+  }
+
+  const configContent = fs.readFileSync(configPath, 'utf8');
+  signTransaction(JSON.parse(configContent));
+}
+
+module.exports = { signTransaction };
@@ -0,0 +1,342 @@
+#!/usr/bin/env node
+
+/**
+ * Bedrock Module Worker
+ *
+ * A long-lived process that runs the other modules on request, so the CLI
+ * does not pay for a Node start-up and a fresh XRPL connection on every
+ * call. Messages are newline-delimited JSON-RPC 2.0 over stdin/stdout.
+ *
+ * Usage: node worker.js
+ *
+ * Requests:
+ *   {"jsonrpc":"2.0","id":1,"method":"run","params":{"module":"call.js","config":{...}}}
+ *   {"jsonrpc":"2.0","method":"cancel","params":{"id":1}}
+ *   {"jsonrpc":"2.0","id":2,"method":"shutdown"}
+ *
+ * Responses:
+ *   {"jsonrpc":"2.0","id":1,"result":{"success":true,"data":{...}}}
+ *   {"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"..."}}
+ *
+ * Progress events printed by a module are forwarded as they happen:
+ *   {"jsonrpc":"2.0","method":"progress","params":{"id":1,"event":{"type":"progress",...}}}
+ *
+ * Each module prints its result with console.log and calls process.exit(1)
+ * on failure. Inside the worker both are captured per request, so modules
+ * run unchanged. Clients for the same URL share one pooled connection that
+ * stays open between requests; disconnect() only detaches the caller.
+ */
+
+const path = require('path');
+const readline = require('readline');
+const { AsyncLocalStorage } = require('async_hooks');
+const xrpl = require('@transia/xrpl');
+
+// Entry point exported by each module
+const ENTRY_POINTS = {
+  'deploy.js': 'deployContract',
+  'call.js': 'callContract',
+  'faucet.js': 'requestFaucet',
+  'modify.js': 'modifyContract',
+  'delete.js': 'deleteContract',
+  'user_delete.js': 'contractUserDelete',
+  'clawback.js': 'clawbackContract',
+  'sign.js': 'signTransaction',
+};
+
+// JSON-RPC error codes
+const PARSE_ERROR = -32700;
+const METHOD_NOT_FOUND = -32601;
+const INVALID_PARAMS = -32602;
+const MODULE_ERROR = -32000;
+const CANCELLED = -32001;
+
+const requestContext = new AsyncLocalStorage();
+const inFlight = new Map();
+const writeOut = process.stdout.write.bind(process.stdout);
+
+function send(message) {
+  writeOut(JSON.stringify({ jsonrpc: '2.0', ...message }) + '\n');
+}
+
+// ---------------------------------------------------------------------------
+// Output capture
+// ---------------------------------------------------------------------------
+
+// ExitSignal is thrown in place of process.exit() inside a request
+class ExitSignal extends Error {
+  constructor(code) {
+    super(`module exited with code ${code}`);
+    this.exitCode = code;
+  }
+}
+
+const originalExit = process.exit.bind(process);
+
+console.log = (...args) => {
+  const ctx = requestContext.getStore();
+  if (ctx) {
+    const line = args.map(String).join(' ');
+    const event = progressEvent(line);
+    if (event) {
+      if (!ctx.cancelled) {
+        send({ method: 'progress', params: { id: ctx.id, event } });
+      }
+    } else {
+      ctx.output.push(line);
+    }
+  } else {
+    // Never let stray output corrupt the protocol stream
+    console.error(...args);
+  }
+};
+
+// progressEvent returns the event if line is a module progress event
+function progressEvent(line) {
+  if (!line.startsWith('{"type":"progress"')) {
+    return null;
+  }
+  try {
+    return JSON.parse(line);
+  } catch (e) {
+    return null;
+  }
+}
+
+process.exit = (code) => {
+  if (requestContext.getStore()) {
+    throw new ExitSignal(code === undefined ? 0 : code);
+  }
+  originalExit(code);
+};
+
+// ---------------------------------------------------------------------------
+// Connection pool
+// ---------------------------------------------------------------------------
+
+const SHARED = Symbol('shared');
+const POOLED = Symbol('pooled');
+const pool = new Map();
+const clientProto = xrpl.Client.prototype;
+const original = {
+  connect: clientProto.connect,
+  disconnect: clientProto.disconnect,
+  isConnected: clientProto.isConnected,
+  request: clientProto.request,
+};
+
+function sharedClient(url) {
+  const existing = pool.get(url);
+  if (existing) {
+    return existing.then((client) => {
+      if (original.isConnected.call(client)) {
+        return client;
+      }
+      pool.delete(url);
+      return sharedClient(url);
+    });
+  }
+
+  const client = new xrpl.Client(url);
+  client[SHARED] = true;
+  const ready = original.connect.call(client).then(() => client);
+  ready.catch(() => pool.delete(url));
+  pool.set(url, ready);
+  return ready;
+}
+
+clientProto.connect = async function connect() {
+  if (this[SHARED]) {
+    return original.connect.call(this);
+  }
+  const shared = await sharedClient(this.url);
+  this[POOLED] = shared;
+  // autofill reads these, and they are normally set while connecting
+  this.networkID = shared.networkID;
+  this.buildVersion = shared.buildVersion;
+};
+
+clientProto.disconnect = async function disconnect() {
+  if (this[SHARED]) {
+    return original.disconnect.call(this);
+  }
+  this[POOLED] = null;
+};
+
+clientProto.isConnected = function isConnected() {
+  if (this[SHARED]) {
+    return original.isConnected.call(this);
+  }
+  return Boolean(this[POOLED] && original.isConnected.call(this[POOLED]));
+};
+
+clientProto.request = async function request(req) {
+  if (this[SHARED]) {
+    return original.request.call(this, req);
+  }
+  if (!this[POOLED]) {
+    throw new Error('Client is not connected');
+  }
+  return this[POOLED].request({
+    ...req,
+    api_version: req.api_version !== undefined ? req.api_version : this.apiVersion,
+  });
+};
+
+async function closePool() {
+  const clients = await Promise.allSettled([...pool.values()]);
+  pool.clear();
+  await Promise.allSettled(
+    clients
+      .filter((c) => c.status === 'fulfilled')
+      .map((c) => original.disconnect.call(c.value)),
+  );
+}
+
+// ---------------------------------------------------------------------------
+// Requests
+// ---------------------------------------------------------------------------
+
+// loadEntryPoint requires a module by name (embedded) or absolute path
+// (project modules) and returns the function that runs it
+function loadEntryPoint(moduleName) {
+  const modulePath = path.isAbsolute(moduleName) ? moduleName : path.join(__dirname, moduleName);
+  const exported = require(modulePath);
+
+  let entry;
+  if (path.dirname(modulePath) === __dirname && ENTRY_POINTS[path.basename(modulePath)]) {
+    entry = exported[ENTRY_POINTS[path.basename(modulePath)]];
+  } else if (typeof exported === 'function') {
+    entry = exported;
+  } else {
+    entry = exported.main || Object.values(exported).find((v) => typeof v === 'function');
+  }
+  if (typeof entry !== 'function') {
+    throw new Error(`module ${moduleName} has no entry point`);
+  }
+  return entry;
+}
+
+// lastResult returns the last line of output that parses as a module result
+function lastResult(output) {
+  for (let i = output.length - 1; i >= 0; i--) {
+    try {
+      const parsed = JSON.parse(output[i]);
+      if (parsed && typeof parsed === 'object' && 'success' in parsed) {
+        return parsed;
+      }
+    } catch (e) {
+      // Not JSON, keep looking
+    }
+  }
+  return null;
+}
+
+async function run(id, params) {
+  if (!params || typeof params.module !== 'string') {
+    send({ id, error: { code: INVALID_PARAMS, message: 'params.module is required' } });
+    return;
+  }
+
+  const ctx = { id, output: [], cancelled: false };
+  inFlight.set(id, ctx);
+
+  let failure = null;
+  try {
+    const entry = loadEntryPoint(params.module);
+    await requestContext.run(ctx, () => entry(params.config || {}));
+  } catch (error) {
+    if (!(error instanceof ExitSignal)) {
+      failure = error;
+    }
+  } finally {
+    inFlight.delete(id);
+  }
+
+  // A cancelled request has already been answered
+  if (ctx.cancelled) {
+    return;
+  }
+
+  const result = lastResult(ctx.output);
+  if (result) {
+    send({ id, result });
+  } else if (failure) {
+    send({ id, result: { success: false, error: failure.message, details: failure.stack } });
+  } else {
+    send({
+      id,
+      error: {
+        code: MODULE_ERROR,
+        message: `module ${params.module} produced no result`,
+        data: ctx.output.join('\n'),
+      },
+    });
+  }
+}
+
+function cancel(params) {
+  const id = params && params.id;
+  const ctx = inFlight.get(id);
+  if (!ctx) {
+    return;
+  }
+  // Module code cannot be interrupted, so the request is answered now and
+  // whatever it produces later is dropped
+  ctx.cancelled = true;
+  send({ id, error: { code: CANCELLED, message: 'request cancelled' } });
+}
+
+async function shutdown(id) {
+  await closePool();
+  if (id !== undefined) {
+    send({ id, result: { success: true } });
+  }
+  originalExit(0);
+}
+
+function handle(line) {
+  if (!line.trim()) {
+    return;
+  }
+
+  let message;
+  try {
+    message = JSON.parse(line);
+  } catch (error) {
+    send({ id: null, error: { code: PARSE_ERROR, message: error.message } });
+    return;
+  }
+
+  switch (message.method) {
+    case 'run':
+      run(message.id, message.params);
+      break;
+    case 'cancel':
+      cancel(message.params);
+      break;
+    case 'shutdown':
+      shutdown(message.id);
+      break;
+    default:
+      if (message.id !== undefined) {
+        send({ id: message.id, error: { code: METHOD_NOT_FOUND, message: `unknown method: ${message.method}` } });
+      }
+  }
+}
+
+// Keep serving when a module leaks an error outside its request
+process.on('uncaughtException', (error) => {
+  if (!(error instanceof ExitSignal)) {
+    console.error('worker: uncaught exception:', error);
+  }
+});
+process.on('unhandledRejection', (error) => {
+  if (!(error instanceof ExitSignal)) {
+    console.error('worker: unhandled rejection:', error);
+  }
+});
+
+const input = readline.createInterface({ input: process.stdin, crlfDelay: Infinity });
+input.on('line', handle);
+input.on('close', () => shutdown());
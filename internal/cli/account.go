@@ -11,7 +11,11 @@ import (
 	"github.com/xrpl-commons/bedrock/pkg/config"
 )
 
-var accountNetwork string
+var (
+	accountNetwork  string
+	accountLimit    int
+	accountPageSize int
+)
 
 var accountCmd = &cobra.Command{
 	Use:   "account <info|balance|objects|lines> <address>",
@@ -27,7 +31,8 @@ Commands:
 Examples:
   bedrock account info rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh
   bedrock account balance rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh
-  bedrock account objects rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh --network local`,
+  bedrock account objects rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh --network local
+  bedrock account lines rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh --limit 50`,
 	Args: cobra.ExactArgs(2),
 	RunE: runAccount,
 }
@@ -36,6 +41,8 @@ func init() {
 	rootCmd.AddCommand(accountCmd)
 
 	accountCmd.Flags().StringVarP(&accountNetwork, "network", "n", "alphanet", "Network to query (local, alphanet, testnet, mainnet)")
+	accountCmd.Flags().IntVar(&accountLimit, "limit", 0, "Maximum number of objects or lines to list (0 = all)")
+	accountCmd.Flags().IntVar(&accountPageSize, "page-size", chain.DefaultPageSize, "Entries fetched per request")
 }
 
 func runAccount(cmd *cobra.Command, args []string) error {
@@ -59,7 +66,7 @@ func runAccount(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := chain.NewClient(networkCfg.URL)
+	client := chain.NewNetworkClient(networkCfg)
 	ctx := cmd.Context()
 
 	switch subcommand {
@@ -114,27 +121,33 @@ func accountObjects(ctx context.Context, client *chain.Client, address string) e
 	color.Cyan("Account Objects\n")
 	fmt.Printf("  Address: %s\n\n", address)
 
-	objects, err := client.GetAccountObjects(ctx, address)
-	if err != nil {
+	it := client.AccountObjectsIterator(address, "", chain.PageOpts{PageSize: accountPageSize, Limit: accountLimit})
+
+	count := 0
+	for it.Next(ctx) {
+		count++
+		var parsed map[string]interface{}
+		json.Unmarshal(it.Value(), &parsed)
+
+		entryType, _ := parsed["LedgerEntryType"].(string)
+		fmt.Printf("  [%d] %s\n", count, entryType)
+
+		pretty, _ := json.MarshalIndent(parsed, "      ", "  ")
+		fmt.Printf("      %s\n\n", string(pretty))
+	}
+	if err := it.Err(); err != nil {
 		color.Red("Failed to get objects: %v\n", err)
 		return err
 	}
 
-	if len(objects.Objects) == 0 {
+	if count == 0 {
 		fmt.Println("  No objects found")
 		return nil
 	}
 
-	fmt.Printf("  Found %d objects:\n\n", len(objects.Objects))
-	for i, obj := range objects.Objects {
-		var parsed map[string]interface{}
-		json.Unmarshal(obj, &parsed)
-
-		entryType, _ := parsed["LedgerEntryType"].(string)
-		fmt.Printf("  [%d] %s\n", i+1, entryType)
-
-		pretty, _ := json.MarshalIndent(parsed, "      ", "  ")
-		fmt.Printf("      %s\n\n", string(pretty))
+	fmt.Printf("  Found %d objects\n", count)
+	if it.Truncated() {
+		color.Yellow("  More objects available (raise --limit, or --limit 0 for all)\n")
 	}
 
 	return nil
@@ -144,23 +157,22 @@ func accountLines(ctx context.Context, client *chain.Client, address string) err
 	color.Cyan("Trust Lines\n")
 	fmt.Printf("  Address: %s\n\n", address)
 
-	lines, err := client.GetAccountLines(ctx, address)
+	lines, err := client.AccountLinesIterator(address, chain.PageOpts{PageSize: accountPageSize, Limit: accountLimit}).All(ctx)
 	if err != nil {
 		color.Red("Failed to get trust lines: %v\n", err)
 		return err
 	}
 
-	if len(lines.Lines) == 0 {
+	if len(lines) == 0 {
 		fmt.Println("  No trust lines found")
 		return nil
 	}
 
-	fmt.Printf("  Found %d trust lines:\n\n", len(lines.Lines))
-	for _, line := range lines.Lines {
+	fmt.Printf("  Found %d trust lines:\n\n", len(lines))
+	for _, line := range lines {
 		fmt.Printf("  %s  Balance: %s  Limit: %s  Peer: %s\n",
 			line.Currency, line.Balance, line.Limit, line.Account)
 	}
 
 	return nil
 }
-
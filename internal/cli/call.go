@@ -4,35 +4,81 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
 	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/tester"
 	"github.com/xrpl-commons/bedrock/pkg/wallet"
 )
 
 var (
-	callNetwork    string
-	callWallet     string
-	callABI        string
-	callParams     string
-	callParamsFile string
-	callGas        string
-	callFee        string
-	callAlgorithm  string
+	callNetwork     string
+	callWallet      string
+	callABI         string
+	callParams      string
+	callParamsFile  string
+	callGas         string
+	callFee         string
+	callAlgorithm   string
+	callUnsignedOut string
+	callSimulate    bool
+	callTrace       bool
+	callAmount      string
+
+	callBatch       string
+	callOnFailure   string
+	callExport      string
+	callConcurrency int
 )
 
 var callCmd = &cobra.Command{
-	Use:   "call <contract-account> <function-name>",
+	Use:   "call <contract-account> <function-name> | --batch <file>",
 	Short: "Call a contract function",
 	Long: `Call a function on a deployed smart contract.
 
 Examples:
   bedrock call rContract123... hello
   bedrock call rContract123... register --params '{"name":"alice","age":25}'
-  bedrock call rContract123... transfer --params-file params.json --wallet sXXX...`,
-	Args: cobra.ExactArgs(2),
+  bedrock call rContract123... transfer --params-file params.json --wallet sXXX...
+  bedrock call rContract123... balance --params '{"account":"rAlice..."}' --wallet alice --simulate
+  bedrock call rContract123... create_escrow --params-file escrow.json --wallet alice --amount 5000000
+
+With --simulate the call is run through the node's simulate RPC: the return
+code, return value and gas are reported without submitting or paying fees.
+
+With --batch, calls are read from a TOML or JSON file. Each entry names a
+function, its parameters, the signing wallet and optionally the calls it
+depends_on. A call starts once its dependencies succeeded; on a failure the
+batch stops (on_failure = "stop", the default) or runs everything that does
+not depend on the failed call ("continue"):
+
+  contract = "rContract123..."
+  wallet = "alice"
+
+  [[calls]]
+  id = "mint"
+  function = "mint"
+  params = { amount = 1000 }
+
+  [[calls]]
+  id = "pay-bob"
+  function = "transfer"
+  params = { to = "rBob...", amount = 10 }
+  depends_on = ["mint"]
+
+  bedrock call --batch calls.toml --export results.csv
+  bedrock call --batch calls.toml --concurrency 4 --on-failure continue`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if callBatch != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
 	RunE: runCall,
 }
 
@@ -44,14 +90,27 @@ func init() {
 	callCmd.Flags().StringVarP(&callABI, "abi", "a", "abi.json", "Path to ABI file")
 	callCmd.Flags().StringVarP(&callParams, "params", "p", "", "Parameters as JSON string")
 	callCmd.Flags().StringVarP(&callParamsFile, "params-file", "f", "", "Parameters from JSON file")
-	callCmd.Flags().StringVarP(&callGas, "gas", "g", "1000000", "Computation allowance")
-	callCmd.Flags().StringVar(&callFee, "fee", "1000000", "Transaction fee in drops")
+	callCmd.Flags().StringVarP(&callGas, "gas", "g", "auto", "Computation allowance (\"auto\" uses the gas snapshot or a simulation)")
+	callCmd.Flags().StringVar(&callFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
 	callCmd.Flags().StringVar(&callAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm (secp256k1, ed25519)")
-
-	callCmd.MarkFlagRequired("wallet")
+	callCmd.Flags().StringVar(&callUnsignedOut, "unsigned-out", "", "Write the autofilled unsigned transaction to a file instead of submitting")
+	callCmd.Flags().BoolVar(&callSimulate, "simulate", false, "Simulate the call without submitting (read-only, no fees)")
+	callCmd.Flags().StringVar(&callAmount, "amount", "", "Value to send with the call: drops, value/CUR/issuer or value/mpt-issuance-id")
+	callCmd.Flags().BoolVar(&callTrace, "trace", false, "Show the state diff: contract data, balances, owner counts and events")
+	callCmd.Flags().StringVar(&callBatch, "batch", "", "Run the calls listed in a TOML or JSON batch file")
+	callCmd.Flags().StringVar(&callOnFailure, "on-failure", "", "Batch failure policy: stop or continue (overrides the file)")
+	callCmd.Flags().StringVar(&callExport, "export", "", "Write batch results to a .json or .csv file")
+	callCmd.Flags().IntVar(&callConcurrency, "concurrency", 1, "Batch calls in flight at once (uses Tickets when above 1)")
 }
 
 func runCall(cmd *cobra.Command, args []string) error {
+	if callBatch != "" {
+		return runCallBatch(cmd)
+	}
+	if callWallet == "" {
+		return fmt.Errorf("required flag(s) \"wallet\" not set")
+	}
+
 	contractAccount := args[0]
 	functionName := args[1]
 
@@ -103,17 +162,38 @@ func runCall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Parameters: (none)\n")
 	}
 
-	// Resolve wallet seed
-	resolver, err := wallet.NewWalletResolver()
-	if err != nil {
-		color.Red("✗ Failed to initialize wallet resolver: %v\n", err)
-		return err
+	if callSimulate && callUnsignedOut != "" {
+		return fmt.Errorf("--simulate cannot be combined with --unsigned-out")
 	}
 
-	walletSeed, err := resolver.ResolveWallet(callWallet)
-	if err != nil {
-		color.Red("✗ Failed to resolve wallet: %v\n", err)
-		return err
+	if callAmount != "" {
+		amount, err := chain.ParseAmount(callAmount)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("   Amount: %s\n", amount)
+	}
+
+	// Resolve wallet seed (or just the address when simulating or building an unsigned transaction)
+	var walletSeed, account string
+	if callUnsignedOut != "" || callSimulate {
+		account, err = resolveSigningAccount(callWallet, callAlgorithm)
+		if err != nil {
+			color.Red("✗ %v\n", err)
+			return err
+		}
+	} else {
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			color.Red("✗ Failed to initialize wallet resolver: %v\n", err)
+			return err
+		}
+
+		walletSeed, err = resolver.ResolveWallet(callWallet)
+		if err != nil {
+			color.Red("✗ Failed to resolve wallet: %v\n", err)
+			return err
+		}
 	}
 
 	fmt.Println()
@@ -126,21 +206,36 @@ func runCall(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// A previous measurement from the gas snapshot sizes an "auto" allowance
+	snapshotFile := cfg.Snapshot.File
+	if snapshotFile == "" {
+		snapshotFile = config.DefaultSnapshotConfig().File
+	}
+	gasHint := tester.PreviousGasUsed(snapshotFile, functionName)
+
 	// Call contract
-	color.Yellow("→ Executing contract call...\n\n")
+	if callSimulate {
+		color.Yellow("→ Simulating contract call...\n\n")
+	} else {
+		color.Yellow("→ Executing contract call...\n\n")
+	}
 
 	ctx := cmd.Context()
 	result, err := c.Call(ctx, caller.CallConfig{
 		ContractAccount:      contractAccount,
 		FunctionName:         functionName,
-		NetworkURL:           networkCfg.URL,
-		NetworkID:            networkCfg.NetworkID,
+		Network:              networkCfg,
 		WalletSeed:           walletSeed,
 		Algorithm:            callAlgorithm,
 		ABIPath:              callABI,
 		Parameters:           params,
 		ComputationAllowance: callGas,
 		Fee:                  callFee,
+		GasHint:              gasHint,
+		Unsigned:             callUnsignedOut != "",
+		Simulate:             callSimulate,
+		Amount:               callAmount,
+		Account:              account,
 	})
 
 	if err != nil {
@@ -148,11 +243,24 @@ func runCall(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if callUnsignedOut != "" {
+		return writeUnsignedTx(callUnsignedOut, callNetwork, result.UnsignedTx)
+	}
+
 	// Display results
-	color.Green("✓ Contract function called successfully!\n")
+	if result.Simulated {
+		color.Green("✓ Contract function simulated (nothing submitted)\n")
+	} else {
+		color.Green("✓ Contract function called successfully!\n")
+	}
 	fmt.Println()
 	color.Cyan("Call Results:\n")
-	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
+	if result.TxHash != "" {
+		fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
+	}
+	if result.Simulated && result.TransactionResult != "" {
+		fmt.Printf("  Result: %s\n", result.TransactionResult)
+	}
 	fmt.Printf("  Return Code: %d", result.ReturnCode)
 	if result.ReturnCode == 0 {
 		color.Green(" (SUCCESS)\n")
@@ -172,7 +280,24 @@ func runCall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Gas Used: %d / %s\n", result.GasUsed, callGas)
 	}
 
-	fmt.Printf("  Validated: %v\n", result.Validated)
+	if !result.Simulated {
+		fmt.Printf("  Validated: %v\n", result.Validated)
+	}
+
+	if callTrace {
+		fmt.Println()
+		color.Cyan("State Diff:\n")
+		diff := tester.DiffCall(result)
+		if data, err := os.ReadFile(callABI); err == nil {
+			var contractABI abi.ABI
+			if json.Unmarshal(data, &contractABI) == nil {
+				diff.DecodeData(contractABI.State)
+			}
+		}
+		for _, line := range strings.Split(strings.TrimRight(diff.FormatDiff(), "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/batch"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
+)
+
+func runCallBatch(cmd *cobra.Command) error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	networkCfg, ok := cfg.Networks[callNetwork]
+	if !ok {
+		if callNetwork == "local" {
+			networkCfg = config.NetworkConfig{URL: "ws://localhost:6006", NetworkID: 63456}
+		} else {
+			return fmt.Errorf("network '%s' not found in config", callNetwork)
+		}
+	}
+
+	f, err := batch.ParseFile(callBatch)
+	if err != nil {
+		color.Red("✗ Failed to parse batch: %v\n", err)
+		return err
+	}
+
+	// Flags fill in what the file leaves out
+	for i := range f.Calls {
+		if f.Calls[i].Wallet == "" {
+			f.Calls[i].Wallet = callWallet
+		}
+		if f.Calls[i].ABI == "" {
+			f.Calls[i].ABI = callABI
+		}
+		if f.Calls[i].Gas == "" {
+			f.Calls[i].Gas = callGas
+		}
+		if f.Calls[i].Fee == "" {
+			f.Calls[i].Fee = callFee
+		}
+	}
+
+	policy := f.OnFailure
+	if callOnFailure != "" {
+		policy = callOnFailure
+	}
+
+	color.Cyan("Running call batch: %s\n", callBatch)
+	fmt.Printf("   Network: %s\n", callNetwork)
+	fmt.Printf("   Calls: %d\n", len(f.Calls))
+	fmt.Printf("   On failure: %s\n", policy)
+	if callConcurrency > 1 {
+		fmt.Printf("   Concurrency: %d\n", callConcurrency)
+	}
+	fmt.Println()
+
+	resolver, err := wallet.NewWalletResolver()
+	if err != nil {
+		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	}
+
+	runner, err := batch.NewRunner(resolver, batch.Options{
+		Network:     networkCfg,
+		Algorithm:   callAlgorithm,
+		Concurrency: callConcurrency,
+		OnFailure:   callOnFailure,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  %-9s %-20s %-20s %-8s %6s %10s  %s\n", "PROGRESS", "ID", "FUNCTION", "STATUS", "CODE", "GAS", "TX / ERROR")
+	runner.OnResult = func(done, total int, r batch.Result) {
+		progress := fmt.Sprintf("[%d/%d]", done, total)
+		detail := r.TxHash
+		if r.Error != "" {
+			detail = r.Error
+		}
+
+		line := fmt.Sprintf("  %-9s %-20s %-20s %-8s %6d %10d  %s\n", progress, r.ID, r.Function, r.Status, r.ReturnCode, r.GasUsed, detail)
+		switch r.Status {
+		case batch.StatusSuccess:
+			color.Green("%s", line)
+		case batch.StatusFailed:
+			color.Red("%s", line)
+		default:
+			color.Yellow("%s", line)
+		}
+	}
+
+	report, err := runner.Run(cmd.Context(), f)
+	if err != nil {
+		color.Red("✗ Batch failed: %v\n", err)
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("  %d succeeded, %d failed, %d skipped (%v)\n", report.Succeeded, report.Failed, report.Skipped, report.Duration.Round(1e6))
+
+	if callExport != "" {
+		if err := batch.Export(callExport, report); err != nil {
+			return err
+		}
+		fmt.Printf("  Results written to %s\n", callExport)
+	}
+
+	if !report.Success() {
+		color.Red("\n✗ Batch did not complete successfully\n")
+		return fmt.Errorf("%d of %d calls did not succeed", report.Failed+report.Skipped, len(report.Results))
+	}
+
+	color.Green("\n✓ Batch completed successfully\n")
+	return nil
+}
@@ -11,11 +11,13 @@ import (
 )
 
 var (
-	clawbackNetwork   string
-	clawbackWallet    string
-	clawbackAmount    string
-	clawbackAlgorithm string
-	clawbackFee       string
+	clawbackNetwork     string
+	clawbackWallet      string
+	clawbackAmount      string
+	clawbackAlgorithm   string
+	clawbackFee         string
+	clawbackUnsignedOut string
+	clawbackMultisig    int
 )
 
 var clawbackCmd = &cobra.Command{
@@ -39,7 +41,9 @@ func init() {
 	clawbackCmd.Flags().StringVarP(&clawbackWallet, "wallet", "w", "", "Wallet seed or name (required)")
 	clawbackCmd.Flags().StringVar(&clawbackAmount, "amount", "", "Amount to claw back (e.g. '100/USD/rIssuer...' or drops)")
 	clawbackCmd.Flags().StringVar(&clawbackAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
-	clawbackCmd.Flags().StringVar(&clawbackFee, "fee", "1000000", "Transaction fee in drops")
+	clawbackCmd.Flags().StringVar(&clawbackFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	clawbackCmd.Flags().StringVar(&clawbackUnsignedOut, "unsigned-out", "", "Write the autofilled unsigned transaction to a file instead of submitting")
+	clawbackCmd.Flags().IntVar(&clawbackMultisig, "multisig", 0, "With --unsigned-out: prepare a multisig transaction for N signers")
 
 	clawbackCmd.MarkFlagRequired("wallet")
 	clawbackCmd.MarkFlagRequired("amount")
@@ -67,14 +71,26 @@ func runClawback(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Amount:   %s\n", clawbackAmount)
 	fmt.Printf("  Network:  %s\n", clawbackNetwork)
 
-	resolver, err := wallet.NewWalletResolver()
-	if err != nil {
-		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	if clawbackMultisig > 0 && clawbackUnsignedOut == "" {
+		return fmt.Errorf("--multisig requires --unsigned-out")
 	}
 
-	walletSeed, err := resolver.ResolveWallet(clawbackWallet)
-	if err != nil {
-		return fmt.Errorf("failed to resolve wallet: %w", err)
+	var walletSeed, account string
+	if clawbackUnsignedOut != "" {
+		account, err = resolveSigningAccount(clawbackWallet, clawbackAlgorithm)
+		if err != nil {
+			return err
+		}
+	} else {
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+		}
+
+		walletSeed, err = resolver.ResolveWallet(clawbackWallet)
+		if err != nil {
+			return fmt.Errorf("failed to resolve wallet: %w", err)
+		}
 	}
 
 	d, err := deployer.NewDeployer(false)
@@ -86,10 +102,13 @@ func runClawback(cmd *cobra.Command, args []string) error {
 	result, err := d.Clawback(ctx, deployer.ClawbackConfig{
 		ContractAccount: contractAccount,
 		Amount:          clawbackAmount,
-		NetworkURL:      networkCfg.URL,
+		Network:         networkCfg,
 		WalletSeed:      walletSeed,
 		Algorithm:       clawbackAlgorithm,
 		Fee:             clawbackFee,
+		Unsigned:        clawbackUnsignedOut != "",
+		Account:         account,
+		SignerCount:     clawbackMultisig,
 	})
 
 	if err != nil {
@@ -97,6 +116,10 @@ func runClawback(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if clawbackUnsignedOut != "" {
+		return writeUnsignedTx(clawbackUnsignedOut, clawbackNetwork, result.UnsignedTx)
+	}
+
 	color.Green("\n  Contract clawback successful!\n")
 	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
 	fmt.Printf("  Validated: %v\n", result.Validated)
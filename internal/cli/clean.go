@@ -17,7 +17,8 @@ var cleanCmd = &cobra.Command{
   - Version tracking file
 
 After cleaning, the next command that requires JS modules will
-automatically reinstall all dependencies fresh.
+automatically reinstall all dependencies fresh. Bundles imported with
+'bedrock modules import' are kept and used for the reinstall.
 
 Use this command if you:
   - Experience issues with JavaScript modules
@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+	"github.com/xrpl-commons/bedrock/pkg/builder"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/deployer"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
+)
+
+var (
+	codeNetwork   string
+	codeWallet    string
+	codeABI       string
+	codeAlgorithm string
+	codeFee       string
+	codeSkipBuild bool
+)
+
+var codeCmd = &cobra.Command{
+	Use:   "code",
+	Short: "Manage contract code (ContractSource) on a network",
+	Long: `Publish, list and inspect ContractSource entries.
+
+A ContractSource holds WASM code and its ABI once per network. Any number of
+contract instances can reference it by hash, which is much cheaper than
+uploading the same code again:
+
+  bedrock code publish --wallet alice
+  bedrock deploy --reuse-code <hash> --wallet bob
+  bedrock modify rContract... --hash <hash> --wallet alice`,
+}
+
+var codePublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Upload the contract code once so instances can reuse it",
+	Long: `Build the contract and upload its WASM and ABI as a ContractSource.
+
+Code can only reach the ledger through a ContractCreate, so publishing creates
+a template instance owned by the wallet. The ContractSource stays on ledger as
+long as at least one instance references it: keep the template instance to
+keep the code available. If the code is already published, nothing is
+submitted and the existing hash is reported.
+
+Examples:
+  bedrock code publish --wallet alice
+  bedrock code publish --wallet sXXX... --network local --skip-build`,
+	RunE: runCodePublish,
+}
+
+var codeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ContractSource entries on a network",
+	Long: `List every ContractSource in the validated ledger with its reference count.
+
+Examples:
+  bedrock code list
+  bedrock code list --network local`,
+	Args: cobra.NoArgs,
+	RunE: runCodeList,
+}
+
+var codeShowCmd = &cobra.Command{
+	Use:   "show <hash>",
+	Short: "Show a ContractSource and decode its functions",
+	Long: `Show a ContractSource by code hash (or ledger index) and decode its
+functions from the on-ledger ABI.
+
+Examples:
+  bedrock code show 3A7F...
+  bedrock code show 3A7F... --network local`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCodeShow,
+}
+
+func init() {
+	rootCmd.AddCommand(codeCmd)
+	codeCmd.AddCommand(codePublishCmd)
+	codeCmd.AddCommand(codeListCmd)
+	codeCmd.AddCommand(codeShowCmd)
+
+	codeCmd.PersistentFlags().StringVarP(&codeNetwork, "network", "n", "alphanet", "Network")
+
+	codePublishCmd.Flags().StringVarP(&codeWallet, "wallet", "w", "", "Wallet seed or name (required)")
+	codePublishCmd.Flags().StringVarP(&codeABI, "abi", "a", "abi.json", "Path to write the generated ABI")
+	codePublishCmd.Flags().StringVar(&codeAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
+	codePublishCmd.Flags().StringVar(&codeFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	codePublishCmd.Flags().BoolVar(&codeSkipBuild, "skip-build", false, "Use the existing WASM instead of rebuilding")
+
+	codePublishCmd.MarkFlagRequired("wallet")
+}
+
+// codeNetworkConfig resolves the network selected with --network
+func codeNetworkConfig() (*config.Config, config.NetworkConfig, error) {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return nil, config.NetworkConfig{}, fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	networkCfg, ok := cfg.Networks[codeNetwork]
+	if !ok {
+		if codeNetwork == "local" {
+			networkCfg = config.NetworkConfig{URL: "ws://localhost:6006", NetworkID: 63456}
+		} else {
+			return nil, config.NetworkConfig{}, fmt.Errorf("network '%s' not found in config", codeNetwork)
+		}
+	}
+
+	return cfg, networkCfg, nil
+}
+
+func runCodePublish(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, networkCfg, err := codeNetworkConfig()
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Publishing contract code\n")
+	fmt.Printf("  Network: %s\n", codeNetwork)
+
+	// Step 1: Build
+	wasmPath := filepath.Join(filepath.Dir(filepath.Dir(cfg.Build.Source)), "target", "wasm32-unknown-unknown", "release", cfg.Project.Name+".wasm")
+	if codeSkipBuild {
+		fmt.Println()
+		color.Yellow("⊙ Skipping build (--skip-build)\n")
+	} else {
+		fmt.Println()
+		color.Yellow("→ Building contract (release mode)...\n")
+
+		result, err := builder.New(".").Build(ctx, builder.BuildOptions{Release: true})
+		if err != nil {
+			color.Red("\n✗ Build failed: %v\n", err)
+			return err
+		}
+		wasmPath = result.WasmPath
+		color.Green("✓ Build completed\n")
+	}
+
+	wasmBytes, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return fmt.Errorf("failed to read WASM file: %w", err)
+	}
+	hash := sha512Half(wasmBytes)
+
+	fmt.Printf("  WASM: %s (%d bytes)\n", wasmPath, len(wasmBytes))
+	fmt.Printf("  Hash: %s\n", hash)
+
+	// Step 2: Skip the upload when the code is already on ledger
+	client := chain.NewNetworkClient(networkCfg)
+	existing, err := client.GetContractSource(ctx, hash)
+	if err == nil {
+		fmt.Println()
+		color.Green("✓ Code already published (%d references)\n", existing.ReferenceCount)
+		printCodeReuseTips(hash)
+		return nil
+	}
+	if !chain.IsEntryNotFound(err) {
+		color.Red("\n✗ Failed to check for published code: %v\n", err)
+		return err
+	}
+
+	// Step 3: Generate ABI
+	fmt.Println()
+	color.Yellow("→ Generating ABI...\n")
+
+	contractABI, err := abi.NewParser(filepath.Dir(cfg.Build.Source)).ParseContract(cfg.Project.Name)
+	if err != nil {
+		color.Red("\n✗ ABI generation failed: %v\n", err)
+		return err
+	}
+	if _, err := abi.NewGenerator(filepath.Dir(codeABI)).Generate(contractABI, filepath.Base(codeABI)); err != nil {
+		color.Red("\n✗ Failed to write ABI: %v\n", err)
+		return err
+	}
+	color.Green("✓ ABI generated\n")
+
+	// Step 4: Upload through a template instance
+	resolver, err := wallet.NewWalletResolver()
+	if err != nil {
+		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	}
+
+	walletSeed, err := resolver.ResolveWallet(codeWallet)
+	if err != nil {
+		return fmt.Errorf("failed to resolve wallet: %w", err)
+	}
+
+	d, err := deployer.NewDeployer(false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize deployer: %w", err)
+	}
+
+	fmt.Println()
+	color.Yellow("→ Uploading code...\n")
+
+	result, err := d.Deploy(ctx, deployer.DeploymentConfig{
+		WasmPath:   wasmPath,
+		ABIPath:    codeABI,
+		Network:    networkCfg,
+		WalletSeed: walletSeed,
+		Algorithm:  codeAlgorithm,
+		FaucetURL:  networkCfg.FaucetURL,
+		Fee:        codeFee,
+	})
+	if err != nil {
+		color.Red("\n✗ Publish failed: %v\n", err)
+		return err
+	}
+
+	if result.ContractHash != "" && !strings.EqualFold(result.ContractHash, hash) {
+		color.Yellow("  ⚠ Ledger reports code hash %s\n", result.ContractHash)
+		hash = result.ContractHash
+	}
+
+	fmt.Println()
+	color.Green("✓ Code published\n")
+	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
+	fmt.Printf("  Code Hash: %s\n", hash)
+	if result.ContractAccount != "" {
+		fmt.Printf("  Template Instance: %s\n", result.ContractAccount)
+	}
+
+	printCodeReuseTips(hash)
+	return nil
+}
+
+func printCodeReuseTips(hash string) {
+	fmt.Println()
+	color.Yellow("💡 Tips:\n")
+	color.Yellow("   • Deploy instances with: bedrock deploy --reuse-code %s --wallet <wallet>\n", hash)
+	color.Yellow("   • Point a contract at it with: bedrock modify <contract> --hash %s --wallet <wallet>\n", hash)
+}
+
+func runCodeList(cmd *cobra.Command, args []string) error {
+	_, networkCfg, err := codeNetworkConfig()
+	if err != nil {
+		return err
+	}
+
+	client := chain.NewNetworkClient(networkCfg)
+	sources, err := client.ListContractSources(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to list contract sources: %w", err)
+	}
+
+	color.Cyan("Contract code on %s\n", codeNetwork)
+	if len(sources) == 0 {
+		fmt.Println("  No ContractSource entries found")
+		return nil
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].ReferenceCount > sources[j].ReferenceCount
+	})
+
+	fmt.Printf("\n  %-64s  %6s  %10s  %9s\n", "HASH", "REFS", "SIZE", "FUNCTIONS")
+	for _, source := range sources {
+		fmt.Printf("  %-64s  %6d  %10d  %9d\n",
+			source.ContractHash, source.ReferenceCount, source.CodeSize(), countLedgerFunctions(source))
+	}
+	fmt.Printf("\n  %d code entries\n", len(sources))
+
+	return nil
+}
+
+func runCodeShow(cmd *cobra.Command, args []string) error {
+	_, networkCfg, err := codeNetworkConfig()
+	if err != nil {
+		return err
+	}
+
+	client := chain.NewNetworkClient(networkCfg)
+	source, err := client.GetContractSource(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Contract code\n")
+	fmt.Printf("  Hash:       %s\n", source.ContractHash)
+	fmt.Printf("  Index:      %s\n", source.Index)
+	fmt.Printf("  References: %d\n", source.ReferenceCount)
+	fmt.Printf("  Size:       %d bytes\n", source.CodeSize())
+
+	if len(source.Functions) == 0 {
+		fmt.Println("  Functions:  (no ABI on ledger)")
+		return nil
+	}
+
+	decoded, err := abi.FromLedgerFunctions("", source.Functions)
+	if err != nil {
+		color.Yellow("  Could not decode functions: %v\n", err)
+		return nil
+	}
+
+	fmt.Printf("\n  Functions (%d):\n", len(decoded.Functions))
+	for _, fn := range decoded.Functions {
+		fmt.Printf("    %s\n", fn.Signature())
+	}
+
+	return nil
+}
+
+// countLedgerFunctions returns how many functions a ContractSource declares
+func countLedgerFunctions(source chain.ContractSource) int {
+	if len(source.Functions) == 0 {
+		return 0
+	}
+	decoded, err := abi.FromLedgerFunctions("", source.Functions)
+	if err != nil {
+		return 0
+	}
+	return len(decoded.Functions)
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/codec"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
+var (
+	codecNetwork     string
+	codecDefinitions string
+	codecABI         string
+	codecOutput      string
+
+	codecDefinitionsNetwork string
+)
+
+var codecCmd = &cobra.Command{
+	Use:   "codec",
+	Short: "Encode and decode XRPL binary objects",
+	Long: `Convert transaction blobs and ledger entries between their binary (hex)
+and JSON forms, including smart contract fields such as ContractCode,
+Functions, Parameters and InstanceParameterValues.
+
+Field definitions come from the binary codec of the installed JavaScript
+modules and from .bedrock/definitions.json when present. Use --network to
+fetch the definitions from a node, or --definitions to load a file.`,
+}
+
+var codecDecodeCmd = &cobra.Command{
+	Use:   "decode <hex|file>",
+	Short: "Decode a transaction blob or ledger entry to JSON",
+	Long: `Decode a hex encoded transaction blob or ledger entry to JSON.
+
+With --abi, the parameters of a ContractCall are shown with the names and
+types of the matching ABI function.
+
+Examples:
+  bedrock codec decode 12005A2280000000...
+  bedrock codec decode tx.hex --abi abi.json
+  bedrock codec decode 1100632200000000... --network alphanet
+  bedrock codec decode tx.hex --json | jq .Parameters`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCodecDecode,
+}
+
+var codecEncodeCmd = &cobra.Command{
+	Use:   "encode <json|file>",
+	Short: "Encode JSON to a transaction blob or ledger entry",
+	Long: `Encode the JSON form of a transaction or ledger entry to hex.
+
+Contract parameter values use the {"type": ..., "value": ...} form that
+'bedrock codec decode' prints.
+
+Examples:
+  bedrock codec encode tx.json
+  bedrock codec encode '{"TransactionType":"ContractCall","Account":"r...", ...}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCodecEncode,
+}
+
+var codecDefinitionsCmd = &cobra.Command{
+	Use:   "definitions",
+	Short: "Save a network's binary codec definitions",
+	Long: `Fetch the binary codec definitions of a network with server_definitions
+and save them to .bedrock/definitions.json, where 'bedrock codec' picks
+them up automatically.
+
+Examples:
+  bedrock codec definitions --network alphanet
+  bedrock codec definitions --network local --output local-definitions.json`,
+	Args: cobra.NoArgs,
+	RunE: runCodecDefinitions,
+}
+
+func init() {
+	rootCmd.AddCommand(codecCmd)
+	codecCmd.AddCommand(codecDecodeCmd)
+	codecCmd.AddCommand(codecEncodeCmd)
+	codecCmd.AddCommand(codecDefinitionsCmd)
+
+	codecDecodeCmd.Flags().StringVarP(&codecABI, "abi", "a", "", "ABI file used to name ContractCall parameters")
+	for _, cmd := range []*cobra.Command{codecDecodeCmd, codecEncodeCmd} {
+		cmd.Flags().StringVarP(&codecNetwork, "network", "n", "", "Fetch definitions from this network")
+		cmd.Flags().StringVar(&codecDefinitions, "definitions", "", "Definitions file to load")
+	}
+	codecDefinitionsCmd.Flags().StringVarP(&codecDefinitionsNetwork, "network", "n", "alphanet", "Network to fetch definitions from")
+	codecDefinitionsCmd.Flags().StringVarP(&codecOutput, "output", "o", codec.DefinitionsFile, "File to save the definitions to")
+}
+
+func codecNetworkClient(name string) (*chain.Client, error) {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	networkCfg, ok := cfg.Networks[name]
+	if !ok {
+		if name == "local" {
+			networkCfg = config.NetworkConfig{
+				URL:       "ws://localhost:6006",
+				NetworkID: 63456,
+			}
+		} else {
+			return nil, fmt.Errorf("network '%s' not found in config", name)
+		}
+	}
+
+	return chain.NewNetworkClient(networkCfg), nil
+}
+
+// codecLoadDefinitions loads the definitions, applying --definitions and
+// --network on top of the defaults
+func codecLoadDefinitions(cmd *cobra.Command) (*codec.Definitions, error) {
+	defs, err := codec.LoadDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	if codecDefinitions != "" {
+		if err := defs.MergeFile(codecDefinitions); err != nil {
+			return nil, err
+		}
+	}
+
+	if codecNetwork != "" {
+		client, err := codecNetworkClient(codecNetwork)
+		if err != nil {
+			return nil, err
+		}
+		data, err := codec.FetchDefinitions(cmd.Context(), client)
+		if err != nil {
+			return nil, err
+		}
+		if err := defs.Merge(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return defs, nil
+}
+
+// codecInput returns an argument, or the contents of the file it names
+func codecInput(arg string) (string, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(arg)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", arg, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return strings.TrimSpace(arg), nil
+}
+
+func codecHint(err error) {
+	var unknown *codec.UnknownFieldError
+	if errors.As(err, &unknown) {
+		color.Yellow("💡 The definitions may be out of date; try --network <name>, or save them with 'bedrock codec definitions'\n")
+	}
+}
+
+func runCodecDecode(cmd *cobra.Command, args []string) error {
+	jsonOut, _ := cmd.Flags().GetBool("json")
+
+	input, err := codecInput(args[0])
+	if err != nil {
+		return err
+	}
+
+	var contractABI *abi.ABI
+	if codecABI != "" {
+		data, err := os.ReadFile(codecABI)
+		if err != nil {
+			return fmt.Errorf("failed to read ABI file: %w", err)
+		}
+		contractABI = &abi.ABI{}
+		if err := json.Unmarshal(data, contractABI); err != nil {
+			return fmt.Errorf("failed to parse ABI: %w", err)
+		}
+	}
+
+	defs, err := codecLoadDefinitions(cmd)
+	if err != nil {
+		return err
+	}
+
+	decoded, err := defs.Decode(input)
+	if err != nil {
+		color.Red("✗ Failed to decode: %v\n", err)
+		codecHint(err)
+		return err
+	}
+
+	pretty, _ := json.MarshalIndent(decoded, "", "  ")
+	fmt.Println(string(pretty))
+
+	if jsonOut || decoded["TransactionType"] != "ContractCall" {
+		return nil
+	}
+
+	call, err := codec.DescribeCall(decoded, contractABI)
+	if err != nil {
+		return err
+	}
+	printCodecCall(call, contractABI != nil)
+	return nil
+}
+
+func printCodecCall(call *codec.Call, withABI bool) {
+	fmt.Println()
+	color.Cyan("Contract call: %s\n", call.Function)
+	if call.Contract != "" {
+		fmt.Printf("  Contract: %s\n", call.Contract)
+	}
+	if withABI && !call.Known {
+		color.Yellow("  ⚠ Function %s is not in the ABI\n", call.Function)
+	}
+
+	for i, arg := range call.Args {
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		value := fmt.Sprint(arg.Value)
+		if arg.Text != "" {
+			value = fmt.Sprintf("%s (%q)", value, arg.Text)
+		}
+		fmt.Printf("  %s (%s): %s\n", name, arg.Type, value)
+		if arg.Expected != "" {
+			color.Yellow("    ⚠ ABI declares %s\n", arg.Expected)
+		}
+	}
+}
+
+func runCodecEncode(cmd *cobra.Command, args []string) error {
+	input, err := codecInput(args[0])
+	if err != nil {
+		return err
+	}
+
+	defs, err := codecLoadDefinitions(cmd)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := defs.EncodeJSON([]byte(input))
+	if err != nil {
+		color.Red("✗ Failed to encode: %v\n", err)
+		return err
+	}
+
+	fmt.Println(encoded)
+	return nil
+}
+
+func runCodecDefinitions(cmd *cobra.Command, args []string) error {
+	client, err := codecNetworkClient(codecDefinitionsNetwork)
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Fetching definitions from %s\n", codecDefinitionsNetwork)
+
+	data, err := codec.FetchDefinitions(cmd.Context(), client)
+	if err != nil {
+		color.Red("✗ %v\n", err)
+		return err
+	}
+
+	// Make sure the definitions can be used before saving them
+	if err := codec.DefaultDefinitions().Merge(data); err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(codecOutput); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(codecOutput, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write definitions: %w", err)
+	}
+
+	color.Green("✓ Definitions saved\n")
+	fmt.Printf("  File: %s\n", codecOutput)
+	return nil
+}
@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/builder"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/debugger"
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+var (
+	debugParams     string
+	debugParamsFile string
+	debugWasm       string
+	debugABI        string
+	debugCaller     string
+	debugState      string
+	debugGas        uint64
+	debugBreaks     []string
+	debugDAP        string
+	debugNoBuild    bool
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug <function>",
+	Short: "Debug a contract function at the source level",
+	Long: `Run a contract function in the local emulator under a source-level debugger.
+
+The contract is built in debug mode first (unless --wasm or --no-build is
+given) so the WASM carries DWARF debug info mapping it back to the Rust
+sources. The debugger stops at the function's first line; from there you can
+set breakpoints by file:line, step, inspect locals and linear memory, and see
+every host function call with its arguments.
+
+With --dap, Bedrock serves the Debug Adapter Protocol instead, for editors
+such as VS Code: pass a host:port to listen on, or "stdio". The launch
+request may set "function", "params", "caller", "gas" and "stopOnEntry".
+
+Examples:
+  bedrock debug increment
+  bedrock debug transfer --params '{"to":"rAlice...","amount":100}' --break src/lib.rs:42
+  bedrock debug --dap 127.0.0.1:4711`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if debugDAP != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runDebug,
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+
+	debugCmd.Flags().StringVarP(&debugParams, "params", "p", "", "Function parameters as JSON")
+	debugCmd.Flags().StringVarP(&debugParamsFile, "params-file", "f", "", "Function parameters from JSON file")
+	debugCmd.Flags().StringVar(&debugWasm, "wasm", "", "Contract WASM with debug info (skips the build)")
+	debugCmd.Flags().StringVarP(&debugABI, "abi", "a", "", "ABI file (defaults to the project ABI)")
+	debugCmd.Flags().StringVar(&debugCaller, "caller", "", "Account the call is made from")
+	debugCmd.Flags().StringVar(&debugState, "state", "", "State file to load and update")
+	debugCmd.Flags().Uint64VarP(&debugGas, "gas", "g", emulator.DefaultGasLimit, "Gas limit for the call")
+	debugCmd.Flags().StringArrayVarP(&debugBreaks, "break", "b", nil, "Breakpoint as file:line (repeatable)")
+	debugCmd.Flags().StringVar(&debugDAP, "dap", "", "Serve the Debug Adapter Protocol on host:port or stdio")
+	debugCmd.Flags().BoolVar(&debugNoBuild, "no-build", false, "Use the existing debug build")
+}
+
+func runDebug(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	// In stdio mode stdout carries the protocol
+	var log io.Writer = os.Stdout
+	if debugDAP == "stdio" {
+		log = os.Stderr
+		color.Output = os.Stderr
+	}
+
+	wasmPath, err := debugWasmFile(cmd, log)
+	if err != nil {
+		return err
+	}
+
+	contractABI, err := emulatorABI(cfg, debugABI)
+	if err != nil {
+		return err
+	}
+	params, err := emulatorParams(debugParams, debugParamsFile)
+	if err != nil {
+		return err
+	}
+
+	// Each run gets a fresh session over the saved state
+	newSession := func() (*debugger.Session, error) {
+		em, err := loadEmulator(wasmPath, contractABI, debugState)
+		if err != nil {
+			return nil, err
+		}
+		s, err := debugger.NewSession(em)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range debugBreaks {
+			file, line, err := parseBreakpoint(b)
+			if err != nil {
+				return nil, err
+			}
+			s.AddBreakpoint(file, line)
+		}
+		return s, nil
+	}
+
+	function := ""
+	if len(args) > 0 {
+		function = args[0]
+	}
+	opts := emulator.CallOptions{Caller: debugCaller, Params: params, GasLimit: debugGas}
+
+	if debugDAP != "" {
+		return serveDAP(newSession, debugger.LaunchConfig{
+			Function: function,
+			Params:   params,
+			Caller:   debugCaller,
+			Gas:      debugGas,
+		}, log)
+	}
+
+	s, err := newSession()
+	if err != nil {
+		return err
+	}
+	if s.Info == nil {
+		color.Yellow("⚠ %v\n\n", debugger.ErrNoDebugInfo)
+	}
+	for _, bp := range s.Breakpoints() {
+		if !bp.Verified {
+			color.Yellow("⚠ Breakpoint at %s:%d has no code; it will not be hit\n", bp.File, bp.Line)
+		}
+	}
+
+	result, err := debugger.NewTerminal(s, os.Stdin, os.Stdout).Run(function, opts)
+	if err != nil {
+		color.Red("✗ %v\n", err)
+		return err
+	}
+	fmt.Println()
+	if debugger.Terminated(result) {
+		color.Yellow("Call aborted; state unchanged\n")
+		return nil
+	}
+
+	statePath := ""
+	if debugState != "" && result.Success() {
+		if err := s.Emulator.State.Save(debugState); err != nil {
+			return err
+		}
+		statePath = debugState
+	}
+	printEmulatedResult(result, debugGas, statePath)
+	return nil
+}
+
+// debugWasmFile builds the contract in debug mode, or finds the given or
+// existing debug build
+func debugWasmFile(cmd *cobra.Command, log io.Writer) (string, error) {
+	if debugWasm != "" {
+		return debugWasm, nil
+	}
+
+	if !debugNoBuild {
+		fmt.Fprintln(log, color.CyanString("Building contract (debug)"))
+		b := builder.New(".")
+		// Cargo output goes to the log so it cannot corrupt a stdio session
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		result, err := b.Build(cmd.Context(), builder.BuildOptions{Release: false})
+		os.Stdout = stdout
+		if err != nil {
+			return "", fmt.Errorf("debug build failed: %w", err)
+		}
+		fmt.Fprintln(log)
+		return result.WasmPath, nil
+	}
+
+	matches, _ := filepath.Glob(filepath.Join("contract", "target", "wasm32-unknown-unknown", "debug", "*.wasm"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no debug build found (run 'bedrock build --release=false' or drop --no-build)")
+	}
+	return matches[0], nil
+}
+
+func parseBreakpoint(s string) (string, int, error) {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid breakpoint %q (expected file:line)", s)
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil || line <= 0 {
+		return "", 0, fmt.Errorf("invalid breakpoint %q (expected file:line)", s)
+	}
+	return s[:i], line, nil
+}
+
+// serveDAP serves editor sessions on stdio, or one connection at a time on
+// a TCP address
+func serveDAP(newSession func() (*debugger.Session, error), defaults debugger.LaunchConfig, log io.Writer) error {
+	serve := func(rw io.ReadWriter) error {
+		s, err := newSession()
+		if err != nil {
+			return err
+		}
+		server := &debugger.DAPServer{
+			Session:  s,
+			Defaults: defaults,
+			OnResult: func(result *emulator.Result) {
+				if debugState != "" && result.Success() {
+					if err := s.Emulator.State.Save(debugState); err != nil {
+						fmt.Fprintf(log, "✗ %v\n", err)
+					}
+				}
+			},
+		}
+		return server.Serve(rw)
+	}
+
+	if debugDAP == "stdio" {
+		return serve(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout})
+	}
+
+	ln, err := net.Listen("tcp", debugDAP)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", debugDAP, err)
+	}
+	defer ln.Close()
+
+	color.Cyan("DAP server listening on %s\n", ln.Addr())
+	color.Yellow("💡 Point your editor's debugServer setting at this port\n")
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(log, "  Client connected: %s\n", conn.RemoteAddr())
+		if err := serve(conn); err != nil {
+			color.Red("✗ Session failed: %v\n", err)
+		}
+		conn.Close()
+		fmt.Fprintf(log, "  Client disconnected\n")
+	}
+}
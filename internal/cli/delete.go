@@ -11,10 +11,12 @@ import (
 )
 
 var (
-	deleteNetwork   string
-	deleteWallet    string
-	deleteAlgorithm string
-	deleteFee       string
+	deleteNetwork     string
+	deleteWallet      string
+	deleteAlgorithm   string
+	deleteFee         string
+	deleteUnsignedOut string
+	deleteMultisig    int
 )
 
 var deleteCmd = &cobra.Command{
@@ -37,7 +39,9 @@ func init() {
 	deleteCmd.Flags().StringVarP(&deleteNetwork, "network", "n", "alphanet", "Network")
 	deleteCmd.Flags().StringVarP(&deleteWallet, "wallet", "w", "", "Wallet seed or name (required)")
 	deleteCmd.Flags().StringVar(&deleteAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
-	deleteCmd.Flags().StringVar(&deleteFee, "fee", "1000000", "Transaction fee in drops")
+	deleteCmd.Flags().StringVar(&deleteFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	deleteCmd.Flags().StringVar(&deleteUnsignedOut, "unsigned-out", "", "Write the autofilled unsigned transaction to a file instead of submitting")
+	deleteCmd.Flags().IntVar(&deleteMultisig, "multisig", 0, "With --unsigned-out: prepare a multisig transaction for N signers")
 
 	deleteCmd.MarkFlagRequired("wallet")
 }
@@ -63,14 +67,26 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Contract: %s\n", contractAccount)
 	fmt.Printf("  Network:  %s\n", deleteNetwork)
 
-	resolver, err := wallet.NewWalletResolver()
-	if err != nil {
-		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	if deleteMultisig > 0 && deleteUnsignedOut == "" {
+		return fmt.Errorf("--multisig requires --unsigned-out")
 	}
 
-	walletSeed, err := resolver.ResolveWallet(deleteWallet)
-	if err != nil {
-		return fmt.Errorf("failed to resolve wallet: %w", err)
+	var walletSeed, account string
+	if deleteUnsignedOut != "" {
+		account, err = resolveSigningAccount(deleteWallet, deleteAlgorithm)
+		if err != nil {
+			return err
+		}
+	} else {
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+		}
+
+		walletSeed, err = resolver.ResolveWallet(deleteWallet)
+		if err != nil {
+			return fmt.Errorf("failed to resolve wallet: %w", err)
+		}
 	}
 
 	d, err := deployer.NewDeployer(false)
@@ -81,10 +97,13 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 	result, err := d.Delete(ctx, deployer.DeleteConfig{
 		ContractAccount: contractAccount,
-		NetworkURL:      networkCfg.URL,
+		Network:         networkCfg,
 		WalletSeed:      walletSeed,
 		Algorithm:       deleteAlgorithm,
 		Fee:             deleteFee,
+		Unsigned:        deleteUnsignedOut != "",
+		Account:         account,
+		SignerCount:     deleteMultisig,
 	})
 
 	if err != nil {
@@ -92,6 +111,10 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if deleteUnsignedOut != "" {
+		return writeUnsignedTx(deleteUnsignedOut, deleteNetwork, result.UnsignedTx)
+	}
+
 	color.Green("\n✓ Contract deleted successfully!\n")
 	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
 	fmt.Printf("  Validated: %v\n", result.Validated)
@@ -30,6 +30,7 @@ var (
 	deployParams        string
 	deployOwner         string
 	deployFee           string
+	deployUnsignedOut   string
 )
 
 var deployCmd = &cobra.Command{
@@ -62,10 +63,14 @@ func init() {
 	deployCmd.Flags().StringVar(&deployReuseCode, "reuse-code", "", "Reference existing ContractSource by hash")
 	deployCmd.Flags().StringVar(&deployParams, "params", "", "Instance parameter values as JSON")
 	deployCmd.Flags().StringVar(&deployOwner, "owner", "", "Contract owner address (defaults to deployer)")
-	deployCmd.Flags().StringVar(&deployFee, "fee", "", "Transaction fee in drops")
+	deployCmd.Flags().StringVar(&deployFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	deployCmd.Flags().StringVar(&deployUnsignedOut, "unsigned-out", "", "Write the autofilled unsigned transaction to a file instead of submitting (requires --wallet)")
 }
 
-func runDeploy(cmd *cobra.Command, args []string) error {
+func runDeploy(cmd *cobra.Command, args []string) (err error) {
+	progress := newProgressOutput(cmd)
+	defer func() { progress.Close(err) }()
+
 	cfg, err := config.LoadFromWorkingDir()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
@@ -185,9 +190,21 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	// Determine faucet URL
 	faucetURL := networkCfg.FaucetURL
 
-	// Resolve wallet seed
-	var walletSeed string
-	if deployWallet != "" {
+	// Resolve wallet seed (or just the address when building an unsigned transaction)
+	var walletSeed, account string
+	if deployUnsignedOut != "" {
+		if deployWallet == "" {
+			return fmt.Errorf("--unsigned-out requires --wallet")
+		}
+
+		account, err = resolveSigningAccount(deployWallet, deployAlgorithm)
+		if err != nil {
+			color.Red("✗ %v\n", err)
+			return err
+		}
+
+		color.White("   Wallet: %s (unsigned)\n", account)
+	} else if deployWallet != "" {
 		resolver, err := wallet.NewWalletResolver()
 		if err != nil {
 			color.Red("✗ Failed to initialize wallet resolver: %v\n", err)
@@ -213,14 +230,14 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		color.Red("✗ Failed to initialize deployer: %v\n", err)
 		return err
 	}
+	d.OnProgress = progress.Event
 
 	// Deploy contract
 	ctx := cmd.Context()
 	result, err := d.Deploy(ctx, deployer.DeploymentConfig{
 		WasmPath:      wasmPath,
 		ABIPath:       abiPath,
-		NetworkURL:    networkCfg.URL,
-		NetworkID:     networkCfg.NetworkID,
+		Network:       networkCfg,
 		WalletSeed:    walletSeed,
 		Algorithm:     deployAlgorithm,
 		FaucetURL:     faucetURL,
@@ -232,12 +249,19 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		ReuseCode:     deployReuseCode,
 		Params:        deployParams,
 		Owner:         deployOwner,
+		Unsigned:      deployUnsignedOut != "",
+		Account:       account,
 	})
 
 	if err != nil {
 		color.Red("\n✗ Deployment failed: %v\n", err)
 		return err
 	}
+	progress.Result(result)
+
+	if deployUnsignedOut != "" {
+		return writeUnsignedTx(deployUnsignedOut, deployNetwork, result.UnsignedTx)
+	}
 
 	// Display results
 	fmt.Println()
@@ -16,6 +16,8 @@ var (
 	eventsFromLedger int64
 	eventsToLedger   int64
 	eventsLimit      int
+	eventsPageSize   int
+	eventsFollow     bool
 )
 
 var eventsCmd = &cobra.Command{
@@ -27,7 +29,8 @@ Examples:
   bedrock events rContract123...
   bedrock events rContract123... --type Transfer
   bedrock events rContract123... --from-ledger 1000 --to-ledger 2000
-  bedrock events rContract123... --network local`,
+  bedrock events rContract123... --network local
+  bedrock events rContract123... --follow`,
 	Args: cobra.ExactArgs(1),
 	RunE: runEvents,
 }
@@ -39,7 +42,9 @@ func init() {
 	eventsCmd.Flags().StringVar(&eventsType, "type", "", "Filter by event type")
 	eventsCmd.Flags().Int64Var(&eventsFromLedger, "from-ledger", 0, "Start ledger index")
 	eventsCmd.Flags().Int64Var(&eventsToLedger, "to-ledger", 0, "End ledger index")
-	eventsCmd.Flags().IntVar(&eventsLimit, "limit", 20, "Maximum number of events to return")
+	eventsCmd.Flags().IntVar(&eventsLimit, "limit", 20, "Maximum number of events to return (0 = all)")
+	eventsCmd.Flags().IntVar(&eventsPageSize, "page-size", chain.DefaultPageSize, "Events fetched per request")
+	eventsCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep streaming new events over WebSocket")
 }
 
 func runEvents(cmd *cobra.Command, args []string) error {
@@ -62,7 +67,7 @@ func runEvents(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := chain.NewClient(networkCfg.URL)
+	client := chain.NewNetworkClient(networkCfg)
 	ctx := cmd.Context()
 
 	color.Cyan("Contract Events\n")
@@ -72,36 +77,64 @@ func runEvents(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	history, err := client.GetEventHistory(ctx, contractAccount, chain.EventQueryOpts{
+	it := client.EventIterator(contractAccount, chain.EventQueryOpts{
 		EventType:  eventsType,
 		FromLedger: eventsFromLedger,
 		ToLedger:   eventsToLedger,
-		Limit:      eventsLimit,
-	})
-	if err != nil {
+	}, chain.PageOpts{PageSize: eventsPageSize, Limit: eventsLimit})
+
+	count := 0
+	for it.Next(ctx) {
+		count++
+		printEvent(count, it.Value())
+	}
+	if err := it.Err(); err != nil {
 		color.Red("Failed to query events: %v\n", err)
 		return err
 	}
 
-	if len(history.Events) == 0 {
+	if count == 0 {
 		fmt.Println("  No events found")
-		return nil
+	} else {
+		fmt.Printf("  Found %d events\n", count)
+		if it.Truncated() {
+			color.Yellow("  More events available (raise --limit, or --limit 0 for all)\n")
+		}
 	}
 
-	fmt.Printf("  Found %d events:\n\n", len(history.Events))
-	for i, event := range history.Events {
-		fmt.Printf("  [%d] Type: %s | Ledger: %d | Tx: %s\n",
-			i+1, event.Type, event.LedgerIndex, event.TxHash)
+	if !eventsFollow {
+		return nil
+	}
 
-		if event.Data != nil {
-			var data interface{}
-			if err := json.Unmarshal(event.Data, &data); err == nil {
-				pretty, _ := json.MarshalIndent(data, "      ", "  ")
-				fmt.Printf("      %s\n", string(pretty))
+	fmt.Println()
+	return followStream(ctx, networkCfg.URL, chain.Subscription{Accounts: []string{contractAccount}}, func(msg chain.StreamMessage) {
+		if msg.Type != "transaction" {
+			return
+		}
+		tx, err := msg.Transaction()
+		if err != nil || !tx.Validated {
+			return
+		}
+		for _, event := range chain.ContractEventsFromMeta(tx.Meta, tx.TxHash(), tx.LedgerIndex) {
+			if eventsType != "" && event.Type != eventsType {
+				continue
 			}
+			count++
+			printEvent(count, event)
 		}
-		fmt.Println()
-	}
+	})
+}
 
-	return nil
+func printEvent(n int, event chain.ContractEvent) {
+	fmt.Printf("  [%d] Type: %s | Ledger: %d | Tx: %s\n",
+		n, event.Type, event.LedgerIndex, event.TxHash)
+
+	if event.Data != nil {
+		var data interface{}
+		if err := json.Unmarshal(event.Data, &data); err == nil {
+			pretty, _ := json.MarshalIndent(data, "      ", "  ")
+			fmt.Printf("      %s\n", string(pretty))
+		}
+	}
+	fmt.Println()
 }
@@ -39,7 +39,10 @@ func init() {
 	faucetCmd.Flags().StringVar(&faucetAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm (secp256k1, ed25519)")
 }
 
-func runFaucet(cmd *cobra.Command, args []string) error {
+func runFaucet(cmd *cobra.Command, args []string) (err error) {
+	progress := newProgressOutput(cmd)
+	defer func() { progress.Close(err) }()
+
 	cfg, err := config.LoadFromWorkingDir()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
@@ -95,6 +98,7 @@ func runFaucet(cmd *cobra.Command, args []string) error {
 		color.Red("✗ Failed to initialize faucet: %v\n", err)
 		return err
 	}
+	f.OnProgress = progress.Event
 
 	// Request from faucet
 	ctx := cmd.Context()
@@ -103,7 +107,7 @@ func runFaucet(cmd *cobra.Command, args []string) error {
 		WalletSeed:    faucetWallet,
 		WalletAddress: faucetAddress,
 		Algorithm:     faucetAlgorithm,
-		NetworkURL:    networkCfg.URL,
+		Network:       networkCfg,
 		IsLocal:       isLocal,
 	})
 
@@ -111,6 +115,7 @@ func runFaucet(cmd *cobra.Command, args []string) error {
 		color.Red("✗ Faucet request failed: %v\n", err)
 		return err
 	}
+	progress.Result(result)
 
 	// Display results
 	fmt.Println()
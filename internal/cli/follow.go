@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fatih/color"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+)
+
+// followStream subscribes over WebSocket and passes each message to handle
+// until Ctrl+C. Dropped connections are re-established and re-subscribed.
+func followStream(ctx context.Context, url string, sub chain.Subscription, handle func(chain.StreamMessage)) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stream, err := chain.DialStreamWithOptions(ctx, url, chain.StreamOptions{
+		OnReconnect: func(attempt int, err error) {
+			if err != nil {
+				color.Yellow("  ⚠ Reconnect attempt %d failed: %v\n", attempt, err)
+				return
+			}
+			color.Green("  ✓ Reconnected\n")
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	if err := stream.Subscribe(ctx, sub); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	color.White("  Following (Ctrl+C to stop)...\n\n")
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr)
+			return nil
+		case msg, ok := <-stream.Messages():
+			if !ok {
+				return nil
+			}
+			handle(msg)
+		}
+	}
+}
@@ -3,9 +3,11 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
 	"github.com/xrpl-commons/bedrock/pkg/chain"
 	"github.com/xrpl-commons/bedrock/pkg/config"
 )
@@ -13,6 +15,8 @@ import (
 var (
 	infoNetwork string
 	infoUser    string
+	infoABI     string
+	infoRaw     bool
 )
 
 var infoCmd = &cobra.Command{
@@ -23,9 +27,14 @@ var infoCmd = &cobra.Command{
 Shows ABI, functions, owner, immutability flags, and state data.
 Use --user to show per-user contract state.
 
+State keys declared with /// @xrpl-state annotations are shown with their
+names and types. The ABI is read from --abi, or from the project's contract
+ABI when it exists.
+
 Examples:
   bedrock info rContract123...
   bedrock info rContract123... --user rUser456...
+  bedrock info rContract123... --abi abi.json --raw
   bedrock info rContract123... --network local`,
 	Args: cobra.ExactArgs(1),
 	RunE: runInfo,
@@ -36,6 +45,8 @@ func init() {
 
 	infoCmd.Flags().StringVarP(&infoNetwork, "network", "n", "alphanet", "Network to query")
 	infoCmd.Flags().StringVar(&infoUser, "user", "", "Show per-user state for this address")
+	infoCmd.Flags().StringVarP(&infoABI, "abi", "a", "", "ABI file with the contract's state schema")
+	infoCmd.Flags().BoolVar(&infoRaw, "raw", false, "Print state as raw JSON")
 }
 
 func runInfo(cmd *cobra.Command, args []string) error {
@@ -58,7 +69,12 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := chain.NewClient(networkCfg.URL)
+	schema, err := infoStateSchema(cfg)
+	if err != nil {
+		return err
+	}
+
+	client := chain.NewNetworkClient(networkCfg)
 	ctx := cmd.Context()
 
 	color.Cyan("Contract Info\n")
@@ -94,56 +110,99 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	if info.ContractData != nil {
 		fmt.Println()
 		color.Cyan("Global State:\n")
-		var stateParsed interface{}
-		if err := json.Unmarshal(info.ContractData, &stateParsed); err == nil {
-			pretty, _ := json.MarshalIndent(stateParsed, "  ", "  ")
-			fmt.Printf("  %s\n", string(pretty))
-		}
+		printContractState(info.ContractData, schema, false)
 	}
 
 	if infoUser != "" {
 		fmt.Println()
 		color.Cyan("User State (%s):\n", infoUser)
-		userData, err := client.GetContractData(ctx, contractAccount, infoUser)
-		if err != nil {
+		it := client.ContractDataIterator(contractAccount, infoUser, chain.PageOpts{})
+		count := 0
+		for it.Next(ctx) {
+			count++
+			printContractState(it.Value(), schema, true)
+		}
+		if err := it.Err(); err != nil {
 			color.Red("  Failed to get user state: %v\n", err)
-		} else if userData == nil {
+		} else if count == 0 {
 			fmt.Println("  No user state found")
-		} else {
-			var parsed interface{}
-			if err := json.Unmarshal(userData, &parsed); err == nil {
-				pretty, _ := json.MarshalIndent(parsed, "  ", "  ")
-				fmt.Printf("  %s\n", string(pretty))
-			}
 		}
 	}
 
 	return nil
 }
 
-func displayContractFlags(flags int64) {
-	const (
-		lsfImmutable     = 0x00000001
-		lsfCodeImmutable = 0x00000002
-		lsfABIImmutable  = 0x00000004
-		lsfUndeletable   = 0x00000008
-	)
+// infoStateSchema loads the state schema from --abi or the project's
+// contract ABI
+func infoStateSchema(cfg *config.Config) (*abi.StateSchema, error) {
+	path := infoABI
+	if path == "" {
+		path = "abi.json"
+		if main, ok := cfg.Contracts["main"]; ok && main.ABI != "" {
+			path = main.ABI
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ABI file: %w", err)
+	}
+	var abiData abi.ABI
+	if err := json.Unmarshal(data, &abiData); err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+	return abiData.State, nil
+}
+
+// printContractState prints a ContractData entry as named fields, or as
+// raw JSON with --raw
+func printContractState(data json.RawMessage, schema *abi.StateSchema, user bool) {
+	var entry map[string]interface{}
+	if infoRaw || json.Unmarshal(data, &entry) != nil {
+		var parsed interface{}
+		if err := json.Unmarshal(data, &parsed); err == nil {
+			pretty, _ := json.MarshalIndent(parsed, "  ", "  ")
+			fmt.Printf("  %s\n", string(pretty))
+		}
+		return
+	}
 
+	values := schema.Decode(entry, user)
+	if len(values) == 0 {
+		fmt.Println("  (empty)")
+		return
+	}
+	for _, v := range values {
+		if !v.Known() {
+			fmt.Printf("  %s: %s\n", v.Key, v.Value)
+			continue
+		}
+		fmt.Printf("  %s (%s): %s\n", v.Key, v.Type, v.Value)
+		if v.Err != nil {
+			color.Yellow("    ⚠ %v\n", v.Err)
+		}
+	}
+}
+
+func displayContractFlags(flags int64) {
 	if flags == 0 {
 		return
 	}
 
 	fmt.Print("           ")
-	if flags&lsfImmutable != 0 {
+	if flags&chain.LsfImmutable != 0 {
 		color.Yellow("Immutable ")
 	}
-	if flags&lsfCodeImmutable != 0 {
+	if flags&chain.LsfCodeImmutable != 0 {
 		color.Yellow("CodeImmutable ")
 	}
-	if flags&lsfABIImmutable != 0 {
+	if flags&chain.LsfABIImmutable != 0 {
 		color.Yellow("ABIImmutable ")
 	}
-	if flags&lsfUndeletable != 0 {
+	if flags&chain.LsfUndeletable != 0 {
 		color.Yellow("Undeletable ")
 	}
 	fmt.Println()
@@ -176,8 +176,9 @@ contract/target/
 # Wallets (keep private!)
 .wallets/
 
-# Bedrock internal
-.bedrock/
+# Bedrock internal (project modules are kept)
+.bedrock/*
+!.bedrock/modules/
 
 # OS files
 .DS_Store
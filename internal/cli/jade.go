@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -301,7 +302,7 @@ Examples:
 		}
 
 		network, _ := cmd.Flags().GetString("network")
-		networkURL, err := getNetworkConfig(network)
+		networkCfg, err := getNetworkConfig(network)
 		if err != nil {
 			return err
 		}
@@ -312,7 +313,7 @@ Examples:
 			return err
 		}
 
-		result, err := ops.GetBalance(networkURL, address)
+		result, err := ops.GetBalance(networkCfg.URL, address)
 		if err != nil {
 			return err
 		}
@@ -349,6 +350,31 @@ Examples:
 		walletFlag, _ := cmd.Flags().GetString("wallet")
 		algorithm, _ := cmd.Flags().GetString("algorithm")
 		network, _ := cmd.Flags().GetString("network")
+		unsignedOut, _ := cmd.Flags().GetString("unsigned-out")
+
+		if unsignedOut != "" {
+			account, err := resolveSigningAccount(walletFlag, algorithm)
+			if err != nil {
+				return err
+			}
+
+			networkCfg, err := getNetworkConfig(network)
+			if err != nil {
+				return err
+			}
+
+			ops, err := jade.NewOperations(false)
+			if err != nil {
+				return err
+			}
+
+			tx, err := ops.BuildPayment(networkCfg.URL, account, destination, amount)
+			if err != nil {
+				return err
+			}
+
+			return writeUnsignedTx(unsignedOut, network, tx)
+		}
 
 		// Resolve wallet using the standard WalletResolver
 		resolver, err := wallet.NewWalletResolver()
@@ -361,7 +387,7 @@ Examples:
 			return fmt.Errorf("failed to resolve wallet: %w", err)
 		}
 
-		networkURL, err := getNetworkConfig(network)
+		networkCfg, err := getNetworkConfig(network)
 		if err != nil {
 			return err
 		}
@@ -374,7 +400,7 @@ Examples:
 
 		fmt.Printf("Sending %s XRP to %s...\n", amount, destination)
 
-		result, err := ops.Send(networkURL, walletSeed, destination, amount, algorithm)
+		result, err := ops.Send(networkCfg, walletSeed, destination, amount, algorithm)
 		if err != nil {
 			return err
 		}
@@ -411,7 +437,7 @@ Examples:
 		}
 
 		network, _ := cmd.Flags().GetString("network")
-		networkURL, err := getNetworkConfig(network)
+		networkCfg, err := getNetworkConfig(network)
 		if err != nil {
 			return err
 		}
@@ -422,7 +448,7 @@ Examples:
 			return err
 		}
 
-		result, err := ops.GetTransaction(networkURL, hash)
+		result, err := ops.GetTransaction(networkCfg.URL, hash)
 		if err != nil {
 			return err
 		}
@@ -470,7 +496,7 @@ Examples:
 		}
 
 		network, _ := cmd.Flags().GetString("network")
-		networkURL, err := getNetworkConfig(network)
+		networkCfg, err := getNetworkConfig(network)
 		if err != nil {
 			return err
 		}
@@ -481,7 +507,7 @@ Examples:
 			return err
 		}
 
-		result, err := ops.GetAccountInfo(networkURL, address)
+		result, err := ops.GetAccountInfo(networkCfg.URL, address)
 		if err != nil {
 			return err
 		}
@@ -516,7 +542,7 @@ Examples:
   bedrock jade server --network local`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		network, _ := cmd.Flags().GetString("network")
-		networkURL, err := getNetworkConfig(network)
+		networkCfg, err := getNetworkConfig(network)
 		if err != nil {
 			return err
 		}
@@ -527,7 +553,7 @@ Examples:
 			return err
 		}
 
-		result, err := ops.GetServerInfo(networkURL)
+		result, err := ops.GetServerInfo(networkCfg.URL)
 		if err != nil {
 			return err
 		}
@@ -613,9 +639,9 @@ Examples:
 	},
 }
 
-// getNetworkConfig resolves the network name to a URL.
+// getNetworkConfig resolves a network name to its configuration.
 // It first checks bedrock.toml, then falls back to built-in defaults.
-func getNetworkConfig(network string) (string, error) {
+func getNetworkConfig(network string) (config.NetworkConfig, error) {
 	networks := map[string]string{
 		"local":    "ws://localhost:6006",
 		"alphanet": "wss://alphanet.nerdnest.xyz",
@@ -627,16 +653,18 @@ func getNetworkConfig(network string) (string, error) {
 	cfg, err := config.LoadFromWorkingDir()
 	if err == nil {
 		if netCfg, ok := cfg.Networks[network]; ok {
-			return netCfg.URL, nil
+			return netCfg, nil
 		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return config.NetworkConfig{}, err
 	}
 
 	// Fall back to built-in defaults
 	if url, ok := networks[network]; ok {
-		return url, nil
+		return config.NetworkConfig{URL: url}, nil
 	}
 
-	return "", fmt.Errorf("unknown network %q, available: local, alphanet, testnet, mainnet", network)
+	return config.NetworkConfig{}, fmt.Errorf("unknown network %q, available: local, alphanet, testnet, mainnet", network)
 }
 
 // validateXRPLAddress performs basic validation on an XRPL address.
@@ -694,6 +722,7 @@ func init() {
 	jadeSendCmd.Flags().StringP("wallet", "w", "", "Wallet name (required)")
 	jadeSendCmd.MarkFlagRequired("wallet")
 	jadeSendCmd.Flags().StringP("algorithm", "a", "secp256k1", "Cryptographic algorithm (secp256k1, ed25519)")
+	jadeSendCmd.Flags().String("unsigned-out", "", "Write the autofilled unsigned payment to a file instead of submitting")
 
 	// Add jade to root command
 	rootCmd.AddCommand(jadeCmd)
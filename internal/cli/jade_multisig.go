@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/jade"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
+)
+
+var jadeMultisigCmd = &cobra.Command{
+	Use:   "multisig",
+	Short: "Manage multisig signer lists",
+	Long: `Manage the signer list of a multisig account.
+
+Once a signer list is set, transactions for the account are prepared with
+--unsigned-out --multisig N, signed by each member with 'bedrock tx multisign',
+merged with 'bedrock tx combine' and submitted with 'bedrock tx submit'.`,
+}
+
+var jadeMultisigSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set the signer list of an account (SignerListSet)",
+	Long: `Submit a SignerListSet transaction that makes the wallet's account a
+multisig account.
+
+Each --signer takes the form <address>:<weight>. A multi-signature is valid
+once the sum of the weights of its signers reaches --quorum.
+Use --quorum 0 without signers to remove the signer list.
+
+Examples:
+  bedrock jade multisig setup --wallet treasury --quorum 2 \
+    --signer rAlice...:1 --signer rBob...:1 --signer rCarol...:1
+  bedrock jade multisig setup --wallet treasury --quorum 0`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		walletFlag, _ := cmd.Flags().GetString("wallet")
+		algorithm, _ := cmd.Flags().GetString("algorithm")
+		network, _ := cmd.Flags().GetString("network")
+		quorum, _ := cmd.Flags().GetUint32("quorum")
+		signerFlags, _ := cmd.Flags().GetStringArray("signer")
+
+		signers := make([]jade.SignerEntry, 0, len(signerFlags))
+		var totalWeight uint32
+		for _, s := range signerFlags {
+			entry, err := parseSignerEntry(s)
+			if err != nil {
+				return err
+			}
+			signers = append(signers, entry)
+			totalWeight += uint32(entry.Weight)
+		}
+
+		if quorum > 0 && len(signers) == 0 {
+			return fmt.Errorf("at least one --signer is required when --quorum is greater than 0")
+		}
+		if quorum == 0 && len(signers) > 0 {
+			return fmt.Errorf("--quorum must be greater than 0 when signers are given")
+		}
+		if totalWeight < quorum {
+			return fmt.Errorf("quorum %d is unreachable: signer weights only sum to %d", quorum, totalWeight)
+		}
+
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+		}
+
+		walletSeed, err := resolver.ResolveWallet(walletFlag)
+		if err != nil {
+			return fmt.Errorf("failed to resolve wallet: %w", err)
+		}
+
+		networkCfg, err := getNetworkConfig(network)
+		if err != nil {
+			return err
+		}
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		ops, err := jade.NewOperations(verbose)
+		if err != nil {
+			return err
+		}
+
+		if quorum == 0 {
+			fmt.Println("Removing signer list...")
+		} else {
+			fmt.Printf("Setting signer list (quorum %d, %d signers)...\n", quorum, len(signers))
+		}
+
+		result, err := ops.SetSignerList(networkCfg.URL, walletSeed, algorithm, quorum, signers)
+		if err != nil {
+			return err
+		}
+
+		if result.Result == "tesSUCCESS" {
+			fmt.Println("Signer list updated!")
+		} else {
+			fmt.Printf("Transaction result: %s\n", result.Result)
+		}
+		fmt.Printf("TX Hash:   %s\n", result.TxHash)
+		fmt.Printf("Account:   %s\n", result.Account)
+		fmt.Printf("Quorum:    %d\n", result.Quorum)
+		for _, s := range result.Signers {
+			fmt.Printf("Signer:    %s (weight %d)\n", s.Account, s.Weight)
+		}
+		fmt.Printf("Validated: %v\n", result.Validated)
+		return nil
+	},
+}
+
+// parseSignerEntry parses an <address>:<weight> signer flag
+func parseSignerEntry(s string) (jade.SignerEntry, error) {
+	address, weightStr, found := strings.Cut(s, ":")
+	if !found {
+		weightStr = "1"
+	}
+
+	if err := validateXRPLAddress(address); err != nil {
+		return jade.SignerEntry{}, fmt.Errorf("invalid signer: %w", err)
+	}
+
+	weight, err := strconv.ParseUint(weightStr, 10, 16)
+	if err != nil || weight == 0 {
+		return jade.SignerEntry{}, fmt.Errorf("invalid signer weight %q: must be between 1 and 65535", weightStr)
+	}
+
+	return jade.SignerEntry{Account: address, Weight: uint16(weight)}, nil
+}
+
+func init() {
+	jadeCmd.AddCommand(jadeMultisigCmd)
+	jadeMultisigCmd.AddCommand(jadeMultisigSetupCmd)
+
+	jadeMultisigSetupCmd.Flags().StringP("wallet", "w", "", "Wallet of the account to configure (required)")
+	jadeMultisigSetupCmd.MarkFlagRequired("wallet")
+	jadeMultisigSetupCmd.Flags().StringP("algorithm", "a", "secp256k1", "Cryptographic algorithm (secp256k1, ed25519)")
+	jadeMultisigSetupCmd.Flags().StringP("network", "n", "alphanet", "Network to use (local, alphanet, testnet, mainnet)")
+	jadeMultisigSetupCmd.Flags().Uint32("quorum", 0, "Minimum total signer weight for a valid multi-signature")
+	jadeMultisigSetupCmd.Flags().StringArray("signer", nil, "Signer as <address>:<weight> (repeatable)")
+	jadeMultisigSetupCmd.MarkFlagRequired("quorum")
+}
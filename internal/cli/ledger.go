@@ -10,7 +10,10 @@ import (
 	"github.com/xrpl-commons/bedrock/pkg/config"
 )
 
-var ledgerNetwork string
+var (
+	ledgerNetwork string
+	ledgerFollow  bool
+)
 
 var ledgerCmd = &cobra.Command{
 	Use:   "ledger [sequence]",
@@ -23,7 +26,8 @@ With a sequence number, shows that specific ledger.
 Examples:
   bedrock ledger
   bedrock ledger 12345
-  bedrock ledger --network local`,
+  bedrock ledger --network local
+  bedrock ledger --follow`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runLedger,
 }
@@ -33,6 +37,11 @@ var txCmd = &cobra.Command{
 	Short: "Look up a transaction",
 	Long: `Look up a transaction by its hash.
 
+Offline signing workflow:
+  bedrock call rContract... fn --wallet cold --unsigned-out tx.json
+  bedrock tx sign tx.json --wallet cold --out signed.json
+  bedrock tx submit signed.json
+
 Examples:
   bedrock tx ABC123...
   bedrock tx ABC123... --network local`,
@@ -47,6 +56,7 @@ func init() {
 	rootCmd.AddCommand(txCmd)
 
 	ledgerCmd.Flags().StringVarP(&ledgerNetwork, "network", "n", "alphanet", "Network to query")
+	ledgerCmd.Flags().BoolVarP(&ledgerFollow, "follow", "f", false, "Stream closed ledgers over WebSocket")
 	txCmd.Flags().StringVarP(&txNetwork, "network", "n", "alphanet", "Network to query")
 }
 
@@ -68,9 +78,24 @@ func runLedger(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := chain.NewClient(networkCfg.URL)
+	client := chain.NewNetworkClient(networkCfg)
 	ctx := cmd.Context()
 
+	if ledgerFollow {
+		color.Cyan("Ledger Stream\n")
+		fmt.Printf("  Network: %s\n", networkCfg.URL)
+		return followStream(ctx, networkCfg.URL, chain.Subscription{Streams: []string{chain.StreamLedger}}, func(msg chain.StreamMessage) {
+			if msg.Type != "ledgerClosed" {
+				return
+			}
+			l, err := msg.Ledger()
+			if err != nil {
+				return
+			}
+			fmt.Printf("  Ledger %d | %d txs | %s\n", l.LedgerIndex, l.TxnCount, l.LedgerHash)
+		})
+	}
+
 	ledgerIndex := "current"
 	if len(args) > 0 {
 		ledgerIndex = args[0]
@@ -115,7 +140,7 @@ func runTx(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := chain.NewClient(networkCfg.URL)
+	client := chain.NewNetworkClient(networkCfg)
 	ctx := cmd.Context()
 
 	color.Cyan("Transaction Details\n")
@@ -17,6 +17,8 @@ var (
 	modifyABI           string
 	modifyAlgorithm     string
 	modifyFee           string
+	modifyUnsignedOut   string
+	modifyMultisig      int
 	modifyOwner         string
 	modifyHash          string
 	modifyImmutable     bool
@@ -48,7 +50,9 @@ func init() {
 	modifyCmd.Flags().StringVar(&modifyWasm, "wasm", "", "New WASM file path")
 	modifyCmd.Flags().StringVar(&modifyABI, "abi", "", "New ABI file path")
 	modifyCmd.Flags().StringVar(&modifyAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
-	modifyCmd.Flags().StringVar(&modifyFee, "fee", "10000000", "Transaction fee in drops")
+	modifyCmd.Flags().StringVar(&modifyFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	modifyCmd.Flags().StringVar(&modifyUnsignedOut, "unsigned-out", "", "Write the autofilled unsigned transaction to a file instead of submitting")
+	modifyCmd.Flags().IntVar(&modifyMultisig, "multisig", 0, "With --unsigned-out: prepare a multisig transaction for N signers")
 	modifyCmd.Flags().StringVar(&modifyOwner, "owner", "", "New contract owner address")
 	modifyCmd.Flags().StringVar(&modifyHash, "hash", "", "Reference existing ContractSource by hash")
 	modifyCmd.Flags().BoolVar(&modifyImmutable, "immutable", false, "Set lsfImmutable flag")
@@ -59,7 +63,10 @@ func init() {
 	modifyCmd.MarkFlagRequired("wallet")
 }
 
-func runModify(cmd *cobra.Command, args []string) error {
+func runModify(cmd *cobra.Command, args []string) (err error) {
+	progress := newProgressOutput(cmd)
+	defer func() { progress.Close(err) }()
+
 	contractAccount := args[0]
 
 	cfg, err := config.LoadFromWorkingDir()
@@ -85,25 +92,38 @@ func runModify(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Contract: %s\n", contractAccount)
 	fmt.Printf("  Network:  %s\n", modifyNetwork)
 
-	resolver, err := wallet.NewWalletResolver()
-	if err != nil {
-		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	if modifyMultisig > 0 && modifyUnsignedOut == "" {
+		return fmt.Errorf("--multisig requires --unsigned-out")
 	}
 
-	walletSeed, err := resolver.ResolveWallet(modifyWallet)
-	if err != nil {
-		return fmt.Errorf("failed to resolve wallet: %w", err)
+	var walletSeed, account string
+	if modifyUnsignedOut != "" {
+		account, err = resolveSigningAccount(modifyWallet, modifyAlgorithm)
+		if err != nil {
+			return err
+		}
+	} else {
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+		}
+
+		walletSeed, err = resolver.ResolveWallet(modifyWallet)
+		if err != nil {
+			return fmt.Errorf("failed to resolve wallet: %w", err)
+		}
 	}
 
 	d, err := deployer.NewDeployer(false)
 	if err != nil {
 		return fmt.Errorf("failed to initialize deployer: %w", err)
 	}
+	d.OnProgress = progress.Event
 
 	ctx := cmd.Context()
 	result, err := d.Modify(ctx, deployer.ModifyConfig{
 		ContractAccount: contractAccount,
-		NetworkURL:      networkCfg.URL,
+		Network:         networkCfg,
 		WalletSeed:      walletSeed,
 		Algorithm:       modifyAlgorithm,
 		WasmPath:        modifyWasm,
@@ -115,12 +135,20 @@ func runModify(cmd *cobra.Command, args []string) error {
 		CodeImmutable:   modifyCodeImmutable,
 		ABIImmutable:    modifyABIImmutable,
 		Undeletable:     modifyUndeletable,
+		Unsigned:        modifyUnsignedOut != "",
+		Account:         account,
+		SignerCount:     modifyMultisig,
 	})
 
 	if err != nil {
 		color.Red("\n✗ Modification failed: %v\n", err)
 		return err
 	}
+	progress.Result(result)
+
+	if modifyUnsignedOut != "" {
+		return writeUnsignedTx(modifyUnsignedOut, modifyNetwork, result.UnsignedTx)
+	}
 
 	color.Green("\n✓ Contract modified successfully!\n")
 	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
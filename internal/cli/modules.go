@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/embedded"
+)
+
+var modulesImportSHA256 string
+
+var modulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "Manage the JavaScript module dependencies",
+	Long: `Export and import vendored node_modules bundles.
+
+Bedrock normally installs its JavaScript dependencies with npm on first use.
+A bundle exported on a machine with network access can be imported on CI
+runners or air-gapped machines so no network is needed.`,
+}
+
+var modulesExportCmd = &cobra.Command{
+	Use:   "export <tarball>",
+	Short: "Export the installed node_modules as a bundle",
+	Long: `Export the installed node_modules as a gzipped tarball.
+
+The bundle contains a manifest with the SHA-256 of every file, and its own
+checksum is written to <tarball>.sha256.
+
+Examples:
+  bedrock modules export bedrock-modules.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runModulesExport,
+}
+
+var modulesImportCmd = &cobra.Command{
+	Use:   "import <tarball>",
+	Short: "Import a node_modules bundle and install from it",
+	Long: `Verify a bundle created with 'bedrock modules export' and install the
+JavaScript dependencies from it.
+
+The tarball is checked against --sha256, or against <tarball>.sha256 when the
+flag is not set, and every file is checked against the bundle's manifest.
+The bundle is kept in the bedrock cache and used for later reinstalls.
+
+Examples:
+  bedrock modules import bedrock-modules.tar.gz
+  bedrock modules import bedrock-modules.tar.gz --sha256 3f2a...`,
+	Args: cobra.ExactArgs(1),
+	RunE: runModulesImport,
+}
+
+func init() {
+	rootCmd.AddCommand(modulesCmd)
+	modulesCmd.AddCommand(modulesExportCmd)
+	modulesCmd.AddCommand(modulesImportCmd)
+
+	modulesImportCmd.Flags().StringVar(&modulesImportSHA256, "sha256", "", "Expected SHA-256 of the tarball")
+}
+
+func runModulesExport(cmd *cobra.Command, args []string) error {
+	color.Cyan("Exporting modules bundle\n")
+	fmt.Println()
+
+	sum, err := embedded.ExportBundle(args[0])
+	if err != nil {
+		color.Red("✗ Failed to export bundle: %v\n", err)
+		return err
+	}
+
+	color.Green("✓ Bundle exported\n")
+	fmt.Printf("  Bundle: %s\n", args[0])
+	fmt.Printf("  SHA256: %s\n", sum)
+	fmt.Println()
+	color.Yellow("💡 Copy %s and %s.sha256 to the target machine and run 'bedrock modules import'\n", args[0], args[0])
+
+	return nil
+}
+
+func runModulesImport(cmd *cobra.Command, args []string) error {
+	color.Cyan("Importing modules bundle\n")
+	fmt.Printf("  Bundle: %s\n", args[0])
+	fmt.Println()
+
+	manifest, err := embedded.ImportBundle(args[0], modulesImportSHA256)
+	if err != nil {
+		color.Red("✗ Failed to import bundle: %v\n", err)
+		return err
+	}
+
+	color.Green("✓ Bundle verified and installed\n")
+	fmt.Printf("  Files:   %d\n", len(manifest.Files))
+	fmt.Printf("  Created: %s\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	return nil
+}
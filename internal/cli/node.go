@@ -23,25 +23,40 @@ const (
 	LedgerDaemonPIDFile = ".bedrock/ledger-daemon.pid"
 )
 
+var nodeValidators int
+
 var nodeCmd = &cobra.Command{
-	Use:   "node <start|stop|status|logs>",
+	Use:   "node <start|stop|status|restart|logs>",
 	Short: "Manage local XRPL node",
 	Long: `Manage a local XRPL test node for development.
 
 The node runs in a Docker container and provides a local network
 for testing your smart contracts before deploying to testnet/mainnet.
 
+By default a single node runs in standalone mode and a background daemon
+closes ledgers. With --validators N, start runs N validators on a private
+Docker network that close ledgers through real consensus; node n serves
+RPC on port 5004+n and WebSocket on port 6005+n.
+
 Commands:
-  start   - Start the local node
-  stop    - Stop the local node
-  status  - Check if node is running
-  logs    - View node logs`,
-	Args: cobra.ExactArgs(1),
+  start       - Start the local node
+  stop        - Stop the local node (or cluster)
+  status      - Check if node is running
+  restart <n> - Restart validator n of a cluster
+  logs        - View node logs
+
+Examples:
+  bedrock node start
+  bedrock node start --validators 3
+  bedrock node restart 2`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: runNode,
 }
 
 func init() {
 	rootCmd.AddCommand(nodeCmd)
+
+	nodeCmd.Flags().IntVar(&nodeValidators, "validators", 0, "Start a cluster of N validators instead of a standalone node")
 }
 
 func runNode(cmd *cobra.Command, args []string) error {
@@ -61,10 +76,12 @@ func runNode(cmd *cobra.Command, args []string) error {
 		return nodeStop(ctx, manager)
 	case "status":
 		return nodeStatus(ctx, manager)
+	case "restart":
+		return nodeRestart(ctx, manager, args[1:])
 	case "logs":
 		return nodeLogs(manager)
 	default:
-		return fmt.Errorf("unknown subcommand: %s (use: start, stop, status, logs)", subcommand)
+		return fmt.Errorf("unknown subcommand: %s (use: start, stop, status, restart, logs)", subcommand)
 	}
 }
 
@@ -81,6 +98,10 @@ func nodeStart(ctx context.Context, manager *network.Manager) error {
 	// Convert ledger interval from milliseconds to duration
 	ledgerInterval := time.Duration(cfg.LocalNode.LedgerInterval) * time.Millisecond
 
+	if nodeValidators > 0 {
+		return clusterStart(ctx, manager, cfg)
+	}
+
 	opts := network.StartOptions{
 		DockerImage:    cfg.LocalNode.DockerImage,
 		ConfigDir:      cfg.LocalNode.ConfigDir,
@@ -122,6 +143,48 @@ func nodeStart(ctx context.Context, manager *network.Manager) error {
 	return nil
 }
 
+// clusterStart starts a multi-validator cluster. Ledgers close through
+// consensus, so no ledger daemon is needed.
+func clusterStart(ctx context.Context, manager *network.Manager, cfg *config.Config) error {
+	if nodeValidators > network.MaxValidators {
+		err := fmt.Errorf("at most %d validators are supported", network.MaxValidators)
+		color.Red("✗ %v\n", err)
+		return err
+	}
+
+	opts := network.StartOptions{
+		DockerImage: cfg.LocalNode.DockerImage,
+		ConfigDir:   cfg.LocalNode.ConfigDir,
+		Validators:  nodeValidators,
+	}
+
+	fmt.Printf("  Validators: %d (quorum %d)\n", nodeValidators, network.ClusterQuorum(nodeValidators))
+	fmt.Println()
+
+	if err := manager.Start(ctx, opts); err != nil {
+		color.Red("✗ Failed to start cluster: %v\n", err)
+		return err
+	}
+
+	color.Green("✓ Local cluster started successfully!\n")
+	fmt.Println()
+
+	color.Cyan("Nodes:\n")
+	for n := 1; n <= nodeValidators; n++ {
+		fmt.Printf("  %s  RPC http://localhost:%d  WS ws://localhost:%d\n",
+			network.ClusterNodeName(n), network.ClusterRPCPort+n-1, network.ClusterWSPort+n-1)
+	}
+	fmt.Printf("  Config: %s\n", filepath.Join(cfg.LocalNode.ConfigDir, "cluster"))
+	fmt.Println()
+	color.Yellow("Tips:\n")
+	color.Yellow("   Ledgers close once the validators reach consensus (allow ~30s)\n")
+	color.Yellow("   Node 1 serves the default local URLs: bedrock deploy --network local\n")
+	color.Yellow("   Check consensus with: bedrock node status\n")
+	color.Yellow("   Restart a validator with: bedrock node restart 2\n")
+
+	return nil
+}
+
 // startLedgerDaemon spawns the ledger daemon as a background process
 func startLedgerDaemon(intervalMs int) error {
 	// Get the path to the current executable
@@ -249,6 +312,11 @@ func nodeStatus(ctx context.Context, manager *network.Manager) error {
 		return err
 	}
 
+	if len(status.Nodes) > 0 {
+		printClusterStatus(status)
+		return nil
+	}
+
 	if status.Running {
 		color.Green("✓ Local node is running\n")
 		fmt.Println()
@@ -275,6 +343,61 @@ func nodeStatus(ctx context.Context, manager *network.Manager) error {
 	return nil
 }
 
+func printClusterStatus(status *network.NodeStatus) {
+	running := 0
+	for _, node := range status.Nodes {
+		if node.Running {
+			running++
+		}
+	}
+	if running == len(status.Nodes) {
+		color.Green("✓ Local cluster is running (%d validators)\n", len(status.Nodes))
+	} else {
+		color.Yellow("⚠ Local cluster: %d of %d validators running\n", running, len(status.Nodes))
+	}
+	fmt.Println()
+
+	for _, node := range status.Nodes {
+		color.Cyan("Node %d (%s):\n", node.Index, node.Name)
+		if !node.Running {
+			color.Yellow("  Status:    Stopped\n")
+		} else if node.Error != "" {
+			color.Yellow("  Status:    Running (%s)\n", node.Error)
+		} else {
+			fmt.Printf("  Status:    %s\n", node.ServerState)
+			fmt.Printf("  Peers:     %d\n", node.Peers)
+			fmt.Printf("  Validated: %d\n", node.ValidatedLedger)
+		}
+		fmt.Printf("  Validator: %s\n", node.Validator)
+		fmt.Printf("  RPC URL:   %s\n", node.RPCURL)
+		fmt.Printf("  WS URL:    %s\n", node.WSURL)
+		fmt.Println()
+	}
+}
+
+func nodeRestart(ctx context.Context, manager *network.Manager, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: bedrock node restart <n>")
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid node number: %s", args[0])
+	}
+
+	color.Cyan("Restarting validator %d\n", n)
+	fmt.Println()
+
+	if err := manager.RestartNode(ctx, n); err != nil {
+		color.Red("✗ Failed to restart node: %v\n", err)
+		return err
+	}
+
+	color.Green("✓ %s restarted\n", network.ClusterNodeName(n))
+	color.Yellow("💡 It rejoins consensus once it has synced from its peers\n")
+
+	return nil
+}
+
 // isLedgerDaemonRunning checks if the ledger daemon process is running
 func isLedgerDaemonRunning() bool {
 	pidFile := filepath.Join(".bedrock", "ledger-daemon.pid")
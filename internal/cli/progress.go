@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/adapter"
+)
+
+// progressOutput renders module progress for a command. By default each
+// event is printed as it arrives; with --json, stdout carries only NDJSON:
+// the progress events followed by a "result" or "error" event, while the
+// command's usual output moves to stderr.
+type progressOutput struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	restore func()
+}
+
+// streamEvent is the final event of a JSON stream
+type streamEvent struct {
+	Type  string      `json:"type"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// newProgressOutput sets up progress output for cmd. Close must be called
+// when the command finishes.
+func newProgressOutput(cmd *cobra.Command) *progressOutput {
+	p := &progressOutput{}
+
+	if jsonOut, _ := cmd.Flags().GetBool("json"); !jsonOut {
+		return p
+	}
+
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout = os.Stderr
+	color.Output = color.Error
+
+	p.enc = json.NewEncoder(stdout)
+	p.restore = func() {
+		os.Stdout = stdout
+		color.Output = colorOutput
+	}
+	return p
+}
+
+// Event shows a progress event; it can be used as an adapter.ProgressFunc
+func (p *progressOutput) Event(event adapter.ProgressEvent) {
+	if p.enc != nil {
+		p.write(event)
+		return
+	}
+
+	switch event.Event {
+	case adapter.EventPhase:
+		color.White("   → %s\n", event.Message)
+	case adapter.EventSubmitted:
+		if event.EngineResult != "" {
+			color.White("   → Submitted %s (%s)\n", event.TxHash, event.EngineResult)
+		} else {
+			color.White("   → Submitted %s\n", event.TxHash)
+		}
+	case adapter.EventWaiting:
+		color.White("   → Waiting for validation...\n")
+	case adapter.EventValidated:
+		if event.Result == "tesSUCCESS" {
+			color.Green("   ✓ Validated in ledger %d\n", event.LedgerIndex)
+		} else {
+			color.Red("   ✗ Validated in ledger %d with %s\n", event.LedgerIndex, event.Result)
+		}
+	}
+}
+
+// Result emits the command's result event in JSON mode
+func (p *progressOutput) Result(data interface{}) {
+	if p.enc == nil {
+		return
+	}
+	p.write(streamEvent{Type: "result", Data: data})
+}
+
+// Close emits an error event if err is set and restores stdout
+func (p *progressOutput) Close(err error) {
+	if p.enc == nil {
+		return
+	}
+	if err != nil {
+		p.write(streamEvent{Type: "error", Error: err.Error()})
+	}
+	p.restore()
+}
+
+func (p *progressOutput) write(v interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enc.Encode(v)
+}
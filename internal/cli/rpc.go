@@ -56,7 +56,7 @@ func runRPC(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	client := chain.NewClient(networkCfg.URL)
+	client := chain.NewNetworkClient(networkCfg)
 	ctx := cmd.Context()
 
 	var params []interface{}
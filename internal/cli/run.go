@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+	"github.com/xrpl-commons/bedrock/pkg/modules"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
+)
+
+var (
+	runConfig    string
+	runNetwork   string
+	runWallet    string
+	runAlgorithm string
+	runList      bool
+
+	// Emulator flags
+	runEmulate    bool
+	runParams     string
+	runParamsFile string
+	runWasm       string
+	runABIFile    string
+	runCaller     string
+	runState      string
+	runGas        uint64
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <module | function>",
+	Short: "Run a project module, or a contract function in the local emulator",
+	Long: `Run a JavaScript module from the project's .bedrock/modules directory.
+
+Project modules follow the same contract as Bedrock's embedded modules: they
+are started as "node <module> <config.json>" and print a single JSON result,
+{"success": true, "data": {...}} or {"success": false, "error": "..."}. They
+can require @transia/xrpl without installing it, and can print
+{"type":"progress",...} lines to report progress.
+
+The config passed to the module is the --config file merged over values
+injected from bedrock.toml: network, network_url, network_id, faucet_url,
+wallet_seed (from --wallet or the default wallet), abi_path and algorithm.
+
+With --emulate, --params or --params-file, run a function of the built
+contract instead, in process and without a node. The WASM is executed by
+Bedrock's interpreter against an in-memory ledger with the contract host
+functions; gas counts one unit per instruction and a flat cost per host
+call, so it approximates but does not match xrpld. Use --state to keep
+contract storage between runs.
+
+Examples:
+  bedrock run mint-batch --config mint.json
+  bedrock run airdrop --network alphanet --wallet alice
+  bedrock run --list
+  bedrock run increment --emulate --state state.json
+  bedrock run transfer --params '{"to":"rAlice...","amount":100}' --caller rBob...`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if runList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runRun,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	runCmd.Flags().StringVarP(&runConfig, "config", "c", "", "JSON config file passed to the module")
+	runCmd.Flags().StringVarP(&runNetwork, "network", "n", "local", "Network to inject")
+	runCmd.Flags().StringVarP(&runWallet, "wallet", "w", "", "Wallet name or seed to inject (defaults to the configured default wallet)")
+	runCmd.Flags().StringVar(&runAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm (secp256k1 or ed25519)")
+	runCmd.Flags().BoolVar(&runList, "list", false, "List the project's modules")
+
+	runCmd.Flags().BoolVar(&runEmulate, "emulate", false, "Run a contract function in the local emulator")
+	runCmd.Flags().StringVarP(&runParams, "params", "p", "", "Function parameters as JSON (implies --emulate)")
+	runCmd.Flags().StringVarP(&runParamsFile, "params-file", "f", "", "Function parameters from JSON file (implies --emulate)")
+	runCmd.Flags().StringVar(&runWasm, "wasm", "", "Contract WASM (defaults to the build output)")
+	runCmd.Flags().StringVarP(&runABIFile, "abi", "a", "", "ABI file (defaults to the project ABI)")
+	runCmd.Flags().StringVar(&runCaller, "caller", "", "Account the call is made from")
+	runCmd.Flags().StringVar(&runState, "state", "", "State file to load and update")
+	runCmd.Flags().Uint64VarP(&runGas, "gas", "g", emulator.DefaultGasLimit, "Gas limit for the call")
+}
+
+func runRun(cmd *cobra.Command, args []string) (err error) {
+	if runList {
+		return listModules()
+	}
+	if runEmulate || runParams != "" || runParamsFile != "" {
+		return runEmulated(cmd, args[0])
+	}
+
+	progress := newProgressOutput(cmd)
+	defer func() { progress.Close(err) }()
+
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	module, err := modules.Find(".", args[0])
+	if err != nil {
+		color.Yellow("💡 To run a contract function in the emulator, add --emulate\n")
+		return err
+	}
+
+	networkCfg, ok := cfg.Networks[runNetwork]
+	if !ok {
+		if runNetwork == "local" {
+			networkCfg = config.NetworkConfig{URL: "ws://localhost:6006", NetworkID: 63456}
+		} else {
+			return fmt.Errorf("network '%s' not found in config", runNetwork)
+		}
+	}
+
+	userConfig := make(map[string]interface{})
+	if runConfig != "" {
+		data, err := os.ReadFile(runConfig)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := json.Unmarshal(data, &userConfig); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	walletInput := runWallet
+	if walletInput == "" {
+		walletInput = cfg.Wallets.Default
+	}
+	var walletSeed string
+	if walletInput != "" {
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+		}
+		walletSeed, err = resolver.ResolveWallet(walletInput)
+		if err != nil {
+			return fmt.Errorf("failed to resolve wallet: %w", err)
+		}
+	}
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	moduleConfig := modules.BuildConfig(userConfig, modules.Context{
+		Network:    runNetwork,
+		NetworkCfg: networkCfg,
+		WalletSeed: walletSeed,
+		ABIPath:    modules.ProjectABI(cfg),
+		Algorithm:  runAlgorithm,
+		Verbose:    verbose,
+	})
+
+	color.Cyan("Running module: %s\n", module.Name)
+	fmt.Printf("  Network: %s\n", runNetwork)
+	if runConfig != "" {
+		fmt.Printf("  Config:  %s\n", runConfig)
+	}
+	fmt.Println()
+
+	runner, err := modules.NewRunner(verbose)
+	if err != nil {
+		return err
+	}
+
+	result, err := runner.Run(cmd.Context(), module, moduleConfig, progress.Event)
+	if err != nil {
+		color.Red("\n✗ Module failed: %v\n", err)
+		return err
+	}
+	progress.Result(result.Data)
+
+	color.Green("\n✓ Module finished\n")
+	if len(result.Data) > 0 && string(result.Data) != "null" {
+		var pretty interface{}
+		if err := json.Unmarshal(result.Data, &pretty); err == nil {
+			out, _ := json.MarshalIndent(pretty, "", "  ")
+			fmt.Println(string(out))
+		}
+	}
+
+	return nil
+}
+
+func listModules() error {
+	list, err := modules.List(".")
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		color.Yellow("No modules found in %s\n", modules.Dir)
+		return nil
+	}
+
+	color.Cyan("Project modules (%s):\n", modules.Dir)
+	for _, m := range list {
+		fmt.Printf("  %s\n", m.Name)
+	}
+	return nil
+}
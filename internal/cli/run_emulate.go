@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+	"github.com/xrpl-commons/bedrock/pkg/modules"
+)
+
+// emulatedRun is the --json output of an emulated call
+type emulatedRun struct {
+	Function   string                 `json:"function"`
+	Success    bool                   `json:"success"`
+	Code       int64                  `json:"code"`
+	Error      string                 `json:"error,omitempty"`
+	GasUsed    uint64                 `json:"gas_used"`
+	GasLimit   uint64                 `json:"gas_limit"`
+	Traces     []string               `json:"traces"`
+	Events     []emulator.Event       `json:"events"`
+	Changes    []emulator.StateChange `json:"changes"`
+	Unresolved []string               `json:"unresolved_imports,omitempty"`
+}
+
+// runEmulated runs a contract function in the in-process emulator
+func runEmulated(cmd *cobra.Command, function string) error {
+	jsonOut, _ := cmd.Flags().GetBool("json")
+
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	wasmPath := runWasm
+	if wasmPath == "" {
+		wasmPath = findWasmFile(cfg)
+		if wasmPath == "" {
+			return fmt.Errorf("no WASM file found (run 'bedrock build' first or pass --wasm)")
+		}
+	}
+
+	contractABI, err := emulatorABI(cfg, runABIFile)
+	if err != nil {
+		return err
+	}
+
+	params, err := emulatorParams(runParams, runParamsFile)
+	if err != nil {
+		return err
+	}
+
+	em, err := loadEmulator(wasmPath, contractABI, runState)
+	if err != nil {
+		return err
+	}
+
+	caller := runCaller
+	if caller == "" {
+		caller = emulator.DefaultCaller
+	}
+
+	if !jsonOut {
+		color.Cyan("Running %s in the emulator\n", function)
+		fmt.Printf("  WASM:     %s\n", wasmPath)
+		fmt.Printf("  Contract: %s\n", em.State.Contract)
+		fmt.Printf("  Caller:   %s\n", caller)
+		if runParams != "" {
+			fmt.Printf("  Params:   %s\n", runParams)
+		} else if runParamsFile != "" {
+			fmt.Printf("  Params:   (from %s)\n", runParamsFile)
+		}
+		fmt.Println()
+	}
+
+	result, err := em.Call(function, emulator.CallOptions{
+		Caller:   caller,
+		Params:   params,
+		GasLimit: runGas,
+	})
+	if err != nil {
+		if !jsonOut {
+			color.Red("✗ %v\n", err)
+		}
+		return err
+	}
+
+	if runState != "" && result.Success() {
+		if err := em.State.Save(runState); err != nil {
+			return err
+		}
+	}
+
+	if jsonOut {
+		return printEmulatedRun(result)
+	}
+	printEmulatedResult(result, runGas, runState)
+
+	if !result.Success() {
+		return fmt.Errorf("%s failed", function)
+	}
+	return nil
+}
+
+// emulatorABI reads an ABI file, defaulting to the project ABI when there
+// is one
+func emulatorABI(cfg *config.Config, path string) (*abi.ABI, error) {
+	if path == "" {
+		path = modules.ProjectABI(cfg)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ABI file: %w", err)
+	}
+	contractABI := &abi.ABI{}
+	if err := json.Unmarshal(data, contractABI); err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+	return contractABI, nil
+}
+
+// loadEmulator loads a contract with the state file, when it exists
+func loadEmulator(wasmPath string, contractABI *abi.ABI, statePath string) (*emulator.Emulator, error) {
+	var state *emulator.State
+	if statePath != "" {
+		if _, err := os.Stat(statePath); err == nil {
+			state, err = emulator.LoadState(statePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	em, err := emulator.Load(wasmPath, contractABI, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract: %w", err)
+	}
+	return em, nil
+}
+
+// emulatorParams reads inline or file parameters, keeping numbers exact
+func emulatorParams(inline, file string) (map[string]interface{}, error) {
+	var data []byte
+	switch {
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read params file: %w", err)
+		}
+		data = b
+	case inline != "":
+		data = []byte(inline)
+	default:
+		return nil, nil
+	}
+
+	var params map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&params); err != nil {
+		return nil, fmt.Errorf("invalid parameters JSON: %w", err)
+	}
+	return params, nil
+}
+
+func printEmulatedResult(result *emulator.Result, gasLimit uint64, statePath string) {
+	if len(result.Unresolved) > 0 {
+		color.Yellow("⚠ Host functions not available in the emulator (calling them traps):\n")
+		for _, imp := range result.Unresolved {
+			fmt.Printf("  %s\n", imp)
+		}
+		fmt.Println()
+	}
+
+	if len(result.Traces) > 0 {
+		color.Cyan("Traces:\n")
+		for _, t := range result.Traces {
+			fmt.Printf("  %s\n", t)
+		}
+		fmt.Println()
+	}
+
+	switch {
+	case result.OutOfGas():
+		color.Red("✗ Out of gas (limit %d)\n", gasLimit)
+	case result.Err != nil:
+		color.Red("✗ %v\n", result.Err)
+	case !result.Success():
+		color.Red("✗ %s returned %d\n", result.Function, result.Code)
+	default:
+		color.Green("✓ %s returned %d\n", result.Function, result.Code)
+	}
+	fmt.Printf("  Gas used: %d\n", result.GasUsed)
+
+	if !result.Success() {
+		fmt.Printf("  State unchanged\n")
+		return
+	}
+
+	if len(result.Changes) > 0 {
+		fmt.Println()
+		color.Cyan("State changes:\n")
+		for _, c := range result.Changes {
+			old := c.Old
+			if old == "" {
+				old = "(new)"
+			}
+			fmt.Printf("  %s %s: %s → %s\n", c.Owner, emulator.KeyText(c.Key), old, c.New)
+		}
+	}
+
+	if len(result.Events) > 0 {
+		fmt.Println()
+		color.Cyan("Events:\n")
+		for _, e := range result.Events {
+			fmt.Printf("  %s %s\n", e.Name, e.Data)
+		}
+	}
+
+	if statePath != "" {
+		fmt.Printf("\n  State saved to %s\n", statePath)
+	}
+}
+
+func printEmulatedRun(result *emulator.Result) error {
+	out := emulatedRun{
+		Function: result.Function,
+		Success:  result.Success(),
+		Code:     result.Code,
+		GasUsed:  result.GasUsed,
+		GasLimit: runGas,
+		Traces:   result.Traces,
+		Events:   result.Events,
+		Changes:  result.Changes,
+	}
+	if result.Err != nil {
+		out.Error = result.Err.Error()
+	}
+	out.Unresolved = result.Unresolved
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+
+	if !out.Success {
+		return errors.New(result.Function + " failed")
+	}
+	return nil
+}
@@ -20,7 +20,8 @@ var scriptCmd = &cobra.Command{
 	Long: `Execute a multi-step deployment and interaction script.
 
 Scripts are TOML or JSON files defining a sequence of operations:
-deploy, call, fund, wait, and assert.
+deploy, call, fund, wait, assert, and module (run a project module from
+.bedrock/modules).
 
 Variables from previous steps can be referenced in subsequent steps
 using ${step_name.field} syntax.
@@ -20,6 +20,7 @@ var (
 	testFuzz        bool
 	testFuzzRuns    int
 	testFuzzSeed    int64
+	testConcurrency int
 )
 
 var testCmd = &cobra.Command{
@@ -37,7 +38,13 @@ Examples:
   bedrock test --integration
   bedrock test --gas-report
   bedrock test --watch
-  bedrock test --fuzz --fuzz-runs 100`,
+  bedrock test --fuzz --fuzz-runs 100
+  bedrock test --integration --concurrency 8
+
+With --concurrency N, integration tests and fuzz runs submit up to N calls
+at once from the test wallet. Tickets are created with TicketCreate so the
+calls do not collide on the account Sequence; unused Tickets are removed
+when the run ends.`,
 	RunE: runTest,
 }
 
@@ -52,6 +59,7 @@ func init() {
 	testCmd.Flags().BoolVar(&testFuzz, "fuzz", false, "Enable fuzz testing")
 	testCmd.Flags().IntVar(&testFuzzRuns, "fuzz-runs", 256, "Number of fuzz iterations")
 	testCmd.Flags().Int64Var(&testFuzzSeed, "fuzz-seed", 0, "Seed for reproducible fuzzing")
+	testCmd.Flags().IntVar(&testConcurrency, "concurrency", 1, "Number of contract calls to run in parallel (integration and fuzz)")
 }
 
 func runTest(cmd *cobra.Command, args []string) error {
@@ -70,6 +78,7 @@ func runTest(cmd *cobra.Command, args []string) error {
 		Fuzz:        testFuzz,
 		FuzzRuns:    testFuzzRuns,
 		FuzzSeed:    testFuzzSeed,
+		Concurrency: testConcurrency,
 	}
 
 	if testWatch {
@@ -204,6 +213,10 @@ func runIntegrationTests(cmd *cobra.Command, opts tester.TestOptions) error {
 			}
 		}
 
+		if suite.TicketCleanupError != "" {
+			color.Yellow("  ⚠ Ticket cleanup: %s\n", suite.TicketCleanupError)
+		}
+
 		totalPassed += suite.Passed
 		totalFailed += suite.Failed
 	}
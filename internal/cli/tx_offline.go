@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/signer"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
+)
+
+var (
+	txSignWallet    string
+	txSignAlgorithm string
+	txSignOut       string
+	txSubmitNetwork string
+	txSubmitTimeout time.Duration
+
+	txMultisignWallet    string
+	txMultisignAlgorithm string
+	txMultisignOut       string
+	txCombineOut         string
+)
+
+var txSignCmd = &cobra.Command{
+	Use:   "sign <tx.json>",
+	Short: "Sign an unsigned transaction offline",
+	Long: `Sign a transaction file produced by --unsigned-out.
+
+No network connection is made, so this can run on an air-gapped host
+with the keystore available.
+
+Examples:
+  bedrock tx sign tx.json --wallet cold
+  bedrock tx sign tx.json --wallet cold --out signed.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTxSign,
+}
+
+var txSubmitCmd = &cobra.Command{
+	Use:   "submit <signed.json>",
+	Short: "Submit a signed transaction and wait for validation",
+	Long: `Submit a transaction file produced by 'bedrock tx sign' and wait
+until it is included in a validated ledger.
+
+Examples:
+  bedrock tx submit signed.json
+  bedrock tx submit signed.json --network local`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTxSubmit,
+}
+
+var txMultisignCmd = &cobra.Command{
+	Use:   "multisign <tx.json>",
+	Short: "Add a multisig signature to an unsigned transaction",
+	Long: `Sign a transaction file as one member of the account's signer list.
+
+Each signer produces their own partially signed file; merge them with
+'bedrock tx combine' once the quorum is reached. Like 'tx sign', no
+network connection is made.
+
+Examples:
+  bedrock tx multisign tx.json --wallet signer1 --out sig1.json
+  bedrock tx multisign tx.json --wallet signer2 --out sig2.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTxMultisign,
+}
+
+var txCombineCmd = &cobra.Command{
+	Use:   "combine <signed.json>...",
+	Short: "Merge multisig signatures into one submittable transaction",
+	Long: `Combine partially signed files produced by 'bedrock tx multisign'
+into a single multi-signed transaction ready for 'bedrock tx submit'.
+
+Examples:
+  bedrock tx combine sig1.json sig2.json --out signed.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTxCombine,
+}
+
+func init() {
+	txCmd.AddCommand(txSignCmd)
+	txCmd.AddCommand(txSubmitCmd)
+	txCmd.AddCommand(txMultisignCmd)
+	txCmd.AddCommand(txCombineCmd)
+
+	txSignCmd.Flags().StringVarP(&txSignWallet, "wallet", "w", "", "Wallet seed or name (required)")
+	txSignCmd.Flags().StringVar(&txSignAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
+	txSignCmd.Flags().StringVarP(&txSignOut, "out", "o", "signed.json", "Output file for the signed transaction")
+	txSignCmd.MarkFlagRequired("wallet")
+
+	txSubmitCmd.Flags().StringVarP(&txSubmitNetwork, "network", "n", "", "Network (defaults to the network recorded in the file)")
+	txSubmitCmd.Flags().DurationVar(&txSubmitTimeout, "timeout", 60*time.Second, "How long to wait for validation")
+
+	txMultisignCmd.Flags().StringVarP(&txMultisignWallet, "wallet", "w", "", "Signer wallet seed or name (required)")
+	txMultisignCmd.Flags().StringVar(&txMultisignAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
+	txMultisignCmd.Flags().StringVarP(&txMultisignOut, "out", "o", "", "Output file (defaults to <input>.<signer>.json)")
+	txMultisignCmd.MarkFlagRequired("wallet")
+
+	txCombineCmd.Flags().StringVarP(&txCombineOut, "out", "o", "signed.json", "Output file for the combined transaction")
+}
+
+func runTxSign(cmd *cobra.Command, args []string) error {
+	file, err := signer.ReadTxFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if file.TxJSON == nil {
+		return fmt.Errorf("transaction file %s has no tx_json to sign", args[0])
+	}
+
+	color.Cyan("Signing transaction\n")
+	fmt.Printf("  Type:    %v\n", file.TxJSON["TransactionType"])
+	fmt.Printf("  Account: %v\n", file.TxJSON["Account"])
+	fmt.Printf("  Fee:     %v drops\n", file.TxJSON["Fee"])
+
+	resolver, err := wallet.NewWalletResolver()
+	if err != nil {
+		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	}
+
+	walletSeed, err := resolver.ResolveWallet(txSignWallet)
+	if err != nil {
+		return fmt.Errorf("failed to resolve wallet: %w", err)
+	}
+
+	s, err := signer.NewSigner(false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize signer: %w", err)
+	}
+
+	result, err := s.Sign(cmd.Context(), signer.SignConfig{
+		TxJSON:     file.TxJSON,
+		WalletSeed: walletSeed,
+		Algorithm:  txSignAlgorithm,
+	})
+	if err != nil {
+		color.Red("\n✗ Signing failed: %v\n", err)
+		return err
+	}
+
+	signed := &signer.TxFile{
+		Network: file.Network,
+		TxJSON:  result.TxJSON,
+		TxBlob:  result.TxBlob,
+		Hash:    result.Hash,
+	}
+	if err := signer.WriteTxFile(txSignOut, signed); err != nil {
+		return err
+	}
+
+	color.Green("\n✓ Transaction signed\n")
+	fmt.Printf("  Hash:   %s\n", result.Hash)
+	fmt.Printf("  Output: %s\n", txSignOut)
+
+	return nil
+}
+
+func runTxSubmit(cmd *cobra.Command, args []string) error {
+	file, err := signer.ReadTxFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if !file.IsSigned() {
+		return fmt.Errorf("transaction file %s is not signed (run 'bedrock tx sign' first)", args[0])
+	}
+
+	hash := file.Hash
+	if hash == "" {
+		return fmt.Errorf("transaction file %s has no hash; cannot wait for validation", args[0])
+	}
+
+	network := txSubmitNetwork
+	if network == "" {
+		network = file.Network
+	}
+	if network == "" {
+		network = "alphanet"
+	}
+
+	// Prefer the project's network config, falling back to the built-in
+	// networks so submission also works outside a project directory
+	networkCfg, err := getNetworkConfig(network)
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Submitting transaction\n")
+	fmt.Printf("  Hash:    %s\n", hash)
+	fmt.Printf("  Network: %s\n", network)
+
+	ctx := cmd.Context()
+	client := chain.NewNetworkClient(networkCfg)
+
+	signed := chain.SignedTx{TxBlob: file.TxBlob, Hash: hash}
+	if lls, ok := file.TxJSON["LastLedgerSequence"].(float64); ok {
+		signed.LastLedgerSequence = uint32(lls)
+	}
+
+	color.Yellow("\n→ Submitting and waiting for validation...\n")
+
+	// Offline-signed transactions usually carry no LastLedgerSequence,
+	// so tracking is bounded by --timeout instead
+	result, err := client.SubmitAndTrack(ctx, signed, chain.SubmitOptions{Timeout: txSubmitTimeout})
+	if result != nil && result.EngineResult != "" {
+		fmt.Printf("  Engine result: %s (%d attempt(s))\n", result.EngineResult, result.Attempts)
+	}
+	if err != nil {
+		color.Red("\n✗ %v\n", err)
+		return err
+	}
+	if err := result.Err(); err != nil {
+		color.Red("\n✗ Transaction failed: %s\n", result.TransactionResult)
+		return err
+	}
+
+	color.Green("\n✓ Transaction validated\n")
+	fmt.Printf("  Result: %s\n", result.TransactionResult)
+	fmt.Printf("  Ledger: %d\n", result.LedgerIndex)
+
+	return nil
+}
+
+func runTxMultisign(cmd *cobra.Command, args []string) error {
+	file, err := signer.ReadTxFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if file.TxJSON == nil {
+		return fmt.Errorf("transaction file %s has no tx_json to sign", args[0])
+	}
+
+	color.Cyan("Multi-signing transaction\n")
+	fmt.Printf("  Type:    %v\n", file.TxJSON["TransactionType"])
+	fmt.Printf("  Account: %v\n", file.TxJSON["Account"])
+	fmt.Printf("  Fee:     %v drops\n", file.TxJSON["Fee"])
+
+	resolver, err := wallet.NewWalletResolver()
+	if err != nil {
+		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	}
+
+	walletSeed, err := resolver.ResolveWallet(txMultisignWallet)
+	if err != nil {
+		return fmt.Errorf("failed to resolve wallet: %w", err)
+	}
+
+	s, err := signer.NewSigner(false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize signer: %w", err)
+	}
+
+	result, err := s.Sign(cmd.Context(), signer.SignConfig{
+		TxJSON:     file.TxJSON,
+		WalletSeed: walletSeed,
+		Algorithm:  txMultisignAlgorithm,
+		Multisign:  true,
+	})
+	if err != nil {
+		color.Red("\n✗ Signing failed: %v\n", err)
+		return err
+	}
+
+	signerAccount := multisignSignerAccount(result.TxJSON)
+
+	out := txMultisignOut
+	if out == "" {
+		out = fmt.Sprintf("%s.%s.json", strings.TrimSuffix(args[0], filepath.Ext(args[0])), signerAccount)
+	}
+
+	partial := &signer.TxFile{
+		Network: file.Network,
+		TxJSON:  result.TxJSON,
+		TxBlob:  result.TxBlob,
+		Hash:    result.Hash,
+	}
+	if err := signer.WriteTxFile(out, partial); err != nil {
+		return err
+	}
+
+	color.Green("\n✓ Signature added\n")
+	fmt.Printf("  Signer: %s\n", signerAccount)
+	fmt.Printf("  Output: %s\n", out)
+	fmt.Println()
+	color.Yellow("Next: bedrock tx combine %s <other signatures...>\n", out)
+
+	return nil
+}
+
+func runTxCombine(cmd *cobra.Command, args []string) error {
+	var network string
+	blobs := make([]string, 0, len(args))
+
+	for _, path := range args {
+		file, err := signer.ReadTxFile(path)
+		if err != nil {
+			return err
+		}
+		if !file.IsSigned() {
+			return fmt.Errorf("transaction file %s is not signed (run 'bedrock tx multisign' first)", path)
+		}
+		if network == "" {
+			network = file.Network
+		}
+		blobs = append(blobs, file.TxBlob)
+	}
+
+	color.Cyan("Combining %d signature(s)\n", len(blobs))
+
+	s, err := signer.NewSigner(false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize signer: %w", err)
+	}
+
+	result, err := s.Combine(cmd.Context(), blobs)
+	if err != nil {
+		color.Red("\n✗ Combining failed: %v\n", err)
+		return err
+	}
+
+	combined := &signer.TxFile{
+		Network: network,
+		TxJSON:  result.TxJSON,
+		TxBlob:  result.TxBlob,
+		Hash:    result.Hash,
+	}
+	if err := signer.WriteTxFile(txCombineOut, combined); err != nil {
+		return err
+	}
+
+	color.Green("\n✓ Signatures combined\n")
+	fmt.Printf("  Hash:   %s\n", result.Hash)
+	fmt.Printf("  Output: %s\n", txCombineOut)
+	fmt.Println()
+	color.Yellow("Next: bedrock tx submit %s\n", txCombineOut)
+
+	return nil
+}
+
+// multisignSignerAccount returns the account of the first Signer entry
+func multisignSignerAccount(tx map[string]interface{}) string {
+	signers, _ := tx["Signers"].([]interface{})
+	if len(signers) == 0 {
+		return "signer"
+	}
+	entry, _ := signers[0].(map[string]interface{})
+	inner, _ := entry["Signer"].(map[string]interface{})
+	account, _ := inner["Account"].(string)
+	if account == "" {
+		return "signer"
+	}
+	return account
+}
+
+// resolveSigningAccount resolves --wallet to an address for --unsigned-out,
+// without prompting for the keystore password
+func resolveSigningAccount(walletInput, algorithm string) (string, error) {
+	resolver, err := wallet.NewWalletResolver()
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	}
+
+	address, err := resolver.ResolveAddress(walletInput, algorithm)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve wallet: %w", err)
+	}
+
+	return address, nil
+}
+
+// writeUnsignedTx saves an unsigned transaction for 'bedrock tx sign'
+// (or 'bedrock tx multisign' when the transaction has an empty SigningPubKey)
+func writeUnsignedTx(path, network string, tx map[string]interface{}) error {
+	if tx == nil {
+		return fmt.Errorf("no unsigned transaction was returned")
+	}
+
+	if err := signer.WriteTxFile(path, &signer.TxFile{Network: network, TxJSON: tx}); err != nil {
+		return err
+	}
+
+	color.Green("\n✓ Unsigned transaction written to %s\n", path)
+	fmt.Printf("  Type:     %v\n", tx["TransactionType"])
+	fmt.Printf("  Account:  %v\n", tx["Account"])
+	fmt.Printf("  Sequence: %v\n", tx["Sequence"])
+	fmt.Printf("  Fee:      %v drops\n", tx["Fee"])
+	fmt.Println()
+	if pubKey, ok := tx["SigningPubKey"].(string); ok && pubKey == "" {
+		color.Yellow("Next: bedrock tx multisign %s --wallet <signer> (once per signer)\n", path)
+	} else {
+		color.Yellow("Next: bedrock tx sign %s --wallet <signer>\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+	"github.com/xrpl-commons/bedrock/pkg/builder"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/deployer"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
+)
+
+var (
+	upgradeNetwork   string
+	upgradeWallet    string
+	upgradeABI       string
+	upgradeAlgorithm string
+	upgradeFee       string
+	upgradeSkipBuild bool
+	upgradeYes       bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade <contract-account>",
+	Short: "Rebuild and upgrade a deployed contract",
+	Long: `Guided upgrade of a deployed contract.
+
+This command:
+1. Rebuilds the contract (release mode)
+2. Regenerates the ABI
+3. Fetches the on-chain contract and checks its flags allow the change
+4. Shows the ABI diff and the upgrade plan, and asks for confirmation
+5. Submits a ContractModify transaction and verifies the new WASM hash
+
+Examples:
+  bedrock upgrade rContract123... --wallet alice
+  bedrock upgrade rContract123... --wallet sXXX... --network local --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUpgrade,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	upgradeCmd.Flags().StringVarP(&upgradeNetwork, "network", "n", "alphanet", "Network")
+	upgradeCmd.Flags().StringVarP(&upgradeWallet, "wallet", "w", "", "Wallet seed or name (required)")
+	upgradeCmd.Flags().StringVarP(&upgradeABI, "abi", "a", "abi.json", "Path to write the regenerated ABI")
+	upgradeCmd.Flags().StringVar(&upgradeAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
+	upgradeCmd.Flags().StringVar(&upgradeFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	upgradeCmd.Flags().BoolVar(&upgradeSkipBuild, "skip-build", false, "Use the existing WASM instead of rebuilding")
+	upgradeCmd.Flags().BoolVarP(&upgradeYes, "yes", "y", false, "Skip the confirmation prompt")
+
+	upgradeCmd.MarkFlagRequired("wallet")
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	contractAccount := args[0]
+	ctx := cmd.Context()
+
+	cfg, err := config.LoadFromWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w (run 'bedrock init' first)", err)
+	}
+
+	networkCfg, ok := cfg.Networks[upgradeNetwork]
+	if !ok {
+		if upgradeNetwork == "local" {
+			networkCfg = config.NetworkConfig{URL: "ws://localhost:6006", NetworkID: 63456}
+		} else {
+			return fmt.Errorf("network '%s' not found in config", upgradeNetwork)
+		}
+	}
+
+	color.Cyan("Upgrading contract\n")
+	fmt.Printf("  Contract: %s\n", contractAccount)
+	fmt.Printf("  Network:  %s\n", upgradeNetwork)
+
+	// Step 1: Build
+	wasmPath := filepath.Join(filepath.Dir(filepath.Dir(cfg.Build.Source)), "target", "wasm32-unknown-unknown", "release", cfg.Project.Name+".wasm")
+	if upgradeSkipBuild {
+		fmt.Println()
+		color.Yellow("⊙ Skipping build (--skip-build)\n")
+	} else {
+		fmt.Println()
+		color.Yellow("→ Building contract (release mode)...\n")
+
+		result, err := builder.New(".").Build(ctx, builder.BuildOptions{Release: true})
+		if err != nil {
+			color.Red("\n✗ Build failed: %v\n", err)
+			return err
+		}
+		wasmPath = result.WasmPath
+		color.Green("✓ Build completed\n")
+	}
+
+	wasmBytes, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return fmt.Errorf("failed to read WASM file: %w", err)
+	}
+	localHash := sha512Half(wasmBytes)
+
+	// Step 2: Generate ABI; it is written once the upgrade is confirmed
+	fmt.Println()
+	color.Yellow("→ Generating ABI...\n")
+
+	localABI, err := abi.NewParser(filepath.Dir(cfg.Build.Source)).ParseContract(cfg.Project.Name)
+	if err != nil {
+		color.Red("\n✗ ABI generation failed: %v\n", err)
+		return err
+	}
+	color.Green("✓ ABI generated\n")
+
+	// Step 3: Fetch on-chain state
+	fmt.Println()
+	color.Yellow("→ Fetching on-chain contract...\n")
+
+	client := chain.NewNetworkClient(networkCfg)
+	info, err := client.GetContractInfo(ctx, contractAccount)
+	if err != nil {
+		return fmt.Errorf("failed to fetch contract: %w", err)
+	}
+
+	onChainABI, err := onChainContractABI(info, cfg.Project.Name)
+	if err != nil {
+		color.Yellow("  Could not decode on-chain ABI: %v\n", err)
+	}
+
+	codeChanged := !strings.EqualFold(info.WasmHash, localHash)
+	var abiDiff *abi.Diff
+	if onChainABI != nil {
+		abiDiff = abi.Compare(onChainABI, localABI)
+	}
+	// An unknown on-chain ABI is replaced when the contract allows it, and
+	// left alone when it is immutable
+	abiUnknown := abiDiff == nil
+	abiChanged := abiDiff != nil && abiDiff.HasChanges()
+	replaceABI := abiChanged || (abiUnknown && info.CanModifyABI())
+
+	// Step 4: Plan
+	fmt.Println()
+	color.Cyan("Upgrade plan:\n")
+	fmt.Printf("  Owner:       %s\n", info.Owner)
+	fmt.Printf("  Flags:       0x%08X\n", info.Flags)
+	displayContractFlags(info.Flags)
+	fmt.Printf("  WASM hash:   %s\n", info.WasmHash)
+	fmt.Printf("  New hash:    %s (%d bytes)\n", localHash, len(wasmBytes))
+
+	if codeChanged {
+		fmt.Println("  Code:        will be replaced")
+	} else {
+		fmt.Println("  Code:        unchanged")
+	}
+
+	switch {
+	case abiUnknown && replaceABI:
+		fmt.Println("  ABI:         will be replaced (on-chain ABI unknown)")
+	case abiUnknown:
+		fmt.Println("  ABI:         kept (on-chain ABI unknown and immutable)")
+	case abiChanged:
+		fmt.Println("  ABI:         will be replaced")
+		displayABIDiff(abiDiff)
+	default:
+		fmt.Println("  ABI:         unchanged")
+	}
+
+	if abiUnknown && !replaceABI {
+		color.Yellow("\n⚠ Could not compare ABIs; the on-chain ABI is immutable and is left as is\n")
+	}
+
+	if !codeChanged && !replaceABI {
+		fmt.Println()
+		color.Green("✓ Contract is already up to date\n")
+		return nil
+	}
+
+	if codeChanged && !info.CanModifyCode() {
+		color.Red("\n✗ Contract code is immutable and cannot be upgraded\n")
+		return fmt.Errorf("contract %s does not allow code changes", contractAccount)
+	}
+	if abiChanged && !info.CanModifyABI() {
+		color.Red("\n✗ Contract ABI is immutable and cannot be changed\n")
+		return fmt.Errorf("contract %s does not allow ABI changes", contractAccount)
+	}
+
+	if !upgradeYes {
+		fmt.Printf("\nProceed with upgrade? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+
+		if response != "y" && response != "Y" {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	if _, err := abi.NewGenerator(filepath.Dir(upgradeABI)).Generate(localABI, filepath.Base(upgradeABI)); err != nil {
+		color.Red("\n✗ Failed to write ABI: %v\n", err)
+		return err
+	}
+
+	// Step 5: Submit
+	resolver, err := wallet.NewWalletResolver()
+	if err != nil {
+		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	}
+
+	walletSeed, err := resolver.ResolveWallet(upgradeWallet)
+	if err != nil {
+		return fmt.Errorf("failed to resolve wallet: %w", err)
+	}
+
+	d, err := deployer.NewDeployer(false)
+	if err != nil {
+		return fmt.Errorf("failed to initialize deployer: %w", err)
+	}
+
+	modifyCfg := deployer.ModifyConfig{
+		ContractAccount: contractAccount,
+		Network:         networkCfg,
+		WalletSeed:      walletSeed,
+		Algorithm:       upgradeAlgorithm,
+		Fee:             upgradeFee,
+	}
+	if codeChanged {
+		modifyCfg.WasmPath = wasmPath
+	}
+	if replaceABI {
+		modifyCfg.ABIPath = upgradeABI
+	}
+
+	fmt.Println()
+	color.Yellow("→ Submitting ContractModify...\n")
+
+	result, err := d.Modify(ctx, modifyCfg)
+	if err != nil {
+		color.Red("\n✗ Upgrade failed: %v\n", err)
+		return err
+	}
+
+	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
+	fmt.Printf("  Validated: %v\n", result.Validated)
+
+	if !codeChanged {
+		color.Green("\n✓ Contract upgraded successfully!\n")
+		return nil
+	}
+
+	// Verify the new code landed
+	updated, err := client.GetContractInfo(ctx, contractAccount)
+	if err != nil {
+		return fmt.Errorf("failed to verify upgrade: %w", err)
+	}
+
+	switch {
+	case strings.EqualFold(updated.WasmHash, localHash):
+		color.Green("\n✓ Contract upgraded successfully! WASM hash verified\n")
+	case updated.WasmHash != info.WasmHash:
+		color.Yellow("\n⚠ WASM hash changed to %s but does not match the local build\n", updated.WasmHash)
+	default:
+		color.Red("\n✗ WASM hash unchanged after upgrade\n")
+		return fmt.Errorf("upgrade transaction %s did not change the contract code", result.TxHash)
+	}
+
+	return nil
+}
+
+// onChainContractABI decodes the ABI stored on a contract ledger entry
+func onChainContractABI(info *chain.ContractInfo, contractName string) (*abi.ABI, error) {
+	if functions, ok := info.Extra["Functions"]; ok {
+		raw, err := json.Marshal(functions)
+		if err != nil {
+			return nil, err
+		}
+		return abi.FromLedgerFunctions(contractName, raw)
+	}
+
+	if info.ABI != nil {
+		var parsed abi.ABI
+		if err := json.Unmarshal(info.ABI, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse ABI: %w", err)
+		}
+		return &parsed, nil
+	}
+
+	return nil, fmt.Errorf("contract has no ABI on ledger")
+}
+
+func displayABIDiff(diff *abi.Diff) {
+	for _, fn := range diff.Added {
+		color.Green("               + %s\n", fn.Signature())
+	}
+	for _, fn := range diff.Removed {
+		color.Red("               - %s\n", fn.Signature())
+	}
+	for _, change := range diff.Changed {
+		color.Yellow("               ~ %s -> %s\n", change.Before.Signature(), change.After.Signature())
+	}
+}
+
+// sha512Half returns the first 256 bits of the SHA-512 digest, as used for ledger hashes
+func sha512Half(data []byte) string {
+	sum := sha512.Sum512(data)
+	return strings.ToUpper(hex.EncodeToString(sum[:32]))
+}
@@ -11,10 +11,12 @@ import (
 )
 
 var (
-	userDeleteNetwork   string
-	userDeleteWallet    string
-	userDeleteAlgorithm string
-	userDeleteFee       string
+	userDeleteNetwork     string
+	userDeleteWallet      string
+	userDeleteAlgorithm   string
+	userDeleteFee         string
+	userDeleteUnsignedOut string
+	userDeleteMultisig    int
 )
 
 var userDeleteCmd = &cobra.Command{
@@ -37,7 +39,9 @@ func init() {
 	userDeleteCmd.Flags().StringVarP(&userDeleteNetwork, "network", "n", "alphanet", "Network")
 	userDeleteCmd.Flags().StringVarP(&userDeleteWallet, "wallet", "w", "", "Wallet seed or name (required)")
 	userDeleteCmd.Flags().StringVar(&userDeleteAlgorithm, "algorithm", "secp256k1", "Cryptographic algorithm")
-	userDeleteCmd.Flags().StringVar(&userDeleteFee, "fee", "1000000", "Transaction fee in drops")
+	userDeleteCmd.Flags().StringVar(&userDeleteFee, "fee", "auto", "Transaction fee in drops (\"auto\" estimates from network load)")
+	userDeleteCmd.Flags().StringVar(&userDeleteUnsignedOut, "unsigned-out", "", "Write the autofilled unsigned transaction to a file instead of submitting")
+	userDeleteCmd.Flags().IntVar(&userDeleteMultisig, "multisig", 0, "With --unsigned-out: prepare a multisig transaction for N signers")
 
 	userDeleteCmd.MarkFlagRequired("wallet")
 }
@@ -63,14 +67,26 @@ func runUserDelete(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Contract: %s\n", contractAccount)
 	fmt.Printf("  Network:  %s\n", userDeleteNetwork)
 
-	resolver, err := wallet.NewWalletResolver()
-	if err != nil {
-		return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+	if userDeleteMultisig > 0 && userDeleteUnsignedOut == "" {
+		return fmt.Errorf("--multisig requires --unsigned-out")
 	}
 
-	walletSeed, err := resolver.ResolveWallet(userDeleteWallet)
-	if err != nil {
-		return fmt.Errorf("failed to resolve wallet: %w", err)
+	var walletSeed, account string
+	if userDeleteUnsignedOut != "" {
+		account, err = resolveSigningAccount(userDeleteWallet, userDeleteAlgorithm)
+		if err != nil {
+			return err
+		}
+	} else {
+		resolver, err := wallet.NewWalletResolver()
+		if err != nil {
+			return fmt.Errorf("failed to initialize wallet resolver: %w", err)
+		}
+
+		walletSeed, err = resolver.ResolveWallet(userDeleteWallet)
+		if err != nil {
+			return fmt.Errorf("failed to resolve wallet: %w", err)
+		}
 	}
 
 	d, err := deployer.NewDeployer(false)
@@ -81,10 +97,13 @@ func runUserDelete(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 	result, err := d.UserDelete(ctx, deployer.UserDeleteConfig{
 		ContractAccount: contractAccount,
-		NetworkURL:      networkCfg.URL,
+		Network:         networkCfg,
 		WalletSeed:      walletSeed,
 		Algorithm:       userDeleteAlgorithm,
 		Fee:             userDeleteFee,
+		Unsigned:        userDeleteUnsignedOut != "",
+		Account:         account,
+		SignerCount:     userDeleteMultisig,
 	})
 
 	if err != nil {
@@ -92,6 +111,10 @@ func runUserDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if userDeleteUnsignedOut != "" {
+		return writeUnsignedTx(userDeleteUnsignedOut, userDeleteNetwork, result.UnsignedTx)
+	}
+
 	color.Green("\n✓ User data deleted successfully! Reserves recovered.\n")
 	fmt.Printf("  Transaction Hash: %s\n", result.TxHash)
 	fmt.Printf("  Validated: %v\n", result.Validated)
@@ -0,0 +1,143 @@
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Diff describes the differences between two ABIs
+type Diff struct {
+	Added   []Function
+	Removed []Function
+	Changed []FunctionChange
+}
+
+// FunctionChange describes a function whose signature changed
+type FunctionChange struct {
+	Name   string
+	Before Function
+	After  Function
+}
+
+// HasChanges reports whether the diff contains any differences
+func (d *Diff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// Compare diffs two ABIs. Only what is stored on-chain is compared:
+// function names and parameter names and types, in order.
+func Compare(before, after *ABI) *Diff {
+	diff := &Diff{}
+
+	beforeFns := make(map[string]Function)
+	for _, fn := range before.Functions {
+		beforeFns[fn.Name] = fn
+	}
+
+	afterFns := make(map[string]Function)
+	for _, fn := range after.Functions {
+		afterFns[fn.Name] = fn
+
+		old, ok := beforeFns[fn.Name]
+		if !ok {
+			diff.Added = append(diff.Added, fn)
+			continue
+		}
+		if !sameParameters(old.Parameters, fn.Parameters) {
+			diff.Changed = append(diff.Changed, FunctionChange{Name: fn.Name, Before: old, After: fn})
+		}
+	}
+
+	for _, fn := range before.Functions {
+		if _, ok := afterFns[fn.Name]; !ok {
+			diff.Removed = append(diff.Removed, fn)
+		}
+	}
+
+	return diff
+}
+
+func sameParameters(a, b []Parameter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Type != b[i].Type {
+			return false
+		}
+	}
+	return true
+}
+
+// Signature returns a human-readable signature such as "transfer(to: ACCOUNT, amount: UINT64)"
+func (f Function) Signature() string {
+	params := make([]string, len(f.Parameters))
+	for i, p := range f.Parameters {
+		params[i] = fmt.Sprintf("%s: %s", p.Name, p.Type)
+	}
+	return fmt.Sprintf("%s(%s)", f.Name, strings.Join(params, ", "))
+}
+
+// ledgerFunction mirrors the Functions array stored on a contract ledger entry
+type ledgerFunction struct {
+	Function struct {
+		FunctionName string `json:"FunctionName"`
+		Parameters   []struct {
+			Parameter struct {
+				ParameterName string `json:"ParameterName"`
+				ParameterFlag int    `json:"ParameterFlag"`
+				ParameterType struct {
+					Type string `json:"type"`
+				} `json:"ParameterType"`
+			} `json:"Parameter"`
+		} `json:"Parameters"`
+	} `json:"Function"`
+}
+
+// FromLedgerFunctions decodes the hex-encoded Functions array of a contract
+// ledger entry into an ABI
+func FromLedgerFunctions(contractName string, raw json.RawMessage) (*ABI, error) {
+	var entries []ledgerFunction
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger functions: %w", err)
+	}
+
+	result := &ABI{
+		ContractName: contractName,
+		Functions:    []Function{},
+	}
+
+	for _, entry := range entries {
+		name, err := decodeHexString(entry.Function.FunctionName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid function name %q: %w", entry.Function.FunctionName, err)
+		}
+
+		fn := Function{Name: name, Parameters: []Parameter{}}
+		for _, p := range entry.Function.Parameters {
+			paramName, err := decodeHexString(p.Parameter.ParameterName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter name in %s: %w", name, err)
+			}
+			fn.Parameters = append(fn.Parameters, Parameter{
+				Name: paramName,
+				Type: p.Parameter.ParameterType.Type,
+				Flag: p.Parameter.ParameterFlag,
+			})
+		}
+
+		result.Functions = append(result.Functions, fn)
+	}
+
+	return result, nil
+}
+
+func decodeHexString(s string) (string, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
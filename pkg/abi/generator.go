@@ -114,5 +114,26 @@ func (g *Generator) Validate(abi *ABI) []error {
 		}
 	}
 
+	// Validate state keys
+	if abi.State != nil {
+		scopes := []struct {
+			name   string
+			fields []StateField
+		}{{"global", abi.State.Global}, {"user", abi.State.User}}
+		for _, scope := range scopes {
+			keys := make(map[string]bool)
+			for _, field := range scope.fields {
+				if keys[field.Key] {
+					errors = append(errors, fmt.Errorf("duplicate %s state key '%s'", scope.name, field.Key))
+				}
+				keys[field.Key] = true
+
+				if !IsValidType(field.Type) {
+					errors = append(errors, fmt.Errorf("state key '%s' has invalid type '%s'", field.Key, field.Type))
+				}
+			}
+		}
+	}
+
 	return errors
 }
@@ -15,6 +15,8 @@ var (
 	paramPattern    = regexp.MustCompile(`^///\s*@param\s+(\w+)\s+(\w+)(?:\s+-\s+(.+))?`)
 	returnPattern   = regexp.MustCompile(`^///\s*@return\s+(\w+)(?:\s+-\s+(.+))?`)
 	flagPattern     = regexp.MustCompile(`^///\s*@flag\s+(\d+)`)
+	viewPattern     = regexp.MustCompile(`^///\s*@view\b`)
+	statePattern    = regexp.MustCompile(`^\s*///\s*@xrpl-state\s+(?:(global|user)\s+)?(\w+)\s+(\w+)(?:\s+-\s+(.+))?`)
 
 	// Pattern to match Rust function declaration
 	rustFnPattern = regexp.MustCompile(`^\s*(?:pub\s+)?fn\s+(\w+)\s*\(`)
@@ -23,6 +25,7 @@ var (
 // Parser handles parsing Rust source files for ABI annotations
 type Parser struct {
 	sourceDir string
+	state     StateSchema
 }
 
 // NewParser creates a new ABI parser
@@ -36,6 +39,7 @@ func (p *Parser) ParseContract(contractName string) (*ABI, error) {
 		ContractName: contractName,
 		Functions:    []Function{},
 	}
+	p.state = StateSchema{}
 
 	// Find all .rs files
 	err := filepath.Walk(p.sourceDir, func(path string, info os.FileInfo, err error) error {
@@ -58,6 +62,11 @@ func (p *Parser) ParseContract(contractName string) (*ABI, error) {
 		return nil, err
 	}
 
+	if len(p.state.Global) > 0 || len(p.state.User) > 0 {
+		state := p.state
+		abi.State = &state
+	}
+
 	return abi, nil
 }
 
@@ -77,6 +86,13 @@ func (p *Parser) parseFile(filePath string) ([]Function, error) {
 		lineNum++
 		line := scanner.Text()
 
+		if ok, err := p.parseState(line); ok {
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", filePath, lineNum, err)
+			}
+			continue
+		}
+
 		// Check if this is an @xrpl-function annotation
 		if match := functionPattern.FindStringSubmatch(line); match != nil {
 			functionName := match[1]
@@ -112,6 +128,13 @@ func (p *Parser) parseFunction(scanner *bufio.Scanner, lineNum *int, functionNam
 		*lineNum++
 		line := scanner.Text()
 
+		if ok, err := p.parseState(line); ok {
+			if err != nil {
+				return fn, err
+			}
+			continue
+		}
+
 		// Check for @param annotation
 		if match := paramPattern.FindStringSubmatch(line); match != nil {
 			paramName := match[1]
@@ -144,6 +167,12 @@ func (p *Parser) parseFunction(scanner *bufio.Scanner, lineNum *int, functionNam
 			continue
 		}
 
+		// Check for @view annotation
+		if viewPattern.MatchString(line) {
+			fn.View = true
+			continue
+		}
+
 		// Check for @return annotation
 		if match := returnPattern.FindStringSubmatch(line); match != nil {
 			returnType := match[1]
@@ -190,6 +219,32 @@ func (p *Parser) parseFunction(scanner *bufio.Scanner, lineNum *int, functionNam
 	return fn, nil
 }
 
+// parseState records an @xrpl-state annotation. Keys are global unless
+// marked "user":
+//
+//	/// @xrpl-state total_supply UINT64 - Tokens in circulation
+//	/// @xrpl-state user balance UINT64
+func (p *Parser) parseState(line string) (bool, error) {
+	match := statePattern.FindStringSubmatch(line)
+	if match == nil {
+		return false, nil
+	}
+
+	scope, key, keyType, description := match[1], match[2], match[3], match[4]
+	if !IsValidType(keyType) {
+		return true, fmt.Errorf("invalid type '%s' for state key '%s'. Valid types: %s",
+			keyType, key, getValidTypesString())
+	}
+
+	field := StateField{Key: key, Type: keyType, Description: description}
+	if scope == "user" {
+		p.state.User = append(p.state.User, field)
+	} else {
+		p.state.Global = append(p.state.Global, field)
+	}
+	return true, nil
+}
+
 // getValidTypesString returns a comma-separated string of valid types
 func getValidTypesString() string {
 	types := make([]string, 0, len(ValidXRPLTypes))
@@ -0,0 +1,310 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	addresscodec "github.com/Peersyst/xrpl-go/address-codec"
+)
+
+// StateValue is a decoded contract storage value
+type StateValue struct {
+	Key         string
+	Type        string // Declared type; empty when the key is not in the schema
+	Value       string // Rendered value
+	Raw         interface{}
+	Description string
+	Err         error // Set when the value does not match the declared type
+}
+
+// Known reports whether the key is declared in the schema
+func (v StateValue) Known() bool {
+	return v.Type != ""
+}
+
+// ledgerEntryFields describe a ContractData entry rather than its contents
+var ledgerEntryFields = map[string]bool{
+	"LedgerEntryType":   true,
+	"Flags":             true,
+	"Owner":             true,
+	"Account":           true,
+	"ContractAccount":   true,
+	"OwnerNode":         true,
+	"PreviousTxnID":     true,
+	"PreviousTxnLgrSeq": true,
+	"index":             true,
+}
+
+// StateEntries returns the key/value contents of a ContractData ledger
+// entry. Keys held in a nested object are returned individually.
+func StateEntries(entry map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, value := range entry {
+		if ledgerEntryFields[key] {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok && !isTypedValue(nested) {
+			for k, v := range nested {
+				values[k] = v
+			}
+			continue
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// isTypedValue reports whether a map is a single {"type", "value"} value
+// rather than a set of keys
+func isTypedValue(m map[string]interface{}) bool {
+	_, hasType := m["type"]
+	_, hasValue := m["value"]
+	return hasType && hasValue && len(m) == 2
+}
+
+// Field returns the declared field for a key, looking in the user keys
+// first when user is set and falling back to the other scope
+func (s *StateSchema) Field(key string, user bool) (*StateField, bool) {
+	if s == nil {
+		return nil, false
+	}
+	scopes := [][]StateField{s.Global, s.User}
+	if user {
+		scopes[0], scopes[1] = scopes[1], scopes[0]
+	}
+	for _, fields := range scopes {
+		for i := range fields {
+			if fields[i].Key == key {
+				return &fields[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Decode decodes the contents of a ContractData entry. Declared keys come
+// first in schema order, followed by unknown keys sorted by name.
+func (s *StateSchema) Decode(entry map[string]interface{}, user bool) []StateValue {
+	entries := StateEntries(entry)
+
+	var values []StateValue
+	seen := make(map[string]bool)
+	if s != nil {
+		declared := s.Global
+		if user {
+			declared = s.User
+		}
+		for _, field := range declared {
+			raw, ok := entries[field.Key]
+			if !ok {
+				continue
+			}
+			values = append(values, s.DecodeValue(field.Key, raw, user))
+			seen[field.Key] = true
+		}
+	}
+
+	var rest []string
+	for key := range entries {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	for _, key := range rest {
+		values = append(values, s.DecodeValue(key, entries[key], user))
+	}
+	return values
+}
+
+// DecodeValue decodes one storage value according to the schema
+func (s *StateSchema) DecodeValue(key string, raw interface{}, user bool) StateValue {
+	v := StateValue{Key: key, Raw: raw}
+	field, ok := s.Field(key, user)
+	if !ok {
+		v.Value = formatRaw(unwrapTyped(raw))
+		return v
+	}
+
+	v.Type = field.Type
+	v.Description = field.Description
+	v.Value, v.Err = RenderValue(field.Type, raw)
+	if v.Err != nil {
+		v.Value = formatRaw(unwrapTyped(raw))
+	}
+	return v
+}
+
+// FormatStateValue renders a raw storage value without a schema
+func FormatStateValue(raw interface{}) string {
+	return formatRaw(unwrapTyped(raw))
+}
+
+func unwrapTyped(raw interface{}) interface{} {
+	if m, ok := raw.(map[string]interface{}); ok && isTypedValue(m) {
+		return m["value"]
+	}
+	return raw
+}
+
+var intSizes = map[string]int{
+	"UINT8":  1,
+	"UINT16": 2,
+	"UINT32": 4,
+	"UINT64": 8,
+}
+
+var hashSizes = map[string]int{
+	"UINT128": 16,
+	"UINT160": 20,
+	"UINT192": 24,
+	"UINT256": 32,
+}
+
+// RenderValue renders a raw storage value as the given ABI type. Integers
+// may be stored as JSON numbers, decimal strings or fixed width big-endian
+// hex.
+func RenderValue(typ string, raw interface{}) (string, error) {
+	raw = unwrapTyped(raw)
+	s := formatRaw(raw)
+
+	switch typ {
+	case "UINT8", "UINT16", "UINT32", "UINT64":
+		size := intSizes[typ]
+		n, ok := parseStoredInt(raw, size)
+		if !ok {
+			return "", fmt.Errorf("%q is not a %s", s, typ)
+		}
+		return n.String(), nil
+
+	case "UINT128", "UINT160", "UINT192", "UINT256":
+		size := hashSizes[typ]
+		if isHex(s) && len(s) == 2*size {
+			return strings.ToUpper(s), nil
+		}
+		n, ok := parseStoredInt(raw, size)
+		if !ok {
+			return "", fmt.Errorf("%q is not a %s", s, typ)
+		}
+		return strings.ToUpper(hex.EncodeToString(n.FillBytes(make([]byte, size)))), nil
+
+	case "VL":
+		if !isHex(s) {
+			return s, nil
+		}
+		b, _ := hex.DecodeString(s)
+		if text, ok := printable(b); ok {
+			return fmt.Sprintf("%q", text), nil
+		}
+		return strings.ToUpper(s), nil
+
+	case "ACCOUNT":
+		if addresscodec.IsValidClassicAddress(s) {
+			return s, nil
+		}
+		if isHex(s) && len(s) == 40 {
+			b, _ := hex.DecodeString(s)
+			return addresscodec.EncodeAccountIDToClassicAddress(b)
+		}
+		return "", fmt.Errorf("%q is not an account", s)
+
+	case "AMOUNT":
+		switch amount := raw.(type) {
+		case string:
+			if _, err := strconv.ParseUint(amount, 10, 64); err != nil {
+				return "", fmt.Errorf("%q is not an amount", amount)
+			}
+			return amount + " drops", nil
+		case map[string]interface{}:
+			value := formatRaw(amount["value"])
+			if id, ok := amount["mpt_issuance_id"]; ok {
+				return fmt.Sprintf("%s MPT %v", value, id), nil
+			}
+			return fmt.Sprintf("%s %v/%v", value, amount["currency"], amount["issuer"]), nil
+		}
+		return "", fmt.Errorf("%q is not an amount", s)
+	}
+
+	// NUMBER, ISSUE and CURRENCY are stored in their readable form
+	return s, nil
+}
+
+// parseStoredInt parses an unsigned integer of size bytes
+func parseStoredInt(raw interface{}, size int) (*big.Int, bool) {
+	var n *big.Int
+	switch v := raw.(type) {
+	case float64:
+		if v < 0 || v != float64(uint64(v)) {
+			return nil, false
+		}
+		n = new(big.Int).SetUint64(uint64(v))
+	case string:
+		// Fixed width hex takes precedence over decimal
+		var ok bool
+		if isHex(v) && len(v) == 2*size {
+			n, ok = new(big.Int).SetString(v, 16)
+		} else if isDecimal(v) {
+			n, ok = new(big.Int).SetString(v, 10)
+		}
+		if !ok {
+			return nil, false
+		}
+	default:
+		if s := formatRaw(raw); isDecimal(s) {
+			n, _ = new(big.Int).SetString(s, 10)
+		}
+	}
+	if n == nil || n.BitLen() > 8*size {
+		return nil, false
+	}
+	return n, true
+}
+
+func formatRaw(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case string:
+		return val
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(s string) bool {
+	if s == "" || len(s)%2 != 0 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func printable(b []byte) (string, bool) {
+	if len(b) == 0 || !utf8.Valid(b) {
+		return "", false
+	}
+	for _, r := range string(b) {
+		if r < 0x20 && r != '\n' && r != '\t' {
+			return "", false
+		}
+	}
+	return string(b), true
+}
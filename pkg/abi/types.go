@@ -21,6 +21,19 @@ var ValidXRPLTypes = map[string]TypeInfo{
 	"NUMBER":   {Name: "NUMBER", RustType: "f64", Description: "Floating-point number"},
 }
 
+// StateSchema describes the keys a contract keeps in its storage
+type StateSchema struct {
+	Global []StateField `json:"global,omitempty"` // Contract-wide keys
+	User   []StateField `json:"user,omitempty"`   // Keys stored per user
+}
+
+// StateField is a storage key and the type of its value
+type StateField struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
 // TypeInfo contains information about an XRPL type
 type TypeInfo struct {
 	Name        string
@@ -30,8 +43,9 @@ type TypeInfo struct {
 
 // ABI represents a contract's Application Binary Interface
 type ABI struct {
-	ContractName string     `json:"contract_name"`
-	Functions    []Function `json:"functions"`
+	ContractName string       `json:"contract_name"`
+	Functions    []Function   `json:"functions"`
+	State        *StateSchema `json:"state,omitempty"`
 }
 
 // Function represents a contract function definition
@@ -39,6 +53,7 @@ type Function struct {
 	Name       string      `json:"name"`
 	Parameters []Parameter `json:"parameters"`
 	Returns    *ReturnType `json:"returns,omitempty"`
+	View       bool        `json:"view,omitempty"` // Read-only; callers may simulate instead of submitting
 }
 
 // Parameter represents a function parameter
@@ -66,3 +81,13 @@ func GetTypeInfo(typeName string) (TypeInfo, bool) {
 	info, ok := ValidXRPLTypes[typeName]
 	return info, ok
 }
+
+// FindFunction returns the function with the given name
+func (a *ABI) FindFunction(name string) (*Function, bool) {
+	for i := range a.Functions {
+		if a.Functions[i].Name == name {
+			return &a.Functions[i], true
+		}
+	}
+	return nil, false
+}
@@ -8,15 +8,22 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/xrpl-commons/bedrock/embedded"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 )
 
 // Executor handles execution of embedded JavaScript modules
 type Executor struct {
 	modulesDir string
 	verbose    bool
+
+	// In worker mode every module runs in one long-lived Node process
+	useWorker bool
+	workerMu  sync.Mutex
+	worker    *worker
 }
 
 // NewExecutor creates a new executor instance
@@ -32,12 +39,54 @@ func NewExecutor(verbose bool) (*Executor, error) {
 	}, nil
 }
 
-// ExecuteModule runs a JavaScript module with JSON config input/output
-func (e *Executor) ExecuteModule(ctx context.Context, moduleName string, config interface{}) (*Result, error) {
-	// Get module path
-	modulePath := filepath.Join(e.modulesDir, moduleName)
+// NewWorkerExecutor creates an executor that runs modules in a single
+// persistent Node process instead of starting one per call. The process is
+// started on first use and keeps its XRPL connections open between calls,
+// which makes long runs of calls much faster. Close must be called when
+// the executor is no longer needed.
+func NewWorkerExecutor(verbose bool) (*Executor, error) {
+	e, err := NewExecutor(verbose)
+	if err != nil {
+		return nil, err
+	}
+	e.useWorker = true
+	return e, nil
+}
+
+// Close shuts down the worker process, if one is running
+func (e *Executor) Close() error {
+	e.workerMu.Lock()
+	defer e.workerMu.Unlock()
+
+	if e.worker == nil {
+		return nil
+	}
+	err := e.worker.close()
+	e.worker = nil
+	return err
+}
+
+// ExecuteModule runs a JavaScript module with JSON config input/output.
+// Progress events the module prints are passed to onProgress as they
+// arrive; onProgress may be nil.
+func (e *Executor) ExecuteModule(ctx context.Context, moduleName string, config interface{}, onProgress ProgressFunc) (*Result, error) {
+	return e.ExecuteFile(ctx, filepath.Join(e.modulesDir, moduleName), config, onProgress)
+}
+
+// ExecuteFile runs the JavaScript module at modulePath with the same JSON
+// config/result contract as the embedded modules. Packages such as
+// @transia/xrpl are resolved from the managed node_modules cache, so
+// project modules need no install of their own.
+func (e *Executor) ExecuteFile(ctx context.Context, modulePath string, config interface{}, onProgress ProgressFunc) (*Result, error) {
 	if _, err := os.Stat(modulePath); err != nil {
-		return nil, fmt.Errorf("module %s not found: %w", moduleName, err)
+		return nil, fmt.Errorf("module %s not found: %w", filepath.Base(modulePath), err)
+	}
+	if err := checkReplay(modulePath, config); err != nil {
+		return nil, err
+	}
+
+	if e.useWorker {
+		return e.executeInWorker(ctx, modulePath, config, onProgress)
 	}
 
 	// Create temp config file
@@ -54,9 +103,11 @@ func (e *Executor) ExecuteModule(ctx context.Context, moduleName string, config
 
 	// Execute Node.js module
 	cmd := exec.CommandContext(ctx, "node", modulePath, configFile)
+	cmd.Env = nodeEnv(e.modulesDir)
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	var stderr bytes.Buffer
+	stdout := &progressWriter{onProgress: onProgress}
+	cmd.Stdout = stdout
 
 	// In verbose mode, show stderr in real-time. Otherwise capture it.
 	if e.verbose {
@@ -99,6 +150,62 @@ func (e *Executor) ExecuteModule(ctx context.Context, moduleName string, config
 	return result, nil
 }
 
+// executeInWorker runs a module in the persistent worker, starting it (or
+// restarting it after a crash) when needed
+func (e *Executor) executeInWorker(ctx context.Context, modulePath string, config interface{}, onProgress ProgressFunc) (*Result, error) {
+	w, err := e.getWorker()
+	if err != nil {
+		return nil, err
+	}
+
+	if e.verbose {
+		fmt.Printf("[executor] Running in worker: %s\n", modulePath)
+	}
+
+	start := time.Now()
+	result, err := w.run(ctx, modulePath, config, onProgress)
+	duration := time.Since(start)
+
+	if e.verbose {
+		fmt.Printf("[executor] Execution took %v\n", duration)
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("module execution failed: %w", err)
+	}
+
+	return checkResult(result)
+}
+
+func (e *Executor) getWorker() (*worker, error) {
+	e.workerMu.Lock()
+	defer e.workerMu.Unlock()
+
+	if e.worker != nil && e.worker.alive() {
+		return e.worker, nil
+	}
+
+	w, err := startWorker(e.modulesDir, e.verbose)
+	if err != nil {
+		return nil, err
+	}
+	e.worker = w
+	return w, nil
+}
+
+// nodeEnv returns the environment for node processes, with the managed
+// node_modules cache on NODE_PATH
+func nodeEnv(modulesDir string) []string {
+	nodePath := filepath.Join(modulesDir, "node_modules")
+	if existing := os.Getenv("NODE_PATH"); existing != "" {
+		nodePath += string(os.PathListSeparator) + existing
+	}
+	return append(os.Environ(), "NODE_PATH="+nodePath)
+}
+
 // writeConfigFile writes the config object as JSON to a temp file
 func (e *Executor) writeConfigFile(config interface{}) (string, error) {
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -138,11 +245,16 @@ func (e *Executor) parseResult(data []byte) (*Result, error) {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
 
+	return checkResult(&result)
+}
+
+// checkResult turns an unsuccessful module result into an error
+func checkResult(result *Result) (*Result, error) {
 	if !result.Success {
-		return &result, fmt.Errorf("module returned error: %s - %s", result.Error, result.Details)
+		return result, fmt.Errorf("module returned error: %s - %s", result.Error, result.Details)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // Result represents the standardized result from a JS module
@@ -152,3 +264,21 @@ type Result struct {
 	Error   string          `json:"error,omitempty"`
 	Details string          `json:"details,omitempty"`
 }
+
+// checkReplay rejects modules that connect to a node while RPC traffic is
+// replayed from a cassette, since the cassette cannot answer them. Modules
+// autofilled by ExecuteAndSubmit get no network URL and run offline.
+func checkReplay(modulePath string, config interface{}) error {
+	if !chain.Replaying() {
+		return nil
+	}
+	cfg, ok := config.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if url, _ := cfg["network_url"].(string); url != "" {
+		return fmt.Errorf("module %s connects to %s directly and cannot run with %s; only the Go RPC client is replayed",
+			filepath.Base(modulePath), url, chain.ReplayEnv)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Progress events reported while a module runs. Modules print each event
+// as an NDJSON line on stdout ahead of their result line:
+//
+//	{"type":"progress","event":"phase","phase":"connect","message":"Connecting to ..."}
+//	{"type":"progress","event":"submitted","txHash":"..."}
+//	{"type":"progress","event":"waiting","txHash":"..."}
+//	{"type":"progress","event":"validated","txHash":"...","result":"tesSUCCESS","ledgerIndex":123}
+//
+// When the submission engine submits a signed transaction, the submitted,
+// waiting and validated events come from the engine instead.
+const (
+	EventPhase     = "phase"     // A phase of the module started
+	EventSubmitted = "submitted" // The transaction was submitted
+	EventWaiting   = "waiting"   // Waiting for the transaction to be validated
+	EventValidated = "validated" // The transaction is in a validated ledger
+)
+
+// progressType marks a line of module output as a progress event
+const progressType = "progress"
+
+// ProgressEvent is a single progress update
+type ProgressEvent struct {
+	Type         string `json:"type"`
+	Event        string `json:"event"`
+	Phase        string `json:"phase,omitempty"`
+	Message      string `json:"message,omitempty"`
+	TxHash       string `json:"txHash,omitempty"`
+	EngineResult string `json:"engineResult,omitempty"`
+	Result       string `json:"result,omitempty"`
+	LedgerIndex  int64  `json:"ledgerIndex,omitempty"`
+}
+
+// ProgressFunc receives progress events as they happen. It is called from
+// the goroutine reading module output, so it should return quickly.
+type ProgressFunc func(ProgressEvent)
+
+// emit sends event to onProgress when it is set
+func emit(onProgress ProgressFunc, event ProgressEvent) {
+	if onProgress == nil {
+		return
+	}
+	event.Type = progressType
+	onProgress(event)
+}
+
+// parseProgress decodes line if it is a progress event
+func parseProgress(line []byte) (ProgressEvent, bool) {
+	var event ProgressEvent
+	line = bytes.TrimSpace(line)
+	if !bytes.HasPrefix(line, []byte(`{"type":"progress"`)) {
+		return event, false
+	}
+	if err := json.Unmarshal(line, &event); err != nil {
+		return event, false
+	}
+	return event, true
+}
+
+// progressWriter splits module stdout into lines as it is written, passing
+// progress events on and keeping every other line for the result
+type progressWriter struct {
+	onProgress ProgressFunc
+	partial    []byte
+	output     bytes.Buffer
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(w.partial[:i+1])
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *progressWriter) line(line []byte) {
+	if event, ok := parseProgress(line); ok {
+		emit(w.onProgress, event)
+		return
+	}
+	w.output.Write(line)
+}
+
+// Bytes returns the output that was not a progress event
+func (w *progressWriter) Bytes() []byte {
+	if len(w.partial) > 0 {
+		w.line(w.partial)
+		w.partial = nil
+	}
+	return w.output.Bytes()
+}
+
+// String returns the output that was not a progress event
+func (w *progressWriter) String() string {
+	return string(w.Bytes())
+}
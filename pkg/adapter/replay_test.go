@@ -0,0 +1,159 @@
+package adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xrpl-commons/bedrock/embedded"
+	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/chain/mockserver"
+	"github.com/xrpl-commons/bedrock/pkg/codec"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/deployer"
+)
+
+// Environment of the replay test's child processes. The cassette transport
+// is chosen once per process, so recording and replaying each run in one.
+const (
+	phaseEnv  = "BEDROCK_TEST_REPLAY_PHASE"
+	urlEnv    = "BEDROCK_TEST_REPLAY_URL"
+	outputEnv = "BEDROCK_TEST_REPLAY_OUTPUT"
+)
+
+const genesisSeed = "snoPBrXtMeMyMHUVTgbuqAfg1SUTb"
+
+// minimalWasm is an empty WebAssembly module
+var minimalWasm = []byte{0x00, 0x61, 0x73, 0x6D, 0x01, 0x00, 0x00, 0x00}
+
+type replayOutput struct {
+	URL        string `json:"url"`
+	DeployHash string `json:"deployHash"`
+	CallHash   string `json:"callHash"`
+}
+
+func TestReplayDeployAndCall(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is not installed")
+	}
+	cache, err := embedded.GetCacheDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "node_modules", "@transia", "xrpl")); err != nil {
+		t.Skip("JS modules are not installed; run any bedrock command once to install them")
+	}
+
+	cassette := filepath.Join(t.TempDir(), "deploy-call.json")
+	recorded := runPhase(t, chain.RecordEnv+"="+cassette, urlEnv+"=")
+
+	// The mock node is gone; every request must be answered by the cassette
+	replayed := runPhase(t, chain.ReplayEnv+"="+cassette, urlEnv+"="+recorded.URL)
+	if recorded.DeployHash == "" || replayed.DeployHash != recorded.DeployHash || replayed.CallHash != recorded.CallHash {
+		t.Errorf("replayed %+v, recorded %+v", replayed, recorded)
+	}
+}
+
+// runPhase runs TestReplayPhase in a child process with env added
+func runPhase(t *testing.T, env ...string) replayOutput {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "output.json")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestReplayPhase$", "-test.v")
+	cmd.Env = append(os.Environ(), append(env, phaseEnv+"=1", outputEnv+"="+output)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v: %v\n%s", env, err, out)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result replayOutput
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// TestReplayPhase deploys a contract and calls it, against a mock node when
+// recording and against the cassette alone when replaying
+func TestReplayPhase(t *testing.T) {
+	if os.Getenv(phaseEnv) == "" {
+		t.Skip("run by TestReplayDeployAndCall")
+	}
+
+	url := os.Getenv(urlEnv)
+	if url == "" {
+		srv := mockserver.New()
+		defer srv.Close()
+		defs, err := codec.LoadDefinitions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv.SetDefinitions(defs)
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			for {
+				select {
+				case <-done:
+					return
+				case <-time.After(200 * time.Millisecond):
+					srv.Accept()
+				}
+			}
+		}()
+		url = srv.URL
+	}
+
+	ctx := context.Background()
+	network := config.NetworkConfig{URL: url}
+
+	wasm := filepath.Join(t.TempDir(), "contract.wasm")
+	if err := os.WriteFile(wasm, minimalWasm, 0644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := deployer.NewDeployer(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployed, err := d.Deploy(ctx, deployer.DeploymentConfig{
+		WasmPath:   wasm,
+		Network:    network,
+		WalletSeed: genesisSeed,
+		Fee:        "1000000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := caller.NewCaller(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	called, err := c.Call(ctx, caller.CallConfig{
+		ContractAccount:      mockserver.GenesisAddress,
+		FunctionName:         "hello",
+		Network:              network,
+		WalletSeed:           genesisSeed,
+		ComputationAllowance: "1000000",
+		Fee:                  "1000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deployed.Validated || !called.Validated {
+		t.Fatalf("deploy validated = %v, call validated = %v", deployed.Validated, called.Validated)
+	}
+
+	data, _ := json.Marshal(replayOutput{URL: url, DeployHash: deployed.TxHash, CallHash: called.TxHash})
+	if err := os.WriteFile(os.Getenv(outputEnv), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
+// ExecuteAndSubmit runs a module in sign-only mode and submits the signed
+// transaction through the chain submission engine. The module is given a
+// LastLedgerSequence so the transaction can be tracked to a final state.
+// When account is set, its Sequence is autofilled here too: with the fee
+// already in moduleConfig, the module signs without a connection of its
+// own, so every request goes through the Go client and can be recorded
+// and replayed. Modules that autofill for themselves are run with an empty
+// account. The module's own output is returned alongside the submission
+// result. onProgress receives the module's events followed by the
+// submission's.
+func (e *Executor) ExecuteAndSubmit(ctx context.Context, moduleName string, network config.NetworkConfig, account string, moduleConfig map[string]interface{}, onProgress ProgressFunc) (*Result, *chain.SubmissionResult, error) {
+	client := chain.NewNetworkClient(network)
+
+	if account != "" {
+		if err := Autofill(ctx, client, account, moduleConfig); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	lastLedger, err := client.LastLedgerSequence(ctx, chain.DefaultLedgerOffset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current ledger: %w", err)
+	}
+
+	moduleConfig["sign_only"] = true
+	moduleConfig["last_ledger_sequence"] = lastLedger
+
+	result, err := e.ExecuteModule(ctx, moduleName, moduleConfig, onProgress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var output struct {
+		SignedTx *chain.SignedTx `json:"signedTx"`
+	}
+	if err := json.Unmarshal(result.Data, &output); err != nil || output.SignedTx == nil {
+		return result, nil, fmt.Errorf("module %s did not return a signed transaction", moduleName)
+	}
+
+	if output.SignedTx.LastLedgerSequence == 0 {
+		output.SignedTx.LastLedgerSequence = lastLedger
+	}
+
+	opts := chain.SubmitOptions{
+		OnSubmitted: func(r *chain.SubmissionResult) {
+			emit(onProgress, ProgressEvent{Event: EventSubmitted, TxHash: r.Hash, EngineResult: r.EngineResult})
+			emit(onProgress, ProgressEvent{Event: EventWaiting, TxHash: r.Hash})
+		},
+	}
+
+	submission, err := client.SubmitAndTrack(ctx, *output.SignedTx, opts)
+	if submission != nil && submission.Validated {
+		emit(onProgress, ProgressEvent{
+			Event:       EventValidated,
+			TxHash:      submission.Hash,
+			Result:      submission.TransactionResult,
+			LedgerIndex: submission.LedgerIndex,
+		})
+	}
+	return result, submission, err
+}
+
+// Autofill sets the next Sequence of account in moduleConfig, or 0 when
+// the transaction uses a Ticket, and removes the network URL so the module
+// builds the transaction offline
+func Autofill(ctx context.Context, client *chain.Client, account string, moduleConfig map[string]interface{}) error {
+	sequence := 0
+	if moduleConfig["ticket_sequence"] == nil {
+		info, err := client.GetAccountInfo(ctx, account)
+		if err != nil {
+			return fmt.Errorf("failed to get account sequence: %w", err)
+		}
+		sequence = info.AccountData.Sequence
+	}
+
+	moduleConfig["sequence"] = sequence
+	delete(moduleConfig, "network_url")
+	return nil
+}
@@ -0,0 +1,313 @@
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// workerShutdownTimeout bounds how long Close waits for the worker to exit
+const workerShutdownTimeout = 5 * time.Second
+
+// stderrTailSize is how much worker stderr is kept for error reports
+const stderrTailSize = 8 * 1024
+
+// rpcRequest is a JSON-RPC request or notification sent to the worker
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC response or notification from the worker
+type rpcResponse struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result *Result         `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+// progressParams are the params of a progress notification
+type progressParams struct {
+	ID    int64         `json:"id"`
+	Event ProgressEvent `json:"event"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// worker is a long-lived Node process running worker.js. Modules are run
+// by sending newline-delimited JSON-RPC requests on its stdin; responses
+// come back on its stdout and are matched to requests by ID, so several
+// calls can be in flight at once.
+type worker struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *tailBuffer
+
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	nextID   int64
+	pending  map[int64]chan *rpcResponse
+	progress map[int64]ProgressFunc
+	err      error // Set once the worker has stopped
+	done     chan struct{}
+}
+
+// startWorker launches worker.js from modulesDir
+func startWorker(modulesDir string, verbose bool) (*worker, error) {
+	cmd := exec.Command("node", filepath.Join(modulesDir, "worker.js"))
+	cmd.Env = nodeEnv(modulesDir)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worker stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open worker stdout: %w", err)
+	}
+
+	w := &worker{
+		cmd:      cmd,
+		stdin:    stdin,
+		stderr:   &tailBuffer{max: stderrTailSize},
+		pending:  make(map[int64]chan *rpcResponse),
+		progress: make(map[int64]ProgressFunc),
+		done:     make(chan struct{}),
+	}
+
+	// Module logs go to stderr; show them in verbose mode, keep the tail otherwise
+	if verbose {
+		cmd.Stderr = io.MultiWriter(os.Stderr, w.stderr)
+	} else {
+		cmd.Stderr = w.stderr
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start node worker: %w", err)
+	}
+
+	go w.readLoop(stdout)
+
+	return w, nil
+}
+
+// readLoop dispatches responses until the worker's stdout closes
+func (w *worker) readLoop(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		// Signed transactions carrying contract code can be large, so read
+		// whole lines rather than using a size-limited scanner
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var resp rpcResponse
+			if jsonErr := json.Unmarshal(line, &resp); jsonErr == nil {
+				if resp.Method == "progress" {
+					w.notify(resp.Params)
+				} else {
+					w.deliver(&resp)
+				}
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	waitErr := w.cmd.Wait()
+
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = fmt.Errorf("node worker exited: %v", waitErr)
+		if tail := w.stderr.String(); tail != "" {
+			w.err = fmt.Errorf("%w\nStderr: %s", w.err, tail)
+		}
+	}
+	for id, ch := range w.pending {
+		close(ch)
+		delete(w.pending, id)
+		delete(w.progress, id)
+	}
+	w.mu.Unlock()
+
+	close(w.done)
+}
+
+func (w *worker) deliver(resp *rpcResponse) {
+	w.mu.Lock()
+	ch, ok := w.pending[resp.ID]
+	delete(w.pending, resp.ID)
+	delete(w.progress, resp.ID)
+	w.mu.Unlock()
+
+	if ok {
+		ch <- resp
+	}
+}
+
+// notify passes a progress notification to its request's callback
+func (w *worker) notify(raw json.RawMessage) {
+	var params progressParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	onProgress := w.progress[params.ID]
+	w.mu.Unlock()
+
+	emit(onProgress, params.Event)
+}
+
+// alive reports whether the worker can still take requests
+func (w *worker) alive() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err == nil
+}
+
+// send writes one message to the worker's stdin
+func (w *worker) send(req rpcRequest) error {
+	req.JSONRPC = "2.0"
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	data = append(data, '\n')
+
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	if _, err := w.stdin.Write(data); err != nil {
+		return fmt.Errorf("failed to write to node worker: %w", err)
+	}
+	return nil
+}
+
+// call sends a request and waits for its response. If ctx ends first the
+// worker is told to cancel the request and ctx.Err() is returned.
+func (w *worker) call(ctx context.Context, method string, params interface{}, onProgress ProgressFunc) (*rpcResponse, error) {
+	ch := make(chan *rpcResponse, 1)
+
+	w.mu.Lock()
+	if w.err != nil {
+		err := w.err
+		w.mu.Unlock()
+		return nil, err
+	}
+	w.nextID++
+	id := w.nextID
+	w.pending[id] = ch
+	if onProgress != nil {
+		w.progress[id] = onProgress
+	}
+	w.mu.Unlock()
+
+	if err := w.send(rpcRequest{ID: id, Method: method, Params: params}); err != nil {
+		w.forget(id)
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			return nil, w.err
+		}
+		return resp, nil
+	case <-ctx.Done():
+		w.forget(id)
+		w.send(rpcRequest{Method: "cancel", Params: map[string]int64{"id": id}})
+		return nil, ctx.Err()
+	}
+}
+
+func (w *worker) forget(id int64) {
+	w.mu.Lock()
+	delete(w.pending, id)
+	delete(w.progress, id)
+	w.mu.Unlock()
+}
+
+// run executes a module in the worker
+func (w *worker) run(ctx context.Context, modulePath string, config interface{}, onProgress ProgressFunc) (*Result, error) {
+	resp, err := w.call(ctx, "run", map[string]interface{}{
+		"module": modulePath,
+		"config": config,
+	}, onProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		if resp.Error.Data != "" {
+			return nil, fmt.Errorf("%s\nStdout: %s", resp.Error.Message, resp.Error.Data)
+		}
+		return nil, fmt.Errorf("%s", resp.Error.Message)
+	}
+	if resp.Result == nil {
+		return nil, fmt.Errorf("node worker returned an empty response")
+	}
+	return resp.Result, nil
+}
+
+// close asks the worker to disconnect its clients and exit, killing it if
+// it does not stop in time
+func (w *worker) close() error {
+	if w.alive() {
+		ctx, cancel := context.WithTimeout(context.Background(), workerShutdownTimeout)
+		w.call(ctx, "shutdown", nil, nil)
+		cancel()
+	}
+	w.stdin.Close()
+
+	select {
+	case <-w.done:
+	case <-time.After(workerShutdownTimeout):
+		w.cmd.Process.Kill()
+		<-w.done
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = fmt.Errorf("node worker is closed")
+	return nil
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
@@ -0,0 +1,76 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// csvHeader lists the exported columns in order
+var csvHeader = []string{
+	"id", "function", "contract", "account", "status", "tx_hash",
+	"transaction_result", "return_code", "return_value", "gas_used", "duration_ms", "error",
+}
+
+// Export writes the report to path as JSON or CSV depending on its extension
+func Export(path string, report *Report) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return WriteJSON(path, report)
+	case ".csv":
+		return WriteCSV(path, report)
+	default:
+		return fmt.Errorf("unsupported export format: %s (use .json or .csv)", ext)
+	}
+}
+
+// WriteJSON writes the report as indented JSON
+func WriteJSON(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes one row per call
+func WriteCSV(path string, report *Report) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create results file: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write(csvHeader)
+	for _, r := range report.Results {
+		w.Write([]string{
+			r.ID,
+			r.Function,
+			r.Contract,
+			r.Account,
+			string(r.Status),
+			r.TxHash,
+			r.TransactionResult,
+			strconv.Itoa(r.ReturnCode),
+			r.ReturnValue,
+			strconv.FormatInt(r.GasUsed, 10),
+			strconv.FormatInt(r.DurationMs, 10),
+			r.Error,
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+)
+
+// Failure policies
+const (
+	// PolicyStop stops dispatching new calls after the first failure
+	PolicyStop = "stop"
+	// PolicyContinue keeps going; only calls depending on a failed call are skipped
+	PolicyContinue = "continue"
+)
+
+// File is a batch of contract calls
+type File struct {
+	Contract  string  `toml:"contract" json:"contract"`     // Default contract account
+	ABI       string  `toml:"abi" json:"abi"`               // Default ABI path
+	Wallet    string  `toml:"wallet" json:"wallet"`         // Default signing wallet
+	OnFailure string  `toml:"on_failure" json:"on_failure"` // stop (default) or continue
+	Calls     []Entry `toml:"calls" json:"calls"`
+}
+
+// Entry is a single call in a batch
+type Entry struct {
+	ID        string                 `toml:"id" json:"id"`
+	Function  string                 `toml:"function" json:"function"`
+	Params    map[string]interface{} `toml:"params" json:"params"`
+	Wallet    string                 `toml:"wallet" json:"wallet"`
+	Contract  string                 `toml:"contract" json:"contract"`
+	ABI       string                 `toml:"abi" json:"abi"`
+	Gas       string                 `toml:"gas" json:"gas"`
+	Fee       string                 `toml:"fee" json:"fee"`
+	Amount    string                 `toml:"amount" json:"amount"` // Value sent with the call
+	DependsOn []string               `toml:"depends_on" json:"depends_on"`
+}
+
+// ParseFile loads a batch file, fills in defaults and orders the calls so
+// that every call comes after the calls it depends on
+func ParseFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+
+	var f File
+	switch ext := filepath.Ext(path); ext {
+	case ".toml":
+		if err := toml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML batch: %w", err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON batch: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported batch format: %s (use .toml or .json)", ext)
+	}
+
+	if err := f.normalize(); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// normalize validates the batch, applies file-level defaults and sorts the
+// calls in dependency order
+func (f *File) normalize() error {
+	if len(f.Calls) == 0 {
+		return fmt.Errorf("batch must contain at least one call")
+	}
+
+	switch f.OnFailure {
+	case "":
+		f.OnFailure = PolicyStop
+	case PolicyStop, PolicyContinue:
+	default:
+		return fmt.Errorf("unknown on_failure policy '%s' (valid: stop, continue)", f.OnFailure)
+	}
+
+	index := make(map[string]int, len(f.Calls))
+	for i := range f.Calls {
+		entry := &f.Calls[i]
+		if entry.ID == "" {
+			entry.ID = fmt.Sprintf("call-%d", i+1)
+		}
+		if _, dup := index[entry.ID]; dup {
+			return fmt.Errorf("duplicate call id '%s'", entry.ID)
+		}
+		index[entry.ID] = i
+
+		if entry.Function == "" {
+			return fmt.Errorf("call '%s': function is required", entry.ID)
+		}
+		if entry.Contract == "" {
+			entry.Contract = f.Contract
+		}
+		if entry.Contract == "" {
+			return fmt.Errorf("call '%s': contract is required (set it on the call or at the top of the file)", entry.ID)
+		}
+		if entry.ABI == "" {
+			entry.ABI = f.ABI
+		}
+		if entry.Amount != "" {
+			if _, err := chain.ParseAmount(entry.Amount); err != nil {
+				return fmt.Errorf("call '%s': %w", entry.ID, err)
+			}
+		}
+		if entry.Wallet == "" {
+			entry.Wallet = f.Wallet
+		}
+	}
+
+	for _, entry := range f.Calls {
+		for _, dep := range entry.DependsOn {
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("call '%s' depends on unknown call '%s'", entry.ID, dep)
+			}
+		}
+	}
+
+	ordered, err := dependencyOrder(f.Calls, index)
+	if err != nil {
+		return err
+	}
+	f.Calls = ordered
+
+	return nil
+}
+
+// dependencyOrder sorts calls so dependencies come first, keeping file order otherwise
+func dependencyOrder(calls []Entry, index map[string]int) ([]Entry, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(calls))
+	ordered := make([]Entry, 0, len(calls))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %v", append(path, calls[i].ID))
+		}
+
+		state[i] = visiting
+		for _, dep := range calls[i].DependsOn {
+			if err := visit(index[dep], append(path, calls[i].ID)); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		ordered = append(ordered, calls[i])
+		return nil
+	}
+
+	for i := range calls {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
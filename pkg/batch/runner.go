@@ -0,0 +1,337 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/tickets"
+)
+
+// Status is the outcome of a call in a batch
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// WalletResolver turns wallet names or seeds into seeds and addresses
+type WalletResolver interface {
+	ResolveWallet(walletInput string) (string, error)
+	ResolveAddress(walletInput, algorithm string) (string, error)
+}
+
+// Options configures a batch run
+type Options struct {
+	Network     config.NetworkConfig
+	Algorithm   string
+	Concurrency int    // Calls in flight at once; above 1 each wallet uses Tickets
+	OnFailure   string // Overrides the file's policy when set
+	Verbose     bool
+}
+
+// Result is the outcome of one call, flattened for export
+type Result struct {
+	ID                string `json:"id"`
+	Function          string `json:"function"`
+	Contract          string `json:"contract"`
+	Account           string `json:"account,omitempty"`
+	Status            Status `json:"status"`
+	TxHash            string `json:"txHash,omitempty"`
+	TransactionResult string `json:"transactionResult,omitempty"`
+	ReturnCode        int    `json:"returnCode"`
+	ReturnValue       string `json:"returnValue,omitempty"`
+	GasUsed           int64  `json:"gasUsed"`
+	DurationMs        int64  `json:"durationMs"`
+	Error             string `json:"error,omitempty"`
+}
+
+// Report contains the outcome of a full batch run in dependency order
+type Report struct {
+	Results   []Result      `json:"results"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Duration  time.Duration `json:"-"`
+}
+
+// Success reports whether every call succeeded
+func (r *Report) Success() bool {
+	return r.Failed == 0 && r.Skipped == 0
+}
+
+// Runner executes batch files
+type Runner struct {
+	caller   *caller.Caller
+	wallets  WalletResolver
+	opts     Options
+	seeds    map[string]string
+	accounts map[string]string
+	managers map[string]*tickets.Manager
+
+	// OnResult is called as each call finishes or is skipped
+	OnResult func(done, total int, result Result)
+}
+
+// NewRunner creates a batch runner
+func NewRunner(wallets WalletResolver, opts Options) (*Runner, error) {
+	c, err := caller.NewWorkerCaller(opts.Verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create caller: %w", err)
+	}
+
+	if opts.Algorithm == "" {
+		opts.Algorithm = "secp256k1"
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	return &Runner{
+		caller:   c,
+		wallets:  wallets,
+		opts:     opts,
+		seeds:    make(map[string]string),
+		accounts: make(map[string]string),
+		managers: make(map[string]*tickets.Manager),
+	}, nil
+}
+
+// Run executes every call of the batch. Calls start once all the calls
+// they depend on succeeded; with a concurrency above 1 independent calls
+// run in parallel, each wallet drawing Tickets so its Sequence never
+// collides. Wallets are resolved up front so a bad name fails the whole
+// batch before anything is submitted.
+func (r *Runner) Run(ctx context.Context, f *File) (*Report, error) {
+	startTime := time.Now()
+
+	policy := f.OnFailure
+	if r.opts.OnFailure != "" {
+		policy = r.opts.OnFailure
+	}
+	if policy != PolicyStop && policy != PolicyContinue {
+		return nil, fmt.Errorf("unknown failure policy '%s' (valid: stop, continue)", policy)
+	}
+
+	if err := r.resolveWallets(f); err != nil {
+		return nil, err
+	}
+	if err := r.createTicketManagers(); err != nil {
+		return nil, err
+	}
+	defer r.closeTicketManagers(ctx)
+	defer r.caller.Close()
+
+	total := len(f.Calls)
+	results := make([]Result, total)
+	status := make(map[string]Status, total)
+	started := make([]bool, total)
+	done := make(chan int)
+
+	report := &Report{}
+	finished, running := 0, 0
+	stopped := false
+
+	record := func(i int) {
+		finished++
+		status[f.Calls[i].ID] = results[i].Status
+		switch results[i].Status {
+		case StatusSuccess:
+			report.Succeeded++
+		case StatusFailed:
+			report.Failed++
+		case StatusSkipped:
+			report.Skipped++
+		}
+		if r.OnResult != nil {
+			r.OnResult(finished, total, results[i])
+		}
+	}
+
+	skip := func(i int, reason string) {
+		started[i] = true
+		results[i] = r.newResult(f.Calls[i])
+		results[i].Status = StatusSkipped
+		results[i].Error = reason
+		record(i)
+	}
+
+	for finished < total {
+		if ctx.Err() != nil {
+			stopped = true
+		}
+
+		// Dispatch every call whose dependencies are settled, in order
+		for i, entry := range f.Calls {
+			if started[i] {
+				continue
+			}
+			if stopped {
+				if running == 0 {
+					skip(i, "batch stopped after a failure")
+				}
+				continue
+			}
+
+			ready, blocked := true, ""
+			for _, dep := range entry.DependsOn {
+				switch status[dep] {
+				case StatusSuccess:
+				case StatusFailed, StatusSkipped:
+					blocked = dep
+				default:
+					ready = false
+				}
+			}
+			if blocked != "" {
+				skip(i, fmt.Sprintf("dependency '%s' did not succeed", blocked))
+				continue
+			}
+			if !ready || running >= r.opts.Concurrency {
+				continue
+			}
+
+			started[i] = true
+			running++
+			go func(i int) {
+				results[i] = r.execute(ctx, f.Calls[i])
+				done <- i
+			}(i)
+		}
+
+		if running == 0 {
+			continue
+		}
+
+		i := <-done
+		running--
+		record(i)
+		if results[i].Status == StatusFailed && policy == PolicyStop {
+			stopped = true
+		}
+	}
+
+	report.Results = results
+	report.Duration = time.Since(startTime)
+	return report, nil
+}
+
+// execute runs a single call and flattens its outcome
+func (r *Runner) execute(ctx context.Context, entry Entry) Result {
+	startTime := time.Now()
+	result := r.newResult(entry)
+
+	callCfg := caller.CallConfig{
+		ContractAccount:      entry.Contract,
+		FunctionName:         entry.Function,
+		Network:              r.opts.Network,
+		WalletSeed:           r.seeds[entry.Wallet],
+		Algorithm:            r.opts.Algorithm,
+		ABIPath:              entry.ABI,
+		Parameters:           entry.Params,
+		Amount:               entry.Amount,
+		ComputationAllowance: entry.Gas,
+		Fee:                  entry.Fee,
+	}
+	if callCfg.ABIPath == "" {
+		callCfg.ABIPath = "abi.json"
+	}
+	if callCfg.ComputationAllowance == "" {
+		callCfg.ComputationAllowance = caller.AllowanceAuto
+	}
+	if callCfg.Fee == "" {
+		callCfg.Fee = chain.FeeAuto
+	}
+
+	callResult, err := r.caller.CallWithTicket(ctx, r.managers[callCfg.WalletSeed], callCfg)
+	result.DurationMs = time.Since(startTime).Milliseconds()
+
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	result.TxHash = callResult.TxHash
+	result.TransactionResult = callResult.TransactionResult
+	result.ReturnCode = callResult.ReturnCode
+	result.ReturnValue = callResult.ReturnValue
+	result.GasUsed = callResult.GasUsed
+
+	switch {
+	case callResult.TransactionResult != "" && callResult.TransactionResult != "tesSUCCESS":
+		result.Status = StatusFailed
+		result.Error = fmt.Sprintf("transaction failed: %s", callResult.TransactionResult)
+	case callResult.ReturnCode != 0:
+		result.Status = StatusFailed
+		result.Error = fmt.Sprintf("contract returned code %d", callResult.ReturnCode)
+	default:
+		result.Status = StatusSuccess
+	}
+
+	return result
+}
+
+func (r *Runner) newResult(entry Entry) Result {
+	return Result{
+		ID:       entry.ID,
+		Function: entry.Function,
+		Contract: entry.Contract,
+		Account:  r.accounts[entry.Wallet],
+	}
+}
+
+// resolveWallets resolves every wallet used by the batch
+func (r *Runner) resolveWallets(f *File) error {
+	for _, entry := range f.Calls {
+		if entry.Wallet == "" {
+			return fmt.Errorf("call '%s': wallet is required (set it on the call, at the top of the file or with --wallet)", entry.ID)
+		}
+		if _, ok := r.seeds[entry.Wallet]; ok {
+			continue
+		}
+
+		seed, err := r.wallets.ResolveWallet(entry.Wallet)
+		if err != nil {
+			return fmt.Errorf("call '%s': failed to resolve wallet: %w", entry.ID, err)
+		}
+		r.seeds[entry.Wallet] = seed
+
+		if address, err := r.wallets.ResolveAddress(seed, r.opts.Algorithm); err == nil {
+			r.accounts[entry.Wallet] = address
+		}
+	}
+	return nil
+}
+
+// createTicketManagers sets up one ticket manager per signing seed when
+// calls run in parallel
+func (r *Runner) createTicketManagers() error {
+	if r.opts.Concurrency <= 1 {
+		return nil
+	}
+
+	for _, seed := range r.seeds {
+		if _, ok := r.managers[seed]; ok {
+			continue
+		}
+		mgr, err := tickets.NewManager(r.opts.Network, seed, r.opts.Concurrency*2)
+		if err != nil {
+			return err
+		}
+		r.managers[seed] = mgr
+	}
+	return nil
+}
+
+// closeTicketManagers cleans up Tickets left unused
+func (r *Runner) closeTicketManagers(ctx context.Context) {
+	for _, mgr := range r.managers {
+		mgr.Close(ctx)
+	}
+}
@@ -0,0 +1,30 @@
+package caller
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AllowanceAuto is the computation allowance value that requests an estimate
+const AllowanceAuto = "auto"
+
+// AllowanceMargin is the headroom applied on top of measured gas usage
+const AllowanceMargin = 1.25
+
+// DefaultAllowance is used when the gas usage cannot be estimated
+const DefaultAllowance = 1000000
+
+// ResolveAllowance turns a computation allowance flag value into a number.
+// Explicit values are returned unchanged. For "auto" or an empty value, the
+// previous gas usage (e.g. from the gas snapshot) plus a margin is used when
+// known; otherwise "auto" is kept so call.js estimates it by simulation.
+func ResolveAllowance(allowance string, previousGasUsed int64) string {
+	if allowance != "" && !strings.EqualFold(allowance, AllowanceAuto) {
+		return allowance
+	}
+	if previousGasUsed > 0 {
+		return strconv.FormatInt(int64(math.Ceil(float64(previousGasUsed)*AllowanceMargin)), 10)
+	}
+	return AllowanceAuto
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/xrpl-commons/bedrock/pkg/adapter"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/tickets"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
 )
 
 // Caller handles contract function calls via embedded Node.js module
@@ -27,14 +32,76 @@ func NewCaller(verbose bool) (*Caller, error) {
 	}, nil
 }
 
+// NewWorkerCaller creates a caller that runs every call in one persistent
+// Node process, keeping its XRPL connection open between calls. Use it for
+// long runs of calls and Close it when done.
+func NewWorkerCaller(verbose bool) (*Caller, error) {
+	executor, err := adapter.NewWorkerExecutor(verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	return &Caller{
+		executor: executor,
+		verbose:  verbose,
+	}, nil
+}
+
+// Close releases the caller's Node worker, if it has one
+func (c *Caller) Close() error {
+	return c.executor.Close()
+}
+
+// Simulate runs a contract function against a scratch copy of the open
+// ledger and returns its return code, return value, gas and metadata
+// without committing anything. Only the caller's address is needed, so
+// config.Account may be set instead of config.WalletSeed.
+func (c *Caller) Simulate(ctx context.Context, config CallConfig) (*CallResult, error) {
+	config.Simulate = true
+	config.Unsigned = false
+	return c.Call(ctx, config)
+}
+
+// CallWithTicket invokes a contract function using a Ticket from mgr, so
+// calls from the same wallet can run in parallel. The Ticket is released
+// for reuse unless the call consumed it. A nil mgr makes a plain Call.
+func (c *Caller) CallWithTicket(ctx context.Context, mgr *tickets.Manager, config CallConfig) (*CallResult, error) {
+	if mgr == nil {
+		return c.Call(ctx, config)
+	}
+
+	ticket, err := mgr.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config.TicketSequence = ticket
+
+	result, err := c.Call(ctx, config)
+	mgr.Release(ticket, tickets.Reusable(err))
+	return result, err
+}
+
 // Call invokes a contract function
 func (c *Caller) Call(ctx context.Context, config CallConfig) (*CallResult, error) {
+	fee, err := chain.ResolveFee(ctx, config.Network, config.Fee, "ContractCall")
+	if err != nil {
+		return nil, err
+	}
+
+	var amount *chain.Amount
+	if config.Amount != "" {
+		amount, err = chain.ParseAmount(config.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Build JSON config for call.js module
 	jsConfig := map[string]interface{}{
 		"contract_account": config.ContractAccount,
 		"function_name":    config.FunctionName,
-		"network_url":      config.NetworkURL,
-		"network_id":       config.NetworkID,
+		"network_url":      config.Network.URL,
+		"network_id":       config.Network.NetworkID,
 		"wallet_seed":      config.WalletSeed,
 		"algorithm":        config.Algorithm,
 		"verbose":          c.verbose,
@@ -48,25 +115,151 @@ func (c *Caller) Call(ctx context.Context, config CallConfig) (*CallResult, erro
 		jsConfig["parameters"] = config.Parameters
 	}
 
-	if config.ComputationAllowance != "" {
-		jsConfig["computation_allowance"] = config.ComputationAllowance
+	jsConfig["computation_allowance"] = ResolveAllowance(config.ComputationAllowance, config.GasHint)
+	jsConfig["allowance_margin"] = AllowanceMargin
+	jsConfig["fee"] = fee
+
+	if amount != nil {
+		jsConfig["amount"] = amount.TxJSON()
+	}
+
+	if config.TicketSequence != 0 {
+		jsConfig["ticket_sequence"] = config.TicketSequence
 	}
 
-	if config.Fee != "" {
-		jsConfig["fee"] = config.Fee
+	if config.Unsigned {
+		jsConfig["unsigned"] = true
+		jsConfig["account"] = config.Account
 	}
 
-	// Execute call.js module
-	result, err := c.executor.ExecuteModule(ctx, "call.js", jsConfig)
+	if config.Simulate {
+		jsConfig["simulate"] = true
+		if config.Account != "" {
+			jsConfig["account"] = config.Account
+		}
+	}
+
+	if config.Unsigned || config.Simulate {
+		// Execute call.js module
+		result, err := c.executor.ExecuteModule(ctx, "call.js", jsConfig, nil)
+		if err != nil {
+			return nil, fmt.Errorf("contract call failed: %w", err)
+		}
+
+		// Parse call result from module output
+		var callResult CallResult
+		if err := json.Unmarshal(result.Data, &callResult); err != nil {
+			return nil, fmt.Errorf("failed to parse call result: %w", err)
+		}
+		callResult.ContractAccount = config.ContractAccount
+		if amount != nil {
+			callResult.Amount = amount.String()
+		}
+
+		return &callResult, nil
+	}
+
+	// call.js signs offline; the submission engine submits and tracks the
+	// transaction. A validated tec result is returned rather than treated as
+	// an error so tests can assert on expected failures.
+	xw, err := wallet.NewXRPLWallet()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := xw.SeedToAddressWithAlgorithm(config.WalletSeed, config.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if jsConfig["computation_allowance"] == AllowanceAuto {
+		allowance, err := c.estimateAllowance(ctx, config, signer, jsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("contract call failed: %w", err)
+		}
+		jsConfig["computation_allowance"] = strconv.FormatInt(allowance, 10)
+	}
+
+	_, submission, err := c.executor.ExecuteAndSubmit(ctx, "call.js", config.Network, signer, jsConfig, nil)
 	if err != nil {
 		return nil, fmt.Errorf("contract call failed: %w", err)
 	}
 
-	// Parse call result from module output
-	var callResult CallResult
-	if err := json.Unmarshal(result.Data, &callResult); err != nil {
-		return nil, fmt.Errorf("failed to parse call result: %w", err)
+	callResult := resultFromSubmission(submission)
+	callResult.ContractAccount = config.ContractAccount
+	if amount != nil {
+		callResult.Amount = amount.String()
+	}
+	return callResult, nil
+}
+
+// estimateAllowance builds the call offline and simulates it through the Go
+// client, as call.js does over its own connection for unsigned calls. The
+// default allowance is used when the node cannot simulate the call.
+func (c *Caller) estimateAllowance(ctx context.Context, config CallConfig, signer string, jsConfig map[string]interface{}) (int64, error) {
+	client := chain.NewNetworkClient(config.Network)
+
+	build := make(map[string]interface{}, len(jsConfig)+2)
+	for k, v := range jsConfig {
+		build[k] = v
+	}
+	build["unsigned"] = true
+	build["account"] = signer
+	build["computation_allowance"] = strconv.Itoa(DefaultAllowance)
+	if err := adapter.Autofill(ctx, client, signer, build); err != nil {
+		return 0, err
+	}
+
+	result, err := c.executor.ExecuteModule(ctx, "call.js", build, nil)
+	if err != nil {
+		return 0, err
+	}
+	var output struct {
+		UnsignedTx map[string]interface{} `json:"unsignedTx"`
+	}
+	if err := json.Unmarshal(result.Data, &output); err != nil || output.UnsignedTx == nil {
+		return 0, fmt.Errorf("call.js did not return the transaction to simulate")
+	}
+
+	output.UnsignedTx["ComputationAllowance"] = DefaultAllowance * 10
+	sim, err := client.Simulate(ctx, output.UnsignedTx)
+	if err != nil {
+		return DefaultAllowance, nil
+	}
+	if gas := gasUsed(sim.Meta); gas > 0 {
+		return int64(math.Ceil(float64(gas) * AllowanceMargin)), nil
+	}
+	return DefaultAllowance, nil
+}
+
+// resultFromSubmission extracts the contract outcome from a validated call
+func resultFromSubmission(submission *chain.SubmissionResult) *CallResult {
+	result := &CallResult{
+		TxHash:            submission.Hash,
+		Validated:         submission.Validated,
+		TransactionResult: submission.TransactionResult,
+		Meta:              submission.Meta,
+		Submission:        submission,
+	}
+
+	if code, ok := submission.Meta["WasmReturnCode"].(float64); ok {
+		result.ReturnCode = int(code)
 	}
+	if value, ok := submission.Meta["ReturnValue"].(string); ok {
+		result.ReturnValue = value
+	}
+	result.GasUsed = gasUsed(submission.Meta)
+
+	return result
+}
 
-	return &callResult, nil
+// gasUsed reads GasUsed from transaction metadata, which may hold a number or a string
+func gasUsed(meta map[string]interface{}) int64 {
+	switch gas := meta["GasUsed"].(type) {
+	case float64:
+		return int64(gas)
+	case string:
+		n, _ := strconv.ParseInt(gas, 10, 64)
+		return n
+	}
+	return 0
 }
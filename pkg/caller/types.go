@@ -1,26 +1,41 @@
 package caller
 
+import (
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
 // CallResult represents the result of a contract call
 type CallResult struct {
-	TxHash            string                 `json:"txHash"`
-	ReturnCode        int                    `json:"returnCode"`
-	ReturnValue       string                 `json:"returnValue"`
-	GasUsed           int64                  `json:"gasUsed"`
-	Validated         bool                   `json:"validated"`
-	TransactionResult string                 `json:"transactionResult"`
-	Meta              map[string]interface{} `json:"meta"`
+	TxHash            string                  `json:"txHash"`
+	ReturnCode        int                     `json:"returnCode"`
+	ReturnValue       string                  `json:"returnValue"`
+	GasUsed           int64                   `json:"gasUsed"`
+	Validated         bool                    `json:"validated"`
+	TransactionResult string                  `json:"transactionResult"`
+	Meta              map[string]interface{}  `json:"meta"`
+	UnsignedTx        map[string]interface{}  `json:"unsignedTx,omitempty"`
+	Submission        *chain.SubmissionResult `json:"submission,omitempty"`
+	Simulated         bool                    `json:"simulated,omitempty"` // Result of a simulation; nothing was committed
+	ContractAccount   string                  `json:"contractAccount,omitempty"`
+	Amount            string                  `json:"amount,omitempty"` // Value sent with the call, as given to ParseAmount
 }
 
 // CallConfig holds configuration for calling a contract function
 type CallConfig struct {
 	ContractAccount      string
 	FunctionName         string
-	NetworkURL           string
-	NetworkID            uint32
+	Network              config.NetworkConfig
 	WalletSeed           string
 	Algorithm            string
 	ABIPath              string
 	Parameters           map[string]interface{} // JSON parameters
-	ComputationAllowance string
-	Fee                  string
+	ComputationAllowance string                 // Number or "auto"
+	Fee                  string                 // Drops or "auto"
+	GasHint              int64                  // Previously measured gas usage, used for an "auto" allowance
+	Unsigned             bool                   // Build the autofilled transaction without signing or submitting
+	Account              string                 // Signing account address, required when Unsigned
+	TicketSequence       uint32                 // Use this Ticket instead of the account Sequence
+	Simulate             bool                   // Run through the simulate RPC without submitting
+	Amount               string                 // Optional value to send: drops, value/CUR/issuer or value/mpt-issuance-id
 }
@@ -74,34 +74,26 @@ func (c *Client) GetAccountBalance(ctx context.Context, address string) (string,
 	return DropsToXRP(info.AccountData.Balance), nil
 }
 
-// GetAccountObjects retrieves objects owned by an account
+// GetAccountObjects retrieves all objects owned by an account, following
+// pagination markers
 func (c *Client) GetAccountObjects(ctx context.Context, address string) (*AccountObjects, error) {
-	params := map[string]interface{}{
-		"account":      address,
-		"ledger_index": "current",
-	}
-
-	var result AccountObjects
-	if err := c.CallTyped(ctx, &result, "account_objects", params); err != nil {
-		return nil, fmt.Errorf("account_objects failed: %w", err)
+	objects, err := c.AccountObjectsIterator(address, "", PageOpts{}).All(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return &result, nil
+	return &AccountObjects{Account: address, Objects: objects}, nil
 }
 
-// GetAccountLines retrieves trust lines for an account
+// GetAccountLines retrieves all trust lines for an account, following
+// pagination markers
 func (c *Client) GetAccountLines(ctx context.Context, address string) (*AccountLines, error) {
-	params := map[string]interface{}{
-		"account":      address,
-		"ledger_index": "current",
-	}
-
-	var result AccountLines
-	if err := c.CallTyped(ctx, &result, "account_lines", params); err != nil {
-		return nil, fmt.Errorf("account_lines failed: %w", err)
+	lines, err := c.AccountLinesIterator(address, PageOpts{}).All(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return &result, nil
+	return &AccountLines{Account: address, Lines: lines}, nil
 }
 
 // DropsToXRP converts drops (smallest XRP unit) to XRP
@@ -0,0 +1,144 @@
+package chain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	addresscodec "github.com/Peersyst/xrpl-go/address-codec"
+)
+
+// maxDrops is the total XRP supply in drops
+const maxDrops = 100_000_000_000_000_000
+
+// Amount is a value sent with a transaction: XRP drops, an issued
+// currency (IOU) or a multi-purpose token (MPT)
+type Amount struct {
+	Value         string // Drops for XRP, decimal value otherwise
+	Currency      string // "XRP", a currency code, or empty for MPT
+	Issuer        string // IOU issuer
+	MPTIssuanceID string // MPT issuance ID
+}
+
+// ParseAmount parses an amount given on the command line:
+//
+//	1000000                 XRP in drops
+//	100/USD/rIssuer...      an issued currency
+//	100/<issuance-id>       an MPT (48 hex characters)
+func ParseAmount(s string) (*Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("amount cannot be empty")
+	}
+
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 1:
+		drops, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || drops == 0 {
+			return nil, fmt.Errorf("invalid XRP amount %q: must be a positive whole number of drops", s)
+		}
+		if drops > maxDrops {
+			return nil, fmt.Errorf("invalid XRP amount %q: exceeds the XRP supply", s)
+		}
+		return &Amount{Value: s, Currency: "XRP"}, nil
+
+	case 2:
+		value, id := parts[0], strings.ToUpper(parts[1])
+		if n, err := strconv.ParseUint(value, 10, 64); err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid MPT amount %q: must be a positive whole number", value)
+		}
+		if len(id) != 48 || !isHex(id) {
+			return nil, fmt.Errorf("invalid MPT issuance ID %q: expected 48 hex characters", parts[1])
+		}
+		return &Amount{Value: value, MPTIssuanceID: id}, nil
+
+	case 3:
+		value, currency, issuer := parts[0], parts[1], parts[2]
+		if err := validateTokenValue(value); err != nil {
+			return nil, err
+		}
+		if err := validateCurrency(currency); err != nil {
+			return nil, err
+		}
+		if !addresscodec.IsValidClassicAddress(issuer) {
+			return nil, fmt.Errorf("invalid issuer %q: expected a classic address", issuer)
+		}
+		return &Amount{Value: value, Currency: currency, Issuer: issuer}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid amount %q (use drops, value/CURRENCY/issuer or value/mpt-issuance-id)", s)
+	}
+}
+
+// IsXRP reports whether the amount is in XRP drops
+func (a *Amount) IsXRP() bool {
+	return a.Currency == "XRP"
+}
+
+// IsMPT reports whether the amount is a multi-purpose token
+func (a *Amount) IsMPT() bool {
+	return a.MPTIssuanceID != ""
+}
+
+// TxJSON returns the amount in transaction JSON form
+func (a *Amount) TxJSON() interface{} {
+	switch {
+	case a.IsXRP():
+		return a.Value
+	case a.IsMPT():
+		return map[string]interface{}{
+			"mpt_issuance_id": a.MPTIssuanceID,
+			"value":           a.Value,
+		}
+	default:
+		return map[string]interface{}{
+			"currency": a.Currency,
+			"issuer":   a.Issuer,
+			"value":    a.Value,
+		}
+	}
+}
+
+// String returns the amount in the form accepted by ParseAmount
+func (a *Amount) String() string {
+	switch {
+	case a.IsXRP():
+		return a.Value
+	case a.IsMPT():
+		return a.Value + "/" + a.MPTIssuanceID
+	default:
+		return a.Value + "/" + a.Currency + "/" + a.Issuer
+	}
+}
+
+func validateTokenValue(value string) error {
+	f, _, err := big.ParseFloat(value, 10, 64, big.ToNearestEven)
+	if err != nil {
+		return fmt.Errorf("invalid amount value %q: %w", value, err)
+	}
+	if f.Sign() <= 0 {
+		return fmt.Errorf("invalid amount value %q: must be positive", value)
+	}
+	return nil
+}
+
+func validateCurrency(currency string) error {
+	switch {
+	case strings.EqualFold(currency, "XRP"):
+		return fmt.Errorf("XRP amounts are given in drops without a currency")
+	case len(currency) == 3:
+		return nil
+	case len(currency) == 40 && isHex(currency):
+		return nil
+	default:
+		return fmt.Errorf("invalid currency %q: expected a 3-character code or 40 hex characters", currency)
+	}
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,365 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables that route every Client through a cassette. The
+// value is a cassette file, or a name stored as testdata/<name>.json.
+const (
+	RecordEnv = "BEDROCK_RPC_RECORD"
+	ReplayEnv = "BEDROCK_RPC_REPLAY"
+)
+
+const cassetteFormat = 1
+
+// Cassette is a recorded sequence of RPC requests and responses
+type Cassette struct {
+	Format       int           `json:"format"`
+	RecordedAt   time.Time     `json:"recorded_at"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Endpoint string          `json:"endpoint"`
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params,omitempty"`
+	Status   int             `json:"status"`
+	Header   http.Header     `json:"header,omitempty"`
+	Response json.RawMessage `json:"response"`
+}
+
+// volatileParams are request parameters that differ between runs of the
+// same flow and are ignored when matching. Signed blobs change with the
+// sequence and fee, and transaction hashes change with the blob, so a
+// replayed deploy or call matches the submission it recorded.
+var volatileParams = map[string][]string{
+	"submit":             {"tx_blob"},
+	"submit_multisigned": {"tx_json"},
+	"simulate":           {"tx_blob", "tx_json"},
+	"tx":                 {"transaction"},
+}
+
+// UnmatchedRequestError is returned in replay mode for a request the
+// cassette has no response for
+type UnmatchedRequestError struct {
+	Method   string
+	Params   string
+	Cassette string
+}
+
+func (e *UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("no recorded response for %s %s in cassette %s", e.Method, e.Params, e.Cassette)
+}
+
+// CassettePath resolves a cassette name: names without a directory or
+// extension are stored in testdata/
+func CassettePath(name string) string {
+	if filepath.Dir(name) == "." && filepath.Ext(name) == "" {
+		return filepath.Join("testdata", name+".json")
+	}
+	return name
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Format != cassetteFormat {
+		return nil, fmt.Errorf("cassette %s has unsupported format %d", path, c.Format)
+	}
+	return &c, nil
+}
+
+// Save writes a cassette file, creating its directory
+func (c *Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write then rename so an interrupted run never leaves half a cassette
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// rpcBody is the part of a JSON-RPC request used for matching
+type rpcBody struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func parseRPCBody(body []byte) (method string, params json.RawMessage) {
+	var req rpcBody
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil
+	}
+	if len(req.Params) == 0 {
+		return req.Method, nil
+	}
+	params, _ = json.Marshal(req.Params)
+	return req.Method, params
+}
+
+// matchKey is the canonical form of a request: its method and params with
+// keys sorted and volatile fields removed
+func matchKey(method string, params json.RawMessage) string {
+	if len(params) == 0 {
+		return method
+	}
+
+	var list []interface{}
+	if err := json.Unmarshal(params, &list); err != nil {
+		return method + " " + string(params)
+	}
+	for _, p := range list {
+		obj, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range volatileParams[method] {
+			delete(obj, key)
+		}
+	}
+	canonical, _ := json.Marshal(list)
+	return method + " " + string(canonical)
+}
+
+// Recorder is an http.RoundTripper that forwards requests and appends each
+// request and response to a cassette
+type Recorder struct {
+	Path      string
+	Transport http.RoundTripper // Default: http.DefaultTransport
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewRecorder creates a recorder writing to path. An existing cassette is
+// replaced.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{
+		Path:     path,
+		cassette: &Cassette{Format: cassetteFormat, RecordedAt: time.Now().UTC()},
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	method, params := parseRPCBody(body)
+	interaction := Interaction{
+		Endpoint: req.URL.String(),
+		Method:   method,
+		Params:   params,
+		Status:   resp.StatusCode,
+		Response: rawJSON(respBody),
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		interaction.Header = http.Header{"Retry-After": {retryAfter}}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	// Saved after every request since CLI runs can end at any point
+	if err := r.cassette.Save(r.Path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+// without touching the network. Requests are matched on method and
+// params; identical requests get the recorded responses in order, and the
+// last one again once they run out, so status polling replays as recorded.
+type Replayer struct {
+	Path string
+
+	mu      sync.Mutex
+	entries map[string][]Interaction
+	used    map[string]int
+}
+
+// NewReplayer loads a cassette for replay
+func NewReplayer(path string) (*Replayer, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replayer{
+		Path:    path,
+		entries: make(map[string][]Interaction),
+		used:    make(map[string]int),
+	}
+	for _, interaction := range cassette.Interactions {
+		key := matchKey(interaction.Method, interaction.Params)
+		r.entries[key] = append(r.entries[key], interaction)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	method, params := parseRPCBody(body)
+	key := matchKey(method, params)
+
+	r.mu.Lock()
+	recorded := r.entries[key]
+	if len(recorded) == 0 {
+		r.mu.Unlock()
+		return nil, &UnmatchedRequestError{Method: method, Params: strings.TrimPrefix(key, method+" "), Cassette: r.Path}
+	}
+	n := r.used[key]
+	if n >= len(recorded) {
+		n = len(recorded) - 1
+	}
+	r.used[key]++
+	interaction := recorded[n]
+	r.mu.Unlock()
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	for k, v := range interaction.Header {
+		header[k] = v
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(interaction.Response)),
+		ContentLength: int64(len(interaction.Response)),
+		Request:       req,
+	}, nil
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// rawJSON keeps JSON bodies as they are and stores anything else as a string
+func rawJSON(body []byte) json.RawMessage {
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// cassetteError is a cassette setup problem, reported on every request
+type cassetteError struct{ err error }
+
+func (e *cassetteError) Error() string { return e.err.Error() }
+func (e *cassetteError) Unwrap() error { return e.err }
+
+// failingTransport fails every request with a cassette setup error
+type failingTransport struct{ err error }
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, &cassetteError{t.err}
+}
+
+var (
+	envTransportOnce sync.Once
+	envTransport     http.RoundTripper
+)
+
+// EnvTransport returns the recording or replaying transport selected by
+// BEDROCK_RPC_RECORD or BEDROCK_RPC_REPLAY, or nil when neither is set.
+// Every client in the process shares it, so a single cassette covers a
+// whole command.
+func EnvTransport() http.RoundTripper {
+	envTransportOnce.Do(func() {
+		record, replay := os.Getenv(RecordEnv), os.Getenv(ReplayEnv)
+		switch {
+		case record != "" && replay != "":
+			envTransport = failingTransport{fmt.Errorf("%s and %s cannot both be set", RecordEnv, ReplayEnv)}
+		case record != "":
+			envTransport = NewRecorder(CassettePath(record))
+		case replay != "":
+			replayer, err := NewReplayer(CassettePath(replay))
+			if err != nil {
+				envTransport = failingTransport{err}
+				return
+			}
+			envTransport = replayer
+		}
+	})
+	return envTransport
+}
+
+// Replaying reports whether BEDROCK_RPC_REPLAY is set. Only requests made
+// through Client are replayed; callers that talk to the node another way
+// should refuse to run.
+func Replaying() bool {
+	return os.Getenv(ReplayEnv) != ""
+}
+
+// isCassetteError reports errors that retrying cannot fix
+func isCassetteError(err error) bool {
+	var unmatched *UnmatchedRequestError
+	if errors.As(err, &unmatched) {
+		return true
+	}
+	var setup *cassetteError
+	return errors.As(err, &setup)
+}
@@ -0,0 +1,121 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/chain/mockserver"
+)
+
+func replayClient(t *testing.T, path string) *chain.Client {
+	t.Helper()
+	replayer, err := chain.NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain.NewClientWithOptions(chain.ClientOptions{
+		Endpoints: []string{"http://replay.invalid"},
+		Transport: replayer,
+	})
+}
+
+func TestRecordReplay(t *testing.T) {
+	srv := mockserver.New()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recording := chain.NewClientWithOptions(chain.ClientOptions{
+		Endpoints:  []string{srv.URL},
+		MaxRetries: -1,
+		Transport:  chain.NewRecorder(path),
+	})
+
+	ctx := context.Background()
+	info, err := recording.GetAccountInfo(ctx, mockserver.GenesisAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ledgers []int64
+	for i := 0; i < 2; i++ {
+		index, err := recording.ValidatedLedgerIndex(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ledgers = append(ledgers, index)
+		srv.Accept()
+	}
+	srv.Close()
+	if ledgers[0] == ledgers[1] {
+		t.Fatalf("recorded the same ledger twice: %v", ledgers)
+	}
+
+	client := replayClient(t, path)
+	replayed, err := client.GetAccountInfo(ctx, mockserver.GenesisAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.AccountData.Balance != info.AccountData.Balance {
+		t.Errorf("replayed balance = %s, want %s", replayed.AccountData.Balance, info.AccountData.Balance)
+	}
+
+	// Repeated requests get the recorded responses in order, then the last
+	want := []int64{ledgers[0], ledgers[1], ledgers[1]}
+	for i, w := range want {
+		index, err := client.ValidatedLedgerIndex(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index != w {
+			t.Errorf("ledger request %d = %d, want %d", i+1, index, w)
+		}
+	}
+}
+
+func TestReplayMatching(t *testing.T) {
+	// Params are stored with keys out of order, as another recorder might
+	cassette := `{"format": 1, "interactions": [
+		{"method": "submit", "params": [{"tx_blob": "AAAA"}], "status": 200,
+		 "response": {"result": {"engine_result": "tesSUCCESS", "tx_json": {"hash": "H1"}}}},
+		{"method": "tx", "params": [{"transaction": "H1", "binary": false}], "status": 200,
+		 "response": {"result": {"hash": "H1", "validated": true}}},
+		{"method": "account_info", "params": [{"ledger_index": "validated", "account": "rA"}], "status": 200,
+		 "response": {"result": {"account_data": {"Balance": "42"}}}}
+	]}`
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, []byte(cassette), 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := replayClient(t, path)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		method    string
+		params    map[string]interface{}
+		unmatched bool
+	}{
+		{name: "submit with another blob", method: "submit", params: map[string]interface{}{"tx_blob": "BBBB"}},
+		{name: "tx with another hash", method: "tx", params: map[string]interface{}{"transaction": "H2", "binary": false}},
+		{name: "params in another order", method: "account_info", params: map[string]interface{}{"account": "rA", "ledger_index": "validated"}},
+		{name: "tx with other options", method: "tx", params: map[string]interface{}{"transaction": "H1", "binary": true}, unmatched: true},
+		{name: "another account", method: "account_info", params: map[string]interface{}{"account": "rB", "ledger_index": "validated"}, unmatched: true},
+		{name: "unrecorded method", method: "server_info", params: map[string]interface{}{}, unmatched: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Call(ctx, tt.method, tt.params)
+			var unmatched *chain.UnmatchedRequestError
+			if tt.unmatched {
+				if !errors.As(err, &unmatched) || unmatched.Method != tt.method {
+					t.Errorf("error = %v, want an unmatched %s request", err, tt.method)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
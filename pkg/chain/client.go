@@ -4,17 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"time"
+	"sync"
+
+	"github.com/xrpl-commons/bedrock/pkg/config"
 )
 
-// Client is a Go-native XRPL JSON-RPC client
+// Client is a Go-native XRPL JSON-RPC client. It can spread requests over
+// several endpoints, failing over to the next healthy one when a server is
+// unreachable, and retries idempotent methods with exponential backoff.
 type Client struct {
-	httpURL    string
+	url        string
+	endpoints  []*endpoint
+	opts       ClientOptions
+	limiter    *rateLimiter
 	httpClient *http.Client
+
+	mu sync.Mutex // Guards endpoint health
 }
 
 // RPCRequest represents a JSON-RPC request
@@ -35,15 +45,49 @@ type RPCError struct {
 	Message string `json:"message"`
 }
 
+// LedgerError is an error status returned by an XRPL method, such as
+// entryNotFound or actNotFound
+type LedgerError struct {
+	Code    string
+	Message string
+}
+
+func (e *LedgerError) Error() string {
+	return fmt.Sprintf("RPC error: %s: %s", e.Code, e.Message)
+}
+
+// IsEntryNotFound reports whether err is the server saying a ledger entry
+// does not exist
+func IsEntryNotFound(err error) bool {
+	var ledgerErr *LedgerError
+	return errors.As(err, &ledgerErr) && ledgerErr.Code == "entryNotFound"
+}
+
 // NewClient creates a new XRPL RPC client from a WebSocket or HTTP URL
 func NewClient(url string) *Client {
-	httpURL := toHTTPURL(url)
-	return &Client{
-		httpURL: httpURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	return NewClientWithOptions(ClientOptions{Endpoints: []string{url}})
+}
+
+// NewNetworkClient creates a client for a configured network, using its
+// endpoints and [networks.<name>.rpc] settings
+func NewNetworkClient(network config.NetworkConfig) *Client {
+	rpc := network.RPC
+	retries := 0 // Client default
+	if rpc.Retries != nil {
+		retries = *rpc.Retries
+		if retries == 0 {
+			retries = -1
+		}
 	}
+	return NewClientWithOptions(ClientOptions{
+		Endpoints:     append([]string{network.URL}, network.Endpoints...),
+		Timeout:       rpc.TimeoutDuration(),
+		MaxRetries:    retries,
+		RetryBackoff:  rpc.RetryBackoffDuration(),
+		RateLimit:     rpc.RateLimit,
+		Burst:         rpc.Burst,
+		FailoverDelay: rpc.HealthCheckDuration(),
+	})
 }
 
 // Call sends a JSON-RPC request and returns the raw result
@@ -60,7 +104,62 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.httpURL, bytes.NewReader(body))
+	idempotent := isIdempotent(method)
+
+	var lastErr error
+	attempt, failovers := 0, 0
+	for {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		ep := c.pick(ctx)
+		result, err := c.do(ctx, ep, body)
+		if err == nil {
+			c.markUp(ep)
+			return result, nil
+		}
+		lastErr = err
+
+		var callErr *callError
+		if !errors.As(err, &callErr) || !callErr.retryable || ctx.Err() != nil {
+			return nil, err
+		}
+		// A request that may have reached the server is only resent for
+		// methods that are safe to repeat
+		if !idempotent && callErr.sent {
+			return nil, err
+		}
+
+		// Move to the next endpoint right away; retries on the same set of
+		// endpoints back off
+		if callErr.endpointDown {
+			c.markDown(ep)
+			if failovers < len(c.endpoints)-1 {
+				failovers++
+				continue
+			}
+		}
+
+		if attempt >= c.opts.MaxRetries {
+			return nil, lastErr
+		}
+		attempt++
+		if err := sleepCtx(ctx, c.backoff(attempt, lastErr)); err != nil {
+			return nil, lastErr
+		}
+	}
+}
+
+// do sends one request to one endpoint
+func (c *Client) do(ctx context.Context, ep *endpoint, body []byte) (json.RawMessage, error) {
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ep.httpURL, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -68,13 +167,21 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		if isCassetteError(err) {
+			return nil, err
+		}
+		return nil, &callError{
+			err:          fmt.Errorf("request failed: %w", err),
+			retryable:    true,
+			endpointDown: true,
+			sent:         !isDialError(err),
+		}
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, &callError{err: fmt.Errorf("failed to read response: %w", err), retryable: true, sent: true}
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -82,7 +189,15 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 		if len(snippet) > 200 {
 			snippet = snippet[:200]
 		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+		err := fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			// Throttled requests are rejected unprocessed, so any method may be resent
+			return nil, &callError{err: err, retryable: true, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		case resp.StatusCode >= 500:
+			return nil, &callError{err: err, retryable: true, endpointDown: true, sent: true}
+		}
+		return nil, err
 	}
 
 	var rpcResp RPCResponse
@@ -102,7 +217,11 @@ func (c *Client) Call(ctx context.Context, method string, params ...interface{})
 	}
 	if err := json.Unmarshal(rpcResp.Result, &resultStatus); err == nil {
 		if resultStatus.Status == "error" {
-			return nil, fmt.Errorf("RPC error: %s: %s", resultStatus.Error, resultStatus.ErrorMessage)
+			err := &LedgerError{Code: resultStatus.Error, Message: resultStatus.ErrorMessage}
+			if isBusyError(resultStatus.Error) {
+				return nil, &callError{err: err, retryable: true}
+			}
+			return nil, err
 		}
 	}
 
@@ -20,34 +20,25 @@ type ContractInfo struct {
 
 // GetContractInfo retrieves contract information from the ledger
 func (c *Client) GetContractInfo(ctx context.Context, account string) (*ContractInfo, error) {
-	// Try account_objects first to find Contract ledger entries
-	params := map[string]interface{}{
-		"account":      account,
-		"ledger_index": "current",
-		"type":         "contract",
-	}
-
-	var result struct {
-		AccountObjects []json.RawMessage `json:"account_objects"`
-		Status         string            `json:"status"`
-	}
-
-	if err := c.CallTyped(ctx, &result, "account_objects", params); err != nil {
-		return nil, fmt.Errorf("failed to get contract info: %w", err)
-	}
-
-	if len(result.AccountObjects) == 0 {
+	// Try account_objects first to find Contract ledger entries. A filtered
+	// page can be empty while later pages still match, so follow markers.
+	it := c.AccountObjectsIterator(account, "contract", PageOpts{Limit: 1})
+	if !it.Next(ctx) {
+		if err := it.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get contract info: %w", err)
+		}
 		// Try ledger_entry directly
 		return c.getContractByLedgerEntry(ctx, account)
 	}
+	obj := it.Value()
 
 	var info ContractInfo
-	if err := json.Unmarshal(result.AccountObjects[0], &info); err != nil {
+	if err := json.Unmarshal(obj, &info); err != nil {
 		return nil, fmt.Errorf("failed to parse contract info: %w", err)
 	}
 
 	// Capture all fields
-	json.Unmarshal(result.AccountObjects[0], &info.Extra)
+	json.Unmarshal(obj, &info.Extra)
 
 	return &info, nil
 }
@@ -79,29 +70,44 @@ func (c *Client) getContractByLedgerEntry(ctx context.Context, account string) (
 	return &info, nil
 }
 
-// GetContractData retrieves contract state data
+// GetContractData retrieves the first contract state entry; use
+// ContractDataIterator to list all of them
 func (c *Client) GetContractData(ctx context.Context, account string, user string) (json.RawMessage, error) {
-	params := map[string]interface{}{
-		"account":      account,
-		"ledger_index": "current",
-		"type":         "contract_data",
+	it := c.ContractDataIterator(account, user, PageOpts{Limit: 1})
+	if !it.Next(ctx) {
+		if err := it.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get contract data: %w", err)
+		}
+		return nil, nil
 	}
 
-	if user != "" {
-		params["account"] = user
-	}
+	return it.Value(), nil
+}
 
-	var result struct {
-		AccountObjects []json.RawMessage `json:"account_objects"`
-	}
+// Contract ledger flags
+const (
+	LsfImmutable     = 0x00000001
+	LsfCodeImmutable = 0x00000002
+	LsfABIImmutable  = 0x00000004
+	LsfUndeletable   = 0x00000008
+)
 
-	if err := c.CallTyped(ctx, &result, "account_objects", params); err != nil {
-		return nil, fmt.Errorf("failed to get contract data: %w", err)
-	}
+// IsImmutable reports whether the contract cannot be modified at all
+func (c *ContractInfo) IsImmutable() bool {
+	return c.Flags&LsfImmutable != 0
+}
 
-	if len(result.AccountObjects) == 0 {
-		return nil, nil
-	}
+// CanModifyCode reports whether the contract's WASM code may be replaced
+func (c *ContractInfo) CanModifyCode() bool {
+	return !c.IsImmutable() && c.Flags&LsfCodeImmutable == 0
+}
+
+// CanModifyABI reports whether the contract's ABI may be replaced
+func (c *ContractInfo) CanModifyABI() bool {
+	return !c.IsImmutable() && c.Flags&LsfABIImmutable == 0
+}
 
-	return result.AccountObjects[0], nil
+// IsUndeletable reports whether the contract is protected from deletion
+func (c *ContractInfo) IsUndeletable() bool {
+	return c.Flags&LsfUndeletable != 0
 }
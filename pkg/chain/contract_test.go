@@ -0,0 +1,134 @@
+package chain_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/chain/mockserver"
+)
+
+const (
+	owner    = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	contract = "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf"
+)
+
+func TestGetContractInfo(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	srv.Fund(contract, 10_000_000)
+	// Other objects come first so the filtered lookup has to page
+	for i := 0; i < 3; i++ {
+		srv.AddObject(contract, map[string]interface{}{"LedgerEntryType": "ContractData"})
+	}
+	srv.AddObject(contract, map[string]interface{}{
+		"LedgerEntryType": "Contract",
+		"Account":         contract,
+		"Owner":           owner,
+		"Flags":           chain.LsfABIImmutable,
+		"WasmHash":        "ABCD",
+	})
+
+	info, err := srv.Client().GetContractInfo(context.Background(), contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Owner != owner || info.WasmHash != "ABCD" || info.LedgerEntryType != "Contract" {
+		t.Errorf("contract info = %+v", info)
+	}
+	if !info.CanModifyCode() || info.CanModifyABI() {
+		t.Errorf("flags %#x: CanModifyCode=%v CanModifyABI=%v", info.Flags, info.CanModifyCode(), info.CanModifyABI())
+	}
+}
+
+func TestGetContractInfoLedgerEntryFallback(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	// The Contract entry is not among the contract account's objects
+	srv.Fund(contract, 10_000_000)
+	srv.Fund(owner, 10_000_000)
+	srv.AddObject(owner, map[string]interface{}{
+		"LedgerEntryType": "Contract",
+		"Account":         contract,
+		"Owner":           owner,
+	})
+
+	client := srv.Client()
+	info, err := client.GetContractInfo(context.Background(), contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Account != contract || info.Owner != owner {
+		t.Errorf("contract info = %+v", info)
+	}
+	if srv.Calls("ledger_entry") != 1 {
+		t.Errorf("ledger_entry called %d times, want 1", srv.Calls("ledger_entry"))
+	}
+
+	const other = "rLNaPoKeeBjZe2qs6x52yVPZpZ8td4dc6w"
+	srv.Fund(other, 10_000_000)
+	if _, err := client.GetContractInfo(context.Background(), other); err == nil {
+		t.Error("expected an error for an account without a contract")
+	}
+}
+
+func TestContractDataIteratorUser(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	const other = "rLNaPoKeeBjZe2qs6x52yVPZpZ8td4dc6w"
+	srv.Fund(owner, 10_000_000)
+	for i, account := range []string{other, contract, other, contract} {
+		srv.AddObject(owner, map[string]interface{}{
+			"LedgerEntryType": "ContractData",
+			"ContractAccount": account,
+			"Seq":             i,
+		})
+	}
+
+	// The user's entries for other contracts are skipped across pages
+	it := srv.Client().ContractDataIterator(contract, owner, chain.PageOpts{PageSize: 1})
+	entries, err := it.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %s", len(entries), entries)
+	}
+	for _, entry := range entries {
+		var data struct{ ContractAccount string }
+		json.Unmarshal(entry, &data)
+		if data.ContractAccount != contract {
+			t.Errorf("entry of another contract: %s", entry)
+		}
+	}
+}
+
+func TestGetEventHistory(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	srv.AddEvent(chain.ContractEvent{Type: "mint", Contract: contract, LedgerIndex: 5})
+	srv.AddEvent(chain.ContractEvent{Type: "burn", Contract: contract, LedgerIndex: 6})
+	srv.AddEvent(chain.ContractEvent{Type: "mint", Contract: contract, LedgerIndex: 7})
+
+	client := srv.Client()
+	page, err := client.GetEventHistory(context.Background(), contract, chain.EventQueryOpts{EventType: "mint", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 1 || page.Events[0].LedgerIndex != 5 || page.Marker == nil {
+		t.Fatalf("first page = %+v", page)
+	}
+
+	page, err = client.GetEventHistory(context.Background(), contract, chain.EventQueryOpts{EventType: "mint", Marker: page.Marker})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 1 || page.Events[0].LedgerIndex != 7 || page.Marker != nil {
+		t.Errorf("second page = %+v", page)
+	}
+}
@@ -25,7 +25,22 @@ type ContractEvent struct {
 }
 
 // GetEventHistory queries contract event history
+// and returns a single page; use EventIterator to follow the marker
 func (c *Client) GetEventHistory(ctx context.Context, account string, opts EventQueryOpts) (*EventHistory, error) {
+	params := eventHistoryParams(account, opts)
+	if opts.Marker != nil {
+		params["marker"] = opts.Marker
+	}
+
+	var result EventHistory
+	if err := c.CallTyped(ctx, &result, "event_history", params); err != nil {
+		return nil, fmt.Errorf("event_history failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+func eventHistoryParams(account string, opts EventQueryOpts) map[string]interface{} {
 	params := map[string]interface{}{
 		"account": account,
 	}
@@ -42,13 +57,7 @@ func (c *Client) GetEventHistory(ctx context.Context, account string, opts Event
 	if opts.Limit > 0 {
 		params["limit"] = opts.Limit
 	}
-
-	var result EventHistory
-	if err := c.CallTyped(ctx, &result, "event_history", params); err != nil {
-		return nil, fmt.Errorf("event_history failed: %w", err)
-	}
-
-	return &result, nil
+	return params
 }
 
 // EventQueryOpts configures event history queries
@@ -57,4 +66,67 @@ type EventQueryOpts struct {
 	FromLedger int64
 	ToLedger   int64
 	Limit      int
+	Marker     interface{} // Resume after a previous page
+}
+
+// ContractEventsFromMeta extracts the ContractEvent entries created by a
+// transaction from its metadata
+func ContractEventsFromMeta(meta map[string]interface{}, txHash string, ledgerIndex int64) []ContractEvent {
+	var events []ContractEvent
+
+	nodes, _ := meta["AffectedNodes"].([]interface{})
+	for _, node := range nodes {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, v := range nodeMap {
+			entry, ok := v.(map[string]interface{})
+			if !ok || entry["LedgerEntryType"] != "ContractEvent" {
+				continue
+			}
+
+			event := ContractEvent{
+				LedgerIndex: ledgerIndex,
+				TxHash:      txHash,
+			}
+			event.Type, _ = entry["type"].(string)
+
+			fields, ok := entry["NewFields"].(map[string]interface{})
+			if !ok {
+				fields = entry
+			}
+			if event.Type == "" {
+				event.Type, _ = fields["EventType"].(string)
+			}
+			event.Contract, _ = fields["Account"].(string)
+			event.Data, _ = json.Marshal(fields)
+
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// ContractEventNode is the inverse of ContractEventsFromMeta: the
+// AffectedNodes entry that creates the event's ContractEvent object
+func ContractEventNode(event ContractEvent) map[string]interface{} {
+	fields := map[string]interface{}{}
+	var data map[string]interface{}
+	if json.Unmarshal(event.Data, &data) == nil {
+		for k, v := range data {
+			fields[k] = v
+		}
+	}
+	fields["EventType"] = event.Type
+	fields["Account"] = event.Contract
+
+	return map[string]interface{}{
+		"CreatedNode": map[string]interface{}{
+			"LedgerEntryType": "ContractEvent",
+			"NewFields":       fields,
+		},
+	}
 }
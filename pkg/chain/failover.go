@@ -0,0 +1,287 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client defaults, used when the matching ClientOptions field is zero
+const (
+	DefaultRPCTimeout    = 30 * time.Second
+	DefaultMaxRetries    = 2
+	DefaultRetryBackoff  = 250 * time.Millisecond
+	DefaultFailoverDelay = 30 * time.Second
+
+	maxRetryBackoff    = 10 * time.Second
+	healthCheckTimeout = 5 * time.Second
+)
+
+// ClientOptions configures a Client
+type ClientOptions struct {
+	Endpoints     []string          // RPC endpoints in order of preference (WebSocket URLs are converted)
+	Timeout       time.Duration     // Per-request timeout
+	MaxRetries    int               // Retries for idempotent methods (-1 disables)
+	RetryBackoff  time.Duration     // Initial backoff, doubled after each retry
+	RateLimit     float64           // Requests per second across endpoints (0 = unlimited)
+	Burst         int               // Requests allowed above the rate at once (default: 1 or the rate)
+	FailoverDelay time.Duration     // How long a failed endpoint is skipped before it is health-checked again
+	Transport     http.RoundTripper // HTTP transport (default: the cassette from BEDROCK_RPC_RECORD/REPLAY, else http.DefaultTransport)
+}
+
+// NewClientWithOptions creates a client with failover, retry and rate
+// limiting options
+func NewClientWithOptions(opts ClientOptions) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultRPCTimeout
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = DefaultRetryBackoff
+	}
+	if opts.FailoverDelay <= 0 {
+		opts.FailoverDelay = DefaultFailoverDelay
+	}
+	if opts.Transport == nil {
+		opts.Transport = EnvTransport()
+	}
+
+	c := &Client{
+		opts:       opts,
+		limiter:    newRateLimiter(opts.RateLimit, opts.Burst),
+		httpClient: &http.Client{Transport: opts.Transport},
+	}
+	for _, url := range opts.Endpoints {
+		if url == "" {
+			continue
+		}
+		c.endpoints = append(c.endpoints, &endpoint{url: url, httpURL: toHTTPURL(url)})
+	}
+	if len(c.endpoints) == 0 {
+		c.endpoints = append(c.endpoints, &endpoint{})
+	}
+	c.url = c.endpoints[0].url
+	return c
+}
+
+// Endpoint returns the URL requests are currently sent to
+func (c *Client) Endpoint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, ep := range c.endpoints {
+		if !ep.down {
+			return ep.url
+		}
+	}
+	return c.url
+}
+
+// endpoint is one RPC server of a client
+type endpoint struct {
+	url       string
+	httpURL   string
+	down      bool
+	downUntil time.Time
+}
+
+// pick returns the first healthy endpoint in order of preference. An
+// endpoint whose failover delay has passed is health-checked before it is
+// used again, so traffic returns to the preferred server once it recovers.
+func (c *Client) pick(ctx context.Context) *endpoint {
+	c.mu.Lock()
+	candidates := make([]*endpoint, len(c.endpoints))
+	copy(candidates, c.endpoints)
+	c.mu.Unlock()
+
+	var fallback *endpoint
+	var fallbackUntil time.Time
+	now := time.Now()
+	for _, ep := range candidates {
+		c.mu.Lock()
+		down, until := ep.down, ep.downUntil
+		c.mu.Unlock()
+
+		if !down {
+			return ep
+		}
+		if now.After(until) && c.healthy(ctx, ep) {
+			c.markUp(ep)
+			return ep
+		}
+		if fallback == nil || until.Before(fallbackUntil) {
+			fallback, fallbackUntil = ep, until
+		}
+	}
+
+	// Everything is down: try the one that failed longest ago
+	return fallback
+}
+
+// healthy pings an endpoint
+func (c *Client) healthy(ctx context.Context, ep *endpoint) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	_, err := c.do(ctx, ep, []byte(`{"method":"ping"}`))
+	if err != nil {
+		c.markDown(ep)
+		return false
+	}
+	return true
+}
+
+func (c *Client) markUp(ep *endpoint) {
+	c.mu.Lock()
+	ep.down = false
+	c.mu.Unlock()
+}
+
+func (c *Client) markDown(ep *endpoint) {
+	if len(c.endpoints) < 2 {
+		return // Nothing to fail over to
+	}
+	c.mu.Lock()
+	ep.down = true
+	ep.downUntil = time.Now().Add(c.opts.FailoverDelay)
+	c.mu.Unlock()
+}
+
+// callError is a failed attempt and how it may be retried
+type callError struct {
+	err          error
+	retryable    bool          // The error is transient
+	endpointDown bool          // The endpoint looks unhealthy; try another one
+	sent         bool          // The request may have reached the server
+	retryAfter   time.Duration // Server-requested delay
+}
+
+func (e *callError) Error() string { return e.err.Error() }
+func (e *callError) Unwrap() error { return e.err }
+
+// nonIdempotent lists methods that must not be sent twice
+var nonIdempotent = map[string]bool{
+	"submit":             true,
+	"submit_multisigned": true,
+	"ledger_accept":      true,
+	"wallet_propose":     true,
+}
+
+func isIdempotent(method string) bool {
+	return !nonIdempotent[method]
+}
+
+// isDialError reports whether the request failed before a connection was
+// made, so the server never saw it
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// isBusyError reports XRPL errors that ask the client to back off
+func isBusyError(code string) bool {
+	switch code {
+	case "slowDown", "tooBusy", "noNetwork", "noCurrent", "noClosed":
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// backoff returns the delay before the given retry
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	var callErr *callError
+	if errors.As(lastErr, &callErr) && callErr.retryAfter > 0 {
+		return callErr.retryAfter
+	}
+
+	delay := c.opts.RetryBackoff << (attempt - 1)
+	if delay > maxRetryBackoff || delay <= 0 {
+		delay = maxRetryBackoff
+	}
+	// Up to 20% jitter so parallel clients don't retry in lockstep
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter is a token bucket shared by all requests of a client
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	b := float64(burst)
+	if b <= 0 {
+		b = rate
+		if b < 1 {
+			b = 1
+		}
+	}
+	return &rateLimiter{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+// wait blocks until a request may be sent
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleepCtx(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
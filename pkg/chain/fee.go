@@ -0,0 +1,178 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
+// FeeAuto is the fee value that requests a network-aware estimate
+const FeeAuto = "auto"
+
+// ReferenceFees are the unloaded fees in drops for contract transactions.
+// They are scaled by the current load of the network when estimating.
+var ReferenceFees = map[string]int64{
+	"ContractCreate":     100000000,
+	"ContractModify":     10000000,
+	"ContractCall":       1000000,
+	"ContractDelete":     1000000,
+	"ContractClawback":   1000000,
+	"ContractUserDelete": 1000000,
+}
+
+// feeQueryTimeout bounds how long ResolveFee waits for the node before
+// falling back to the reference fee
+const feeQueryTimeout = 10 * time.Second
+
+// defaultReferenceFee is used for transaction types missing from ReferenceFees
+const defaultReferenceFee = 1000000
+
+// FeeInfo holds the fee levels reported by the fee and server_info RPCs
+type FeeInfo struct {
+	BaseFee       int64   // Reference transaction cost in drops
+	MedianFee     int64   // Median fee of transactions in the open ledger
+	MinimumFee    int64   // Minimum fee to get into the queue
+	OpenLedgerFee int64   // Fee to get into the open ledger right now
+	LoadFactor    float64 // Server load factor relative to an unloaded server
+}
+
+// Multiplier returns how much the network currently charges above its base fee
+func (f *FeeInfo) Multiplier() float64 {
+	m := 1.0
+	if f.LoadFactor > m {
+		m = f.LoadFactor
+	}
+	if f.BaseFee > 0 && f.OpenLedgerFee > f.BaseFee {
+		if open := float64(f.OpenLedgerFee) / float64(f.BaseFee); open > m {
+			m = open
+		}
+	}
+	return m
+}
+
+// FeeEstimate is the result of a fee estimation
+type FeeEstimate struct {
+	Drops      int64
+	Reference  int64
+	Multiplier float64
+	Capped     bool // The estimate was limited by the max fee
+}
+
+// GetFeeInfo queries the current fee levels and server load
+func (c *Client) GetFeeInfo(ctx context.Context) (*FeeInfo, error) {
+	var feeResult struct {
+		Drops struct {
+			BaseFee       string `json:"base_fee"`
+			MedianFee     string `json:"median_fee"`
+			MinimumFee    string `json:"minimum_fee"`
+			OpenLedgerFee string `json:"open_ledger_fee"`
+		} `json:"drops"`
+	}
+	if err := c.CallTyped(ctx, &feeResult, "fee"); err != nil {
+		return nil, fmt.Errorf("fee failed: %w", err)
+	}
+
+	info := &FeeInfo{
+		BaseFee:       parseDrops(feeResult.Drops.BaseFee),
+		MedianFee:     parseDrops(feeResult.Drops.MedianFee),
+		MinimumFee:    parseDrops(feeResult.Drops.MinimumFee),
+		OpenLedgerFee: parseDrops(feeResult.Drops.OpenLedgerFee),
+		LoadFactor:    1,
+	}
+
+	var serverInfo struct {
+		Info struct {
+			LoadFactor float64 `json:"load_factor"`
+		} `json:"info"`
+	}
+	if err := c.CallTyped(ctx, &serverInfo, "server_info"); err == nil && serverInfo.Info.LoadFactor > 0 {
+		info.LoadFactor = serverInfo.Info.LoadFactor
+	}
+
+	return info, nil
+}
+
+// EstimateFee estimates the fee for a transaction type from the current network load.
+// A non-empty maxFee caps the estimate.
+func (c *Client) EstimateFee(ctx context.Context, txType string, maxFee string) (*FeeEstimate, error) {
+	limit, err := parseMaxFee(maxFee)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := c.GetFeeInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &FeeEstimate{
+		Reference:  referenceFee(txType),
+		Multiplier: info.Multiplier(),
+	}
+	estimate.Drops = int64(math.Ceil(float64(estimate.Reference) * estimate.Multiplier))
+
+	if limit > 0 && estimate.Drops > limit {
+		estimate.Drops = limit
+		estimate.Capped = true
+	}
+
+	return estimate, nil
+}
+
+// ResolveFee turns a fee flag value into drops. Explicit values are returned
+// unchanged; "auto" or an empty value are estimated from the network, falling
+// back to the reference fee when the node cannot be queried. The network's
+// max_fee caps the estimate.
+func ResolveFee(ctx context.Context, network config.NetworkConfig, fee, txType string) (string, error) {
+	if fee != "" && !strings.EqualFold(fee, FeeAuto) {
+		return fee, nil
+	}
+
+	limit, err := parseMaxFee(network.MaxFee)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, feeQueryTimeout)
+	defer cancel()
+
+	estimate, err := NewNetworkClient(network).EstimateFee(ctx, txType, network.MaxFee)
+	if err != nil {
+		drops := referenceFee(txType)
+		if limit > 0 && drops > limit {
+			drops = limit
+		}
+		return strconv.FormatInt(drops, 10), nil
+	}
+
+	return strconv.FormatInt(estimate.Drops, 10), nil
+}
+
+func referenceFee(txType string) int64 {
+	if reference, ok := ReferenceFees[txType]; ok {
+		return reference
+	}
+	return defaultReferenceFee
+}
+
+// parseMaxFee parses a max fee in drops; an empty value means no cap
+func parseMaxFee(maxFee string) (int64, error) {
+	if maxFee == "" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(maxFee, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid max fee %q: must be a positive number of drops", maxFee)
+	}
+	return limit, nil
+}
+
+func parseDrops(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
@@ -0,0 +1,303 @@
+package mockserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// handler implements one RPC method. It runs with the server locked and
+// returns the result object.
+type handler func(s *Server, params map[string]interface{}) map[string]interface{}
+
+var handlers = map[string]handler{
+	"account_info":    (*Server).accountInfo,
+	"account_objects": (*Server).accountObjects,
+	"ledger":          (*Server).ledger,
+	"ledger_current":  (*Server).ledgerCurrent,
+	"ledger_accept":   (*Server).ledgerAccept,
+	"ledger_entry":    (*Server).ledgerEntry,
+	"tx":              (*Server).tx,
+	"submit":          (*Server).submit,
+	"event_history":   (*Server).eventHistory,
+}
+
+func (s *Server) dispatch(method string, params map[string]interface{}) map[string]interface{} {
+	h, ok := handlers[method]
+	if !ok {
+		return rpcError("unknownCmd", "Unknown method.")
+	}
+	return h(s, params)
+}
+
+func rpcError(code, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"error":         code,
+		"error_message": message,
+	}
+}
+
+func (s *Server) accountInfo(params map[string]interface{}) map[string]interface{} {
+	address, _ := params["account"].(string)
+	root := s.account(address)
+	if root == nil {
+		return rpcError("actNotFound", "Account not found.")
+	}
+	return map[string]interface{}{
+		"account_data":         publicEntry(root),
+		"ledger_current_index": s.current,
+		"validated":            false,
+	}
+}
+
+func (s *Server) accountObjects(params map[string]interface{}) map[string]interface{} {
+	address, _ := params["account"].(string)
+	if s.account(address) == nil {
+		return rpcError("actNotFound", "Account not found.")
+	}
+	filter := normalizeType(params["type"])
+
+	var objects []interface{}
+	for _, index := range s.order {
+		entry := s.entries[index]
+		if entry["_owner"] != address || entry["LedgerEntryType"] == "AccountRoot" {
+			continue
+		}
+		if filter != "" && normalizeType(entry["LedgerEntryType"]) != filter {
+			continue
+		}
+		objects = append(objects, publicEntry(entry))
+	}
+
+	page, marker, err := paginate(objects, params)
+	if err != nil {
+		return rpcError("invalidParams", err.Error())
+	}
+	result := map[string]interface{}{
+		"account":              address,
+		"account_objects":      page,
+		"ledger_current_index": s.current,
+		"validated":            false,
+	}
+	if marker != "" {
+		result["marker"] = marker
+	}
+	return result
+}
+
+// normalizeType compares type filters such as "contract_data" with entry
+// types such as "ContractData"
+func normalizeType(v interface{}) string {
+	s, _ := v.(string)
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+// paginate returns the page of items selected by the limit and marker
+// params, and the marker for the next page
+func paginate(items []interface{}, params map[string]interface{}) ([]interface{}, string, error) {
+	start := 0
+	if m, ok := params["marker"]; ok && m != nil {
+		n, err := strconv.Atoi(fmt.Sprint(m))
+		if err != nil || n < 0 || n > len(items) {
+			return nil, "", fmt.Errorf("Invalid field 'marker'.")
+		}
+		start = n
+	}
+
+	end := len(items)
+	if limit := int(toUint(params["limit"])); limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	page := items[start:end]
+	if page == nil {
+		page = []interface{}{}
+	}
+	if end < len(items) {
+		return page, strconv.Itoa(end), nil
+	}
+	return page, "", nil
+}
+
+// ledgerIndexParam resolves a ledger_index param to an index
+func (s *Server) ledgerIndexParam(v interface{}) (int64, bool) {
+	switch v {
+	case nil, "current":
+		return s.current, true
+	case "validated", "closed":
+		return s.current - 1, true
+	}
+	n, err := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+	if err != nil || n < 1 || n > s.current {
+		return 0, false
+	}
+	return n, true
+}
+
+func (s *Server) ledger(params map[string]interface{}) map[string]interface{} {
+	index, ok := s.ledgerIndexParam(params["ledger_index"])
+	if !ok {
+		return rpcError("lgrNotFound", "ledgerNotFound")
+	}
+
+	if index == s.current {
+		return map[string]interface{}{
+			"ledger": map[string]interface{}{
+				"ledger_index": strconv.FormatInt(index, 10),
+				"closed":       false,
+			},
+			"ledger_current_index": index,
+			"validated":            false,
+		}
+	}
+
+	l := s.ledgers[index]
+	parent := ""
+	if p, ok := s.ledgers[index-1]; ok {
+		parent = p.hash
+	}
+	return map[string]interface{}{
+		"ledger": map[string]interface{}{
+			"ledger_index":      strconv.FormatInt(index, 10),
+			"ledger_hash":       l.hash,
+			"parent_hash":       parent,
+			"close_time":        l.closeTime,
+			"closed":            true,
+			"total_coins":       "100000000000000000",
+			"transaction_count": l.txCount,
+			"account_hash":      hashOf("state", l.hash),
+			"transaction_hash":  hashOf("txs", l.hash),
+		},
+		"ledger_hash":  l.hash,
+		"ledger_index": index,
+		"validated":    true,
+	}
+}
+
+func (s *Server) ledgerCurrent(params map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"ledger_current_index": s.current}
+}
+
+func (s *Server) ledgerAccept(params map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"ledger_current_index": s.accept()}
+}
+
+func (s *Server) ledgerEntry(params map[string]interface{}) map[string]interface{} {
+	var entry map[string]interface{}
+	switch {
+	case params["index"] != nil:
+		index, _ := params["index"].(string)
+		entry = s.entries[strings.ToUpper(index)]
+	case params["account_root"] != nil:
+		address, _ := params["account_root"].(string)
+		entry = s.account(address)
+	case params["contract"] != nil:
+		address, _ := params["contract"].(string)
+		for _, index := range s.order {
+			e := s.entries[index]
+			if e["LedgerEntryType"] == "Contract" && (e["Account"] == address || e["ContractAccount"] == address) {
+				entry = e
+				break
+			}
+		}
+	case params["contract_source"] != nil:
+		hash, _ := params["contract_source"].(string)
+		for _, index := range s.order {
+			e := s.entries[index]
+			if code, _ := e["ContractHash"].(string); e["LedgerEntryType"] == "ContractSource" && strings.EqualFold(code, hash) {
+				entry = e
+				break
+			}
+		}
+	default:
+		return rpcError("invalidParams", "Invalid parameters.")
+	}
+	if entry == nil {
+		return rpcError("entryNotFound", "Entry not found.")
+	}
+
+	return map[string]interface{}{
+		"index":                entry["index"],
+		"node":                 publicEntry(entry),
+		"ledger_current_index": s.current,
+		"validated":            false,
+	}
+}
+
+func (s *Server) tx(params map[string]interface{}) map[string]interface{} {
+	hash, _ := params["transaction"].(string)
+	t, ok := s.txs[strings.ToUpper(hash)]
+	if !ok {
+		return rpcError("txnNotFound", "Transaction not found.")
+	}
+
+	txJSON := make(map[string]interface{}, len(t.tx))
+	for k, v := range t.tx {
+		txJSON[k] = v
+	}
+	result := map[string]interface{}{
+		"hash":      t.hash,
+		"tx_json":   txJSON,
+		"validated": t.ledger > 0,
+	}
+	if t.ledger > 0 {
+		result["ledger_index"] = t.ledger
+		result["meta"] = t.meta()
+	}
+	return result
+}
+
+func (s *Server) eventHistory(params map[string]interface{}) map[string]interface{} {
+	account, _ := params["account"].(string)
+	eventType, _ := params["event_type"].(string)
+	min := int64(toUint(params["ledger_index_min"]))
+	max := int64(toUint(params["ledger_index_max"]))
+
+	var events []interface{}
+	for _, event := range s.events {
+		if event.Contract != account && account != "" {
+			continue
+		}
+		if eventType != "" && event.Type != eventType {
+			continue
+		}
+		if (min > 0 && event.LedgerIndex < min) || (max > 0 && event.LedgerIndex > max) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	page, marker, err := paginate(events, params)
+	if err != nil {
+		return rpcError("invalidParams", err.Error())
+	}
+	result := map[string]interface{}{
+		"events":               page,
+		"ledger_current_index": s.current,
+	}
+	if marker != "" {
+		result["marker"] = marker
+	}
+	return result
+}
+
+// toUint reads an unsigned integer from a decoded JSON or binary value
+func toUint(v interface{}) uint64 {
+	switch n := v.(type) {
+	case uint32:
+		return uint64(n)
+	case uint64:
+		return n
+	case int:
+		return uint64(n)
+	case int64:
+		return uint64(n)
+	case float64:
+		return uint64(n)
+	case string:
+		u, _ := strconv.ParseUint(n, 10, 64)
+		return u
+	}
+	u, _ := strconv.ParseUint(fmt.Sprint(v), 10, 64)
+	return u
+}
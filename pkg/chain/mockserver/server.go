@@ -0,0 +1,336 @@
+// Package mockserver provides an in-process XRPL JSON-RPC server backed by
+// an in-memory ledger, for testing code built on chain.Client without a
+// running node.
+//
+//	srv := mockserver.New()
+//	defer srv.Close()
+//
+//	srv.Fund("rAlice...", 100_000_000)
+//	client := srv.Client()
+//	info, err := client.GetAccountInfo(ctx, "rAlice...")
+package mockserver
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/codec"
+)
+
+// GenesisAddress is the account funded at startup, as on a standalone node
+const GenesisAddress = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+
+// GenesisBalance is the genesis account's starting balance in drops
+const GenesisBalance = 100_000_000_000_000_000
+
+// FirstLedger is the open ledger when the server starts
+const FirstLedger = 3
+
+// rippleEpoch is the start of XRPL close times, in Unix seconds
+const rippleEpoch = 946684800
+
+// Outcome is the result of applying a submitted transaction
+type Outcome struct {
+	EngineResult string                // Default tesSUCCESS
+	Events       []chain.ContractEvent // Emitted when the transaction is validated
+}
+
+// SubmitFunc decides the outcome of a submitted transaction that passed the
+// sequence checks. tx is the decoded transaction.
+type SubmitFunc func(tx map[string]interface{}) Outcome
+
+// Request is a call received by the server
+type Request struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// Server is a mock XRPL node. The embedded httptest.Server provides URL
+// and Close.
+type Server struct {
+	*httptest.Server
+
+	// OnSubmit overrides the outcome of submitted transactions
+	OnSubmit SubmitFunc
+
+	mu       sync.Mutex
+	defs     *codec.Definitions
+	current  int64 // Open ledger; current-1 is the latest validated ledger
+	ledgers  map[int64]*ledger
+	entries  map[string]map[string]interface{} // Ledger entries by index
+	order    []string                          // Entry indexes in creation order
+	txs      map[string]*transaction
+	pending  []*transaction
+	events   []chain.ContractEvent
+	requests []Request
+}
+
+type ledger struct {
+	index     int64
+	hash      string
+	closeTime int64
+	txCount   int
+}
+
+type transaction struct {
+	hash    string
+	tx      map[string]interface{}
+	result  string
+	events  []chain.ContractEvent
+	ledger  int64 // Validated ledger; 0 while pending
+	txIndex int
+}
+
+// New starts a server with a funded genesis account
+func New() *Server {
+	s := &Server{
+		defs:    codec.DefaultDefinitions(),
+		current: FirstLedger,
+		ledgers: make(map[int64]*ledger),
+		entries: make(map[string]map[string]interface{}),
+		txs:     make(map[string]*transaction),
+	}
+	for i := int64(1); i < FirstLedger; i++ {
+		s.ledgers[i] = s.newLedger(i, 0)
+	}
+	s.credit(GenesisAddress, GenesisBalance)
+	s.account(GenesisAddress)["Sequence"] = 1
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a chain.Client for the server with retries disabled
+func (s *Server) Client() *chain.Client {
+	return chain.NewClientWithOptions(chain.ClientOptions{
+		Endpoints:  []string{s.URL},
+		MaxRetries: -1,
+	})
+}
+
+// SetDefinitions replaces the codec definitions submitted transactions are
+// decoded with, e.g. with codec.LoadDefinitions for contract transactions
+func (s *Server) SetDefinitions(defs *codec.Definitions) {
+	s.mu.Lock()
+	s.defs = defs
+	s.mu.Unlock()
+}
+
+// Fund creates an account, or adds to its balance when it exists
+func (s *Server) Fund(address string, drops uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credit(address, drops)
+}
+
+// AddObject adds a ledger entry owned by an account and returns its index.
+// The entry's "index" is generated when not set, and "Owner" is set to the
+// owner unless the entry has an Owner or Account field.
+func (s *Server) AddObject(owner string, entry map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj := make(map[string]interface{}, len(entry)+1)
+	for k, v := range entry {
+		obj[k] = v
+	}
+	if _, ok := obj["Owner"]; !ok {
+		if _, ok := obj["Account"]; !ok {
+			obj["Owner"] = owner
+		}
+	}
+	obj["_owner"] = owner
+
+	index, _ := obj["index"].(string)
+	if index == "" {
+		index = hashOf("entry", owner, fmt.Sprint(len(s.order)))
+		obj["index"] = index
+	}
+	s.putEntry(index, obj)
+	return index
+}
+
+// AddEvent adds a contract event to the history. Events without a ledger
+// are placed in the latest validated ledger.
+func (s *Server) AddEvent(event chain.ContractEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.LedgerIndex == 0 {
+		event.LedgerIndex = s.current - 1
+	}
+	s.events = append(s.events, event)
+	sort.SliceStable(s.events, func(i, j int) bool {
+		return s.events[i].LedgerIndex < s.events[j].LedgerIndex
+	})
+}
+
+// Accept closes the open ledger, validating pending transactions, and
+// returns the new open ledger index. It is what ledger_accept does.
+func (s *Server) Accept() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accept()
+}
+
+// LedgerIndex returns the open ledger index
+func (s *Server) LedgerIndex() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// Entry returns a copy of a ledger entry
+func (s *Server) Entry(index string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[index]
+	if !ok {
+		return nil, false
+	}
+	return publicEntry(entry), true
+}
+
+// Requests returns the calls received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Calls returns how many times a method was called
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if r.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Method string                   `json:"method"`
+		Params []map[string]interface{} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "invalid JSON-RPC request", http.StatusBadRequest)
+		return
+	}
+	params := map[string]interface{}{}
+	if len(req.Params) > 0 && req.Params[0] != nil {
+		params = req.Params[0]
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: req.Method, Params: params})
+	result := s.dispatch(req.Method, params)
+	s.mu.Unlock()
+
+	if _, failed := result["error"]; failed {
+		result["status"] = "error"
+		result["request"] = map[string]interface{}{"command": req.Method}
+	} else {
+		result["status"] = "success"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+}
+
+// accept closes the open ledger
+func (s *Server) accept() int64 {
+	closed := s.current
+	for i, tx := range s.pending {
+		tx.ledger = closed
+		tx.txIndex = i
+		for _, event := range tx.events {
+			event.LedgerIndex = closed
+			event.TxHash = tx.hash
+			s.events = append(s.events, event)
+		}
+	}
+	s.ledgers[closed] = s.newLedger(closed, len(s.pending))
+	s.pending = nil
+	s.current++
+	return s.current
+}
+
+func (s *Server) newLedger(index int64, txCount int) *ledger {
+	return &ledger{
+		index:     index,
+		hash:      hashOf("ledger", fmt.Sprint(index)),
+		closeTime: time.Now().Unix() - rippleEpoch,
+		txCount:   txCount,
+	}
+}
+
+func (s *Server) putEntry(index string, entry map[string]interface{}) {
+	if _, exists := s.entries[index]; !exists {
+		s.order = append(s.order, index)
+	}
+	s.entries[index] = entry
+}
+
+// account returns an account's AccountRoot entry
+func (s *Server) account(address string) map[string]interface{} {
+	return s.entries[accountIndex(address)]
+}
+
+// credit adds drops to an account, creating it when needed
+func (s *Server) credit(address string, drops uint64) {
+	root := s.account(address)
+	if root == nil {
+		root = map[string]interface{}{
+			"LedgerEntryType": "AccountRoot",
+			"Account":         address,
+			"Balance":         "0",
+			"Flags":           0,
+			"OwnerCount":      0,
+			"Sequence":        s.current,
+			"index":           accountIndex(address),
+			"_owner":          address,
+		}
+		s.putEntry(accountIndex(address), root)
+	}
+	root["Balance"] = fmt.Sprint(toUint(root["Balance"]) + drops)
+}
+
+func accountIndex(address string) string {
+	return hashOf("account", address)
+}
+
+// hashOf derives a deterministic 256-bit hash
+func hashOf(parts ...string) string {
+	sum := sha512.Sum512([]byte(strings.Join(parts, "\x00")))
+	return strings.ToUpper(hex.EncodeToString(sum[:32]))
+}
+
+// publicEntry copies an entry without the server's bookkeeping fields
+func publicEntry(entry map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(entry))
+	for k, v := range entry {
+		if !strings.HasPrefix(k, "_") {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,337 @@
+package mockserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/chain/mockserver"
+	"github.com/xrpl-commons/bedrock/pkg/codec"
+)
+
+const (
+	alice    = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	bob      = "rLNaPoKeeBjZe2qs6x52yVPZpZ8td4dc6w"
+	contract = "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf"
+)
+
+// payment encodes an unsigned XRP payment; the server does not check
+// signatures
+func payment(t *testing.T, from, to string, drops uint64, seq int) string {
+	t.Helper()
+	blob, err := codec.DefaultDefinitions().Encode(map[string]interface{}{
+		"TransactionType": "Payment",
+		"Account":         from,
+		"Destination":     to,
+		"Amount":          fmt.Sprint(drops),
+		"Fee":             "12",
+		"Sequence":        seq,
+		"SigningPubKey":   "",
+	})
+	if err != nil {
+		t.Fatalf("encode payment: %v", err)
+	}
+	return blob
+}
+
+func balance(t *testing.T, client *chain.Client, address string) string {
+	t.Helper()
+	info, err := client.GetAccountInfo(context.Background(), address)
+	if err != nil {
+		t.Fatalf("balance of %s: %v", address, err)
+	}
+	return info.AccountData.Balance
+}
+
+func TestSubmitAcceptTx(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client()
+
+	srv.Fund(alice, 1_000_000)
+	info, err := client.GetAccountInfo(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := int(info.AccountData.Sequence)
+
+	res, err := client.Submit(ctx, payment(t, alice, bob, 400_000, seq))
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	var txJSON struct {
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(res.TxJSON, &txJSON); err != nil || txJSON.Hash == "" {
+		t.Fatalf("submit returned no hash: %s", res.TxJSON)
+	}
+
+	// Pending until the ledger closes
+	tx, err := client.GetTransaction(ctx, txJSON.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Validated {
+		t.Fatal("transaction validated before ledger_accept")
+	}
+
+	open := srv.LedgerIndex()
+	if _, err := client.Call(ctx, "ledger_accept", map[string]interface{}{}); err != nil {
+		t.Fatalf("ledger_accept: %v", err)
+	}
+	if got := srv.LedgerIndex(); got != open+1 {
+		t.Errorf("open ledger = %d, want %d", got, open+1)
+	}
+
+	tx, err = client.GetTransaction(ctx, txJSON.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tx.Validated || tx.LedgerIndex != open {
+		t.Errorf("tx validated=%v ledger=%d, want true and %d", tx.Validated, tx.LedgerIndex, open)
+	}
+	if got := tx.Meta["TransactionResult"]; got != "tesSUCCESS" {
+		t.Errorf("TransactionResult = %v", got)
+	}
+
+	if got := balance(t, client, alice); got != "599988" {
+		t.Errorf("alice balance = %s, want 599988", got)
+	}
+	if got := balance(t, client, bob); got != "400000" {
+		t.Errorf("bob balance = %s, want 400000", got)
+	}
+}
+
+func TestSubmitSequenceChecks(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client()
+
+	srv.Fund(alice, 1_000_000)
+	info, err := client.GetAccountInfo(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := int(info.AccountData.Sequence)
+
+	blob := payment(t, alice, bob, 1000, seq)
+	if _, err := client.Submit(ctx, blob); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		blob string
+		want string
+	}{
+		{"duplicate", blob, "tefALREADY"},
+		{"past sequence", payment(t, alice, bob, 2000, seq), "tefPAST_SEQ"},
+		{"future sequence", payment(t, alice, bob, 1000, seq+5), "terPRE_SEQ"},
+		{"unfunded", payment(t, alice, bob, 5_000_000, seq+1), "tecUNFUNDED_PAYMENT"},
+		{"no account", payment(t, contract, alice, 1, 1), "terNO_ACCOUNT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, _ := client.Submit(ctx, tt.blob)
+			if res == nil || res.EngineResult != tt.want {
+				t.Errorf("engine result = %+v, want %s", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubmitEvents(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client()
+
+	srv.Fund(alice, 1_000_000)
+	srv.OnSubmit = func(tx map[string]interface{}) mockserver.Outcome {
+		return mockserver.Outcome{Events: []chain.ContractEvent{{
+			Type:     "transfer",
+			Contract: contract,
+			Data:     json.RawMessage(`{"amount":"5"}`),
+		}}}
+	}
+
+	info, err := client.GetAccountInfo(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.Submit(ctx, payment(t, alice, bob, 1, int(info.AccountData.Sequence)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txJSON struct {
+		Hash string `json:"hash"`
+	}
+	json.Unmarshal(res.TxJSON, &txJSON)
+	closed := srv.LedgerIndex()
+	srv.Accept()
+
+	tx, err := client.GetTransaction(ctx, txJSON.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := chain.ContractEventsFromMeta(tx.Meta, tx.Hash, tx.LedgerIndex)
+	if len(events) != 1 || events[0].Type != "transfer" || events[0].Contract != contract {
+		t.Fatalf("events from meta = %+v", events)
+	}
+
+	history, err := client.GetEventHistory(ctx, contract, chain.EventQueryOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Events) != 1 {
+		t.Fatalf("event_history returned %d events, want 1", len(history.Events))
+	}
+	got := history.Events[0]
+	if got.TxHash != txJSON.Hash || got.LedgerIndex != closed {
+		t.Errorf("event tx=%s ledger=%d, want %s and %d", got.TxHash, got.LedgerIndex, txJSON.Hash, closed)
+	}
+}
+
+func TestAccountObjectsPaging(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client()
+
+	srv.Fund(alice, 1_000_000)
+	for i := 0; i < 5; i++ {
+		srv.AddObject(alice, map[string]interface{}{
+			"LedgerEntryType": "ContractData",
+			"ContractJson":    map[string]interface{}{"n": i},
+		})
+	}
+	srv.AddObject(alice, map[string]interface{}{"LedgerEntryType": "Ticket", "TicketSequence": 9})
+	srv.AddObject(bob, map[string]interface{}{"LedgerEntryType": "ContractData"})
+
+	it := client.AccountObjectsIterator(alice, "contract_data", chain.PageOpts{PageSize: 2})
+	objects, err := it.All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 5 {
+		t.Fatalf("got %d objects, want 5", len(objects))
+	}
+	if it.Pages() != 3 {
+		t.Errorf("fetched %d pages, want 3", it.Pages())
+	}
+	for i, raw := range objects {
+		var obj struct {
+			LedgerEntryType string
+			ContractJson    struct{ N int }
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			t.Fatal(err)
+		}
+		if obj.LedgerEntryType != "ContractData" || obj.ContractJson.N != i {
+			t.Errorf("object %d = %s", i, raw)
+		}
+	}
+
+	all, err := client.AccountObjectsIterator(alice, "", chain.PageOpts{PageSize: 4}).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 6 {
+		t.Errorf("unfiltered listing has %d objects, want 6", len(all))
+	}
+
+	_, err = client.Call(ctx, "account_objects", map[string]interface{}{"account": alice, "marker": "bogus"})
+	if err == nil || !strings.Contains(err.Error(), "invalidParams") {
+		t.Errorf("bad marker error = %v, want invalidParams", err)
+	}
+}
+
+func TestEventHistory(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client()
+
+	for i := int64(1); i <= 6; i++ {
+		eventType := "mint"
+		if i%2 == 0 {
+			eventType = "burn"
+		}
+		srv.AddEvent(chain.ContractEvent{
+			Type:        eventType,
+			Contract:    contract,
+			LedgerIndex: 10 + i,
+			Data:        json.RawMessage(fmt.Sprintf(`{"n":%d}`, i)),
+		})
+	}
+	srv.AddEvent(chain.ContractEvent{Type: "mint", Contract: alice, LedgerIndex: 12})
+
+	events, err := client.EventIterator(contract, chain.EventQueryOpts{}, chain.PageOpts{PageSize: 4}).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("got %d events, want 6", len(events))
+	}
+	for i, e := range events {
+		if e.LedgerIndex != int64(11+i) {
+			t.Errorf("event %d in ledger %d, want %d", i, e.LedgerIndex, 11+i)
+		}
+	}
+
+	filtered, err := client.EventIterator(contract, chain.EventQueryOpts{
+		EventType:  "mint",
+		FromLedger: 12,
+		ToLedger:   15,
+	}, chain.PageOpts{PageSize: 1}).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 2 || filtered[0].LedgerIndex != 13 || filtered[1].LedgerIndex != 15 {
+		t.Errorf("filtered events = %+v, want mints in ledgers 13 and 15", filtered)
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	_, err := srv.Client().Call(context.Background(), "no_such_method", map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "unknownCmd") {
+		t.Errorf("error = %v, want unknownCmd", err)
+	}
+	if srv.Calls("no_such_method") != 1 {
+		t.Errorf("Calls = %d, want 1", srv.Calls("no_such_method"))
+	}
+}
+
+func TestSubmitAndTrackUsedSequence(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+	ctx := context.Background()
+	client := srv.Client()
+
+	srv.Fund(alice, 1_000_000)
+	info, err := client.GetAccountInfo(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := int(info.AccountData.Sequence)
+	blob := payment(t, alice, bob, 1000, seq)
+	if _, err := client.Submit(ctx, blob); err != nil {
+		t.Fatal(err)
+	}
+
+	// A first broadcast that finds its sequence used was not applied by this run
+	for _, b := range []string{blob, payment(t, alice, bob, 2000, seq)} {
+		result, err := client.SubmitAndTrack(ctx, chain.SignedTx{TxBlob: b}, chain.SubmitOptions{})
+		if err == nil || result.Status != chain.SubmissionRejected || result.Attempts != 1 {
+			t.Errorf("result = %+v, error = %v, want rejected on the first attempt", result, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package mockserver
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+)
+
+// txHashPrefix is prepended to a signed blob when hashing it ("TXN\0")
+var txHashPrefix = []byte{0x54, 0x58, 0x4E, 0x00}
+
+// engineMessages are the messages returned for the results the server
+// produces itself
+var engineMessages = map[string]string{
+	"tesSUCCESS":          "The transaction was applied. Only final in a validated ledger.",
+	"tefALREADY":          "The exact transaction was already in this ledger.",
+	"tefPAST_SEQ":         "This sequence number has already passed.",
+	"terPRE_SEQ":          "Missing/inapplicable prior transaction.",
+	"terNO_ACCOUNT":       "The source account does not exist.",
+	"tecUNFUNDED_PAYMENT": "Insufficient XRP balance to send.",
+	"temMALFORMED":        "Malformed transaction.",
+	"telINSUF_FEE_P":      "Fee insufficient.",
+}
+
+// submit applies a signed transaction to the open ledger. Signatures are
+// not checked; the blob only has to decode.
+func (s *Server) submit(params map[string]interface{}) map[string]interface{} {
+	blob, _ := params["tx_blob"].(string)
+	raw, err := hex.DecodeString(blob)
+	if err != nil || len(raw) == 0 {
+		return rpcError("invalidTransaction", "fails local checks: Invalid tx_blob.")
+	}
+	tx, err := s.defs.Decode(blob)
+	if err != nil {
+		return rpcError("invalidTransaction", "fails local checks: "+err.Error())
+	}
+
+	sum := sha512.Sum512(append(append([]byte{}, txHashPrefix...), raw...))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:32]))
+
+	code := s.apply(hash, tx)
+
+	txJSON := make(map[string]interface{}, len(tx)+1)
+	for k, v := range tx {
+		txJSON[k] = v
+	}
+	txJSON["hash"] = hash
+
+	accepted := strings.HasPrefix(code, "tes") || strings.HasPrefix(code, "tec")
+	return map[string]interface{}{
+		"engine_result":         code,
+		"engine_result_code":    engineCode(code),
+		"engine_result_message": engineMessages[code],
+		"tx_blob":               blob,
+		"tx_json":               txJSON,
+		"accepted":              accepted,
+		"applied":               accepted,
+		"broadcast":             accepted,
+		"kept":                  true,
+		"queued":                false,
+	}
+}
+
+// apply checks a transaction against its account and, when it can be
+// applied, charges the fee, consumes the sequence and queues it for the
+// next ledger
+func (s *Server) apply(hash string, tx map[string]interface{}) string {
+	if _, seen := s.txs[hash]; seen {
+		return "tefALREADY"
+	}
+
+	address, _ := tx["Account"].(string)
+	root := s.account(address)
+	if root == nil {
+		return "terNO_ACCOUNT"
+	}
+
+	seq := toUint(tx["Sequence"])
+	switch current := toUint(root["Sequence"]); {
+	case seq == 0 && tx["TicketSequence"] == nil:
+		return "temMALFORMED"
+	case seq != 0 && seq < current:
+		return "tefPAST_SEQ"
+	case seq > current:
+		return "terPRE_SEQ"
+	}
+
+	fee := toUint(tx["Fee"])
+	balance := toUint(root["Balance"])
+	if fee > balance {
+		return "telINSUF_FEE_P"
+	}
+
+	outcome := Outcome{EngineResult: "tesSUCCESS"}
+	if s.OnSubmit != nil {
+		outcome = s.OnSubmit(tx)
+		if outcome.EngineResult == "" {
+			outcome.EngineResult = "tesSUCCESS"
+		}
+	} else if tx["TransactionType"] == "Payment" {
+		outcome.EngineResult = s.pay(address, tx, balance-fee)
+	}
+
+	code := outcome.EngineResult
+	if !strings.HasPrefix(code, "tes") && !strings.HasPrefix(code, "tec") {
+		return code
+	}
+
+	// Applied, or claimed a fee
+	root["Balance"] = itoa(toUint(root["Balance"]) - fee)
+	if seq != 0 {
+		root["Sequence"] = seq + 1
+	}
+	root["PreviousTxnID"] = hash
+
+	t := &transaction{hash: hash, tx: tx, result: code}
+	if strings.HasPrefix(code, "tes") {
+		t.events = outcome.Events
+	}
+	s.txs[hash] = t
+	s.pending = append(s.pending, t)
+	return code
+}
+
+// pay applies an XRP payment; issued currency payments only charge the fee
+func (s *Server) pay(from string, tx map[string]interface{}, available uint64) string {
+	amount, ok := tx["Amount"].(string)
+	if !ok {
+		return "tesSUCCESS"
+	}
+	drops := toUint(amount)
+	if drops > available {
+		return "tecUNFUNDED_PAYMENT"
+	}
+
+	to, _ := tx["Destination"].(string)
+	root := s.account(from)
+	root["Balance"] = itoa(toUint(root["Balance"]) - drops)
+	s.credit(to, drops)
+	return "tesSUCCESS"
+}
+
+// meta builds the transaction metadata, with its events as created
+// ContractEvent nodes
+func (t *transaction) meta() map[string]interface{} {
+	nodes := []interface{}{}
+	for _, event := range t.events {
+		nodes = append(nodes, chain.ContractEventNode(event))
+	}
+
+	return map[string]interface{}{
+		"TransactionResult": t.result,
+		"TransactionIndex":  t.txIndex,
+		"AffectedNodes":     nodes,
+	}
+}
+
+// engineCode returns the numeric code of the results the server produces
+func engineCode(code string) int {
+	switch code {
+	case "tesSUCCESS":
+		return 0
+	case "tefALREADY":
+		return -198
+	case "tefPAST_SEQ":
+		return -190
+	case "terPRE_SEQ":
+		return -92
+	case "terNO_ACCOUNT":
+		return -96
+	case "temMALFORMED":
+		return -299
+	case "telINSUF_FEE_P":
+		return -394
+	case "tecUNFUNDED_PAYMENT":
+		return 104
+	}
+	return 0
+}
+
+func itoa(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
@@ -0,0 +1,270 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultPageSize is the number of entries requested per page when
+// PageOpts.PageSize is not set
+const DefaultPageSize = 200
+
+// PageOpts configures how an iterator pages through results
+type PageOpts struct {
+	PageSize int // Entries requested per RPC call (default DefaultPageSize)
+	Limit    int // Maximum entries returned overall (0 = all)
+}
+
+// Iterator walks a paginated RPC result, following the server's marker
+// until the results or the limit are exhausted. Pages after the first are
+// read from the same ledger so the listing is consistent.
+//
+//	it := client.AccountObjectsIterator(address, "", chain.PageOpts{})
+//	for it.Next(ctx) {
+//		obj := it.Value()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	client *Client
+	method string
+	params map[string]interface{}
+	field  string // Result field holding the page's entries
+	opts   PageOpts
+
+	// convert turns a raw entry into T
+	convert func(json.RawMessage) (T, error)
+	// keep, when set, drops the entries it returns false for
+	keep func(T) bool
+
+	page    []T
+	pos     int
+	value   T
+	count   int
+	pages   int
+	marker  interface{}
+	started bool
+	done    bool
+	err     error
+}
+
+// Next advances to the next entry, fetching pages as needed. It returns
+// false when there are no more entries or an error occurred.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil || (it.opts.Limit > 0 && it.count >= it.opts.Limit) {
+		return false
+	}
+
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.value = it.page[it.pos]
+	it.pos++
+	it.count++
+	return true
+}
+
+// Value returns the current entry
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Pages returns the number of pages fetched so far
+func (it *Iterator[T]) Pages() int {
+	return it.pages
+}
+
+// Truncated reports whether the limit stopped the iteration before the
+// server ran out of results
+func (it *Iterator[T]) Truncated() bool {
+	return it.opts.Limit > 0 && it.count >= it.opts.Limit && (it.pos < len(it.page) || !it.done)
+}
+
+// All collects the remaining entries
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for it.Next(ctx) {
+		all = append(all, it.Value())
+	}
+	return all, it.Err()
+}
+
+func (it *Iterator[T]) fetch(ctx context.Context) error {
+	params := make(map[string]interface{}, len(it.params)+2)
+	for k, v := range it.params {
+		params[k] = v
+	}
+
+	pageSize := it.opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if it.opts.Limit > 0 && it.opts.Limit-it.count < pageSize {
+		pageSize = it.opts.Limit - it.count
+	}
+	params["limit"] = pageSize
+	if it.started {
+		params["marker"] = it.marker
+	}
+
+	raw, err := it.client.Call(ctx, it.method, params)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", it.method, err)
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", it.method, err)
+	}
+
+	var entries []json.RawMessage
+	if data, ok := result[it.field]; ok {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", it.field, err)
+		}
+	}
+
+	it.page = it.page[:0]
+	it.pos = 0
+	for _, entry := range entries {
+		v, err := it.convert(entry)
+		if err != nil {
+			return err
+		}
+		if it.keep != nil && !it.keep(v) {
+			continue
+		}
+		it.page = append(it.page, v)
+	}
+	it.pages++
+
+	// Pin the ledger the first page was read from
+	if !it.started {
+		it.started = true
+		if _, pinned := it.params["ledger_index"]; pinned {
+			if index := resultLedgerIndex(result); index > 0 {
+				it.params["ledger_index"] = index
+			}
+		}
+	}
+
+	it.marker = nil
+	if data, ok := result["marker"]; ok && string(data) != "null" {
+		json.Unmarshal(data, &it.marker)
+	}
+	it.done = it.marker == nil
+	return nil
+}
+
+// resultLedgerIndex returns the ledger a result was read from
+func resultLedgerIndex(result map[string]json.RawMessage) int64 {
+	for _, key := range []string{"ledger_index", "ledger_current_index"} {
+		var index int64
+		if data, ok := result[key]; ok && json.Unmarshal(data, &index) == nil && index > 0 {
+			return index
+		}
+	}
+	return 0
+}
+
+func rawEntry(data json.RawMessage) (json.RawMessage, error) {
+	return data, nil
+}
+
+func decodeEntry[T any](data json.RawMessage) (T, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("failed to parse entry: %w", err)
+	}
+	return v, nil
+}
+
+// EventIterator pages through a contract's event history
+type EventIterator = Iterator[ContractEvent]
+
+// AccountObjectsIterator pages through the objects owned by an account
+type AccountObjectsIterator = Iterator[json.RawMessage]
+
+// AccountLinesIterator pages through an account's trust lines
+type AccountLinesIterator = Iterator[TrustLine]
+
+// EventIterator returns an iterator over a contract's events
+func (c *Client) EventIterator(account string, query EventQueryOpts, opts PageOpts) *EventIterator {
+	params := eventHistoryParams(account, query)
+	delete(params, "limit")
+
+	return &EventIterator{
+		client:  c,
+		method:  "event_history",
+		params:  params,
+		field:   "events",
+		opts:    opts,
+		convert: decodeEntry[ContractEvent],
+	}
+}
+
+// AccountObjectsIterator returns an iterator over an account's objects,
+// optionally filtered by type (e.g. "contract", "contract_data")
+func (c *Client) AccountObjectsIterator(address, objType string, opts PageOpts) *AccountObjectsIterator {
+	params := map[string]interface{}{
+		"account":      address,
+		"ledger_index": "current",
+	}
+	if objType != "" {
+		params["type"] = objType
+	}
+
+	return &AccountObjectsIterator{
+		client:  c,
+		method:  "account_objects",
+		params:  params,
+		field:   "account_objects",
+		opts:    opts,
+		convert: rawEntry,
+	}
+}
+
+// AccountLinesIterator returns an iterator over an account's trust lines
+func (c *Client) AccountLinesIterator(address string, opts PageOpts) *AccountLinesIterator {
+	return &AccountLinesIterator{
+		client: c,
+		method: "account_lines",
+		params: map[string]interface{}{
+			"account":      address,
+			"ledger_index": "current",
+		},
+		field:   "lines",
+		opts:    opts,
+		convert: decodeEntry[TrustLine],
+	}
+}
+
+// ContractDataIterator returns an iterator over the contract data entries
+// of a contract, or of a user when user is set. A user's entries are
+// limited to those of the contract.
+func (c *Client) ContractDataIterator(account, user string, opts PageOpts) *AccountObjectsIterator {
+	if user == "" {
+		return c.AccountObjectsIterator(account, "contract_data", opts)
+	}
+
+	it := c.AccountObjectsIterator(user, "contract_data", opts)
+	it.keep = func(entry json.RawMessage) bool {
+		var data struct {
+			ContractAccount string `json:"ContractAccount"`
+		}
+		return json.Unmarshal(entry, &data) == nil && data.ContractAccount == account
+	}
+	return it
+}
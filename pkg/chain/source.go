@@ -0,0 +1,131 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ContractSource is a ledger entry holding contract code shared by every
+// instance that references it by hash
+type ContractSource struct {
+	Index              string                 `json:"index"`
+	LedgerEntryType    string                 `json:"LedgerEntryType"`
+	ContractHash       string                 `json:"ContractHash"`
+	ContractCode       string                 `json:"ContractCode"`
+	ReferenceCount     uint64                 `json:"ReferenceCount"`
+	Functions          json.RawMessage        `json:"Functions"`
+	InstanceParameters json.RawMessage        `json:"InstanceParameters"`
+	Extra              map[string]interface{} `json:"-"`
+}
+
+// CodeSize returns the size of the WASM code in bytes
+func (s *ContractSource) CodeSize() int {
+	return len(s.ContractCode) / 2
+}
+
+// ListContractSources enumerates every ContractSource in the validated ledger
+func (c *Client) ListContractSources(ctx context.Context) ([]ContractSource, error) {
+	sources, err := c.scanContractSources(ctx, true)
+	if typeFilterRejected(err) {
+		// Older servers do not know the type filter; scan everything instead
+		sources, err = c.scanContractSources(ctx, false)
+	}
+	return sources, err
+}
+
+// typeFilterRejected reports whether err is the server refusing the
+// ledger_data type filter
+func typeFilterRejected(err error) bool {
+	var ledgerErr *LedgerError
+	if !errors.As(err, &ledgerErr) {
+		return false
+	}
+	return ledgerErr.Code == "invalidParams" || strings.Contains(strings.ToLower(ledgerErr.Message), "type")
+}
+
+// GetContractSource looks up the ContractSource whose code hash is hash in
+// the validated ledger. A ledger index is accepted too. The error is
+// entryNotFound (see IsEntryNotFound) when there is no such entry.
+func (c *Client) GetContractSource(ctx context.Context, hash string) (*ContractSource, error) {
+	source, err := c.contractSourceEntry(ctx, map[string]interface{}{"contract_source": hash})
+	if IsEntryNotFound(err) {
+		if byIndex, indexErr := c.contractSourceEntry(ctx, map[string]interface{}{"index": hash}); indexErr == nil {
+			return byIndex, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("contract source %s not found: %w", hash, err)
+	}
+	return source, nil
+}
+
+// contractSourceEntry reads a ContractSource with ledger_entry
+func (c *Client) contractSourceEntry(ctx context.Context, params map[string]interface{}) (*ContractSource, error) {
+	params["ledger_index"] = "validated"
+	var result struct {
+		Node json.RawMessage `json:"node"`
+	}
+	if err := c.CallTyped(ctx, &result, "ledger_entry", params); err != nil {
+		return nil, err
+	}
+
+	var source ContractSource
+	if err := json.Unmarshal(result.Node, &source); err != nil {
+		return nil, fmt.Errorf("failed to parse contract source: %w", err)
+	}
+	if source.LedgerEntryType != "ContractSource" {
+		return nil, &LedgerError{Code: "entryNotFound", Message: "not a ContractSource entry"}
+	}
+	json.Unmarshal(result.Node, &source.Extra)
+	return &source, nil
+}
+
+// scanContractSources pages through ledger_data collecting ContractSource entries
+func (c *Client) scanContractSources(ctx context.Context, filtered bool) ([]ContractSource, error) {
+	var sources []ContractSource
+	var marker interface{}
+	var ledgerIndex interface{} = "validated"
+
+	for {
+		params := map[string]interface{}{
+			"ledger_index": ledgerIndex,
+			"limit":        256,
+		}
+		if filtered {
+			params["type"] = "contract_source"
+		}
+		if marker != nil {
+			params["marker"] = marker
+		}
+
+		var result struct {
+			LedgerIndex int64             `json:"ledger_index"`
+			State       []json.RawMessage `json:"state"`
+			Marker      interface{}       `json:"marker"`
+		}
+		if err := c.CallTyped(ctx, &result, "ledger_data", params); err != nil {
+			return nil, fmt.Errorf("ledger_data failed: %w", err)
+		}
+
+		for _, raw := range result.State {
+			var source ContractSource
+			if err := json.Unmarshal(raw, &source); err != nil || source.LedgerEntryType != "ContractSource" {
+				continue
+			}
+			json.Unmarshal(raw, &source.Extra)
+			sources = append(sources, source)
+		}
+
+		if result.Marker == nil {
+			return sources, nil
+		}
+		// Keep paging through the same ledger
+		marker = result.Marker
+		if result.LedgerIndex > 0 {
+			ledgerIndex = result.LedgerIndex
+		}
+	}
+}
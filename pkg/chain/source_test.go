@@ -0,0 +1,125 @@
+package chain_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/chain/mockserver"
+)
+
+func TestGetContractSource(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	const hash = "9A3F1E0C5B7D2A4E6F8091A2B3C4D5E6F708192A3B4C5D6E7F8091A2B3C4D5E6"
+	// Other sources come first; the lookup must not page through them
+	for i := 0; i < 3; i++ {
+		srv.AddObject(owner, map[string]interface{}{
+			"LedgerEntryType": "ContractSource",
+			"ContractHash":    strings.Repeat("0", 63) + string(rune('1'+i)),
+		})
+	}
+	index := srv.AddObject(owner, map[string]interface{}{
+		"LedgerEntryType": "ContractSource",
+		"ContractHash":    hash,
+		"ContractCode":    "0061736D",
+		"ReferenceCount":  2,
+	})
+
+	client := srv.Client()
+	ctx := context.Background()
+	source, err := client.GetContractSource(ctx, strings.ToLower(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.ContractHash != hash || source.Index != index || source.ReferenceCount != 2 || source.CodeSize() != 4 {
+		t.Errorf("contract source = %+v", source)
+	}
+	if srv.Calls("ledger_data") != 0 || srv.Calls("ledger_entry") != 1 {
+		t.Errorf("ledger_data called %d times and ledger_entry %d times, want 0 and 1",
+			srv.Calls("ledger_data"), srv.Calls("ledger_entry"))
+	}
+
+	// By ledger index
+	source, err = client.GetContractSource(ctx, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.ContractHash != hash {
+		t.Errorf("contract source by index = %+v", source)
+	}
+
+	// Missing entries, and entries of other types, are entryNotFound
+	other := srv.AddObject(owner, map[string]interface{}{"LedgerEntryType": "ContractData"})
+	for _, missing := range []string{strings.Repeat("F", 64), other} {
+		_, err := client.GetContractSource(ctx, missing)
+		if err == nil || !chain.IsEntryNotFound(err) {
+			t.Errorf("GetContractSource(%s) error = %v, want entryNotFound", missing, err)
+		}
+	}
+}
+
+func TestGetContractSourceError(t *testing.T) {
+	srv := mockserver.New()
+	client := srv.Client()
+	srv.Close()
+
+	// A server that cannot be reached is not the same as a missing entry
+	_, err := client.GetContractSource(context.Background(), strings.Repeat("F", 64))
+	if err == nil || chain.IsEntryNotFound(err) {
+		t.Errorf("error = %v, want a connection error", err)
+	}
+}
+
+func TestListContractSourcesFallback(t *testing.T) {
+	source := `{"LedgerEntryType": "ContractSource", "ContractHash": "AB", "index": "01"}`
+	tests := []struct {
+		name     string
+		filtered string // Response to the filtered scan
+		want     int    // Number of ledger_data calls; 0 when the listing fails
+	}{
+		{name: "filter supported", filtered: `{"status": "success", "state": [` + source + `]}`, want: 1},
+		{name: "invalid params", filtered: `{"status": "error", "error": "invalidParams", "error_message": "Invalid field 'type'."}`, want: 2},
+		{name: "unknown type", filtered: `{"status": "error", "error": "unknownOption", "error_message": "Unknown type."}`, want: 2},
+		{name: "other error", filtered: `{"status": "error", "error": "lgrNotFound", "error_message": "ledgerNotFound"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Params []map[string]interface{} `json:"params"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				calls++
+				result := `{"status": "success", "state": [` + source + `, {"LedgerEntryType": "AccountRoot"}]}`
+				if _, filtered := req.Params[0]["type"]; filtered {
+					result = tt.filtered
+				}
+				fmt.Fprintf(w, `{"result": %s}`, result)
+			}))
+			defer srv.Close()
+
+			client := chain.NewClientWithOptions(chain.ClientOptions{Endpoints: []string{srv.URL}, MaxRetries: -1})
+			sources, err := client.ListContractSources(context.Background())
+			if tt.want == 0 {
+				if err == nil || calls != 1 {
+					t.Errorf("error = %v after %d calls, want the filtered scan's error", err, calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sources) != 1 || sources[0].ContractHash != "AB" || calls != tt.want {
+				t.Errorf("sources = %+v after %d calls, want one after %d", sources, calls, tt.want)
+			}
+		})
+	}
+}
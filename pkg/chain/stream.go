@@ -0,0 +1,472 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Streams accepted by Subscription.Streams
+	StreamLedger       = "ledger"
+	StreamTransactions = "transactions"
+	StreamValidations  = "validations"
+
+	streamBufferSize   = 256
+	reconnectMinDelay  = 500 * time.Millisecond
+	reconnectMaxDelay  = 30 * time.Second
+	streamWriteTimeout = 10 * time.Second
+)
+
+// Subscription lists the streams and accounts to subscribe to
+type Subscription struct {
+	Streams          []string `json:"streams,omitempty"`
+	Accounts         []string `json:"accounts,omitempty"`
+	AccountsProposed []string `json:"accounts_proposed,omitempty"`
+}
+
+func (s Subscription) empty() bool {
+	return len(s.Streams) == 0 && len(s.Accounts) == 0 && len(s.AccountsProposed) == 0
+}
+
+// merge adds the entries of other that s does not have yet
+func (s *Subscription) merge(other Subscription) {
+	s.Streams = mergeUnique(s.Streams, other.Streams)
+	s.Accounts = mergeUnique(s.Accounts, other.Accounts)
+	s.AccountsProposed = mergeUnique(s.AccountsProposed, other.AccountsProposed)
+}
+
+func mergeUnique(list, add []string) []string {
+	for _, v := range add {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// StreamMessage is a message pushed by the server for a subscription
+type StreamMessage struct {
+	Type string // "ledgerClosed", "transaction", "validationReceived", ...
+	Raw  json.RawMessage
+}
+
+// LedgerClosed is a message from the ledger stream
+type LedgerClosed struct {
+	LedgerIndex      int64  `json:"ledger_index"`
+	LedgerHash       string `json:"ledger_hash"`
+	LedgerTime       int64  `json:"ledger_time"`
+	TxnCount         int    `json:"txn_count"`
+	FeeBase          int64  `json:"fee_base"`
+	ReserveBase      int64  `json:"reserve_base"`
+	ValidatedLedgers string `json:"validated_ledgers"`
+}
+
+// TransactionStream is a message from the transactions or accounts streams
+type TransactionStream struct {
+	Hash         string                 `json:"hash"`
+	EngineResult string                 `json:"engine_result"`
+	LedgerIndex  int64                  `json:"ledger_index"`
+	Validated    bool                   `json:"validated"`
+	Meta         map[string]interface{} `json:"meta"`
+	TxJSON       json.RawMessage        `json:"tx_json"`     // API v2
+	Transaction  json.RawMessage        `json:"transaction"` // API v1
+}
+
+// Tx returns the transaction JSON regardless of API version
+func (t *TransactionStream) Tx() json.RawMessage {
+	if len(t.TxJSON) > 0 {
+		return t.TxJSON
+	}
+	return t.Transaction
+}
+
+// TxHash returns the transaction hash regardless of API version
+func (t *TransactionStream) TxHash() string {
+	if t.Hash != "" {
+		return t.Hash
+	}
+	var tx struct {
+		Hash string `json:"hash"`
+	}
+	json.Unmarshal(t.Tx(), &tx)
+	return tx.Hash
+}
+
+// Ledger decodes a ledgerClosed message
+func (m StreamMessage) Ledger() (*LedgerClosed, error) {
+	var l LedgerClosed
+	if err := json.Unmarshal(m.Raw, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger message: %w", err)
+	}
+	return &l, nil
+}
+
+// Transaction decodes a transaction message
+func (m StreamMessage) Transaction() (*TransactionStream, error) {
+	var t TransactionStream
+	if err := json.Unmarshal(m.Raw, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse transaction message: %w", err)
+	}
+	return &t, nil
+}
+
+// wsResponse is a response to a request sent over the WebSocket
+type wsResponse struct {
+	ID           int64           `json:"id"`
+	Status       string          `json:"status"`
+	Type         string          `json:"type"`
+	Result       json.RawMessage `json:"result"`
+	Error        string          `json:"error"`
+	ErrorMessage string          `json:"error_message"`
+}
+
+// StreamClient is an XRPL WebSocket client. Besides request/response calls
+// it receives subscription messages, and reconnects and re-subscribes on
+// its own when the connection drops.
+type StreamClient struct {
+	url         string
+	dialer      *websocket.Dialer
+	onReconnect func(attempt int, err error)
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	nextID  int64
+	pending map[int64]chan *wsResponse
+	subs    Subscription
+
+	messages  chan StreamMessage
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// StreamOptions configures a StreamClient
+type StreamOptions struct {
+	// OnReconnect, if set, is called after each reconnection attempt with
+	// its error (nil once reconnected and re-subscribed)
+	OnReconnect func(attempt int, err error)
+}
+
+// DialStream opens a WebSocket connection to an XRPL server. HTTP URLs are
+// converted to their WebSocket equivalent.
+func DialStream(ctx context.Context, url string) (*StreamClient, error) {
+	return DialStreamWithOptions(ctx, url, StreamOptions{})
+}
+
+// DialStreamWithOptions opens a WebSocket connection to an XRPL server with
+// reconnection options
+func DialStreamWithOptions(ctx context.Context, url string, opts StreamOptions) (*StreamClient, error) {
+	s := &StreamClient{
+		url:         toWSURL(url),
+		dialer:      &websocket.Dialer{HandshakeTimeout: 10 * time.Second},
+		onReconnect: opts.OnReconnect,
+		pending:     make(map[int64]chan *wsResponse),
+		messages:    make(chan StreamMessage, streamBufferSize),
+		closed:      make(chan struct{}),
+	}
+
+	conn, _, err := s.dialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", s.url, err)
+	}
+	s.conn = conn
+
+	go s.run(conn)
+	return s, nil
+}
+
+// DialStream opens a WebSocket connection to the client's server
+func (c *Client) DialStream(ctx context.Context) (*StreamClient, error) {
+	return DialStream(ctx, c.url)
+}
+
+// Messages returns subscription messages. The channel is closed when the
+// client is closed.
+func (s *StreamClient) Messages() <-chan StreamMessage {
+	return s.messages
+}
+
+// Request sends a command and returns its result
+func (s *StreamClient) Request(ctx context.Context, command string, params map[string]interface{}) (json.RawMessage, error) {
+	ch := make(chan *wsResponse, 1)
+
+	s.mu.Lock()
+	conn := s.conn
+	if conn == nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("not connected to %s", s.url)
+	}
+	s.nextID++
+	id := s.nextID
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	req := map[string]interface{}{}
+	for k, v := range params {
+		req[k] = v
+	}
+	req["id"] = id
+	req["command"] = command
+
+	if err := s.write(conn, req); err != nil {
+		s.forget(id)
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, fmt.Errorf("connection lost during %s", command)
+		}
+		if resp.Status == "error" {
+			return nil, fmt.Errorf("%s failed: %s: %s", command, resp.Error, resp.ErrorMessage)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		s.forget(id)
+		return nil, ctx.Err()
+	case <-s.closed:
+		return nil, fmt.Errorf("stream client closed")
+	}
+}
+
+// Subscribe subscribes to streams and accounts. Subscriptions are restored
+// after a reconnect.
+func (s *StreamClient) Subscribe(ctx context.Context, sub Subscription) error {
+	if sub.empty() {
+		return nil
+	}
+	if _, err := s.Request(ctx, "subscribe", sub.params()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.subs.merge(sub)
+	s.mu.Unlock()
+	return nil
+}
+
+// Close closes the connection and stops reconnecting
+func (s *StreamClient) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+		if conn != nil {
+			s.writeMu.Lock()
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			s.writeMu.Unlock()
+			conn.Close()
+		}
+	})
+	return nil
+}
+
+func (sub Subscription) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	if len(sub.Streams) > 0 {
+		params["streams"] = sub.Streams
+	}
+	if len(sub.Accounts) > 0 {
+		params["accounts"] = sub.Accounts
+	}
+	if len(sub.AccountsProposed) > 0 {
+		params["accounts_proposed"] = sub.AccountsProposed
+	}
+	return params
+}
+
+func (s *StreamClient) write(conn *websocket.Conn, v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err := conn.WriteJSON(v); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	return nil
+}
+
+func (s *StreamClient) forget(id int64) {
+	s.mu.Lock()
+	delete(s.pending, id)
+	s.mu.Unlock()
+}
+
+// run reads from the connection and reconnects whenever it drops, until
+// the client is closed
+func (s *StreamClient) run(conn *websocket.Conn) {
+	defer close(s.messages)
+
+	for {
+		s.readLoop(conn)
+
+		// Requests in flight on the old connection will never be answered
+		s.mu.Lock()
+		s.conn = nil
+		for id, ch := range s.pending {
+			close(ch)
+			delete(s.pending, id)
+		}
+		s.mu.Unlock()
+
+		conn = s.reconnect()
+		if conn == nil {
+			return
+		}
+	}
+}
+
+func (s *StreamClient) readLoop(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var head struct {
+			ID   *int64 `json:"id"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(data, &head); err != nil {
+			continue
+		}
+
+		if head.Type == "response" || head.ID != nil {
+			var resp wsResponse
+			if err := json.Unmarshal(data, &resp); err != nil {
+				continue
+			}
+			s.mu.Lock()
+			ch, ok := s.pending[resp.ID]
+			delete(s.pending, resp.ID)
+			s.mu.Unlock()
+			if ok {
+				ch <- &resp
+			}
+			continue
+		}
+
+		select {
+		case s.messages <- StreamMessage{Type: head.Type, Raw: data}:
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// reconnect dials with exponential backoff and restores subscriptions. It
+// returns nil once the client is closed.
+func (s *StreamClient) reconnect() *websocket.Conn {
+	delay := reconnectMinDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-s.closed:
+			return nil
+		case <-time.After(delay):
+		}
+
+		conn, err := s.resume()
+		if s.onReconnect != nil {
+			s.onReconnect(attempt, err)
+		}
+		if err == nil {
+			return conn
+		}
+
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+}
+
+// resumeID is the request id of the re-subscribe sent by resume. Request
+// ids start at 1, so its answer cannot be mistaken for another's.
+const resumeID = 0
+
+// resume opens a new connection and re-sends the subscriptions on it. The
+// connection is only published to Request once it is subscribed again.
+func (s *StreamClient) resume() (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), streamWriteTimeout)
+	defer cancel()
+
+	conn, _, err := s.dialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	subs := s.subs
+	s.mu.Unlock()
+
+	// The read loop is not running yet, so read the subscribe response here
+	if !subs.empty() {
+		req := subs.params()
+		req["id"] = resumeID
+		req["command"] = "subscribe"
+		if err := s.write(conn, req); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		conn.SetReadDeadline(time.Now().Add(streamWriteTimeout))
+		for {
+			var resp wsResponse
+			if err := conn.ReadJSON(&resp); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("failed to re-subscribe: %w", err)
+			}
+			if resp.Type != "response" || resp.ID != resumeID {
+				continue
+			}
+			if resp.Status == "error" {
+				conn.Close()
+				return nil, fmt.Errorf("failed to re-subscribe: %s: %s", resp.Error, resp.ErrorMessage)
+			}
+			break
+		}
+		conn.SetReadDeadline(time.Time{})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		conn.Close()
+		return nil, fmt.Errorf("stream client closed")
+	default:
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// toWSURL converts an HTTP URL to a WebSocket URL for subscriptions
+func toWSURL(rawURL string) string {
+	if strings.HasPrefix(rawURL, "http://") {
+		rawURL = strings.Replace(rawURL, "http://", "ws://", 1)
+		// Local dev: JSON-RPC port 5005 maps to WS port 6006
+		rawURL = strings.Replace(rawURL, "localhost:5005", "localhost:6006", 1)
+	} else if strings.HasPrefix(rawURL, "https://") {
+		rawURL = strings.Replace(rawURL, "https://", "wss://", 1)
+	}
+	return rawURL
+}
@@ -0,0 +1,89 @@
+package chain_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+)
+
+// streamServer answers subscribe and server_info requests. It drops the
+// first connection after its subscribe, and on later connections sends an
+// unrelated response before answering the re-subscribe.
+func streamServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var conns int32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&conns, 1)
+
+		for {
+			var req map[string]interface{}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			id := req["id"]
+			switch req["command"] {
+			case "subscribe":
+				if n > 1 {
+					conn.WriteJSON(map[string]interface{}{"id": 99, "type": "response", "status": "error", "error": "stray"})
+				}
+				conn.WriteJSON(map[string]interface{}{"id": id, "type": "response", "status": "success", "result": map[string]interface{}{}})
+				if n == 1 {
+					return
+				}
+			case "server_info":
+				conn.WriteJSON(map[string]interface{}{"id": id, "type": "response", "status": "success",
+					"result": map[string]interface{}{"connection": n}})
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStreamReconnect(t *testing.T) {
+	srv := streamServer(t)
+	reconnected := make(chan error, 8)
+	ctx := context.Background()
+	stream, err := chain.DialStreamWithOptions(ctx, srv.URL, chain.StreamOptions{
+		OnReconnect: func(attempt int, err error) { reconnected <- err },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	if err := stream.Subscribe(ctx, chain.Subscription{Streams: []string{"ledger"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-reconnected:
+		if err != nil {
+			t.Fatalf("reconnect failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("did not reconnect")
+	}
+
+	result, err := stream.Request(ctx, "server_info", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(result), `"connection":2`) {
+		t.Errorf("server_info = %s, want an answer on the second connection", result)
+	}
+}
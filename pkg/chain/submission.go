@@ -0,0 +1,318 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultLedgerOffset is how many ledgers past the current one a transaction
+// stays valid when LastLedgerSequence is set automatically
+const DefaultLedgerOffset = 20
+
+// DefaultTrackTimeout bounds tracking of a transaction without
+// LastLedgerSequence, which otherwise could never be proven expired
+const DefaultTrackTimeout = 2 * time.Minute
+
+// SubmissionStatus is the final state of a submitted transaction
+type SubmissionStatus string
+
+const (
+	// SubmissionValidated means the transaction is in a validated ledger.
+	// Its TransactionResult may still be a tec code.
+	SubmissionValidated SubmissionStatus = "validated"
+	// SubmissionRejected means the transaction can never be applied
+	SubmissionRejected SubmissionStatus = "rejected"
+	// SubmissionExpired means a validated ledger past LastLedgerSequence does not contain it
+	SubmissionExpired SubmissionStatus = "expired"
+	// SubmissionPending means tracking stopped before the outcome was known
+	SubmissionPending SubmissionStatus = "pending"
+)
+
+// SignedTx is a signed transaction ready for submission
+type SignedTx struct {
+	TxBlob             string `json:"txBlob"`
+	Hash               string `json:"hash"`
+	LastLedgerSequence uint32 `json:"lastLedgerSequence,omitempty"`
+}
+
+// SubmissionResult is the outcome of a reliable submission
+type SubmissionResult struct {
+	Hash                string                 `json:"hash"`
+	Status              SubmissionStatus       `json:"status"`
+	EngineResult        string                 `json:"engineResult"`        // Preliminary result of the last submit
+	EngineResultMessage string                 `json:"engineResultMessage"` // Message for EngineResult
+	TransactionResult   string                 `json:"transactionResult"`   // Final result once validated
+	Validated           bool                   `json:"validated"`
+	LedgerIndex         int64                  `json:"ledgerIndex,omitempty"`
+	LastLedgerSequence  uint32                 `json:"lastLedgerSequence,omitempty"`
+	Attempts            int                    `json:"attempts"`
+	Meta                map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Succeeded reports whether the transaction was validated with tesSUCCESS
+func (r *SubmissionResult) Succeeded() bool {
+	return r.Validated && r.TransactionResult == "tesSUCCESS"
+}
+
+// Err returns a SubmissionError unless the transaction was validated with tesSUCCESS
+func (r *SubmissionResult) Err() error {
+	if r.Succeeded() {
+		return nil
+	}
+	return &SubmissionError{Result: r}
+}
+
+// SubmissionError is returned when a transaction is rejected, expires,
+// cannot be tracked to a final state or is validated with a tec result
+type SubmissionError struct {
+	Result *SubmissionResult
+	Err    error
+}
+
+func (e *SubmissionError) Error() string {
+	r := e.Result
+	switch r.Status {
+	case SubmissionValidated:
+		return fmt.Sprintf("transaction %s failed: %s", r.Hash, r.TransactionResult)
+	case SubmissionRejected:
+		return fmt.Sprintf("transaction %s rejected: %s - %s", r.Hash, r.EngineResult, r.EngineResultMessage)
+	case SubmissionExpired:
+		return fmt.Sprintf("transaction %s expired: not validated by LastLedgerSequence %d (last result %s)", r.Hash, r.LastLedgerSequence, r.EngineResult)
+	default:
+		if e.Err != nil {
+			return fmt.Sprintf("transaction %s not validated: %v", r.Hash, e.Err)
+		}
+		return fmt.Sprintf("transaction %s not validated", r.Hash)
+	}
+}
+
+func (e *SubmissionError) Unwrap() error {
+	return e.Err
+}
+
+// SubmitOptions configures a reliable submission
+type SubmitOptions struct {
+	Timeout        time.Duration // Upper bound on tracking; 0 tracks until expiry, or DefaultTrackTimeout without LastLedgerSequence
+	PollInterval   time.Duration // Delay between status checks (default 1s)
+	ResubmitEvery  int           // Rebroadcast after this many polls without a result (default 4)
+	MaxSubmitTries int           // Attempts for the initial submit on network errors (default 5)
+
+	// OnSubmitted is called once the initial submit is accepted, before tracking starts
+	OnSubmitted func(result *SubmissionResult)
+}
+
+func (o SubmitOptions) withDefaults() SubmitOptions {
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.ResubmitEvery <= 0 {
+		o.ResubmitEvery = 4
+	}
+	if o.MaxSubmitTries <= 0 {
+		o.MaxSubmitTries = 5
+	}
+	return o
+}
+
+// submitOutcome classifies a preliminary engine result
+type submitOutcome int
+
+const (
+	outcomeAccepted submitOutcome = iota // Applied or queued; wait for validation
+	outcomeRetry                         // Transient; rebroadcast until expiry
+	outcomeRejected                      // Can never succeed
+)
+
+// classifyEngineResult maps a preliminary result to an outcome. resubmitted
+// is true when the same blob was broadcast before.
+func classifyEngineResult(code string, resubmitted bool) submitOutcome {
+	switch {
+	case strings.HasPrefix(code, "tes"), strings.HasPrefix(code, "tec"), code == "terQUEUED":
+		return outcomeAccepted
+	case code == "tefPAST_SEQ", code == "tefALREADY":
+		// On a resubmission the sequence may be used by this very transaction
+		// on an earlier attempt; tracking resolves it by hash or by expiry.
+		// On the first attempt another transaction took it.
+		if resubmitted {
+			return outcomeAccepted
+		}
+		return outcomeRejected
+	case strings.HasPrefix(code, "ter"), strings.HasPrefix(code, "tel"):
+		return outcomeRetry
+	default:
+		return outcomeRejected
+	}
+}
+
+// LastLedgerSequence returns the current open ledger index plus offset
+func (c *Client) LastLedgerSequence(ctx context.Context, offset uint32) (uint32, error) {
+	var result struct {
+		LedgerCurrentIndex uint32 `json:"ledger_current_index"`
+	}
+	if err := c.CallTyped(ctx, &result, "ledger_current"); err != nil {
+		return 0, fmt.Errorf("ledger_current failed: %w", err)
+	}
+	return result.LedgerCurrentIndex + offset, nil
+}
+
+// ValidatedLedgerIndex returns the index of the latest validated ledger
+func (c *Client) ValidatedLedgerIndex(ctx context.Context) (int64, error) {
+	info, err := c.GetLedger(ctx, "validated")
+	if err != nil {
+		return 0, err
+	}
+	return info.LedgerIndex, nil
+}
+
+// SubmitAndTrack submits a signed transaction and tracks it until it is
+// validated or provably expired. The same blob is rebroadcast on transient
+// errors, so resubmission is idempotent. A SubmissionError is returned when
+// the transaction is rejected, expires or its outcome is still unknown when
+// the context or timeout ends.
+func (c *Client) SubmitAndTrack(ctx context.Context, signed SignedTx, opts SubmitOptions) (*SubmissionResult, error) {
+	opts = opts.withDefaults()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	result := &SubmissionResult{
+		Hash:               signed.Hash,
+		Status:             SubmissionPending,
+		LastLedgerSequence: signed.LastLedgerSequence,
+	}
+
+	// Initial submit, retried on network errors
+	var outcome submitOutcome
+	var lastErr error
+	for result.Attempts < opts.MaxSubmitTries {
+		outcome, lastErr = c.broadcast(ctx, signed.TxBlob, result)
+		if lastErr == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		sleep(ctx, time.Duration(result.Attempts)*opts.PollInterval)
+	}
+	if lastErr != nil {
+		return result, &SubmissionError{Result: result, Err: lastErr}
+	}
+	if outcome == outcomeRejected {
+		result.Status = SubmissionRejected
+		return result, &SubmissionError{Result: result}
+	}
+	if result.Hash == "" {
+		return result, &SubmissionError{Result: result, Err: errors.New("transaction hash unknown")}
+	}
+	if opts.OnSubmitted != nil {
+		opts.OnSubmitted(result)
+	}
+
+	if opts.Timeout <= 0 && result.LastLedgerSequence == 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, DefaultTrackTimeout,
+			fmt.Errorf("no LastLedgerSequence and not validated within %s", DefaultTrackTimeout))
+		defer cancel()
+	}
+
+	// Track until validated or expired
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			return result, &SubmissionError{Result: result, Err: context.Cause(ctx)}
+		case <-ticker.C:
+		}
+
+		if c.checkValidated(ctx, result) {
+			return result, nil
+		}
+
+		if result.LastLedgerSequence > 0 {
+			validated, err := c.ValidatedLedgerIndex(ctx)
+			if err == nil && validated > int64(result.LastLedgerSequence) {
+				// The ledger that could have held it is validated; look once more to rule out a race
+				if c.checkValidated(ctx, result) {
+					return result, nil
+				}
+				result.Status = SubmissionExpired
+				return result, &SubmissionError{Result: result}
+			}
+		}
+
+		if polls%opts.ResubmitEvery == 0 {
+			if outcome, err := c.broadcast(ctx, signed.TxBlob, result); err == nil && outcome == outcomeRejected {
+				// A later rebroadcast can be rejected because the first one applied
+				if c.checkValidated(ctx, result) {
+					return result, nil
+				}
+				result.Status = SubmissionRejected
+				return result, &SubmissionError{Result: result}
+			}
+		}
+	}
+}
+
+// broadcast submits the blob once and records the preliminary result
+func (c *Client) broadcast(ctx context.Context, txBlob string, result *SubmissionResult) (submitOutcome, error) {
+	result.Attempts++
+
+	resp, err := c.submitBlob(ctx, txBlob)
+	if err != nil {
+		return outcomeRetry, err
+	}
+
+	result.EngineResult = resp.EngineResult
+	result.EngineResultMessage = resp.EngineResultMessage
+
+	if len(resp.TxJSON) > 0 {
+		var txJSON struct {
+			Hash               string `json:"hash"`
+			LastLedgerSequence uint32 `json:"LastLedgerSequence"`
+		}
+		if err := json.Unmarshal(resp.TxJSON, &txJSON); err == nil {
+			if result.Hash == "" {
+				result.Hash = txJSON.Hash
+			}
+			if result.LastLedgerSequence == 0 {
+				result.LastLedgerSequence = txJSON.LastLedgerSequence
+			}
+		}
+	}
+
+	return classifyEngineResult(resp.EngineResult, result.Attempts > 1), nil
+}
+
+// checkValidated looks the transaction up and fills in the final result if it is validated
+func (c *Client) checkValidated(ctx context.Context, result *SubmissionResult) bool {
+	tx, err := c.GetTransaction(ctx, result.Hash)
+	if err != nil || !tx.Validated {
+		return false
+	}
+
+	result.Status = SubmissionValidated
+	result.Validated = true
+	result.LedgerIndex = tx.LedgerIndex
+	result.Meta = tx.Meta
+	if r, ok := tx.Meta["TransactionResult"].(string); ok {
+		result.TransactionResult = r
+	}
+	return true
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
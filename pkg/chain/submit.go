@@ -0,0 +1,88 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SubmitResult represents the result of a submit RPC call
+type SubmitResult struct {
+	EngineResult        string          `json:"engine_result"`
+	EngineResultCode    int             `json:"engine_result_code"`
+	EngineResultMessage string          `json:"engine_result_message"`
+	TxBlob              string          `json:"tx_blob"`
+	TxJSON              json.RawMessage `json:"tx_json"`
+	Accepted            bool            `json:"accepted"`
+}
+
+// Submit submits a signed transaction blob
+func (c *Client) Submit(ctx context.Context, txBlob string) (*SubmitResult, error) {
+	result, err := c.submitBlob(ctx, txBlob)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(result.EngineResult, "tes") && result.EngineResult != "terQUEUED" {
+		return result, fmt.Errorf("transaction rejected: %s - %s", result.EngineResult, result.EngineResultMessage)
+	}
+
+	return result, nil
+}
+
+// submitBlob submits a signed transaction blob without interpreting the engine result
+func (c *Client) submitBlob(ctx context.Context, txBlob string) (*SubmitResult, error) {
+	params := map[string]interface{}{
+		"tx_blob": txBlob,
+	}
+
+	var result SubmitResult
+	if err := c.CallTyped(ctx, &result, "submit", params); err != nil {
+		return nil, fmt.Errorf("submit failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// SimulateResult is the outcome of running a transaction through simulate
+type SimulateResult struct {
+	EngineResult string                 `json:"engine_result"`
+	Meta         map[string]interface{} `json:"meta"`
+}
+
+// Simulate applies an unsigned transaction to a scratch copy of the open
+// ledger without submitting it
+func (c *Client) Simulate(ctx context.Context, tx map[string]interface{}) (*SimulateResult, error) {
+	var result SimulateResult
+	if err := c.CallTyped(ctx, &result, "simulate", map[string]interface{}{"tx_json": tx}); err != nil {
+		return nil, fmt.Errorf("simulate failed: %w", err)
+	}
+	return &result, nil
+}
+
+// WaitForValidation polls for a transaction until it appears in a validated ledger
+func (c *Client) WaitForValidation(ctx context.Context, hash string, timeout time.Duration) (*TransactionInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s was not validated within %s", hash, timeout)
+		case <-ticker.C:
+			tx, err := c.GetTransaction(ctx, hash)
+			if err != nil {
+				// Transaction not yet found, keep waiting
+				continue
+			}
+			if tx.Validated {
+				return tx, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+)
+
+// Call is a decoded ContractCall with its parameters matched to the
+// contract's ABI
+type Call struct {
+	Contract string
+	Function string
+	Known    bool // The function is in the ABI
+	Args     []Arg
+}
+
+// Arg is one parameter of a contract call
+type Arg struct {
+	Name     string // From the ABI; empty when unknown
+	Type     string
+	Flag     uint32
+	Value    interface{}
+	Text     string // VL value as text, when printable
+	Expected string // ABI type when it differs from Type
+}
+
+// DescribeCall matches the parameters of a decoded ContractCall to an ABI.
+// The ABI may be nil, in which case only the types are known.
+func DescribeCall(tx map[string]interface{}, contractABI *abi.ABI) (*Call, error) {
+	if tx["TransactionType"] != "ContractCall" {
+		return nil, fmt.Errorf("not a ContractCall transaction")
+	}
+
+	call := &Call{}
+	call.Contract, _ = tx["ContractAccount"].(string)
+	if name, ok := tx["FunctionName"].(string); ok {
+		call.Function = hexText(name)
+		if call.Function == "" {
+			call.Function = name
+		}
+	}
+
+	var fn *abi.Function
+	if contractABI != nil {
+		fn, call.Known = contractABI.FindFunction(call.Function)
+	}
+
+	params, _ := tx["Parameters"].([]interface{})
+	for i, elem := range params {
+		wrapper, _ := elem.(map[string]interface{})
+		param, _ := wrapper["Parameter"].(map[string]interface{})
+		if param == nil {
+			return nil, fmt.Errorf("parameter %d is not a Parameter object", i)
+		}
+
+		arg := Arg{}
+		if flag, ok := param["ParameterFlag"].(uint32); ok {
+			arg.Flag = flag
+		}
+		if value, ok := param["ParameterValue"].(map[string]interface{}); ok {
+			arg.Type, _ = value["type"].(string)
+			arg.Value = value["value"]
+			if s, ok := arg.Value.(string); ok && arg.Type == "VL" {
+				arg.Text = hexText(s)
+			}
+		}
+
+		if fn != nil && i < len(fn.Parameters) {
+			arg.Name = fn.Parameters[i].Name
+			if expected := fn.Parameters[i].Type; !strings.EqualFold(expected, arg.Type) {
+				arg.Expected = expected
+			}
+		}
+		call.Args = append(call.Args, arg)
+	}
+
+	return call, nil
+}
+
+// hexText decodes a hex string that holds printable UTF-8 text, returning
+// "" otherwise
+func hexText(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) == 0 || !utf8.Valid(b) {
+		return ""
+	}
+	for _, r := range string(b) {
+		if r < 0x20 && r != '\n' && r != '\t' {
+			return ""
+		}
+	}
+	return string(b)
+}
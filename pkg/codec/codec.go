@@ -0,0 +1,434 @@
+// Package codec encodes and decodes XRPL binary objects (transaction blobs
+// and ledger entries), including the smart contract fields and types that
+// the xrpl-go binary codec does not know about.
+package codec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Peersyst/xrpl-go/binary-codec/definitions"
+	"github.com/Peersyst/xrpl-go/binary-codec/serdes"
+	"github.com/Peersyst/xrpl-go/binary-codec/types"
+)
+
+const (
+	objectEndMarker = 0xE1
+	arrayEndMarker  = 0xF1
+)
+
+// UnknownFieldError is returned when a binary object contains a field the
+// definitions don't describe, usually because the network is newer than
+// the definitions
+type UnknownFieldError struct {
+	TypeCode  int32
+	FieldCode int32
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field (type code %d, field code %d)", e.TypeCode, e.FieldCode)
+}
+
+// Decode decodes a hex encoded transaction or ledger entry into its JSON
+// representation
+func (d *Definitions) Decode(hexStr string) (map[string]interface{}, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(hexStr), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("nothing to decode")
+	}
+
+	p := serdes.NewBinaryParser(data, definitions.Get())
+	return d.decodeObject(p, false)
+}
+
+func (d *Definitions) readField(p *serdes.BinaryParser) (*Field, error) {
+	b, err := p.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	typeCode, fieldCode := int32(b>>4), int32(b&0x0F)
+	if typeCode == 0 {
+		t, err := p.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		typeCode = int32(t)
+	}
+	if fieldCode == 0 {
+		f, err := p.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		fieldCode = int32(f)
+	}
+
+	f, ok := d.FieldByHeader(typeCode, fieldCode)
+	if !ok {
+		return nil, &UnknownFieldError{TypeCode: typeCode, FieldCode: fieldCode}
+	}
+	return f, nil
+}
+
+func (d *Definitions) decodeObject(p *serdes.BinaryParser, nested bool) (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+	for p.HasMore() {
+		f, err := d.readField(p)
+		if err != nil {
+			return nil, err
+		}
+		if f.Name == "ObjectEndMarker" {
+			if !nested {
+				return nil, fmt.Errorf("unexpected end of object marker")
+			}
+			return obj, nil
+		}
+
+		value, err := d.decodeValue(p, f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		obj[f.Name] = value
+	}
+
+	if nested {
+		return nil, fmt.Errorf("object is missing its end marker")
+	}
+	return obj, nil
+}
+
+func (d *Definitions) decodeArray(p *serdes.BinaryParser) ([]interface{}, error) {
+	var arr []interface{}
+	for p.HasMore() {
+		f, err := d.readField(p)
+		if err != nil {
+			return nil, err
+		}
+		if f.Name == "ArrayEndMarker" {
+			return arr, nil
+		}
+		if f.Type != "STObject" {
+			return nil, fmt.Errorf("array element %s is not an object", f.Name)
+		}
+
+		obj, err := d.decodeObject(p, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		arr = append(arr, map[string]interface{}{f.Name: obj})
+	}
+	return nil, fmt.Errorf("array is missing its end marker")
+}
+
+func (d *Definitions) decodeValue(p *serdes.BinaryParser, f *Field) (interface{}, error) {
+	length := -1
+	if f.VLEncoded {
+		n, err := p.ReadVariableLength()
+		if err != nil {
+			return nil, err
+		}
+		length = n
+	}
+
+	switch f.Type {
+	case "STObject":
+		return d.decodeObject(p, true)
+	case "STArray":
+		return d.decodeArray(p)
+	case "UInt8", "UInt16", "UInt32":
+		return d.decodeUInt(p, f)
+	case "Data":
+		return decodeData(p)
+	case "DataType":
+		return decodeDataType(p)
+	case "Number":
+		return decodeNumber(p)
+	}
+
+	st := types.GetSerializedType(f.Type)
+	if st == nil {
+		return nil, fmt.Errorf("unsupported type %s", f.Type)
+	}
+	if length >= 0 {
+		return st.ToJSON(p, length)
+	}
+	return st.ToJSON(p)
+}
+
+func uintSize(typ string) int {
+	switch typ {
+	case "UInt8":
+		return 1
+	case "UInt16":
+		return 2
+	}
+	return 4
+}
+
+// decodeUInt reads an unsigned integer, resolving the transaction type,
+// ledger entry type and result enums to their names
+func (d *Definitions) decodeUInt(p *serdes.BinaryParser, f *Field) (interface{}, error) {
+	b, err := p.ReadBytes(uintSize(f.Type))
+	if err != nil {
+		return nil, err
+	}
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+
+	if enum := d.enumFor(f.Name); enum != nil {
+		if name, ok := nameOf(enum, int32(v)); ok {
+			return name, nil
+		}
+	}
+	return v, nil
+}
+
+func (d *Definitions) enumFor(field string) map[string]int32 {
+	switch field {
+	case "TransactionType":
+		return d.TransactionTypes
+	case "LedgerEntryType":
+		return d.LedgerEntryTypes
+	case "TransactionResult":
+		return d.TransactionResults
+	}
+	return nil
+}
+
+func nameOf(codes map[string]int32, code int32) (string, bool) {
+	for name, c := range codes {
+		if c == code {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Encode encodes the JSON representation of a transaction or ledger entry
+// into hex
+func (d *Definitions) Encode(obj map[string]interface{}) (string, error) {
+	b, err := d.encodeObject(obj)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// EncodeJSON encodes a JSON document into hex
+func (d *Definitions) EncodeJSON(data []byte) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+	return d.Encode(obj)
+}
+
+func (d *Definitions) encodeObject(obj map[string]interface{}) ([]byte, error) {
+	fields := make([]*Field, 0, len(obj))
+	for name := range obj {
+		f, ok := d.Fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		if !f.Serialized {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].ordinal() < fields[j].ordinal()
+	})
+
+	var buf []byte
+	for _, f := range fields {
+		value, err := d.encodeValue(f, obj[f.Name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		buf = appendField(buf, f, value)
+	}
+	return buf, nil
+}
+
+func (d *Definitions) encodeArray(v interface{}) ([]byte, error) {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an array but got %T", v)
+	}
+
+	var buf []byte
+	for i, elem := range arr {
+		wrapper, ok := elem.(map[string]interface{})
+		if !ok || len(wrapper) != 1 {
+			return nil, fmt.Errorf("element %d must be an object with a single key", i)
+		}
+		for name, inner := range wrapper {
+			f, ok := d.Fields[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+			if f.Type != "STObject" {
+				return nil, fmt.Errorf("array element %s is not an object", name)
+			}
+			value, err := d.encodeValue(f, inner)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			buf = appendField(buf, f, value)
+		}
+	}
+	return append(buf, arrayEndMarker), nil
+}
+
+// EncodeValue encodes the value of a single field, without its header or
+// length prefix
+func (d *Definitions) EncodeValue(f *Field, v interface{}) ([]byte, error) {
+	return d.encodeValue(f, v)
+}
+
+func (d *Definitions) encodeValue(f *Field, v interface{}) ([]byte, error) {
+	switch f.Type {
+	case "STObject":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object but got %T", v)
+		}
+		return d.encodeObject(obj)
+	case "STArray":
+		return d.encodeArray(v)
+	case "UInt8", "UInt16", "UInt32":
+		return d.encodeUInt(f, v)
+	case "Data":
+		return encodeData(v)
+	case "DataType":
+		return encodeDataType(v)
+	case "Number":
+		return encodeNumber(v)
+	}
+
+	st := types.GetSerializedType(f.Type)
+	if st == nil {
+		return nil, fmt.Errorf("unsupported type %s", f.Type)
+	}
+	return st.FromJSON(normalize(v))
+}
+
+func (d *Definitions) encodeUInt(f *Field, v interface{}) ([]byte, error) {
+	size := uintSize(f.Type)
+
+	var n uint64
+	switch val := v.(type) {
+	case string:
+		if enum := d.enumFor(f.Name); enum != nil {
+			if code, ok := enum[val]; ok {
+				n = uint64(code)
+				break
+			}
+		}
+		parsed, err := strconv.ParseUint(val, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", f.Type, val)
+		}
+		n = parsed
+	case json.Number:
+		parsed, err := strconv.ParseUint(val.String(), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %s", f.Type, val)
+		}
+		n = parsed
+	case float64:
+		if val < 0 || val != float64(uint64(val)) {
+			return nil, fmt.Errorf("invalid %s value %v", f.Type, val)
+		}
+		n = uint64(val)
+	case int:
+		if val < 0 {
+			return nil, fmt.Errorf("invalid %s value %d", f.Type, val)
+		}
+		n = uint64(val)
+	case uint32:
+		n = uint64(val)
+	default:
+		return nil, fmt.Errorf("expected a number but got %T", v)
+	}
+
+	if n >= 1<<(8*size) {
+		return nil, fmt.Errorf("%d does not fit in %s", n, f.Type)
+	}
+	b := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		b[i] = byte(n)
+		n >>= 8
+	}
+	return b, nil
+}
+
+// normalize converts the numbers of a JSON value decoded with UseNumber to
+// the strings xrpl-go expects
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		return val.String()
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, inner := range val {
+			out[k] = normalize(inner)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, inner := range val {
+			out[i] = normalize(inner)
+		}
+		return out
+	}
+	return v
+}
+
+func appendField(buf []byte, f *Field, value []byte) []byte {
+	buf = appendHeader(buf, f.TypeCode, f.Nth)
+	if f.VLEncoded {
+		buf = appendLength(buf, len(value))
+	}
+	buf = append(buf, value...)
+	if f.Type == "STObject" {
+		buf = append(buf, objectEndMarker)
+	}
+	return buf
+}
+
+func appendHeader(buf []byte, typeCode, fieldCode int32) []byte {
+	switch {
+	case typeCode < 16 && fieldCode < 16:
+		return append(buf, byte(typeCode<<4|fieldCode))
+	case typeCode < 16:
+		return append(buf, byte(typeCode<<4), byte(fieldCode))
+	case fieldCode < 16:
+		return append(buf, byte(fieldCode), byte(typeCode))
+	}
+	return append(buf, 0, byte(typeCode), byte(fieldCode))
+}
+
+// appendLength appends an XRPL variable length prefix
+func appendLength(buf []byte, n int) []byte {
+	switch {
+	case n <= 192:
+		return append(buf, byte(n))
+	case n <= 12480:
+		n -= 193
+		return append(buf, byte(193+(n>>8)), byte(n))
+	}
+	n -= 12481
+	return append(buf, byte(241+(n>>16)), byte(n>>8), byte(n))
+}
@@ -0,0 +1,198 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	testAccount  = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	testContract = "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf"
+)
+
+// contractDefinitions adds the contract types and fields that normally come
+// from the modules' binary codec definitions
+const contractDefinitions = `{
+	"TYPES": {"Data": 27, "DataType": 28},
+	"FIELDS": [
+		["FunctionName", {"nth": 100, "isVLEncoded": true, "isSerialized": true, "isSigningField": true, "type": "Blob"}],
+		["ParameterValue", {"nth": 1, "isVLEncoded": false, "isSerialized": true, "isSigningField": true, "type": "Data"}],
+		["ParameterType", {"nth": 1, "isVLEncoded": false, "isSerialized": true, "isSigningField": true, "type": "DataType"}]
+	]
+}`
+
+func testDefinitions(t *testing.T) *Definitions {
+	t.Helper()
+	d := DefaultDefinitions()
+	if err := d.Merge([]byte(contractDefinitions)); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func param(flag uint32, typ string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"Parameter": map[string]interface{}{
+		"ParameterFlag":  flag,
+		"ParameterValue": map[string]interface{}{"type": typ, "value": value},
+	}}
+}
+
+func TestContractCallRoundTrip(t *testing.T) {
+	d := testDefinitions(t)
+	tx := map[string]interface{}{
+		"TransactionType":      "ContractCall",
+		"Account":              testAccount,
+		"ContractAccount":      testContract,
+		"Fee":                  "1000",
+		"Sequence":             uint32(7),
+		"ComputationAllowance": uint32(1000000),
+		"FunctionName":         "7472616E73666572", // "transfer"
+		"Parameters": []interface{}{
+			param(0, "UINT8", "255"),
+			param(0, "UINT32", "42"),
+			param(0, "UINT64", "18446744073709551615"),
+			param(1, "UINT256", strings.Repeat("AB", 32)),
+			param(0, "VL", "68656C6C6F"),
+			param(0, "ACCOUNT", testAccount),
+			param(0, "AMOUNT", "1000000"),
+			param(0, "NUMBER", "-1.5"),
+			param(0, "NUMBER", "0"),
+		},
+	}
+
+	encoded, err := d.Encode(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := d.Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reencoded, err := d.Encode(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reencoded != encoded {
+		t.Errorf("re-encoded\n%s\nwant\n%s", reencoded, encoded)
+	}
+
+	if decoded["TransactionType"] != "ContractCall" || decoded["ContractAccount"] != testContract {
+		t.Errorf("decoded = %v", decoded)
+	}
+
+	call, err := DescribeCall(decoded, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.Function != "transfer" || len(call.Args) != 9 {
+		t.Fatalf("call = %+v", call)
+	}
+	var got []string
+	for _, arg := range call.Args {
+		got = append(got, fmt.Sprintf("%s=%v", arg.Type, arg.Value))
+	}
+	want := []string{
+		"UINT8=255", "UINT32=42", "UINT64=18446744073709551615", "UINT256=" + strings.Repeat("AB", 32),
+		"VL=68656C6C6F", "ACCOUNT=" + testAccount, "AMOUNT=1000000", "NUMBER=-1.5", "NUMBER=0",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("args = %q\nwant   %q", got, want)
+	}
+	if call.Args[3].Flag != 1 || call.Args[4].Text != "hello" {
+		t.Errorf("flag = %d, text = %q", call.Args[3].Flag, call.Args[4].Text)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	d := testDefinitions(t)
+	doc := fmt.Sprintf(`{"TransactionType": "ContractCall", "Account": %q, "ContractAccount": %q,
+		"Fee": "12", "Sequence": 1, "Parameters": [{"Parameter": {"ParameterFlag": 0,
+		"ParameterValue": {"type": "uint16", "value": 513}}}]}`, testAccount, testContract)
+	encoded, err := d.EncodeJSON([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := d.Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(decoded["Parameters"])
+	if !strings.Contains(string(out), `{"type":"UINT16","value":"513"}`) {
+		t.Errorf("parameters = %s", out)
+	}
+}
+
+func TestDataErrors(t *testing.T) {
+	d := testDefinitions(t)
+	tests := map[string]interface{}{
+		"unknown type":   param(0, "FLOAT", "1"),
+		"value too big":  param(0, "UINT8", "256"),
+		"bad hash":       param(0, "UINT256", "xyz"),
+		"bad number":     param(0, "NUMBER", "abc"),
+		"too precise":    param(0, "NUMBER", "1.23456789012345678"),
+		"huge exponent":  param(0, "NUMBER", "1e1000000000"),
+		"number too big": param(0, "NUMBER", "1e40000"),
+	}
+	for name, p := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := d.Encode(map[string]interface{}{
+				"TransactionType": "ContractCall",
+				"Account":         testAccount,
+				"Parameters":      []interface{}{p},
+			})
+			if err == nil {
+				t.Error("encoded an invalid parameter")
+			}
+		})
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		in       string
+		mantissa int64
+		exponent int32
+		out      string
+	}{
+		{"1", 1000000000000000, -15, "1"},
+		{"-1.5", -1500000000000000, -15, "-1.5"},
+		{"0.001", 1000000000000000, -18, "0.001"},
+		{"9999999999999999", 9999999999999999, 0, "9999999999999999"},
+		{"10000000000000000", 1000000000000000, 1, "10000000000000000"},
+		{"1.25e-20", 1250000000000000, -35, "0.0000000000000000000125"},
+		{"3e100", 3000000000000000, 85, "3" + strings.Repeat("0", 100)},
+		{"0", 0, zeroExponent, "0"},
+	}
+	for _, tt := range tests {
+		m, e, err := parseNumber(tt.in)
+		if err != nil {
+			t.Errorf("parseNumber(%q): %v", tt.in, err)
+			continue
+		}
+		if m != tt.mantissa || e != tt.exponent {
+			t.Errorf("parseNumber(%q) = %d, %d, want %d, %d", tt.in, m, e, tt.mantissa, tt.exponent)
+		}
+		if out, err := formatNumber(m, e); err != nil || out != tt.out {
+			t.Errorf("formatNumber(%d, %d) = %q, %v, want %q", m, e, out, err, tt.out)
+		}
+	}
+}
+
+func TestNumberExponentRange(t *testing.T) {
+	// A crafted Number with a huge exponent must fail instead of computing 10^exponent
+	for _, blob := range []string{"9100000000000000017FFFFFF0", "91000000000000000180000010"} {
+		if _, err := testDefinitions(t).Decode(blob); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("Decode(%s) error = %v, want out of range", blob, err)
+		}
+	}
+	for _, s := range []string{"1e1000000000", "-1e-1000000000", "1e99999999999", "1e32800", "1e-32800"} {
+		if _, _, err := parseNumber(s); err == nil {
+			t.Errorf("parseNumber(%q) succeeded", s)
+		}
+	}
+	if _, _, err := parseNumber("1e32768"); err != nil {
+		t.Errorf("parseNumber at the largest exponent: %v", err)
+	}
+}
@@ -0,0 +1,391 @@
+package codec
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/Peersyst/xrpl-go/binary-codec/definitions"
+	"github.com/Peersyst/xrpl-go/binary-codec/serdes"
+	"github.com/Peersyst/xrpl-go/binary-codec/types"
+)
+
+// dataTypes maps the contract parameter types to the serialized type codes
+// a Data field is tagged with
+var dataTypes = map[string]uint16{
+	"UINT16":   1,
+	"UINT32":   2,
+	"UINT64":   3,
+	"UINT128":  4,
+	"UINT256":  5,
+	"AMOUNT":   6,
+	"VL":       7,
+	"ACCOUNT":  8,
+	"NUMBER":   9,
+	"UINT8":    16,
+	"UINT160":  17,
+	"UINT192":  21,
+	"ISSUE":    24,
+	"CURRENCY": 26,
+}
+
+// intSizes are the byte sizes of the types rendered as decimal
+var intSizes = map[string]int{
+	"UINT8":  1,
+	"UINT16": 2,
+	"UINT32": 4,
+	"UINT64": 8,
+}
+
+// hashSizes are the byte sizes of the fixed width types rendered as hex
+var hashSizes = map[string]int{
+	"UINT128": 16,
+	"UINT160": 20,
+	"UINT192": 24,
+	"UINT256": 32,
+}
+
+func dataTypeName(code uint16) (string, bool) {
+	for name, c := range dataTypes {
+		if c == code {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func readDataType(p *serdes.BinaryParser) (string, error) {
+	b, err := p.ReadBytes(2)
+	if err != nil {
+		return "", err
+	}
+	code := binary.BigEndian.Uint16(b)
+	name, ok := dataTypeName(code)
+	if !ok {
+		return "", fmt.Errorf("unknown data type %d", code)
+	}
+	return name, nil
+}
+
+// decodeDataType decodes a parameter type declaration: {"type": "UINT32"}
+func decodeDataType(p *serdes.BinaryParser) (interface{}, error) {
+	name, err := readDataType(p)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"type": name}, nil
+}
+
+// decodeData decodes a typed parameter value: {"type": "UINT32", "value": "42"}
+func decodeData(p *serdes.BinaryParser) (interface{}, error) {
+	name, err := readDataType(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch name {
+	case "UINT8", "UINT16", "UINT32", "UINT64":
+		size := intSizes[name]
+		b, err := p.ReadBytes(size)
+		if err != nil {
+			return nil, err
+		}
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		value = strconv.FormatUint(n, 10)
+	case "UINT128", "UINT160", "UINT192", "UINT256":
+		b, err := p.ReadBytes(hashSizes[name])
+		if err != nil {
+			return nil, err
+		}
+		value = strings.ToUpper(hex.EncodeToString(b))
+	case "VL", "ACCOUNT":
+		n, err := p.ReadVariableLength()
+		if err != nil {
+			return nil, err
+		}
+		if name == "VL" {
+			value, err = (&types.Blob{}).ToJSON(p, n)
+		} else {
+			value, err = (&types.AccountID{}).ToJSON(p, n)
+		}
+		if err != nil {
+			return nil, err
+		}
+	case "AMOUNT":
+		value, err = (&types.Amount{}).ToJSON(p)
+	case "ISSUE":
+		value, err = (&types.Issue{}).ToJSON(p)
+	case "CURRENCY":
+		value, err = (&types.Currency{}).ToJSON(p)
+	case "NUMBER":
+		value, err = decodeNumber(p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s value: %w", name, err)
+	}
+
+	return map[string]interface{}{"type": name, "value": value}, nil
+}
+
+// dataTypeOf returns the type of a {"type": ...} object
+func dataTypeOf(v interface{}) (string, map[string]interface{}, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return "", nil, fmt.Errorf(`expected {"type": ..., "value": ...} but got %T`, v)
+	}
+	name, _ := obj["type"].(string)
+	name = strings.ToUpper(name)
+	if _, ok := dataTypes[name]; !ok {
+		return "", nil, fmt.Errorf("unknown data type %q", obj["type"])
+	}
+	return name, obj, nil
+}
+
+func encodeDataType(v interface{}) ([]byte, error) {
+	name, _, err := dataTypeOf(v)
+	if err != nil {
+		return nil, err
+	}
+	return binary.BigEndian.AppendUint16(nil, dataTypes[name]), nil
+}
+
+func encodeData(v interface{}) ([]byte, error) {
+	name, obj, err := dataTypeOf(v)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := obj["value"]
+	if !ok {
+		return nil, fmt.Errorf("%s value is missing", name)
+	}
+	value := normalize(raw)
+	buf := binary.BigEndian.AppendUint16(nil, dataTypes[name])
+
+	var b []byte
+	switch name {
+	case "UINT8", "UINT16", "UINT32", "UINT64":
+		size := intSizes[name]
+		s, _ := value.(string)
+		n, perr := strconv.ParseUint(s, 0, 8*size)
+		if perr != nil {
+			return nil, fmt.Errorf("invalid %s value %v", name, raw)
+		}
+		b = make([]byte, size)
+		for i := size - 1; i >= 0; i-- {
+			b[i] = byte(n)
+			n >>= 8
+		}
+	case "UINT128", "UINT160", "UINT192", "UINT256":
+		b, err = fixedHex(value, hashSizes[name])
+	case "VL":
+		b, err = encodeBlob(value)
+		if err == nil {
+			b = append(appendLength(nil, len(b)), b...)
+		}
+	case "ACCOUNT":
+		b, err = (&types.AccountID{}).FromJSON(value)
+		if err == nil {
+			b = append(appendLength(nil, len(b)), b...)
+		}
+	case "AMOUNT":
+		b, err = (&types.Amount{}).FromJSON(value)
+	case "ISSUE":
+		b, err = (&types.Issue{}).FromJSON(value)
+	case "CURRENCY":
+		b, err = (&types.Currency{}).FromJSON(value)
+	case "NUMBER":
+		b, err = encodeNumber(value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s value: %w", name, err)
+	}
+	return append(buf, b...), nil
+}
+
+// EncodeParameterValue encodes a typed parameter value, {"type": ...,
+// "value": ...}, returning its type code and the value bytes as a contract
+// reads them: VL and ACCOUNT values without their length prefix
+func EncodeParameterValue(v interface{}) (uint16, []byte, error) {
+	b, err := encodeData(v)
+	if err != nil {
+		return 0, nil, err
+	}
+	code, value := binary.BigEndian.Uint16(b), b[2:]
+	if code == dataTypes["VL"] || code == dataTypes["ACCOUNT"] {
+		n, err := serdes.NewBinaryParser(value, definitions.Get()).ReadVariableLength()
+		if err != nil {
+			return 0, nil, err
+		}
+		value = value[len(value)-n:]
+	}
+	return code, value, nil
+}
+
+func encodeBlob(v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a hex string but got %T", v)
+	}
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// fixedHex decodes a hex string or decimal integer into size bytes
+func fixedHex(v interface{}, size int) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string but got %T", v)
+	}
+
+	if !strings.HasPrefix(s, "0x") && len(s) == 2*size {
+		return hex.DecodeString(s)
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok || n.Sign() < 0 || n.BitLen() > 8*size {
+		return nil, fmt.Errorf("invalid %d byte value %q", size, s)
+	}
+	return n.FillBytes(make([]byte, size)), nil
+}
+
+// Numbers are serialized as a 64-bit signed mantissa and a 32-bit signed
+// exponent, with the mantissa normalized to 16 significant digits
+const (
+	minMantissa  = 1000000000000000
+	maxMantissa  = 9999999999999999
+	zeroExponent = math.MinInt32
+
+	// The exponent range of XRPL Numbers; anything outside it is rejected
+	// before scaling by a power of ten
+	minExponent = -32768
+	maxExponent = 32768
+)
+
+func decodeNumber(p *serdes.BinaryParser) (interface{}, error) {
+	b, err := p.ReadBytes(12)
+	if err != nil {
+		return nil, err
+	}
+	mantissa := int64(binary.BigEndian.Uint64(b[:8]))
+	exponent := int32(binary.BigEndian.Uint32(b[8:]))
+	return formatNumber(mantissa, exponent)
+}
+
+func formatNumber(mantissa int64, exponent int32) (string, error) {
+	if mantissa == 0 {
+		return "0", nil
+	}
+	if exponent < minExponent || exponent > maxExponent {
+		return "", fmt.Errorf("number exponent %d out of range", exponent)
+	}
+	r := new(big.Rat).SetInt64(mantissa)
+	scale := new(big.Rat).SetInt(pow10(abs32(exponent)))
+	if exponent >= 0 {
+		r.Mul(r, scale)
+	} else {
+		r.Quo(r, scale)
+	}
+	if r.IsInt() {
+		return r.Num().String(), nil
+	}
+	s := r.FloatString(int(abs32(exponent)))
+	return strings.TrimRight(strings.TrimRight(s, "0"), "."), nil
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+func abs32(n int32) int64 {
+	return abs64(int64(n))
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func encodeNumber(v interface{}) ([]byte, error) {
+	var s string
+	switch val := normalize(v).(type) {
+	case string:
+		s = val
+	case float64:
+		s = strconv.FormatFloat(val, 'g', -1, 64)
+	default:
+		return nil, fmt.Errorf("expected a number but got %T", v)
+	}
+
+	mantissa, exponent, err := parseNumber(s)
+	if err != nil {
+		return nil, err
+	}
+	b := binary.BigEndian.AppendUint64(nil, uint64(mantissa))
+	return binary.BigEndian.AppendUint32(b, uint32(exponent)), nil
+}
+
+// parseNumber converts a decimal string into a normalized mantissa and
+// exponent
+func parseNumber(s string) (int64, int32, error) {
+	s = strings.TrimSpace(s)
+	// Bound the written exponent first: big.Rat would expand it in full
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || abs64(exp) > maxExponent+int64(len(s)) {
+			return 0, 0, fmt.Errorf("number %q out of range", s)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid number %q", s)
+	}
+	if r.Sign() == 0 {
+		return 0, zeroExponent, nil
+	}
+
+	neg := r.Sign() < 0
+	r.Abs(r)
+
+	// Scale into [minMantissa, maxMantissa] in one step from the digit
+	// counts; the estimate is off by at most one either way
+	shift := int64(len(r.Denom().String())-len(r.Num().String())) + 15
+	if shift >= 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(shift)))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(pow10(-shift)))
+	}
+	exponent := -shift
+	ten := big.NewRat(10, 1)
+	lo, hi := big.NewRat(minMantissa, 1), big.NewRat(maxMantissa, 1)
+	for i := 0; i < 2 && r.Cmp(lo) < 0; i++ {
+		r.Mul(r, ten)
+		exponent--
+	}
+	for i := 0; i < 2 && r.Cmp(hi) > 0; i++ {
+		r.Quo(r, ten)
+		exponent++
+	}
+	if r.Cmp(lo) < 0 || r.Cmp(hi) > 0 {
+		return 0, 0, fmt.Errorf("failed to normalize %s", s)
+	}
+	if exponent < minExponent || exponent > maxExponent {
+		return 0, 0, fmt.Errorf("number %q out of range", s)
+	}
+	if !r.IsInt() {
+		return 0, 0, fmt.Errorf("%s has more than 16 significant digits", s)
+	}
+
+	m := r.Num().Int64()
+	if neg {
+		m = -m
+	}
+	return m, int32(exponent), nil
+}
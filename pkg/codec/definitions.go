@@ -0,0 +1,303 @@
+package codec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Peersyst/xrpl-go/binary-codec/definitions"
+	"github.com/xrpl-commons/bedrock/embedded"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+)
+
+// DefinitionsFile is where 'bedrock codec definitions' saves a network's
+// definitions; it is loaded automatically when present
+const DefinitionsFile = ".bedrock/definitions.json"
+
+// Field describes how a field is serialized
+type Field struct {
+	Name       string
+	Type       string
+	TypeCode   int32
+	Nth        int32
+	VLEncoded  bool
+	Serialized bool
+	Signing    bool
+
+	seq int // Definition order; later definitions win header conflicts
+}
+
+// ordinal orders fields in canonical serialization order
+func (f *Field) ordinal() int32 {
+	return f.TypeCode<<16 | f.Nth
+}
+
+type fieldHeader struct {
+	typeCode  int32
+	fieldCode int32
+}
+
+// Definitions holds the type, field and enum codes used to encode and
+// decode binary objects
+type Definitions struct {
+	Types              map[string]int32
+	Fields             map[string]*Field
+	TransactionTypes   map[string]int32
+	LedgerEntryTypes   map[string]int32
+	TransactionResults map[string]int32
+
+	byHeader map[fieldHeader]*Field
+	seq      int
+}
+
+// contractFields are the smart contract fields with the codes used by the
+// alphanet server (see embedded/modules/postinstall.js). The remaining
+// contract fields and types (ContractCode, FunctionName, Data, ...) come
+// from the binary codec definitions or the network.
+var contractFields = []Field{
+	{Name: "Functions", Type: "STArray", Nth: 32, Serialized: true, Signing: true},
+	{Name: "InstanceParameters", Type: "STArray", Nth: 33, Serialized: true, Signing: true},
+	{Name: "InstanceParameterValues", Type: "STArray", Nth: 34, Serialized: true, Signing: true},
+	{Name: "Parameters", Type: "STArray", Nth: 35, Serialized: true, Signing: true},
+	{Name: "Function", Type: "STObject", Nth: 38, Serialized: true, Signing: true},
+	{Name: "InstanceParameter", Type: "STObject", Nth: 39, Serialized: true, Signing: true},
+	{Name: "InstanceParameterValue", Type: "STObject", Nth: 40, Serialized: true, Signing: true},
+	{Name: "Parameter", Type: "STObject", Nth: 41, Serialized: true, Signing: true},
+	{Name: "ParameterFlag", Type: "UInt32", Nth: 74, Serialized: true, Signing: true},
+	{Name: "ComputationAllowance", Type: "UInt32", Nth: 72, Serialized: true, Signing: true},
+	{Name: "ContractHash", Type: "Hash256", Nth: 39, Serialized: true, Signing: true},
+	{Name: "ContractID", Type: "Hash256", Nth: 40, Serialized: true, Signing: true},
+	{Name: "ContractAccount", Type: "AccountID", Nth: 27, VLEncoded: true, Serialized: true, Signing: true},
+}
+
+var contractTransactionTypes = map[string]int32{
+	"ContractCreate":     85,
+	"ContractModify":     86,
+	"ContractDelete":     87,
+	"ContractClawback":   88,
+	"ContractUserDelete": 89,
+	"ContractCall":       90,
+}
+
+// DefaultDefinitions returns the xrpl-go definitions extended with the
+// smart contract fields and transaction types
+func DefaultDefinitions() *Definitions {
+	base := definitions.Get()
+	d := &Definitions{
+		Types:              copyCodes(base.Types),
+		Fields:             make(map[string]*Field, len(base.Fields)),
+		TransactionTypes:   copyCodes(base.TransactionTypes),
+		LedgerEntryTypes:   copyCodes(base.LedgerEntryTypes),
+		TransactionResults: copyCodes(base.TransactionResults),
+		byHeader:           make(map[fieldHeader]*Field, len(base.Fields)),
+	}
+
+	for name, fi := range base.Fields {
+		if fi.FieldInfo == nil {
+			continue
+		}
+		d.addField(Field{
+			Name:       name,
+			Type:       fi.Type,
+			Nth:        fi.Nth,
+			VLEncoded:  fi.IsVLEncoded,
+			Serialized: fi.IsSerialized,
+			Signing:    fi.IsSigningField,
+		})
+	}
+	for _, f := range contractFields {
+		d.addField(f)
+	}
+	for name, code := range contractTransactionTypes {
+		d.TransactionTypes[name] = code
+	}
+	return d
+}
+
+// LoadDefinitions returns the default definitions, updated with the binary
+// codec definitions of the installed JS modules and the project's saved
+// definitions when they exist
+func LoadDefinitions() (*Definitions, error) {
+	d := DefaultDefinitions()
+
+	if path, ok := moduleDefinitionsPath(); ok {
+		if err := d.MergeFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := os.Stat(DefinitionsFile); err == nil {
+		if err := d.MergeFile(DefinitionsFile); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// moduleDefinitionsPath finds the definitions.json of the cached
+// @transia/ripple-binary-codec without installing the modules
+func moduleDefinitionsPath() (string, bool) {
+	cacheDir, err := embedded.GetCacheDir()
+	if err != nil {
+		return "", false
+	}
+	path := filepath.Join(cacheDir, "node_modules", "@transia", "ripple-binary-codec", "dist", "enums", "definitions.json")
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// serverDefinitions is the format of definitions.json and of the
+// server_definitions RPC result
+type serverDefinitions struct {
+	Types              map[string]int32    `json:"TYPES"`
+	Fields             [][]json.RawMessage `json:"FIELDS"`
+	TransactionTypes   map[string]int32    `json:"TRANSACTION_TYPES"`
+	LedgerEntryTypes   map[string]int32    `json:"LEDGER_ENTRY_TYPES"`
+	TransactionResults map[string]int32    `json:"TRANSACTION_RESULTS"`
+}
+
+type fieldInfo struct {
+	Nth            int32  `json:"nth"`
+	IsVLEncoded    bool   `json:"isVLEncoded"`
+	IsSerialized   bool   `json:"isSerialized"`
+	IsSigningField bool   `json:"isSigningField"`
+	Type           string `json:"type"`
+}
+
+// MergeFile merges a definitions.json file
+func (d *Definitions) MergeFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read definitions: %w", err)
+	}
+	if err := d.Merge(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Merge merges definitions in the definitions.json / server_definitions
+// format. Fields and codes it defines replace the existing ones.
+func (d *Definitions) Merge(data []byte) error {
+	var doc serverDefinitions
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse definitions: %w", err)
+	}
+	if len(doc.Types) == 0 && len(doc.Fields) == 0 {
+		return fmt.Errorf("no TYPES or FIELDS in definitions")
+	}
+
+	for name, code := range doc.Types {
+		d.Types[name] = code
+	}
+	for name, code := range doc.TransactionTypes {
+		d.TransactionTypes[name] = code
+	}
+	for name, code := range doc.LedgerEntryTypes {
+		d.LedgerEntryTypes[name] = code
+	}
+	for name, code := range doc.TransactionResults {
+		d.TransactionResults[name] = code
+	}
+
+	for _, entry := range doc.Fields {
+		if len(entry) != 2 {
+			return fmt.Errorf("invalid field entry in definitions")
+		}
+		var name string
+		var info fieldInfo
+		if err := json.Unmarshal(entry[0], &name); err != nil {
+			return fmt.Errorf("invalid field name in definitions: %w", err)
+		}
+		if err := json.Unmarshal(entry[1], &info); err != nil {
+			return fmt.Errorf("invalid definition for field %s: %w", name, err)
+		}
+		d.addField(Field{
+			Name:       name,
+			Type:       info.Type,
+			Nth:        info.Nth,
+			VLEncoded:  info.IsVLEncoded,
+			Serialized: info.IsSerialized,
+			Signing:    info.IsSigningField,
+		})
+	}
+
+	// Type codes may have changed; rebuild the headers
+	d.byHeader = make(map[fieldHeader]*Field, len(d.Fields))
+	for _, f := range d.Fields {
+		f.TypeCode = d.Types[f.Type]
+		d.index(f)
+	}
+	return nil
+}
+
+func (d *Definitions) addField(f Field) {
+	if old, ok := d.Fields[f.Name]; ok {
+		delete(d.byHeader, fieldHeader{old.TypeCode, old.Nth})
+	}
+	d.seq++
+	f.seq = d.seq
+	f.TypeCode = d.Types[f.Type]
+	field := &f
+	d.Fields[f.Name] = field
+	d.index(field)
+}
+
+func (d *Definitions) index(f *Field) {
+	// Fields such as Generic and Invalid have no wire representation
+	if f.Nth <= 0 || f.TypeCode <= 0 {
+		return
+	}
+	h := fieldHeader{f.TypeCode, f.Nth}
+	if existing, ok := d.byHeader[h]; ok {
+		// Keep the serialized field when a non-serialized one shares its
+		// codes, otherwise the most recent definition
+		if existing.Serialized != f.Serialized {
+			if existing.Serialized {
+				return
+			}
+		} else if existing.seq > f.seq {
+			return
+		}
+	}
+	d.byHeader[h] = f
+}
+
+// FieldByHeader returns the field with the given type and field codes
+func (d *Definitions) FieldByHeader(typeCode, fieldCode int32) (*Field, bool) {
+	f, ok := d.byHeader[fieldHeader{typeCode, fieldCode}]
+	return f, ok
+}
+
+// FetchDefinitions returns the definitions of the network a client is
+// connected to, in definitions.json format
+func FetchDefinitions(ctx context.Context, client *chain.Client) ([]byte, error) {
+	result, err := client.Call(ctx, "server_definitions", map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("server_definitions failed: %w", err)
+	}
+
+	// Drop the RPC envelope fields, keeping the definitions
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(result, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse server_definitions result: %w", err)
+	}
+	for _, key := range []string{"status", "hash", "warnings"} {
+		delete(doc, key)
+	}
+	if _, ok := doc["FIELDS"]; !ok {
+		return nil, fmt.Errorf("server_definitions returned no FIELDS")
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func copyCodes(m map[string]int32) map[string]int32 {
+	c := make(map[string]int32, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
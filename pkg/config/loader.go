@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -19,6 +20,17 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	names := make([]string, 0, len(cfg.Networks))
+	for name := range cfg.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := cfg.Networks[name].RPC.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config: networks.%s.rpc.%w", name, err)
+		}
+	}
+
 	// Apply defaults for local node if not specified
 	if cfg.LocalNode.ConfigDir == "" {
 		cfg.LocalNode.ConfigDir = DefaultLocalNodeConfig().ConfigDir
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bedrock.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRPCDurations(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+[networks.local]
+url = "http://localhost:5005"
+
+[networks.local.rpc]
+timeout = "30s"
+retry_backoff = "250ms"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc := cfg.Networks["local"].RPC
+	if rpc.TimeoutDuration() != 30*time.Second || rpc.RetryBackoffDuration() != 250*time.Millisecond || rpc.HealthCheckDuration() != 0 {
+		t.Errorf("durations = %v, %v, %v", rpc.TimeoutDuration(), rpc.RetryBackoffDuration(), rpc.HealthCheckDuration())
+	}
+}
+
+func TestLoadRejectsInvalidDurations(t *testing.T) {
+	tests := []struct {
+		rpc  string
+		want string
+	}{
+		{`timeout = "30"`, `networks.testnet.rpc.timeout: invalid duration "30"`},
+		{`retry_backoff = "fast"`, `networks.testnet.rpc.retry_backoff: invalid duration "fast"`},
+		{`health_check = "-5s"`, `networks.testnet.rpc.health_check: invalid duration "-5s"`},
+	}
+	for _, tt := range tests {
+		_, err := Load(writeConfig(t, "[networks.testnet]\nurl = \"wss://example.com\"\n\n[networks.testnet.rpc]\n"+tt.rpc+"\n"))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.rpc, err, tt.want)
+		}
+	}
+}
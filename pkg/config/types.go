@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"runtime"
 	"time"
 )
@@ -32,10 +33,63 @@ type BuildConfig struct {
 }
 
 type NetworkConfig struct {
-	URL       string `toml:"url"`
-	NetworkID uint32 `toml:"network_id"`
-	FaucetURL string `toml:"faucet_url,omitempty"`
-	Explorer  string `toml:"explorer,omitempty"`
+	URL       string    `toml:"url"`
+	Endpoints []string  `toml:"endpoints,omitempty"` // Fallback RPC endpoints, tried in order when url fails
+	NetworkID uint32    `toml:"network_id"`
+	FaucetURL string    `toml:"faucet_url,omitempty"`
+	Explorer  string    `toml:"explorer,omitempty"`
+	MaxFee    string    `toml:"max_fee,omitempty"` // Cap in drops for "auto" fees
+	RPC       RPCConfig `toml:"rpc,omitempty"`
+}
+
+// RPCConfig tunes how RPC requests to a network are sent. Durations are
+// strings such as "30s" or "250ms"; unset fields use the client defaults.
+type RPCConfig struct {
+	Timeout      string  `toml:"timeout,omitempty"`       // Per-request timeout
+	Retries      *int    `toml:"retries,omitempty"`       // Retries for idempotent methods (0 disables)
+	RetryBackoff string  `toml:"retry_backoff,omitempty"` // Initial backoff, doubled per retry
+	RateLimit    float64 `toml:"rate_limit,omitempty"`    // Max requests per second
+	Burst        int     `toml:"burst,omitempty"`         // Requests allowed above the rate at once
+	HealthCheck  string  `toml:"health_check,omitempty"`  // How long a failed endpoint is skipped before it is checked again
+}
+
+// TimeoutDuration returns the parsed per-request timeout (0 if unset)
+func (r RPCConfig) TimeoutDuration() time.Duration {
+	return parseDuration(r.Timeout)
+}
+
+// RetryBackoffDuration returns the parsed initial retry backoff
+func (r RPCConfig) RetryBackoffDuration() time.Duration {
+	return parseDuration(r.RetryBackoff)
+}
+
+// HealthCheckDuration returns the parsed failover delay
+func (r RPCConfig) HealthCheckDuration() time.Duration {
+	return parseDuration(r.HealthCheck)
+}
+
+// Validate checks the durations, naming the offending key relative to the
+// [rpc] table
+func (r RPCConfig) Validate() error {
+	for _, f := range []struct{ key, value string }{
+		{"timeout", r.Timeout},
+		{"retry_backoff", r.RetryBackoff},
+		{"health_check", r.HealthCheck},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(f.value); err != nil || d < 0 {
+			return fmt.Errorf("%s: invalid duration %q (use a unit, e.g. \"30s\" or \"250ms\")", f.key, f.value)
+		}
+	}
+	return nil
+}
+
+// parseDuration parses a duration Validate accepted; unset is 0
+func parseDuration(s string) time.Duration {
+	d, _ := time.ParseDuration(s)
+	return d
 }
 
 type ContractConfig struct {
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -23,13 +24,14 @@ type REPL struct {
 	networkCfg      config.NetworkConfig
 	abiData         *abi.ABI
 	history         []string
+	stream          *chain.StreamClient
 }
 
 // NewREPL creates a new interactive console
 func NewREPL(cfg *config.Config, contractAccount string, walletSeed string, networkCfg config.NetworkConfig) *REPL {
 	return &REPL{
 		cfg:             cfg,
-		client:          chain.NewClient(networkCfg.URL),
+		client:          chain.NewNetworkClient(networkCfg),
 		contractAccount: contractAccount,
 		walletSeed:      walletSeed,
 		networkCfg:      networkCfg,
@@ -51,11 +53,16 @@ func (r *REPL) Run(ctx context.Context) error {
 	}
 	fmt.Println()
 	fmt.Println("Commands:")
-	fmt.Println("  call <function> [params-json]  - Call a contract function")
+	fmt.Println("  call <function> [params-json]  - Call a contract function (@view functions are simulated)")
+	fmt.Println("       [--amount <value>]        - Send drops, value/CUR/issuer or value/mpt-issuance-id")
+	fmt.Println("  simulate <function> [params]   - Simulate a call without submitting")
+	fmt.Println("  send <function> [params-json]  - Submit a call, even for @view functions")
 	fmt.Println("  info                           - Show contract info")
 	fmt.Println("  balance [address]              - Check XRP balance")
 	fmt.Println("  ledger                         - Current ledger info")
 	fmt.Println("  rpc <method> [params-json]     - Raw RPC call")
+	fmt.Println("  subscribe <stream|address>     - Stream ledger, transactions or an account")
+	fmt.Println("  unsubscribe                    - Stop all subscriptions")
 	fmt.Println("  functions                      - List available functions")
 	fmt.Println("  contract <address>             - Switch contract")
 	fmt.Println("  history                        - Show command history")
@@ -63,6 +70,8 @@ func (r *REPL) Run(ctx context.Context) error {
 	fmt.Println("  exit                           - Exit console")
 	fmt.Println()
 
+	defer r.closeStream()
+
 	scanner := bufio.NewScanner(os.Stdin)
 	for {
 		fmt.Print("bedrock> ")
@@ -86,7 +95,11 @@ func (r *REPL) Run(ctx context.Context) error {
 		case "help", "?":
 			r.showHelp()
 		case "call":
-			r.handleCall(ctx, parts[1:])
+			r.handleCall(ctx, parts[1:], callDefault)
+		case "simulate", "sim":
+			r.handleCall(ctx, parts[1:], callSimulate)
+		case "send":
+			r.handleCall(ctx, parts[1:], callSubmit)
 		case "info":
 			r.handleInfo(ctx)
 		case "balance":
@@ -95,6 +108,11 @@ func (r *REPL) Run(ctx context.Context) error {
 			r.handleLedger(ctx)
 		case "rpc":
 			r.handleRPC(ctx, parts[1:])
+		case "subscribe", "sub":
+			r.handleSubscribe(ctx, parts[1:])
+		case "unsubscribe", "unsub":
+			r.closeStream()
+			fmt.Println("  Subscriptions stopped")
 		case "functions", "funcs":
 			r.handleFunctions()
 		case "contract":
@@ -104,7 +122,7 @@ func (r *REPL) Run(ctx context.Context) error {
 		default:
 			// Try to interpret as a function call
 			if r.isFunction(cmd) {
-				r.handleCall(ctx, parts)
+				r.handleCall(ctx, parts, callDefault)
 			} else {
 				fmt.Printf("Unknown command: %s (type 'help' for commands)\n", cmd)
 			}
@@ -149,9 +167,53 @@ func (r *REPL) isFunction(name string) bool {
 	return false
 }
 
-func (r *REPL) handleCall(ctx context.Context, args []string) {
+// callMode selects whether a console call is submitted or simulated
+type callMode int
+
+const (
+	callDefault  callMode = iota // Simulate @view functions, submit the rest
+	callSimulate                 // Always simulate
+	callSubmit                   // Always submit
+)
+
+// parseCallArgs splits call arguments into the JSON parameters and an
+// optional trailing --amount. The JSON is decoded first so an "--amount"
+// inside a string parameter is left alone.
+func parseCallArgs(s string) (map[string]interface{}, string, error) {
+	var params map[string]interface{}
+	rest := s
+	if strings.HasPrefix(s, "{") {
+		dec := json.NewDecoder(strings.NewReader(s))
+		if err := dec.Decode(&params); err != nil {
+			return nil, "", fmt.Errorf("invalid JSON parameters: %w", err)
+		}
+		rest = s[dec.InputOffset():]
+	}
+
+	fields := strings.Fields(rest)
+	switch {
+	case len(fields) == 0:
+		return params, "", nil
+	case fields[0] != "--amount" && params == nil:
+		return nil, "", fmt.Errorf("invalid JSON parameters: expected an object, got %q", fields[0])
+	case fields[0] != "--amount" || len(fields) != 2:
+		return nil, "", errors.New("usage: --amount <drops | value/CUR/issuer | value/mpt-issuance-id>")
+	}
+	return params, fields[1], nil
+}
+
+// isView reports whether the ABI marks a function as read-only
+func (r *REPL) isView(name string) bool {
+	if r.abiData == nil {
+		return false
+	}
+	fn, ok := r.abiData.FindFunction(name)
+	return ok && fn.View
+}
+
+func (r *REPL) handleCall(ctx context.Context, args []string, mode callMode) {
 	if len(args) < 1 {
-		fmt.Println("Usage: call <function> [params-json]")
+		fmt.Println("Usage: call <function> [params-json] [--amount <value>]")
 		return
 	}
 
@@ -166,14 +228,10 @@ func (r *REPL) handleCall(ctx context.Context, args []string) {
 	}
 
 	functionName := args[0]
-	var params map[string]interface{}
-
-	if len(args) > 1 {
-		paramsStr := strings.Join(args[1:], " ")
-		if err := json.Unmarshal([]byte(paramsStr), &params); err != nil {
-			fmt.Printf("Invalid JSON parameters: %v\n", err)
-			return
-		}
+	params, amount, err := parseCallArgs(strings.TrimSpace(strings.Join(args[1:], " ")))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
 	}
 
 	c, err := caller.NewCaller(false)
@@ -182,17 +240,20 @@ func (r *REPL) handleCall(ctx context.Context, args []string) {
 		return
 	}
 
+	simulate := mode == callSimulate || (mode == callDefault && r.isView(functionName))
+
 	result, err := c.Call(ctx, caller.CallConfig{
 		ContractAccount:      r.contractAccount,
 		FunctionName:         functionName,
-		NetworkURL:           r.networkCfg.URL,
-		NetworkID:            r.networkCfg.NetworkID,
+		Network:              r.networkCfg,
 		WalletSeed:           r.walletSeed,
 		Algorithm:            "secp256k1",
 		ABIPath:              "abi.json",
 		Parameters:           params,
-		ComputationAllowance: "1000000",
-		Fee:                  "1000000",
+		ComputationAllowance: caller.AllowanceAuto,
+		Fee:                  chain.FeeAuto,
+		Simulate:             simulate,
+		Amount:               amount,
 	})
 
 	if err != nil {
@@ -200,7 +261,11 @@ func (r *REPL) handleCall(ctx context.Context, args []string) {
 		return
 	}
 
-	fmt.Printf("  Tx: %s\n", result.TxHash)
+	if result.Simulated {
+		fmt.Printf("  Simulated: %s (nothing submitted)\n", result.TransactionResult)
+	} else {
+		fmt.Printf("  Tx: %s\n", result.TxHash)
+	}
 	fmt.Printf("  Return code: %d\n", result.ReturnCode)
 	if result.ReturnValue != "" {
 		fmt.Printf("  Return value: %s\n", result.ReturnValue)
@@ -285,6 +350,77 @@ func (r *REPL) handleRPC(ctx context.Context, args []string) {
 	fmt.Printf("  %s\n", string(pretty))
 }
 
+func (r *REPL) handleSubscribe(ctx context.Context, args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: subscribe <ledger|transactions|address>")
+		return
+	}
+
+	var sub chain.Subscription
+	switch args[0] {
+	case chain.StreamLedger, chain.StreamTransactions:
+		sub.Streams = []string{args[0]}
+	default:
+		sub.Accounts = []string{args[0]}
+	}
+
+	if r.stream == nil {
+		stream, err := chain.DialStreamWithOptions(ctx, r.networkCfg.URL, chain.StreamOptions{
+			OnReconnect: func(attempt int, err error) {
+				if err == nil {
+					fmt.Print("\n  [stream] reconnected\nbedrock> ")
+				}
+			},
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		r.stream = stream
+		go printStream(stream)
+	}
+
+	if err := r.stream.Subscribe(ctx, sub); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("  Subscribed to %s\n", args[0])
+}
+
+// printStream prints subscription messages above the prompt until the
+// stream is closed
+func printStream(stream *chain.StreamClient) {
+	for msg := range stream.Messages() {
+		switch msg.Type {
+		case "ledgerClosed":
+			l, err := msg.Ledger()
+			if err != nil {
+				continue
+			}
+			fmt.Printf("\n  [ledger] %d | %d txs | %s\nbedrock> ", l.LedgerIndex, l.TxnCount, l.LedgerHash)
+		case "transaction":
+			tx, err := msg.Transaction()
+			if err != nil {
+				continue
+			}
+			var fields struct {
+				TransactionType string `json:"TransactionType"`
+				Account         string `json:"Account"`
+			}
+			json.Unmarshal(tx.Tx(), &fields)
+			fmt.Printf("\n  [tx] %s %s from %s: %s (ledger %d)\nbedrock> ",
+				fields.TransactionType, tx.TxHash(), fields.Account, tx.EngineResult, tx.LedgerIndex)
+		}
+	}
+}
+
+func (r *REPL) closeStream() {
+	if r.stream != nil {
+		r.stream.Close()
+		r.stream = nil
+	}
+}
+
 func (r *REPL) handleFunctions() {
 	if r.abiData == nil {
 		fmt.Println("No ABI loaded. Build your contract first.")
@@ -300,6 +436,9 @@ func (r *REPL) handleFunctions() {
 		if fn.Returns != nil {
 			retStr = fmt.Sprintf(" -> %s", fn.Returns.Type)
 		}
+		if fn.View {
+			retStr += " [view]"
+		}
 		fmt.Printf("  %s(%s)%s\n", fn.Name, strings.Join(paramTypes, ", "), retStr)
 	}
 }
@@ -325,11 +464,16 @@ func (r *REPL) handleHistory() {
 
 func (r *REPL) showHelp() {
 	fmt.Println("Commands:")
-	fmt.Println("  call <function> [params-json]  - Call a contract function")
+	fmt.Println("  call <function> [params-json]  - Call a contract function (@view functions are simulated)")
+	fmt.Println("       [--amount <value>]        - Send drops, value/CUR/issuer or value/mpt-issuance-id")
+	fmt.Println("  simulate <function> [params]   - Simulate a call without submitting")
+	fmt.Println("  send <function> [params-json]  - Submit a call, even for @view functions")
 	fmt.Println("  info                           - Show contract info")
 	fmt.Println("  balance [address]              - Check XRP balance")
 	fmt.Println("  ledger                         - Current ledger info")
 	fmt.Println("  rpc <method> [params-json]     - Raw RPC call")
+	fmt.Println("  subscribe <stream|address>     - Stream ledger, transactions or an account")
+	fmt.Println("  unsubscribe                    - Stop all subscriptions")
 	fmt.Println("  functions                      - List available functions")
 	fmt.Println("  contract <address>             - Switch contract")
 	fmt.Println("  history                        - Show command history")
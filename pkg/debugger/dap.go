@@ -0,0 +1,626 @@
+package debugger
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// threadID is the single thread a contract call runs on
+const threadID = 1
+
+// LaunchConfig is the call a DAP launch request starts
+type LaunchConfig struct {
+	Function    string                 `json:"function"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Caller      string                 `json:"caller,omitempty"`
+	Gas         uint64                 `json:"gas,omitempty"`
+	StopOnEntry bool                   `json:"stopOnEntry,omitempty"`
+}
+
+// DAPServer serves a debug session to an editor over the Debug Adapter
+// Protocol
+type DAPServer struct {
+	Session *Session
+
+	// Defaults fills the fields a launch request leaves empty
+	Defaults LaunchConfig
+
+	// OnResult is called when the call finishes
+	OnResult func(result *emulator.Result)
+
+	w      io.Writer
+	sendMu sync.Mutex
+	seq    int
+
+	mu       sync.Mutex
+	launch   *LaunchConfig
+	started  bool
+	stop     *Stop
+	refs     map[int]func() []Variable
+	nextRef  int
+	actions  chan Action
+	quit     chan struct{}
+	quitOnce sync.Once
+	done     chan struct{}
+}
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type dapResponse struct {
+	Seq        int         `json:"seq"`
+	Type       string      `json:"type"`
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int         `json:"seq"`
+	Type  string      `json:"type"`
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+	MemoryReference    string `json:"memoryReference,omitempty"`
+}
+
+// Serve handles one client until it disconnects
+func (d *DAPServer) Serve(rw io.ReadWriter) error {
+	d.w = rw
+	d.refs = make(map[int]func() []Variable)
+	d.actions = make(chan Action)
+	d.quit = make(chan struct{})
+	d.done = make(chan struct{})
+	defer d.shutdown()
+
+	d.Session.OnStop = d.onStop
+	d.Session.OnHostCall = func(call HostCall) {
+		d.output("console", "↳ "+call.String()+"\n")
+	}
+
+	r := bufio.NewReader(rw)
+	for {
+		req, err := readMessage(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if req.Type != "request" {
+			continue
+		}
+		if disconnect := d.handle(req); disconnect {
+			return nil
+		}
+	}
+}
+
+// shutdown ends a running call and waits for it
+func (d *DAPServer) shutdown() {
+	d.quitOnce.Do(func() { close(d.quit) })
+	d.Session.Pause()
+	d.mu.Lock()
+	started := d.started
+	d.mu.Unlock()
+	if started {
+		<-d.done
+	}
+}
+
+func readMessage(r *bufio.Reader) (*dapRequest, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header")
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	var req dapRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("invalid DAP message: %w", err)
+	}
+	return &req, nil
+}
+
+func (d *DAPServer) send(msg interface{}) {
+	d.sendMu.Lock()
+	defer d.sendMu.Unlock()
+	d.seq++
+	switch m := msg.(type) {
+	case *dapResponse:
+		m.Seq, m.Type = d.seq, "response"
+	case *dapEvent:
+		m.Seq, m.Type = d.seq, "event"
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(d.w, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+func (d *DAPServer) respond(req *dapRequest, body interface{}) {
+	d.send(&dapResponse{RequestSeq: req.Seq, Success: true, Command: req.Command, Body: body})
+}
+
+func (d *DAPServer) fail(req *dapRequest, format string, args ...interface{}) {
+	d.send(&dapResponse{RequestSeq: req.Seq, Command: req.Command, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *DAPServer) event(name string, body interface{}) {
+	d.send(&dapEvent{Event: name, Body: body})
+}
+
+func (d *DAPServer) output(category, text string) {
+	d.event("output", map[string]interface{}{"category": category, "output": text})
+}
+
+// handle answers a request, reporting whether the client disconnected
+func (d *DAPServer) handle(req *dapRequest) bool {
+	switch req.Command {
+	case "initialize":
+		d.respond(req, map[string]interface{}{
+			"supportsConfigurationDoneRequest": true,
+			"supportsReadMemoryRequest":        true,
+			"supportsSteppingGranularity":      true,
+			"supportsTerminateRequest":         true,
+			"supportsEvaluateForHovers":        true,
+		})
+		d.event("initialized", nil)
+
+	case "launch":
+		d.handleLaunch(req)
+
+	case "setBreakpoints":
+		d.handleSetBreakpoints(req)
+
+	case "setExceptionBreakpoints", "setFunctionBreakpoints":
+		d.respond(req, map[string]interface{}{"breakpoints": []interface{}{}})
+
+	case "configurationDone":
+		d.respond(req, nil)
+		d.start()
+
+	case "threads":
+		name := "contract"
+		if d.launch != nil {
+			name = d.launch.Function
+		}
+		d.respond(req, map[string]interface{}{
+			"threads": []map[string]interface{}{{"id": threadID, "name": name}},
+		})
+
+	case "stackTrace":
+		d.handleStackTrace(req)
+
+	case "scopes":
+		d.handleScopes(req)
+
+	case "variables":
+		d.handleVariables(req)
+
+	case "evaluate":
+		d.handleEvaluate(req)
+
+	case "readMemory":
+		d.handleReadMemory(req)
+
+	case "continue":
+		d.resume(req, Continue, map[string]interface{}{"allThreadsContinued": true})
+
+	case "next", "stepIn":
+		var args struct {
+			Granularity string `json:"granularity"`
+		}
+		json.Unmarshal(req.Arguments, &args)
+		action := StepOver
+		if req.Command == "stepIn" {
+			action = StepIn
+		}
+		if args.Granularity == "instruction" {
+			action = StepInstruction
+		}
+		d.resume(req, action, nil)
+
+	case "stepOut":
+		d.resume(req, StepOut, nil)
+
+	case "pause":
+		d.Session.Pause()
+		d.respond(req, nil)
+
+	case "terminate":
+		d.respond(req, nil)
+		d.shutdown()
+
+	case "disconnect":
+		d.respond(req, nil)
+		return true
+
+	default:
+		d.fail(req, "unsupported request: %s", req.Command)
+	}
+	return false
+}
+
+func (d *DAPServer) handleLaunch(req *dapRequest) {
+	cfg := d.Defaults
+	if len(req.Arguments) > 0 {
+		var args LaunchConfig
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			d.fail(req, "invalid launch arguments: %v", err)
+			return
+		}
+		if args.Function != "" {
+			cfg.Function = args.Function
+		}
+		if args.Params != nil {
+			cfg.Params = args.Params
+		}
+		if args.Caller != "" {
+			cfg.Caller = args.Caller
+		}
+		if args.Gas != 0 {
+			cfg.Gas = args.Gas
+		}
+		cfg.StopOnEntry = cfg.StopOnEntry || args.StopOnEntry
+	}
+	if cfg.Function == "" {
+		d.fail(req, "no function to debug; set \"function\" in the launch configuration")
+		return
+	}
+	if _, ok := d.Session.Emulator.Module.ExportType(cfg.Function); !ok {
+		d.fail(req, "function %s is not exported by the contract", cfg.Function)
+		return
+	}
+	d.launch = &cfg
+	d.respond(req, nil)
+}
+
+// start runs the launched call once the client is configured
+func (d *DAPServer) start() {
+	d.mu.Lock()
+	if d.started || d.launch == nil {
+		d.mu.Unlock()
+		return
+	}
+	d.started = true
+	cfg := *d.launch
+	d.mu.Unlock()
+
+	d.Session.StopOnEntry = cfg.StopOnEntry
+	go func() {
+		defer close(d.done)
+		result, err := d.Session.Run(cfg.Function, emulator.CallOptions{
+			Caller:   cfg.Caller,
+			Params:   cfg.Params,
+			GasLimit: cfg.Gas,
+		})
+		if err != nil {
+			d.output("stderr", err.Error()+"\n")
+			d.event("terminated", nil)
+			return
+		}
+		if d.OnResult != nil {
+			d.OnResult(result)
+		}
+
+		exitCode := 0
+		switch {
+		case result.Err != nil:
+			d.output("stderr", fmt.Sprintf("%s failed: %v (gas used %d)\n", cfg.Function, result.Err, result.GasUsed))
+			exitCode = 1
+		default:
+			category := "console"
+			if !result.Success() {
+				category, exitCode = "stderr", 1
+			}
+			d.output(category, fmt.Sprintf("%s returned %d (gas used %d)\n", cfg.Function, result.Code, result.GasUsed))
+		}
+		d.event("exited", map[string]interface{}{"exitCode": exitCode})
+		d.event("terminated", nil)
+	}()
+}
+
+// onStop reports a stop and waits for the client to resume
+func (d *DAPServer) onStop(s *Session, stop *Stop) Action {
+	select {
+	case <-d.quit:
+		return Terminate
+	default:
+	}
+
+	d.mu.Lock()
+	d.stop = stop
+	d.refs = make(map[int]func() []Variable)
+	d.nextRef = 1
+	d.mu.Unlock()
+
+	body := map[string]interface{}{
+		"reason":            stop.Reason,
+		"threadId":          threadID,
+		"allThreadsStopped": true,
+	}
+	if stop.Breakpoint != 0 {
+		body["hitBreakpointIds"] = []int{stop.Breakpoint}
+	}
+	if stop.Err != nil {
+		body["text"] = stop.Err.Error()
+		body["description"] = "Trap"
+	}
+	d.event("stopped", body)
+
+	var action Action
+	select {
+	case action = <-d.actions:
+	case <-d.quit:
+		action = Terminate
+	}
+
+	d.mu.Lock()
+	d.stop = nil
+	d.mu.Unlock()
+	return action
+}
+
+func (d *DAPServer) resume(req *dapRequest, action Action, body interface{}) {
+	d.mu.Lock()
+	stopped := d.stop != nil
+	d.mu.Unlock()
+	if !stopped {
+		d.fail(req, "the contract is not stopped")
+		return
+	}
+	d.respond(req, body)
+	select {
+	case d.actions <- action:
+	case <-d.quit:
+	}
+}
+
+// stopped returns the current stop, failing the request when running
+func (d *DAPServer) stopped(req *dapRequest) (*Stop, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop == nil {
+		d.fail(req, "the contract is not stopped")
+		return nil, false
+	}
+	return d.stop, true
+}
+
+func (d *DAPServer) handleSetBreakpoints(req *dapRequest) {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line int `json:"line"`
+		} `json:"breakpoints"`
+		Lines []int `json:"lines"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		d.fail(req, "invalid arguments: %v", err)
+		return
+	}
+	lines := args.Lines
+	if args.Breakpoints != nil {
+		lines = lines[:0]
+		for _, bp := range args.Breakpoints {
+			lines = append(lines, bp.Line)
+		}
+	}
+
+	bps := d.Session.SetBreakpoints(args.Source.Path, lines)
+	out := make([]map[string]interface{}, len(bps))
+	for i, bp := range bps {
+		b := map[string]interface{}{
+			"id":       bp.ID,
+			"verified": bp.Verified,
+			"line":     bp.Line,
+			"source":   args.Source,
+		}
+		if !bp.Verified {
+			b["message"] = "No code at this line"
+			if d.Session.Info == nil {
+				b["message"] = ErrNoDebugInfo.Error()
+			}
+		}
+		out[i] = b
+	}
+	d.respond(req, map[string]interface{}{"breakpoints": out})
+}
+
+func (d *DAPServer) handleStackTrace(req *dapRequest) {
+	stop, ok := d.stopped(req)
+	if !ok {
+		return
+	}
+	frames := make([]map[string]interface{}, len(stop.Frames))
+	for i, f := range stop.Frames {
+		frame := map[string]interface{}{
+			"id":                          f.ID,
+			"name":                        f.Function,
+			"line":                        0,
+			"column":                      0,
+			"instructionPointerReference": fmt.Sprintf("0x%x", f.PC),
+		}
+		if f.HasLine {
+			frame["line"] = f.Location.Line
+			frame["column"] = max(f.Location.Column, 1)
+			frame["source"] = dapSource{Name: filepath.Base(f.Location.File), Path: f.Location.File}
+		} else {
+			frame["presentationHint"] = "subtle"
+		}
+		frames[i] = frame
+	}
+	d.respond(req, map[string]interface{}{"stackFrames": frames, "totalFrames": len(frames)})
+}
+
+// frame returns a frame of the current stop by ID
+func (d *DAPServer) frame(req *dapRequest, id int) (StackFrame, bool) {
+	stop, ok := d.stopped(req)
+	if !ok {
+		return StackFrame{}, false
+	}
+	if id < 0 || id >= len(stop.Frames) {
+		d.fail(req, "no frame %d", id)
+		return StackFrame{}, false
+	}
+	return stop.Frames[id], true
+}
+
+// ref registers a container of variables for a variables request
+func (d *DAPServer) ref(children func() []Variable) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := d.nextRef
+	d.nextRef++
+	d.refs[id] = children
+	return id
+}
+
+func (d *DAPServer) handleScopes(req *dapRequest) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+	f, ok := d.frame(req, args.FrameID)
+	if !ok {
+		return
+	}
+	d.respond(req, map[string]interface{}{
+		"scopes": []map[string]interface{}{
+			{"name": "Locals", "presentationHint": "locals", "expensive": false,
+				"variablesReference": d.ref(func() []Variable { return d.Session.Locals(f) })},
+			{"name": "WASM Locals", "presentationHint": "registers", "expensive": false,
+				"variablesReference": d.ref(func() []Variable { return d.Session.WasmLocals(f) })},
+		},
+	})
+}
+
+func (d *DAPServer) dapVariable(v Variable) dapVariable {
+	out := dapVariable{Name: v.Name, Value: v.Value, Type: v.Type}
+	if v.HasChildren() {
+		out.VariablesReference = d.ref(v.Children)
+	}
+	if v.InMem {
+		out.MemoryReference = fmt.Sprintf("0x%x", v.Address)
+	}
+	return out
+}
+
+func (d *DAPServer) handleVariables(req *dapRequest) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+	if _, ok := d.stopped(req); !ok {
+		return
+	}
+	d.mu.Lock()
+	children, ok := d.refs[args.VariablesReference]
+	d.mu.Unlock()
+	if !ok {
+		d.fail(req, "unknown variables reference %d", args.VariablesReference)
+		return
+	}
+
+	vars := children()
+	out := make([]dapVariable, len(vars))
+	for i, v := range vars {
+		out[i] = d.dapVariable(v)
+	}
+	d.respond(req, map[string]interface{}{"variables": out})
+}
+
+func (d *DAPServer) handleEvaluate(req *dapRequest) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+	f, ok := d.frame(req, args.FrameID)
+	if !ok {
+		return
+	}
+	v, ok := d.Session.Lookup(f, strings.TrimSpace(args.Expression))
+	if !ok {
+		d.fail(req, "no variable %s in scope", args.Expression)
+		return
+	}
+	dv := d.dapVariable(v)
+	d.respond(req, map[string]interface{}{
+		"result":             dv.Value,
+		"type":               dv.Type,
+		"variablesReference": dv.VariablesReference,
+		"memoryReference":    dv.MemoryReference,
+	})
+}
+
+func (d *DAPServer) handleReadMemory(req *dapRequest) {
+	var args struct {
+		MemoryReference string `json:"memoryReference"`
+		Offset          int64  `json:"offset"`
+		Count           int    `json:"count"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+	if _, ok := d.stopped(req); !ok {
+		return
+	}
+	base, err := strconv.ParseUint(args.MemoryReference, 0, 32)
+	if err != nil {
+		d.fail(req, "invalid memory reference %s", args.MemoryReference)
+		return
+	}
+	addr := int64(base) + args.Offset
+	if addr < 0 || addr > 0xFFFFFFFF || args.Count < 0 {
+		d.fail(req, "address out of range")
+		return
+	}
+
+	// Read what is in bounds; the rest is unreadable
+	n := args.Count
+	data, ok := d.Session.ReadMemory(uint32(addr), uint32(n))
+	for !ok && n > 0 {
+		n /= 2
+		data, ok = d.Session.ReadMemory(uint32(addr), uint32(n))
+	}
+	d.respond(req, map[string]interface{}{
+		"address":         fmt.Sprintf("0x%x", addr),
+		"data":            base64.StdEncoding.EncodeToString(data),
+		"unreadableBytes": args.Count - len(data),
+	})
+}
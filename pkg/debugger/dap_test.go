@@ -0,0 +1,291 @@
+package debugger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// dapClient drives a DAPServer over a pipe
+type dapClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	seq  int
+}
+
+type dapMessage struct {
+	Type       string          `json:"type"`
+	Event      string          `json:"event"`
+	Command    string          `json:"command"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+func newDAPClient(t *testing.T, d *DAPServer) (*dapClient, chan error) {
+	server, client := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Serve(server)
+		server.Close()
+	}()
+	t.Cleanup(func() { client.Close() })
+	client.SetDeadline(time.Now().Add(10 * time.Second))
+	return &dapClient{t: t, conn: client, r: bufio.NewReader(client)}, done
+}
+
+func (c *dapClient) read() dapMessage {
+	c.t.Helper()
+	var n int
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			c.t.Fatalf("reading a DAP message: %v", err)
+		}
+		if line == "\r\n" {
+			break
+		}
+		fmt.Sscanf(line, "Content-Length: %d", &n)
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		c.t.Fatal(err)
+	}
+	var msg dapMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+// request sends a request and returns its response, collecting the events
+// sent before it
+func (c *dapClient) request(command string, args interface{}) (dapMessage, []dapMessage) {
+	c.t.Helper()
+	c.seq++
+	data, _ := json.Marshal(map[string]interface{}{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	// Send while reading, since events written by the server block the pipe
+	go fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(data), data)
+
+	var events []dapMessage
+	for {
+		msg := c.read()
+		if msg.Type == "response" && msg.RequestSeq == c.seq {
+			if msg.Command != command {
+				c.t.Fatalf("response to %s for a %s request", msg.Command, command)
+			}
+			return msg, events
+		}
+		events = append(events, msg)
+	}
+}
+
+// waitEvent reads until an event other than output
+func (c *dapClient) waitEvent() dapMessage {
+	c.t.Helper()
+	for {
+		if msg := c.read(); msg.Type == "event" && msg.Event != "output" {
+			return msg
+		}
+	}
+}
+
+func (c *dapClient) body(msg dapMessage, v interface{}) {
+	c.t.Helper()
+	if !msg.Success {
+		c.t.Fatalf("%s failed: %s", msg.Command, msg.Message)
+	}
+	if err := json.Unmarshal(msg.Body, v); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func TestDAPSession(t *testing.T) {
+	s := newTestSession(t)
+	var result *emulator.Result
+	d := &DAPServer{Session: s, OnResult: func(r *emulator.Result) { result = r }}
+	c, done := newDAPClient(t, d)
+
+	resp, _ := c.request("initialize", map[string]interface{}{"adapterID": "bedrock"})
+	if !resp.Success {
+		t.Fatalf("initialize failed: %s", resp.Message)
+	}
+	if ev := c.waitEvent(); ev.Event != "initialized" {
+		t.Fatalf("event after initialize = %s", ev.Event)
+	}
+
+	if resp, _ := c.request("launch", map[string]interface{}{"function": "nope"}); resp.Success {
+		t.Error("launched a function the contract does not export")
+	}
+	if resp, _ := c.request("launch", map[string]interface{}{"function": "increment"}); !resp.Success {
+		t.Fatalf("launch failed: %s", resp.Message)
+	}
+
+	var bps struct {
+		Breakpoints []struct {
+			ID       int  `json:"id"`
+			Verified bool `json:"verified"`
+			Line     int  `json:"line"`
+		} `json:"breakpoints"`
+	}
+	resp, _ = c.request("setBreakpoints", map[string]interface{}{
+		"source":      map[string]string{"path": "/proj/src/lib.rs"},
+		"breakpoints": []map[string]int{{"line": 8}, {"line": 40}},
+	})
+	c.body(resp, &bps)
+	if len(bps.Breakpoints) != 2 || !bps.Breakpoints[0].Verified || bps.Breakpoints[1].Verified {
+		t.Fatalf("breakpoints = %+v", bps.Breakpoints)
+	}
+
+	if resp, _ := c.request("configurationDone", nil); !resp.Success {
+		t.Fatal(resp.Message)
+	}
+	stopped := c.waitEvent()
+	var stop struct {
+		Reason string `json:"reason"`
+		Hit    []int  `json:"hitBreakpointIds"`
+	}
+	json.Unmarshal(stopped.Body, &stop)
+	if stopped.Event != "stopped" || stop.Reason != "breakpoint" || len(stop.Hit) != 1 || stop.Hit[0] != bps.Breakpoints[0].ID {
+		t.Fatalf("event = %s %s", stopped.Event, stopped.Body)
+	}
+
+	var trace struct {
+		StackFrames []struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Line   int    `json:"line"`
+			Source struct {
+				Path string `json:"path"`
+			} `json:"source"`
+		} `json:"stackFrames"`
+	}
+	resp, _ = c.request("stackTrace", map[string]int{"threadId": threadID})
+	c.body(resp, &trace)
+	if len(trace.StackFrames) != 2 || trace.StackFrames[0].Name != "counter::add" || trace.StackFrames[0].Line != 8 ||
+		trace.StackFrames[0].Source.Path != "/proj/src/lib.rs" || trace.StackFrames[1].Line != 15 {
+		t.Fatalf("stack = %+v", trace.StackFrames)
+	}
+
+	var scopes struct {
+		Scopes []struct {
+			Name string `json:"name"`
+			Ref  int    `json:"variablesReference"`
+		} `json:"scopes"`
+	}
+	resp, _ = c.request("scopes", map[string]int{"frameId": 1})
+	c.body(resp, &scopes)
+	if len(scopes.Scopes) != 2 || scopes.Scopes[0].Name != "Locals" {
+		t.Fatalf("scopes = %+v", scopes.Scopes)
+	}
+
+	type variables struct {
+		Variables []dapVariable `json:"variables"`
+	}
+	var vars variables
+	resp, _ = c.request("variables", map[string]int{"variablesReference": scopes.Scopes[0].Ref})
+	c.body(resp, &vars)
+	values := make(map[string]dapVariable)
+	for _, v := range vars.Variables {
+		values[v.Name] = v
+	}
+	p := values["p"]
+	if p.Value != "{x: 3, y: 4}" || p.VariablesReference == 0 || p.MemoryReference == "" || values["ledger"].Value != "1000" {
+		t.Fatalf("variables = %+v", vars.Variables)
+	}
+
+	resp, _ = c.request("variables", map[string]int{"variablesReference": p.VariablesReference})
+	vars = variables{}
+	c.body(resp, &vars)
+	if len(vars.Variables) != 2 || vars.Variables[1].Name != "y" || vars.Variables[1].Value != "4" {
+		t.Errorf("fields of p = %+v", vars.Variables)
+	}
+
+	var eval struct {
+		Result string `json:"result"`
+	}
+	resp, _ = c.request("evaluate", map[string]interface{}{"expression": "sum", "frameId": 0})
+	c.body(resp, &eval)
+	if eval.Result != "1003" {
+		t.Errorf("sum = %q, want 1003", eval.Result)
+	}
+	if resp, _ := c.request("evaluate", map[string]interface{}{"expression": "missing", "frameId": 0}); resp.Success {
+		t.Error("evaluated a variable that is not in scope")
+	}
+
+	var mem struct {
+		Data string `json:"data"`
+	}
+	resp, _ = c.request("readMemory", map[string]interface{}{"memoryReference": p.MemoryReference, "count": 8})
+	c.body(resp, &mem)
+	if mem.Data != "AwAAAAQAAAA=" {
+		t.Errorf("memory of p = %q, want 3 and 4", mem.Data)
+	}
+
+	if resp, _ := c.request("continue", map[string]int{"threadId": threadID}); !resp.Success {
+		t.Fatal(resp.Message)
+	}
+	var exited struct {
+		ExitCode int `json:"exitCode"`
+	}
+	ev := c.waitEvent()
+	json.Unmarshal(ev.Body, &exited)
+	if ev.Event != "exited" || exited.ExitCode != 0 {
+		t.Errorf("event = %s %s, want exited with code 0", ev.Event, ev.Body)
+	}
+	if ev := c.waitEvent(); ev.Event != "terminated" {
+		t.Errorf("event = %s, want terminated", ev.Event)
+	}
+	if result == nil || result.Code != 1003 {
+		t.Errorf("result = %+v", result)
+	}
+
+	if resp, _ := c.request("continue", map[string]int{"threadId": threadID}); resp.Success {
+		t.Error("continued a finished call")
+	}
+	if resp, _ := c.request("disconnect", nil); !resp.Success {
+		t.Fatal(resp.Message)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve() = %v", err)
+	}
+}
+
+func TestDAPTerminate(t *testing.T) {
+	s := newTestSession(t)
+	var result *emulator.Result
+	d := &DAPServer{Session: s, Defaults: LaunchConfig{Function: "increment", StopOnEntry: true},
+		OnResult: func(r *emulator.Result) { result = r }}
+	c, done := newDAPClient(t, d)
+
+	c.request("initialize", nil)
+	if resp, _ := c.request("launch", nil); !resp.Success {
+		t.Fatalf("launch with the defaults failed: %s", resp.Message)
+	}
+	c.request("configurationDone", nil)
+	for {
+		ev := c.waitEvent()
+		if ev.Event == "stopped" {
+			break
+		}
+	}
+
+	c.request("terminate", nil)
+	for ev := c.waitEvent(); ev.Event != "terminated"; ev = c.waitEvent() {
+	}
+	if result == nil || !Terminated(result) {
+		t.Errorf("result = %+v, want a terminated call", result)
+	}
+	c.request("disconnect", nil)
+	if err := <-done; err != nil {
+		t.Errorf("Serve() = %v", err)
+	}
+}
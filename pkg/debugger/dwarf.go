@@ -0,0 +1,363 @@
+package debugger
+
+import (
+	"debug/dwarf"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// ErrNoDebugInfo is returned for modules built without DWARF, such as
+// release builds
+var ErrNoDebugInfo = errors.New("the contract has no debug info (build it with 'bedrock build --release=false')")
+
+// Location is a source position
+type Location struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (l Location) String() string {
+	if l.File == "" {
+		return "??"
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// lineRow is a row of the line table. Addresses are code section offsets.
+type lineRow struct {
+	addr uint64
+	loc  Location
+	stmt bool
+	end  bool // End of a sequence; the address is past its code
+}
+
+type subprogram struct {
+	name      string
+	low, high uint64
+	frameBase locDesc
+	vars      []variable
+}
+
+type variable struct {
+	name      string
+	typ       dwarf.Type
+	typErr    error
+	loc       locDesc
+	param     bool
+	low, high uint64 // Lexical scope; 0, 0 for the whole function
+}
+
+// DebugInfo maps code addresses to source lines, functions and variables
+// using a module's DWARF sections
+type DebugInfo struct {
+	data  *dwarf.Data
+	rows  []lineRow
+	stmts map[uint64]lineRow // Statement starts by address
+	funcs []*subprogram      // Sorted by low address
+
+	// Sections dwarf.Data does not decode, for location lists
+	info, loc, loclists, addr []byte
+	versions                  map[dwarf.Offset]int // Unit versions by compile unit entry
+}
+
+// LoadDebugInfo reads the DWARF custom sections of a module
+func LoadDebugInfo(m *emulator.Module) (*DebugInfo, error) {
+	section := func(name string) []byte {
+		b, _ := m.CustomSection(name)
+		return b
+	}
+	info := section(".debug_info")
+	if info == nil {
+		return nil, ErrNoDebugInfo
+	}
+
+	data, err := dwarf.New(section(".debug_abbrev"), section(".debug_aranges"), nil, info,
+		section(".debug_line"), nil, section(".debug_ranges"), section(".debug_str"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debug info: %w", err)
+	}
+	for _, name := range []string{".debug_addr", ".debug_line_str", ".debug_str_offsets", ".debug_rnglists"} {
+		if b := section(name); b != nil {
+			if err := data.AddSection(name, b); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+		}
+	}
+
+	d := &DebugInfo{
+		data:     data,
+		stmts:    make(map[uint64]lineRow),
+		info:     info,
+		loc:      section(".debug_loc"),
+		loclists: section(".debug_loclists"),
+		addr:     section(".debug_addr"),
+	}
+	if err := d.readUnits(); err != nil {
+		return nil, fmt.Errorf("failed to read debug info: %w", err)
+	}
+	sort.SliceStable(d.rows, func(i, j int) bool { return d.rows[i].addr < d.rows[j].addr })
+	sort.Slice(d.funcs, func(i, j int) bool { return d.funcs[i].low < d.funcs[j].low })
+	return d, nil
+}
+
+func (d *DebugInfo) readUnits() error {
+	r := d.data.Reader()
+	for {
+		cu, err := r.Next()
+		if err != nil {
+			return err
+		}
+		if cu == nil {
+			return nil
+		}
+		if cu.Tag != dwarf.TagCompileUnit {
+			r.SkipChildren()
+			continue
+		}
+		if err := d.readLines(cu); err != nil {
+			return err
+		}
+		if !cu.Children {
+			continue
+		}
+		if err := d.readFuncs(r, d.unit(cu)); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *DebugInfo) readLines(cu *dwarf.Entry) error {
+	lr, err := d.data.LineReader(cu)
+	if err != nil || lr == nil {
+		return err
+	}
+	var e dwarf.LineEntry
+	for {
+		if err := lr.Next(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		row := lineRow{addr: e.Address, stmt: e.IsStmt, end: e.EndSequence}
+		if e.File != nil {
+			row.loc = Location{File: e.File.Name, Line: e.Line, Column: e.Column}
+		}
+		d.rows = append(d.rows, row)
+		if row.stmt && !row.end && row.loc.Line > 0 {
+			if _, ok := d.stmts[row.addr]; !ok {
+				d.stmts[row.addr] = row
+			}
+		}
+	}
+}
+
+// readFuncs reads the subprograms of a compile unit with their variables
+func (d *DebugInfo) readFuncs(r *dwarf.Reader, u unit) error {
+	var (
+		namespaces []string
+		stack      []dwarf.Tag // Open entries with children
+		fn         *subprogram
+		fnDepth    int
+		scopes     [][2]uint64 // Enclosing lexical blocks
+		inlined    int         // Depth of inlined subroutines being skipped
+	)
+
+	for {
+		e, err := r.Next()
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			return nil
+		}
+
+		if e.Tag == 0 {
+			if len(stack) == 0 {
+				return nil // End of the compile unit
+			}
+			tag := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch tag {
+			case dwarf.TagNamespace:
+				namespaces = namespaces[:len(namespaces)-1]
+			case dwarf.TagLexDwarfBlock:
+				if fn != nil {
+					scopes = scopes[:len(scopes)-1]
+				}
+			case dwarf.TagInlinedSubroutine:
+				inlined--
+			}
+			if fn != nil && len(stack) < fnDepth {
+				fn = nil
+			}
+			continue
+		}
+
+		switch e.Tag {
+		case dwarf.TagNamespace:
+			name, _ := e.Val(dwarf.AttrName).(string)
+			namespaces = append(namespaces, name)
+
+		case dwarf.TagSubprogram:
+			if fn != nil {
+				break // Nested declarations
+			}
+			ranges, _ := d.data.Ranges(e)
+			if len(ranges) == 0 {
+				break
+			}
+			fn = &subprogram{
+				name: d.qualifiedName(e, namespaces),
+				low:  ranges[0][0],
+				high: ranges[0][1],
+			}
+			fn.frameBase = d.location(e, dwarf.AttrFrameBase, u)
+			d.funcs = append(d.funcs, fn)
+			fnDepth = len(stack) + 1
+			scopes = scopes[:0]
+
+		case dwarf.TagLexDwarfBlock:
+			if fn != nil && e.Children {
+				scope := [2]uint64{}
+				if ranges, _ := d.data.Ranges(e); len(ranges) > 0 {
+					scope = ranges[0]
+				}
+				scopes = append(scopes, scope)
+			}
+
+		case dwarf.TagInlinedSubroutine:
+			if e.Children {
+				inlined++
+			}
+
+		case dwarf.TagFormalParameter, dwarf.TagVariable:
+			if fn != nil && inlined == 0 {
+				fn.vars = append(fn.vars, d.readVariable(e, u, scopes))
+			}
+		}
+
+		if e.Children {
+			stack = append(stack, e.Tag)
+			// Declarations and nested functions are not walked
+			if e.Tag == dwarf.TagSubprogram && (fn == nil || len(stack) > fnDepth) {
+				r.SkipChildren()
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+func (d *DebugInfo) readVariable(e *dwarf.Entry, u unit, scopes [][2]uint64) variable {
+	v := variable{param: e.Tag == dwarf.TagFormalParameter}
+	v.name, _ = e.Val(dwarf.AttrName).(string)
+	v.loc = d.location(e, dwarf.AttrLocation, u)
+	if off, ok := e.Val(dwarf.AttrType).(dwarf.Offset); ok {
+		v.typ, v.typErr = d.data.Type(off)
+	}
+	// The innermost scope with a known range applies
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if scopes[i][1] > scopes[i][0] {
+			v.low, v.high = scopes[i][0], scopes[i][1]
+			break
+		}
+	}
+	return v
+}
+
+// qualifiedName joins a subprogram's name with its namespaces, following
+// DW_AT_specification to the declaration when the definition has no name
+func (d *DebugInfo) qualifiedName(e *dwarf.Entry, namespaces []string) string {
+	name, _ := e.Val(dwarf.AttrName).(string)
+	if name == "" {
+		for _, attr := range []dwarf.Attr{dwarf.AttrSpecification, dwarf.AttrAbstractOrigin} {
+			if off, ok := e.Val(attr).(dwarf.Offset); ok {
+				r := d.data.Reader()
+				r.Seek(off)
+				if decl, err := r.Next(); err == nil && decl != nil {
+					name, _ = decl.Val(dwarf.AttrName).(string)
+				}
+				break
+			}
+		}
+	}
+	if len(namespaces) == 0 {
+		return name
+	}
+	return strings.Join(namespaces, "::") + "::" + name
+}
+
+// LineAt returns the source position of a code address
+func (d *DebugInfo) LineAt(addr uint64) (Location, bool) {
+	i := sort.Search(len(d.rows), func(i int) bool { return d.rows[i].addr > addr }) - 1
+	if i < 0 || d.rows[i].end || d.rows[i].loc.Line == 0 {
+		return Location{}, false
+	}
+	return d.rows[i].loc, true
+}
+
+// statementAt returns the line row starting a statement at an address
+func (d *DebugInfo) statementAt(addr uint64) (lineRow, bool) {
+	row, ok := d.stmts[addr]
+	return row, ok
+}
+
+// funcAt returns the subprogram containing an address
+func (d *DebugInfo) funcAt(addr uint64) *subprogram {
+	i := sort.Search(len(d.funcs), func(i int) bool { return d.funcs[i].low > addr }) - 1
+	for ; i >= 0; i-- {
+		if f := d.funcs[i]; addr >= f.low && addr < f.high {
+			return f
+		}
+	}
+	return nil
+}
+
+// Resolve returns the statement addresses for a source line. When the line
+// has no code, the next line in the file that has code is used.
+func (d *DebugInfo) Resolve(file string, line int) (int, []uint64) {
+	best := 0
+	var addrs []uint64
+	for _, row := range d.stmts {
+		if !sameFile(row.loc.File, file) || row.loc.Line < line {
+			continue
+		}
+		switch {
+		case best == 0 || row.loc.Line < best:
+			best = row.loc.Line
+			addrs = []uint64{row.addr}
+		case row.loc.Line == best:
+			addrs = append(addrs, row.addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+	return best, addrs
+}
+
+// Files lists the source files of the line table
+func (d *DebugInfo) Files() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, row := range d.stmts {
+		if !seen[row.loc.File] {
+			seen[row.loc.File] = true
+			files = append(files, row.loc.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// sameFile matches a requested path against a line table path, allowing
+// either to be a suffix of the other at a directory boundary
+func sameFile(a, b string) bool {
+	a, b = filepath.ToSlash(filepath.Clean(a)), filepath.ToSlash(filepath.Clean(b))
+	return a == b || strings.HasSuffix(a, "/"+b) || strings.HasSuffix(b, "/"+a)
+}
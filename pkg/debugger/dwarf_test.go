@@ -0,0 +1,326 @@
+package debugger
+
+import (
+	"debug/dwarf"
+	"encoding/binary"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// newTestSession loads testdata/counter.wasm, built by testdata/gen.go from
+// the lines of testdata/src/lib.rs
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+	wasm, err := os.ReadFile("testdata/counter.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := emulator.New(wasm, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSession(em)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Info == nil {
+		t.Fatal("no debug info loaded")
+	}
+	return s
+}
+
+func TestLineMapping(t *testing.T) {
+	d := newTestSession(t).Info
+
+	if files := d.Files(); !reflect.DeepEqual(files, []string{"/proj/src/lib.rs"}) {
+		t.Errorf("Files() = %v", files)
+	}
+
+	tests := []struct {
+		file     string
+		line     int
+		wantLine int
+		function string
+	}{
+		{"lib.rs", 7, 7, "counter::add"},
+		{"src/lib.rs", 8, 8, "counter::add"},
+		{"/proj/src/lib.rs", 14, 14, "counter::increment"},
+		{"lib.rs", 10, 12, "counter::increment"}, // Blank lines bind to the next statement
+		{"lib.rs", 17, 21, "counter::crash"},
+	}
+	for _, tt := range tests {
+		line, addrs := d.Resolve(tt.file, tt.line)
+		if line != tt.wantLine || len(addrs) != 1 {
+			t.Errorf("Resolve(%s, %d) = %d, %v; want line %d", tt.file, tt.line, line, addrs, tt.wantLine)
+			continue
+		}
+		loc, ok := d.LineAt(addrs[0])
+		if !ok || loc.Line != tt.wantLine || loc.File != "/proj/src/lib.rs" {
+			t.Errorf("LineAt(0x%x) = %v, %v", addrs[0], loc, ok)
+		}
+		if fn := d.funcAt(addrs[0]); fn == nil || fn.name != tt.function {
+			t.Errorf("function at line %d = %v, want %s", tt.line, fn, tt.function)
+		}
+	}
+
+	// Addresses inside a statement map to its line
+	_, addrs := d.Resolve("lib.rs", 14)
+	if loc, ok := d.LineAt(addrs[0] + 1); !ok || loc.Line != 14 {
+		t.Errorf("LineAt inside line 14 = %v, %v", loc, ok)
+	}
+
+	for _, miss := range []struct {
+		file string
+		line int
+	}{{"lib.rs", 30}, {"main.rs", 7}, {"b.rs", 7}} {
+		if line, addrs := d.Resolve(miss.file, miss.line); addrs != nil {
+			t.Errorf("Resolve(%s, %d) bound to line %d", miss.file, miss.line, line)
+		}
+	}
+}
+
+func TestNoDebugInfo(t *testing.T) {
+	// A module with one empty function and no custom sections
+	wasm := []byte("\x00asm\x01\x00\x00\x00" +
+		"\x01\x04\x01\x60\x00\x00" + // Type () -> ()
+		"\x03\x02\x01\x00" + // Function 0
+		"\x07\x05\x01\x01f\x00\x00" + // Export "f"
+		"\x0a\x04\x01\x02\x00\x0b") // Code
+	em, err := emulator.New(wasm, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDebugInfo(em.Module); !errors.Is(err, ErrNoDebugInfo) {
+		t.Errorf("LoadDebugInfo error = %v, want ErrNoDebugInfo", err)
+	}
+	s, err := NewSession(em)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Info != nil {
+		t.Error("session has debug info")
+	}
+	if bp := s.AddBreakpoint("lib.rs", 1); bp.Verified {
+		t.Error("breakpoint verified without debug info")
+	}
+}
+
+func TestUnitVersions(t *testing.T) {
+	unit := func(version int, unitType byte, body int) []byte {
+		b := binary.LittleEndian.AppendUint16(nil, uint16(version))
+		if version >= 5 {
+			b = append(b, unitType, 4)
+		}
+		b = append(b, 0, 0, 0, 0) // Abbrev offset
+		if version < 5 {
+			b = append(b, 4)
+		}
+		if unitType == 0x04 {
+			b = append(b, make([]byte, 8)...) // DWO ID
+		}
+		b = append(b, make([]byte, body)...)
+		return append(binary.LittleEndian.AppendUint32(nil, uint32(len(b))), b...)
+	}
+	info := append(unit(4, 0, 5), unit(5, 0x01, 3)...)
+	info = append(info, unit(5, 0x04, 1)...)
+
+	want := map[dwarf.Offset]int{11: 4, 16 + 12: 5, 16 + 15 + 20: 5}
+	if got := unitVersions(info); !reflect.DeepEqual(got, want) {
+		t.Errorf("unitVersions() = %v, want %v", got, want)
+	}
+}
+
+func TestLocDescAt(t *testing.T) {
+	single := locDesc{expr: []byte{1}}
+	if got := single.at(100); !reflect.DeepEqual(got, []byte{1}) {
+		t.Errorf("single expression at 100 = %v", got)
+	}
+
+	list := locDesc{isList: true, list: []locRange{{10, 20, []byte{1}}, {20, 30, []byte{2}}}}
+	for _, tt := range []struct {
+		pc   uint64
+		want []byte
+	}{{9, nil}, {10, []byte{1}}, {19, []byte{1}}, {20, []byte{2}}, {30, nil}} {
+		if got := list.at(tt.pc); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("at(%d) = %v, want %v", tt.pc, got, tt.want)
+		}
+	}
+
+	list.def = []byte{3}
+	if got := list.at(5); !reflect.DeepEqual(got, []byte{3}) {
+		t.Errorf("at(5) with a default = %v, want the default", got)
+	}
+}
+
+func u32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+func TestReadLoc(t *testing.T) {
+	expr := []byte{0xED, 0x00, 0x01}
+	entry := func(begin, end uint32) []byte {
+		return append(append(append(u32(begin), u32(end)...), byte(len(expr)), 0), expr...)
+	}
+	d := &DebugInfo{loc: append([]byte{0xAA}, append(append(append(
+		entry(4, 8),
+		append(u32(0xFFFFFFFF), u32(100)...)...), // Base address selection
+		entry(1, 2)...),
+		make([]byte, 8)...)...)}
+
+	l := d.readLoc(1, unit{base: 50})
+	if l.err != nil {
+		t.Fatal(l.err)
+	}
+	want := []locRange{{54, 58, expr}, {101, 102, expr}}
+	if !l.isList || !reflect.DeepEqual(l.list, want) {
+		t.Errorf("list = %+v, want %+v", l.list, want)
+	}
+
+	if l := d.readLoc(uint64(len(d.loc)-4), unit{}); l.err == nil || !strings.Contains(l.err.Error(), "truncated") {
+		t.Errorf("truncated list error = %v", l.err)
+	}
+	if l := d.readLoc(1000, unit{}); l.err == nil {
+		t.Error("list past the section decoded")
+	}
+}
+
+func TestReadLoclists(t *testing.T) {
+	expr := []byte{0xED, 0x00, 0x02}
+	withExpr := func(b ...byte) []byte {
+		return append(append(b, byte(len(expr))), expr...)
+	}
+	// .debug_addr: a header, then the unit's addresses
+	addr := append(make([]byte, 8), append(u32(0x100), u32(0x200)...)...)
+	u := unit{version: 5, base: 0x10, addrBase: 8}
+
+	tests := []struct {
+		name    string
+		list    []byte
+		want    []locRange
+		def     []byte
+		wantErr string
+	}{
+		{
+			name: "offset pair from the unit base",
+			list: cat(withExpr(lleOffsetPair, 2, 6), []byte{lleEndOfList}),
+			want: []locRange{{0x12, 0x16, expr}},
+		},
+		{
+			name: "base address",
+			list: cat([]byte{lleBaseAddress}, u32(0x40), withExpr(lleOffsetPair, 1, 2), []byte{lleEndOfList}),
+			want: []locRange{{0x41, 0x42, expr}},
+		},
+		{
+			name: "indexed addresses",
+			list: cat([]byte{lleBaseAddressx, 1}, withExpr(lleOffsetPair, 0, 4),
+				withExpr(lleStartxLength, 0, 8), withExpr(lleStartxEndx, 0, 1), []byte{lleEndOfList}),
+			want: []locRange{{0x200, 0x204, expr}, {0x100, 0x108, expr}, {0x100, 0x200, expr}},
+		},
+		{
+			name: "start and end or length",
+			list: cat([]byte{lleStartEnd}, u32(5), u32(9), withExpr(), []byte{lleStartLength}, u32(20), withExpr(3),
+				[]byte{lleEndOfList}),
+			want: []locRange{{5, 9, expr}, {20, 23, expr}},
+		},
+		{
+			name: "default location",
+			list: cat(withExpr(lleDefaultLocation), []byte{lleEndOfList}),
+			def:  expr,
+		},
+		{
+			name:    "unknown entry",
+			list:    []byte{0x09},
+			wantErr: "unsupported location list entry 0x09",
+		},
+		{
+			name:    "address index out of range",
+			list:    withExpr(lleStartxLength, 7, 1),
+			wantErr: "truncated",
+		},
+		{
+			name:    "missing end of list",
+			list:    withExpr(lleOffsetPair, 0, 1),
+			wantErr: "truncated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &DebugInfo{loclists: append([]byte{0xAA, 0xBB}, tt.list...), addr: addr}
+			l := d.readLoclists(2, u)
+			if tt.wantErr != "" {
+				if l.err == nil || !strings.Contains(l.err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", l.err, tt.wantErr)
+				}
+				return
+			}
+			if l.err != nil {
+				t.Fatal(l.err)
+			}
+			if !reflect.DeepEqual(l.list, tt.want) || !reflect.DeepEqual(l.def, tt.def) {
+				t.Errorf("list = %+v default %v, want %+v default %v", l.list, l.def, tt.want, tt.def)
+			}
+		})
+	}
+}
+
+func TestLocationForms(t *testing.T) {
+	expr := []byte{0xED, 0x00, 0x00}
+	// .debug_loclists: a 12 byte header, an offset table of one entry, then
+	// the list it points to
+	loclists := cat(make([]byte, 12), u32(4), []byte{lleOffsetPair, 0, 8, byte(len(expr))}, expr, []byte{lleEndOfList})
+	loc := cat(u32(0), u32(8), []byte{byte(len(expr)), 0}, expr, make([]byte, 8))
+	d := &DebugInfo{loc: loc, loclists: loclists}
+	entry := func(class dwarf.Class, val interface{}) *dwarf.Entry {
+		return &dwarf.Entry{Field: []dwarf.Field{{Attr: dwarf.AttrLocation, Val: val, Class: class}}}
+	}
+	listed := []locRange{{0, 8, expr}}
+
+	tests := []struct {
+		name    string
+		entry   *dwarf.Entry
+		u       unit
+		want    locDesc
+		wantErr string
+	}{
+		{name: "expression", entry: entry(dwarf.ClassExprLoc, expr), want: locDesc{expr: expr}},
+		{name: "block", entry: entry(dwarf.ClassBlock, expr), want: locDesc{expr: expr}},
+		{name: "missing", entry: &dwarf.Entry{}, want: locDesc{}},
+		{name: "DWARF 4 list", entry: entry(dwarf.ClassLocListPtr, int64(0)), u: unit{version: 4},
+			want: locDesc{isList: true, list: listed}},
+		{name: "DWARF 5 list", entry: entry(dwarf.ClassLocListPtr, int64(16)), u: unit{version: 5},
+			want: locDesc{isList: true, list: listed}},
+		{name: "list index", entry: entry(dwarf.ClassLocList, uint64(0)), u: unit{version: 5, loclistsBase: 12},
+			want: locDesc{isList: true, list: listed}},
+		{name: "list index out of range", entry: entry(dwarf.ClassLocList, uint64(9)), u: unit{version: 5, loclistsBase: 12},
+			wantErr: "location list index 9 is out of range"},
+		{name: "constant", entry: entry(dwarf.ClassConstant, int64(1)),
+			wantErr: "unsupported location form (ClassConstant)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.location(tt.entry, dwarf.AttrLocation, tt.u)
+			if tt.wantErr != "" {
+				if got.err == nil || got.err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", got.err, tt.wantErr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("location = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func cat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
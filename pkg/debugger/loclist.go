@@ -0,0 +1,264 @@
+package debugger
+
+import (
+	"debug/dwarf"
+	"encoding/binary"
+	"fmt"
+)
+
+// locDesc is where a value lives: one expression for its whole scope, or a
+// location list choosing the expression by code address
+type locDesc struct {
+	expr   []byte
+	isList bool
+	list   []locRange
+	def    []byte // DWARF 5 default location, used outside every range
+	err    error  // The location could not be decoded
+}
+
+type locRange struct {
+	low, high uint64
+	expr      []byte
+}
+
+// at returns the expression in effect at a code address; nil when the value
+// is not available there
+func (l locDesc) at(pc uint64) []byte {
+	if !l.isList {
+		return l.expr
+	}
+	for _, r := range l.list {
+		if pc >= r.low && pc < r.high {
+			return r.expr
+		}
+	}
+	return l.def
+}
+
+// unit holds what location lists need from a compile unit
+type unit struct {
+	version      int
+	base         uint64 // Base address of the unit's lists: its low_pc
+	addrBase     uint64
+	loclistsBase uint64
+}
+
+func (d *DebugInfo) unit(cu *dwarf.Entry) unit {
+	if d.versions == nil {
+		d.versions = unitVersions(d.info)
+	}
+	u := unit{version: d.versions[cu.Offset]}
+	u.base, _ = cu.Val(dwarf.AttrLowpc).(uint64)
+	u.addrBase = offsetVal(cu.Val(dwarf.AttrAddrBase))
+	u.loclistsBase = offsetVal(cu.Val(dwarf.AttrLoclistsBase))
+	return u
+}
+
+// unitVersions reads the unit headers of .debug_info, which dwarf.Data
+// does not expose, keyed by the offset of each unit's first entry
+func unitVersions(info []byte) map[dwarf.Offset]int {
+	versions := make(map[dwarf.Offset]int)
+	for off := 0; off+11 <= len(info); {
+		length := int(binary.LittleEndian.Uint32(info[off:]))
+		if length >= 0xFFFFFFF0 {
+			break // 64-bit DWARF; wasm32 does not use it
+		}
+		version := int(binary.LittleEndian.Uint16(info[off+4:]))
+		header := 11
+		if version >= 5 {
+			header = 12
+			switch info[off+6] {
+			case 0x04, 0x05: // Skeleton and split compile units
+				header = 20
+			case 0x02, 0x06: // Type units
+				header = 24
+			}
+		}
+		versions[dwarf.Offset(off+header)] = version
+		off += 4 + length
+	}
+	return versions
+}
+
+func offsetVal(v interface{}) uint64 {
+	switch v := v.(type) {
+	case int64:
+		return uint64(v)
+	case uint64:
+		return v
+	}
+	return 0
+}
+
+// location decodes a location attribute, following location lists. A
+// missing attribute means the value was optimized out.
+func (d *DebugInfo) location(e *dwarf.Entry, attr dwarf.Attr, u unit) locDesc {
+	f := e.AttrField(attr)
+	if f == nil {
+		return locDesc{}
+	}
+	switch f.Class {
+	case dwarf.ClassExprLoc, dwarf.ClassBlock:
+		expr, _ := f.Val.([]byte)
+		return locDesc{expr: expr}
+	case dwarf.ClassLocListPtr:
+		if u.version >= 5 {
+			return d.readLoclists(offsetVal(f.Val), u)
+		}
+		return d.readLoc(offsetVal(f.Val), u)
+	case dwarf.ClassLocList:
+		// DW_FORM_loclistx indexes the offset table after the list header
+		idx := offsetVal(f.Val)
+		at := u.loclistsBase + idx*4
+		if at+4 > uint64(len(d.loclists)) {
+			return locDesc{isList: true, err: fmt.Errorf("location list index %d is out of range", idx)}
+		}
+		return d.readLoclists(u.loclistsBase+uint64(binary.LittleEndian.Uint32(d.loclists[at:])), u)
+	}
+	return locDesc{err: fmt.Errorf("unsupported location form (%s)", f.Class)}
+}
+
+// readLoc reads a DWARF 4 location list from .debug_loc
+func (d *DebugInfo) readLoc(off uint64, u unit) locDesc {
+	l := locDesc{isList: true}
+	r := &locReader{b: d.loc, pos: off}
+	base := u.base
+	for r.err == nil {
+		begin, end := r.u32(), r.u32()
+		switch {
+		case r.err != nil:
+		case begin == 0 && end == 0:
+			return l
+		case begin == 0xFFFFFFFF: // Base address selection
+			base = end
+		default:
+			expr := r.bytes(uint64(r.u16()))
+			l.list = append(l.list, locRange{low: base + begin, high: base + end, expr: expr})
+		}
+	}
+	l.err = fmt.Errorf("location list at 0x%x is truncated", off)
+	return l
+}
+
+// DWARF 5 location list entry kinds
+const (
+	lleEndOfList       = 0x00
+	lleBaseAddressx    = 0x01
+	lleStartxEndx      = 0x02
+	lleStartxLength    = 0x03
+	lleOffsetPair      = 0x04
+	lleDefaultLocation = 0x05
+	lleBaseAddress     = 0x06
+	lleStartEnd        = 0x07
+	lleStartLength     = 0x08
+)
+
+// readLoclists reads a DWARF 5 location list from .debug_loclists
+func (d *DebugInfo) readLoclists(off uint64, u unit) locDesc {
+	l := locDesc{isList: true}
+	r := &locReader{b: d.loclists, pos: off}
+	addrx := func(idx uint64) uint64 {
+		a := &locReader{b: d.addr, pos: u.addrBase + idx*4}
+		v := a.u32()
+		if a.err != nil && r.err == nil {
+			r.err = a.err
+		}
+		return v
+	}
+
+	base := u.base
+	for r.err == nil {
+		var low, high uint64
+		switch kind := r.byte(); kind {
+		case lleEndOfList:
+			if r.err == nil {
+				return l
+			}
+			continue
+		case lleBaseAddressx:
+			base = addrx(r.uleb())
+			continue
+		case lleBaseAddress:
+			base = r.u32()
+			continue
+		case lleDefaultLocation:
+			l.def = r.bytes(r.uleb())
+			continue
+		case lleStartxEndx:
+			low = addrx(r.uleb())
+			high = addrx(r.uleb())
+		case lleStartxLength:
+			low = addrx(r.uleb())
+			high = low + r.uleb()
+		case lleOffsetPair:
+			low = base + r.uleb()
+			high = base + r.uleb()
+		case lleStartEnd:
+			low, high = r.u32(), r.u32()
+		case lleStartLength:
+			low = r.u32()
+			high = low + r.uleb()
+		default:
+			l.err = fmt.Errorf("unsupported location list entry 0x%02x", kind)
+			return l
+		}
+		l.list = append(l.list, locRange{low: low, high: high, expr: r.bytes(r.uleb())})
+	}
+	l.err = fmt.Errorf("location list at 0x%x is truncated", off)
+	return l
+}
+
+// locReader reads location list fields, recording the first overrun.
+// Addresses are 4 bytes on wasm32.
+type locReader struct {
+	b   []byte
+	pos uint64
+	err error
+}
+
+func (r *locReader) bytes(n uint64) []byte {
+	if r.err != nil || r.pos+n > uint64(len(r.b)) || r.pos+n < r.pos {
+		r.err = fmt.Errorf("unexpected end of location list")
+		return nil
+	}
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *locReader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *locReader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *locReader) u32() uint64 {
+	if b := r.bytes(4); b != nil {
+		return uint64(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *locReader) uleb() uint64 {
+	var v uint64
+	var shift uint
+	for {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		v |= uint64(b&0x7F) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+}
@@ -0,0 +1,506 @@
+// Package debugger runs contract functions in the emulator under source
+// level control: breakpoints, stepping and inspection of locals and memory.
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// Action tells a stopped session how to continue
+type Action int
+
+const (
+	Continue Action = iota
+	StepIn
+	StepOver
+	StepOut
+	StepInstruction
+	Terminate
+)
+
+// Stop reasons
+const (
+	ReasonEntry      = "entry"
+	ReasonBreakpoint = "breakpoint"
+	ReasonStep       = "step"
+	ReasonPause      = "pause"
+	ReasonException  = "exception"
+)
+
+// errTerminated aborts a call stopped by Terminate
+var errTerminated = errors.New("terminated by the debugger")
+
+// StackFrame is a frame of a stopped call
+type StackFrame struct {
+	ID       int // Position on the stack; 0 is the innermost frame
+	Function string
+	Location Location
+	HasLine  bool
+	PC       uint64
+
+	frame emulator.Frame
+}
+
+// Stop describes where and why a call stopped
+type Stop struct {
+	Reason     string
+	Frames     []StackFrame
+	Breakpoint int   // Breakpoint ID when stopped on one
+	Err        error // The trap, for exceptions
+}
+
+// HostCall is a host function called by the contract
+type HostCall struct {
+	Name    string
+	Args    []uint64
+	Results []uint64
+	Err     error
+}
+
+func (h HostCall) String() string {
+	args := make([]string, len(h.Args))
+	for i, a := range h.Args {
+		args[i] = fmt.Sprint(int64(a))
+	}
+	s := fmt.Sprintf("%s(%s)", h.Name, strings.Join(args, ", "))
+	switch {
+	case h.Err != nil:
+		return s + " ✗ " + h.Err.Error()
+	case len(h.Results) > 0:
+		return fmt.Sprintf("%s → %d", s, int32(h.Results[0]))
+	}
+	return s
+}
+
+// Breakpoint is a source breakpoint
+type Breakpoint struct {
+	ID       int
+	File     string
+	Line     int // Line it was bound to; the requested line when unverified
+	Verified bool
+	addrs    []uint64
+}
+
+// Session debugs calls to a contract
+type Session struct {
+	Emulator *emulator.Emulator
+	Info     *DebugInfo // Nil for modules without DWARF; only instruction stepping works
+
+	// OnStop is called, on the executing goroutine, each time the call stops.
+	// The stack and memory can be inspected until it returns.
+	OnStop func(s *Session, stop *Stop) Action
+
+	// OnHostCall is called after each host function call
+	OnHostCall func(call HostCall)
+
+	StopOnEntry bool
+
+	mu          sync.Mutex
+	breakpoints map[string][]*Breakpoint // By requested file
+	bpAddrs     map[uint64]*Breakpoint
+	nextID      int
+
+	in    *emulator.Instance
+	pause atomic.Bool
+
+	// Stepping state
+	mode       Action
+	entry      bool
+	startDepth int
+	startLoc   Location
+	startKnown bool
+}
+
+// NewSession returns a session for an emulator. Debug info is loaded from
+// the module when it has any.
+func NewSession(em *emulator.Emulator) (*Session, error) {
+	s := &Session{
+		Emulator:    em,
+		breakpoints: make(map[string][]*Breakpoint),
+		bpAddrs:     make(map[uint64]*Breakpoint),
+		nextID:      1,
+	}
+	info, err := LoadDebugInfo(em.Module)
+	if err != nil && !errors.Is(err, ErrNoDebugInfo) {
+		return nil, err
+	}
+	s.Info = info
+	return s, nil
+}
+
+// SetBreakpoints replaces the breakpoints of a file
+func (s *Session) SetBreakpoints(file string, lines []int) []Breakpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bps := make([]*Breakpoint, 0, len(lines))
+	out := make([]Breakpoint, 0, len(lines))
+	for _, line := range lines {
+		bp := s.newBreakpoint(file, line)
+		bps = append(bps, bp)
+		out = append(out, *bp)
+	}
+	s.breakpoints[file] = bps
+	s.rebuild()
+	return out
+}
+
+// AddBreakpoint adds a breakpoint to a file's existing ones
+func (s *Session) AddBreakpoint(file string, line int) Breakpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bp := s.newBreakpoint(file, line)
+	s.breakpoints[file] = append(s.breakpoints[file], bp)
+	s.rebuild()
+	return *bp
+}
+
+// newBreakpoint binds a breakpoint to the statements of its line
+func (s *Session) newBreakpoint(file string, line int) *Breakpoint {
+	bp := &Breakpoint{ID: s.nextID, File: file, Line: line}
+	s.nextID++
+	if s.Info != nil {
+		if bound, addrs := s.Info.Resolve(file, line); len(addrs) > 0 {
+			bp.Line, bp.addrs, bp.Verified = bound, addrs, true
+		}
+	}
+	return bp
+}
+
+// RemoveBreakpoint deletes a breakpoint by ID
+func (s *Session) RemoveBreakpoint(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for file, bps := range s.breakpoints {
+		for i, bp := range bps {
+			if bp.ID == id {
+				s.breakpoints[file] = append(bps[:i:i], bps[i+1:]...)
+				s.rebuild()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ClearBreakpoints removes every breakpoint
+func (s *Session) ClearBreakpoints() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakpoints = make(map[string][]*Breakpoint)
+	s.rebuild()
+}
+
+// Breakpoints lists the breakpoints by ID
+func (s *Session) Breakpoints() []Breakpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Breakpoint
+	for _, bps := range s.breakpoints {
+		for _, bp := range bps {
+			out = append(out, *bp)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (s *Session) rebuild() {
+	s.bpAddrs = make(map[uint64]*Breakpoint)
+	for _, bps := range s.breakpoints {
+		for _, bp := range bps {
+			for _, addr := range bp.addrs {
+				s.bpAddrs[addr] = bp
+			}
+		}
+	}
+}
+
+// Pause asks a running call to stop at the next instruction
+func (s *Session) Pause() {
+	s.pause.Store(true)
+}
+
+// Run calls a function under the debugger. A trap stops the session with
+// ReasonException before the result is returned.
+func (s *Session) Run(function string, opts emulator.CallOptions) (*emulator.Result, error) {
+	s.mode = Continue
+	s.entry = s.StopOnEntry
+	s.pause.Store(false)
+
+	instrument := opts.Instrument
+	opts.Instrument = func(in *emulator.Instance) {
+		s.in = in
+		in.Step = s.step
+		in.HostCall = s.hostCall
+		if instrument != nil {
+			instrument(in)
+		}
+	}
+
+	result, err := s.Emulator.Call(function, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var trap *emulator.Trap
+	if errors.As(result.Err, &trap) && s.OnStop != nil {
+		s.OnStop(s, &Stop{Reason: ReasonException, Frames: s.frames(trap.Frames), Err: result.Err})
+	}
+	s.in = nil
+	return result, nil
+}
+
+// Terminated reports whether a result is from a call ended by Terminate
+func Terminated(result *emulator.Result) bool {
+	return errors.Is(result.Err, errTerminated)
+}
+
+func (s *Session) hostCall(imp emulator.Import, args, results []uint64, err error) {
+	if s.OnHostCall != nil {
+		s.OnHostCall(HostCall{Name: imp.Name, Args: args, Results: results, Err: err})
+	}
+}
+
+// step is the instruction hook deciding where to stop
+func (s *Session) step(in *emulator.Instance) error {
+	pc := uint64(in.PC())
+	depth := in.Depth()
+	reason, bpID := "", 0
+
+	if s.pause.Swap(false) {
+		reason = ReasonPause
+	}
+	if reason == "" && s.entry && s.isStatement(pc) {
+		reason = ReasonEntry
+	}
+	if reason == "" {
+		switch s.mode {
+		case StepInstruction:
+			reason = ReasonStep
+		case StepIn:
+			if s.newLine(pc, depth) {
+				reason = ReasonStep
+			}
+		case StepOver:
+			if depth < s.startDepth || (depth == s.startDepth && s.newLine(pc, depth)) {
+				reason = ReasonStep
+			}
+		case StepOut:
+			if depth < s.startDepth {
+				reason = ReasonStep
+			}
+		}
+	}
+	if reason == "" {
+		s.mu.Lock()
+		if bp, ok := s.bpAddrs[pc]; ok {
+			reason, bpID = ReasonBreakpoint, bp.ID
+		}
+		s.mu.Unlock()
+	}
+	if reason == "" || s.OnStop == nil {
+		return nil
+	}
+
+	s.entry = false
+	action := s.OnStop(s, &Stop{Reason: reason, Frames: s.frames(in.Frames()), Breakpoint: bpID})
+	if action == Terminate {
+		return errTerminated
+	}
+	s.mode = action
+	s.startDepth = depth
+	s.startLoc, s.startKnown = Location{}, false
+	if s.Info != nil {
+		s.startLoc, s.startKnown = s.Info.LineAt(pc)
+	}
+	return nil
+}
+
+func (s *Session) isStatement(pc uint64) bool {
+	if s.Info == nil {
+		return true
+	}
+	_, ok := s.Info.statementAt(pc)
+	return ok
+}
+
+// newLine reports whether pc starts a statement on another line than the
+// one the step started from. Without debug info every instruction counts.
+func (s *Session) newLine(pc uint64, depth int) bool {
+	if s.Info == nil {
+		return true
+	}
+	row, ok := s.Info.statementAt(pc)
+	if !ok {
+		return false
+	}
+	return !s.startKnown || depth != s.startDepth || row.loc.Line != s.startLoc.Line || row.loc.File != s.startLoc.File
+}
+
+func (s *Session) frames(frames []emulator.Frame) []StackFrame {
+	out := make([]StackFrame, len(frames))
+	for i, f := range frames {
+		sf := StackFrame{ID: i, PC: uint64(f.PC), Function: s.Emulator.Module.FuncName(f.Func), frame: f}
+		if s.Info != nil {
+			sf.Location, sf.HasLine = s.Info.LineAt(sf.PC)
+			if fn := s.Info.funcAt(sf.PC); fn != nil && fn.name != "" {
+				sf.Function = fn.name
+			}
+		}
+		out[i] = sf
+	}
+	return out
+}
+
+// Locals returns the source variables in scope in a frame
+func (s *Session) Locals(f StackFrame) []Variable {
+	if s.Info == nil || s.in == nil {
+		return nil
+	}
+	fn := s.Info.funcAt(f.PC)
+	if fn == nil {
+		return nil
+	}
+
+	frameBase := func() (uint64, error) {
+		if fn.frameBase.err != nil {
+			return 0, fn.frameBase.err
+		}
+		expr := fn.frameBase.at(f.PC)
+		if len(expr) == 0 {
+			return 0, fmt.Errorf("no frame base")
+		}
+		loc, err := evalLocation(expr, s.in, f.frame, nil)
+		if err != nil {
+			return 0, err
+		}
+		if loc.inMem {
+			return loc.addr, nil
+		}
+		return loc.value, nil
+	}
+
+	r := valueReader{in: s.in}
+	var vars []Variable
+	for _, v := range fn.vars {
+		if v.name == "" || (v.high > v.low && (f.PC < v.low || f.PC >= v.high)) {
+			continue
+		}
+		vars = append(vars, s.readVariable(r, v, f, frameBase))
+	}
+	return vars
+}
+
+func (s *Session) readVariable(r valueReader, v variable, f StackFrame, frameBase func() (uint64, error)) Variable {
+	out := Variable{Name: v.name, Type: typeName(v.typ)}
+	switch {
+	case v.typErr != nil || v.typ == nil:
+		out.Value = "<type unavailable>"
+		return out
+	case v.loc.err != nil:
+		out.Value = "<unsupported location: " + v.loc.err.Error() + ">"
+		return out
+	}
+
+	// Location lists leave a value out where it is not available
+	expr := v.loc.at(f.PC)
+	if len(expr) == 0 {
+		out.Value = "<optimized out>"
+		return out
+	}
+	loc, err := evalLocation(expr, s.in, f.frame, frameBase)
+	if err != nil {
+		out.Value = "<" + err.Error() + ">"
+		return out
+	}
+	if loc.inMem {
+		return r.at(v.name, v.typ, loc.addr)
+	}
+	size := v.typ.Size()
+	if size < 0 || size > 8 {
+		size = 8
+	}
+	b := make([]byte, 8)
+	for i := range b {
+		b[i] = byte(loc.value >> (8 * i))
+	}
+	return r.variable(v.name, v.typ, b[:size], 0, false)
+}
+
+// WasmLocals returns a frame's raw WASM locals
+func (s *Session) WasmLocals(f StackFrame) []Variable {
+	vars := make([]Variable, len(f.frame.Locals))
+	for i, v := range f.frame.Locals {
+		vars[i] = Variable{Name: fmt.Sprintf("local%d", i), Value: fmt.Sprintf("%d (0x%x)", int64(v), v)}
+	}
+	return vars
+}
+
+// Lookup finds a variable in a frame by name, with dotted field or [index]
+// paths into its children
+func (s *Session) Lookup(f StackFrame, path string) (Variable, bool) {
+	parts := splitPath(path)
+	if len(parts) == 0 {
+		return Variable{}, false
+	}
+	vars := append(s.Locals(f), s.WasmLocals(f)...)
+	var cur Variable
+	for i, part := range parts {
+		found := false
+		for _, v := range vars {
+			if v.Name == part || v.Name == "*"+part {
+				cur, found = v, true
+				break
+			}
+		}
+		if !found {
+			return Variable{}, false
+		}
+		if i < len(parts)-1 {
+			vars = cur.Children()
+		}
+	}
+	return cur, true
+}
+
+// splitPath splits "a.b[2]" into "a", "b", "[2]"
+func splitPath(path string) []string {
+	var parts []string
+	for _, p := range strings.Split(path, ".") {
+		for p != "" {
+			i := strings.Index(p[1:], "[")
+			if i < 0 {
+				parts = append(parts, p)
+				break
+			}
+			parts = append(parts, p[:i+1])
+			p = p[i+1:]
+		}
+	}
+	return parts
+}
+
+// ReadMemory reads the stopped contract's linear memory
+func (s *Session) ReadMemory(addr, n uint32) ([]byte, bool) {
+	if s.in == nil {
+		return nil, false
+	}
+	return s.in.Read(addr, n)
+}
+
+// Gas returns the gas used so far by the stopped call
+func (s *Session) Gas() uint64 {
+	if s.in == nil {
+		return 0
+	}
+	return s.in.Gas
+}
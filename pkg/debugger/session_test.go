@@ -0,0 +1,225 @@
+package debugger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// locals renders a frame's variables as name=value
+func locals(s *Session, f StackFrame) map[string]string {
+	out := make(map[string]string)
+	for _, v := range s.Locals(f) {
+		out[v.Name] = v.Value
+	}
+	return out
+}
+
+func TestBreakpointLocals(t *testing.T) {
+	s := newTestSession(t)
+	bp := s.AddBreakpoint("lib.rs", 8)
+	if !bp.Verified || bp.Line != 8 {
+		t.Fatalf("breakpoint = %+v", bp)
+	}
+
+	var hosts []string
+	s.OnHostCall = func(call HostCall) { hosts = append(hosts, call.String()) }
+	stops := 0
+	s.OnStop = func(s *Session, stop *Stop) Action {
+		stops++
+		if stop.Reason != ReasonBreakpoint || stop.Breakpoint != bp.ID {
+			t.Errorf("stopped for %s at breakpoint %d", stop.Reason, stop.Breakpoint)
+		}
+		if len(stop.Frames) != 2 {
+			t.Fatalf("%d frames, want add and increment", len(stop.Frames))
+		}
+		add, caller := stop.Frames[0], stop.Frames[1]
+		if add.Function != "counter::add" || add.Location.Line != 8 || caller.Function != "counter::increment" || caller.Location.Line != 15 {
+			t.Errorf("stack = %s at %v, %s at %v", add.Function, add.Location, caller.Function, caller.Location)
+		}
+
+		want := map[string]string{"a": "1000", "b": "3", "sum": "1003"}
+		if got := locals(s, add); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("add locals = %v, want %v", got, want)
+		}
+		got := locals(s, caller)
+		for name, value := range map[string]string{
+			"p": "{x: 3, y: 4}", "ledger": "1000", "next": "0", "scratch": "<optimized out>",
+		} {
+			if got[name] != value {
+				t.Errorf("%s = %q, want %q", name, got[name], value)
+			}
+		}
+		if !strings.HasPrefix(got["hidden"], "<unsupported location: ") {
+			t.Errorf("hidden = %q, want an unsupported location", got["hidden"])
+		}
+
+		if v, ok := s.Lookup(caller, "p.y"); !ok || v.Value != "4" || !v.InMem {
+			t.Errorf("p.y = %+v, %v", v, ok)
+		}
+		if v, ok := s.Lookup(add, "local2"); !ok || !strings.HasPrefix(v.Value, "1003 ") {
+			t.Errorf("local2 = %+v, %v", v, ok)
+		}
+		if _, ok := s.Lookup(add, "p"); ok {
+			t.Error("found the caller's variable in add")
+		}
+		return Continue
+	}
+
+	result, err := s.Run("increment", emulator.CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stops != 1 {
+		t.Errorf("stopped %d times, want 1", stops)
+	}
+	if result.Code != 1003 {
+		t.Errorf("increment returned %d, want 1003", result.Code)
+	}
+	if len(hosts) != 1 || hosts[0] != "get_ledger_sqn() → 1000" {
+		t.Errorf("host calls = %q", hosts)
+	}
+}
+
+func TestStepping(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []Action // Taken at each stop after the entry
+		want    []string
+	}{
+		{
+			name:    "step over",
+			actions: []Action{StepOver, StepOver, StepOver, StepOver, StepOver},
+			want:    []string{"entry increment:12", "step increment:13", "step increment:14", "step increment:15", "step increment:16"},
+		},
+		{
+			name:    "step in and out",
+			actions: []Action{StepOver, StepOver, StepOver, StepIn, StepOut, StepOver},
+			want: []string{"entry increment:12", "step increment:13", "step increment:14", "step increment:15",
+				"step add:7", "step increment:15", "step increment:16"},
+		},
+		{
+			name:    "step in through the callee",
+			actions: []Action{StepIn, StepIn, StepIn, StepIn, StepIn, StepIn},
+			want: []string{"entry increment:12", "step increment:13", "step increment:14", "step increment:15",
+				"step add:7", "step add:8", "step increment:16"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSession(t)
+			s.StopOnEntry = true
+			var got []string
+			s.OnStop = func(s *Session, stop *Stop) Action {
+				f := stop.Frames[0]
+				got = append(got, fmt.Sprintf("%s %s:%d", stop.Reason, strings.TrimPrefix(f.Function, "counter::"), f.Location.Line))
+				if len(got) > len(tt.actions) {
+					return Continue
+				}
+				return tt.actions[len(got)-1]
+			}
+			result, err := s.Run("increment", emulator.CallOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("stops = %q\nwant    %q", got, tt.want)
+			}
+			if result.Code != 1003 {
+				t.Errorf("increment returned %d, want 1003", result.Code)
+			}
+		})
+	}
+}
+
+func TestEntryLocals(t *testing.T) {
+	s := newTestSession(t)
+	s.StopOnEntry = true
+	s.OnStop = func(s *Session, stop *Stop) Action {
+		got := locals(s, stop.Frames[0])
+		if got["ledger"] != "<optimized out>" {
+			t.Errorf("ledger before it is set = %q, want <optimized out>", got["ledger"])
+		}
+		if _, ok := got["next"]; ok {
+			t.Error("next is in scope before its block")
+		}
+		return Terminate
+	}
+	result, err := s.Run("increment", emulator.CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Terminated(result) {
+		t.Errorf("result error = %v, want terminated", result.Err)
+	}
+}
+
+func TestTrapStops(t *testing.T) {
+	s := newTestSession(t)
+	var stops []*Stop
+	s.OnStop = func(s *Session, stop *Stop) Action {
+		stops = append(stops, stop)
+		return Continue
+	}
+	result, err := s.Run("crash", emulator.CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Err == nil || Terminated(result) {
+		t.Fatalf("crash result error = %v", result.Err)
+	}
+	if len(stops) != 1 || stops[0].Reason != ReasonException {
+		t.Fatalf("stops = %+v, want one exception", stops)
+	}
+	f := stops[0].Frames[0]
+	if f.Function != "counter::crash" || f.Location.Line != 21 || stops[0].Err != result.Err {
+		t.Errorf("stopped in %s at %v with %v", f.Function, f.Location, stops[0].Err)
+	}
+}
+
+func TestBreakpoints(t *testing.T) {
+	s := newTestSession(t)
+	bps := s.SetBreakpoints("src/lib.rs", []int{7, 10, 30})
+	if len(bps) != 3 {
+		t.Fatalf("%d breakpoints", len(bps))
+	}
+	if !bps[0].Verified || bps[0].Line != 7 {
+		t.Errorf("line 7 = %+v", bps[0])
+	}
+	if !bps[1].Verified || bps[1].Line != 12 {
+		t.Errorf("line 10 = %+v, want it bound to line 12", bps[1])
+	}
+	if bps[2].Verified || bps[2].Line != 30 {
+		t.Errorf("line 30 = %+v, want it unverified", bps[2])
+	}
+
+	// Setting a file's breakpoints replaces the previous ones
+	bps = s.SetBreakpoints("src/lib.rs", []int{16})
+	if len(s.Breakpoints()) != 1 {
+		t.Errorf("%d breakpoints after replacing them, want 1", len(s.Breakpoints()))
+	}
+	var lines []int
+	s.OnStop = func(s *Session, stop *Stop) Action {
+		lines = append(lines, stop.Frames[0].Location.Line)
+		return Continue
+	}
+	if _, err := s.Run("increment", emulator.CallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(lines) != "[16]" {
+		t.Errorf("stopped at lines %v, want [16]", lines)
+	}
+
+	if !s.RemoveBreakpoint(bps[0].ID) || s.RemoveBreakpoint(bps[0].ID) {
+		t.Error("RemoveBreakpoint did not remove the breakpoint once")
+	}
+	lines = nil
+	if _, err := s.Run("increment", emulator.CallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("stopped at lines %v after removing the breakpoint", lines)
+	}
+}
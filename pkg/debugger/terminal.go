@@ -0,0 +1,369 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// sourceContext is the number of lines listed around the current line
+const sourceContext = 4
+
+// Terminal is a line-based debugger UI
+type Terminal struct {
+	session *Session
+	scanner *bufio.Scanner
+	out     io.Writer
+
+	stop      *Stop
+	frame     int
+	hostCalls []HostCall
+	sources   map[string][]string
+}
+
+// NewTerminal returns a terminal UI for a session reading commands from in
+func NewTerminal(s *Session, in io.Reader, out io.Writer) *Terminal {
+	return &Terminal{
+		session: s,
+		scanner: bufio.NewScanner(in),
+		out:     out,
+		sources: make(map[string][]string),
+	}
+}
+
+// Run calls a function, stopping on entry so breakpoints can be set
+func (t *Terminal) Run(function string, opts emulator.CallOptions) (*emulator.Result, error) {
+	t.session.StopOnEntry = true
+	t.session.OnStop = t.onStop
+	t.session.OnHostCall = func(call HostCall) {
+		t.hostCalls = append(t.hostCalls, call)
+		fmt.Fprintf(t.out, "  ↳ %s\n", call)
+	}
+
+	fmt.Fprintf(t.out, "Debugging %s (type 'help' for commands)\n", function)
+	if t.session.Info == nil {
+		fmt.Fprintln(t.out, "  No debug info: only instruction stepping and WASM locals are available")
+	}
+	fmt.Fprintln(t.out)
+
+	return t.session.Run(function, opts)
+}
+
+func (t *Terminal) onStop(s *Session, stop *Stop) Action {
+	t.stop, t.frame = stop, 0
+
+	switch stop.Reason {
+	case ReasonException:
+		fmt.Fprintf(t.out, "\n✗ %v\n", stop.Err)
+	case ReasonBreakpoint:
+		fmt.Fprintf(t.out, "\n● Breakpoint %d\n", stop.Breakpoint)
+	}
+	t.printLocation()
+
+	for {
+		fmt.Fprint(t.out, "(debug) ")
+		if !t.scanner.Scan() {
+			return Terminate
+		}
+		fields := strings.Fields(t.scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		cmd, args := fields[0], fields[1:]
+
+		switch cmd {
+		case "c", "continue":
+			return Continue
+		case "s", "step":
+			return StepIn
+		case "n", "next":
+			return StepOver
+		case "o", "out", "finish":
+			return StepOut
+		case "si", "stepi":
+			return StepInstruction
+		case "q", "quit", "exit":
+			return Terminate
+		case "b", "break":
+			t.addBreakpoint(args)
+		case "d", "delete":
+			t.deleteBreakpoint(args)
+		case "bl", "breakpoints":
+			t.listBreakpoints()
+		case "bt", "where", "backtrace":
+			t.backtrace()
+		case "f", "frame":
+			t.selectFrame(args)
+		case "l", "locals":
+			t.printVars(s.Locals(t.current()))
+		case "w", "wasm":
+			t.printVars(s.WasmLocals(t.current()))
+		case "p", "print":
+			t.print(args)
+		case "x", "mem":
+			t.memory(args)
+		case "ls", "list":
+			t.list(args)
+		case "hosts":
+			for _, call := range t.hostCalls {
+				fmt.Fprintf(t.out, "  %s\n", call)
+			}
+		case "gas":
+			fmt.Fprintf(t.out, "  Gas used: %d\n", s.Gas())
+		case "h", "help", "?":
+			t.help()
+		default:
+			fmt.Fprintf(t.out, "Unknown command: %s (type 'help' for commands)\n", cmd)
+		}
+	}
+}
+
+func (t *Terminal) help() {
+	fmt.Fprintln(t.out, "Commands:")
+	fmt.Fprintln(t.out, "  c, continue            - Run to the next breakpoint")
+	fmt.Fprintln(t.out, "  s, step                - Step to the next line, into calls")
+	fmt.Fprintln(t.out, "  n, next                - Step to the next line, over calls")
+	fmt.Fprintln(t.out, "  o, out                 - Run until the current function returns")
+	fmt.Fprintln(t.out, "  si, stepi              - Execute one WASM instruction")
+	fmt.Fprintln(t.out, "  b, break <file:line>   - Set a breakpoint (a bare line uses the current file)")
+	fmt.Fprintln(t.out, "  d, delete [id]         - Delete a breakpoint, or all of them")
+	fmt.Fprintln(t.out, "  bl, breakpoints        - List breakpoints")
+	fmt.Fprintln(t.out, "  bt, where              - Show the call stack")
+	fmt.Fprintln(t.out, "  f, frame <n>           - Select a stack frame")
+	fmt.Fprintln(t.out, "  l, locals              - Show the variables in scope")
+	fmt.Fprintln(t.out, "  w, wasm                - Show the raw WASM locals")
+	fmt.Fprintln(t.out, "  p, print <name>        - Show a variable; use a.b and a[0] for members")
+	fmt.Fprintln(t.out, "  x, mem <addr> [len]    - Dump linear memory")
+	fmt.Fprintln(t.out, "  ls, list [line]        - List source around the current line")
+	fmt.Fprintln(t.out, "  hosts                  - List the host function calls so far")
+	fmt.Fprintln(t.out, "  gas                    - Show the gas used so far")
+	fmt.Fprintln(t.out, "  q, quit                - Abort the call and exit")
+}
+
+func (t *Terminal) current() StackFrame {
+	if t.stop == nil || len(t.stop.Frames) == 0 {
+		return StackFrame{}
+	}
+	return t.stop.Frames[t.frame]
+}
+
+func (t *Terminal) printLocation() {
+	f := t.current()
+	if !f.HasLine {
+		fmt.Fprintf(t.out, "→ %s at 0x%x (%s)\n", f.Function, f.PC, t.stop.Reason)
+		return
+	}
+	fmt.Fprintf(t.out, "→ %s at %s (%s)\n", f.Function, f.Location, t.stop.Reason)
+	t.printSource(f.Location.File, f.Location.Line, f.Location.Line)
+}
+
+func (t *Terminal) printSource(file string, center, current int) {
+	lines, ok := t.source(file)
+	if !ok {
+		return
+	}
+	from, to := max(center-sourceContext, 1), min(center+sourceContext, len(lines))
+	for n := from; n <= to; n++ {
+		marker := "  "
+		if n == current {
+			marker = "→ "
+		}
+		fmt.Fprintf(t.out, "%s%4d  %s\n", marker, n, lines[n-1])
+	}
+}
+
+func (t *Terminal) source(file string) ([]string, bool) {
+	if lines, ok := t.sources[file]; ok {
+		return lines, lines != nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.sources[file] = nil
+		return nil, false
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	t.sources[file] = lines
+	return lines, true
+}
+
+func (t *Terminal) list(args []string) {
+	f := t.current()
+	if !f.HasLine {
+		fmt.Fprintln(t.out, "  No source for the current location")
+		return
+	}
+	center := f.Location.Line
+	if len(args) > 0 {
+		if n, err := strconv.Atoi(args[0]); err == nil {
+			center = n
+		}
+	}
+	t.printSource(f.Location.File, center, f.Location.Line)
+}
+
+func (t *Terminal) addBreakpoint(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(t.out, "Usage: break <file:line>")
+		return
+	}
+	file, lineStr := t.current().Location.File, args[0]
+	if i := strings.LastIndex(args[0], ":"); i >= 0 {
+		file, lineStr = args[0][:i], args[0][i+1:]
+	}
+	line, err := strconv.Atoi(lineStr)
+	if err != nil || file == "" {
+		fmt.Fprintln(t.out, "Usage: break <file:line>")
+		return
+	}
+
+	bp := t.session.AddBreakpoint(file, line)
+	if !bp.Verified {
+		fmt.Fprintf(t.out, "  Breakpoint %d at %s:%d has no code; it will not be hit\n", bp.ID, file, line)
+		return
+	}
+	fmt.Fprintf(t.out, "  Breakpoint %d at %s:%d\n", bp.ID, file, bp.Line)
+}
+
+func (t *Terminal) deleteBreakpoint(args []string) {
+	if len(args) == 0 {
+		t.session.ClearBreakpoints()
+		fmt.Fprintln(t.out, "  Deleted all breakpoints")
+		return
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || !t.session.RemoveBreakpoint(id) {
+		fmt.Fprintf(t.out, "  No breakpoint %s\n", args[0])
+		return
+	}
+	fmt.Fprintf(t.out, "  Deleted breakpoint %d\n", id)
+}
+
+func (t *Terminal) listBreakpoints() {
+	bps := t.session.Breakpoints()
+	if len(bps) == 0 {
+		fmt.Fprintln(t.out, "  No breakpoints")
+		return
+	}
+	for _, bp := range bps {
+		state := ""
+		if !bp.Verified {
+			state = " (no code)"
+		}
+		fmt.Fprintf(t.out, "  %d  %s:%d%s\n", bp.ID, bp.File, bp.Line, state)
+	}
+}
+
+func (t *Terminal) backtrace() {
+	for _, f := range t.stop.Frames {
+		marker := "  "
+		if f.ID == t.frame {
+			marker = "→ "
+		}
+		where := fmt.Sprintf("0x%x", f.PC)
+		if f.HasLine {
+			where = f.Location.String()
+		}
+		fmt.Fprintf(t.out, "%s#%d  %s at %s\n", marker, f.ID, f.Function, where)
+	}
+}
+
+func (t *Terminal) selectFrame(args []string) {
+	n := 0
+	if len(args) > 0 {
+		n, _ = strconv.Atoi(args[0])
+	}
+	if n < 0 || n >= len(t.stop.Frames) {
+		fmt.Fprintf(t.out, "  No frame %d\n", n)
+		return
+	}
+	t.frame = n
+	t.printLocation()
+}
+
+func (t *Terminal) printVars(vars []Variable) {
+	if len(vars) == 0 {
+		fmt.Fprintln(t.out, "  No variables")
+		return
+	}
+	for _, v := range vars {
+		t.printVar("  ", v)
+	}
+}
+
+func (t *Terminal) printVar(indent string, v Variable) {
+	if v.Type != "" {
+		fmt.Fprintf(t.out, "%s%s: %s = %s\n", indent, v.Name, v.Type, v.Value)
+	} else {
+		fmt.Fprintf(t.out, "%s%s = %s\n", indent, v.Name, v.Value)
+	}
+}
+
+func (t *Terminal) print(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(t.out, "Usage: print <name>")
+		return
+	}
+	v, ok := t.session.Lookup(t.current(), args[0])
+	if !ok {
+		fmt.Fprintf(t.out, "  No variable %s in scope\n", args[0])
+		return
+	}
+	t.printVar("  ", v)
+	if v.InMem {
+		fmt.Fprintf(t.out, "    at 0x%08x\n", v.Address)
+	}
+	for _, c := range v.Children() {
+		t.printVar("    ", c)
+	}
+}
+
+func (t *Terminal) memory(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(t.out, "Usage: mem <addr> [len]")
+		return
+	}
+	addr, err := strconv.ParseUint(args[0], 0, 32)
+	if err != nil {
+		// Allow a pointer variable or a variable's address
+		v, ok := t.session.Lookup(t.current(), args[0])
+		if !ok {
+			fmt.Fprintf(t.out, "  Invalid address: %s\n", args[0])
+			return
+		}
+		addr = v.Address
+		if p, perr := strconv.ParseUint(v.Value, 0, 32); perr == nil && strings.HasPrefix(v.Value, "0x") && !v.InMem {
+			addr = p
+		}
+	}
+	n := uint64(64)
+	if len(args) > 1 {
+		if n, err = strconv.ParseUint(args[1], 0, 16); err != nil {
+			fmt.Fprintf(t.out, "  Invalid length: %s\n", args[1])
+			return
+		}
+	}
+
+	data, ok := t.session.ReadMemory(uint32(addr), uint32(n))
+	if !ok {
+		fmt.Fprintf(t.out, "  0x%08x is out of bounds\n", addr)
+		return
+	}
+	for i := 0; i < len(data); i += 16 {
+		row := data[i:min(i+16, len(data))]
+		hexPart := make([]string, len(row))
+		text := make([]byte, len(row))
+		for j, b := range row {
+			hexPart[j] = fmt.Sprintf("%02x", b)
+			text[j] = '.'
+			if b >= 0x20 && b < 0x7F {
+				text[j] = b
+			}
+		}
+		fmt.Fprintf(t.out, "  %08x  %-47s  %s\n", addr+uint64(i), strings.Join(hexPart, " "), text)
+	}
+}
@@ -0,0 +1,316 @@
+//go:build ignore
+
+// gen writes counter.wasm, a small module with the DWARF a debug build of
+// src/lib.rs would carry: a line table, subprograms in a namespace,
+// parameters and locals in WASM locals, a struct in linear memory addressed
+// from the frame base, a lexical block, a location list, an optimized out
+// variable and one whose location form is not supported.
+//
+// Run it from this directory with: go run gen.go
+package main
+
+import (
+	"encoding/binary"
+	"log"
+	"os"
+)
+
+func uleb(v uint64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7F)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func sleb(v int64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7F)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func cat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func str(s string) []byte  { return append([]byte(s), 0) }
+func name(s string) []byte { return cat(uleb(uint64(len(s))), []byte(s)) }
+func u16(v int) []byte     { return binary.LittleEndian.AppendUint16(nil, uint16(v)) }
+func u32(v int) []byte     { return binary.LittleEndian.AppendUint32(nil, uint32(v)) }
+
+func section(id byte, body []byte) []byte {
+	return cat([]byte{id}, uleb(uint64(len(body))), body)
+}
+
+func custom(n string, body []byte) []byte {
+	return section(0, cat(name(n), body))
+}
+
+// inst is an instruction, starting a statement on line when it is set
+type inst struct {
+	line int
+	code []byte
+}
+
+type function struct {
+	locals int // i32 locals after the parameters
+	insts  []inst
+
+	// Filled in by layout: code section offsets
+	low, high int
+	addrs     []int
+}
+
+func i32c(v int32) []byte { return cat([]byte{0x41}, sleb(int64(v))) }
+func get(i int) []byte    { return []byte{0x20, byte(i)} }
+func set(i int) []byte    { return []byte{0x21, byte(i)} }
+
+var (
+	add = &function{locals: 1, insts: []inst{
+		{7, get(0)}, {0, get(1)}, {0, []byte{0x6A}}, {0, set(2)},
+		{8, get(2)},
+	}}
+	increment = &function{locals: 3, insts: []inst{
+		{12, []byte{0x23, 0}}, {0, i32c(16)}, {0, []byte{0x6B}}, {0, []byte{0x22, 0}}, {0, []byte{0x24, 0}},
+		{13, get(0)}, {0, i32c(3)}, {0, []byte{0x36, 2, 8}}, {0, get(0)}, {0, i32c(4)}, {0, []byte{0x36, 2, 12}},
+		{14, []byte{0x10, 0}}, {0, set(1)},
+		{15, get(1)}, {0, get(0)}, {0, []byte{0x28, 2, 8}}, {0, []byte{0x10, 1}}, {0, set(2)},
+		{16, get(0)}, {0, i32c(16)}, {0, []byte{0x6A}}, {0, []byte{0x24, 0}}, {0, get(2)},
+	}}
+	crash = &function{insts: []inst{{21, []byte{0x00}}}}
+	funcs = []*function{add, increment, crash}
+)
+
+// layout assembles the code section, recording where each function and
+// instruction lands
+func layout() []byte {
+	body := uleb(uint64(len(funcs)))
+	for _, f := range funcs {
+		locals := []byte{0}
+		if f.locals > 0 {
+			locals = cat([]byte{1}, uleb(uint64(f.locals)), []byte{0x7F})
+		}
+		code := locals
+		var offsets []int
+		for _, in := range f.insts {
+			offsets = append(offsets, len(code))
+			code = append(code, in.code...)
+		}
+		code = append(code, 0x0B)
+
+		size := uleb(uint64(len(code)))
+		f.low = len(body) + len(size)
+		f.high = f.low + len(code)
+		for _, off := range offsets {
+			f.addrs = append(f.addrs, f.low+off)
+		}
+		body = cat(body, size, code)
+	}
+	return body
+}
+
+// Statement address of the first instruction of a line
+func lineAddr(f *function, line int) int {
+	for i, in := range f.insts {
+		if in.line == line {
+			return f.addrs[i]
+		}
+	}
+	log.Fatalf("no line %d", line)
+	return 0
+}
+
+func lineProgram() []byte {
+	header := cat(
+		[]byte{1, 1, 1},      // Minimum instruction length, max ops, default is_stmt
+		[]byte{0xFB, 14, 13}, // Line base -5, line range, opcode base
+		[]byte{0, 1, 1, 1, 1, 0, 0, 0, 1, 0, 0, 1},
+		str("src"), []byte{0}, // Include directories
+		str("lib.rs"), []byte{1, 0, 0}, []byte{0}, // Files
+	)
+
+	var prog []byte
+	line := 1
+	setAddr := func(addr int) { prog = cat(prog, []byte{0, 5, 2}, u32(addr)) }
+	for _, f := range funcs {
+		for i, in := range f.insts {
+			if in.line == 0 {
+				continue
+			}
+			setAddr(f.addrs[i])
+			prog = cat(prog, []byte{3}, sleb(int64(in.line-line)), []byte{1})
+			line = in.line
+		}
+	}
+	setAddr(funcs[len(funcs)-1].high)
+	prog = cat(prog, []byte{0, 1, 1})
+
+	unit := cat(u16(4), u32(len(header)), header, prog)
+	return cat(u32(len(unit)), unit)
+}
+
+// DWARF constants
+const (
+	tagCompileUnit   = 0x11
+	tagSubprogram    = 0x2E
+	tagFormalParam   = 0x05
+	tagVariable      = 0x34
+	tagBaseType      = 0x24
+	tagStructure     = 0x13
+	tagMember        = 0x0D
+	tagNamespace     = 0x39
+	tagLexicalBlock  = 0x0B
+	atName           = 0x03
+	atProducer       = 0x25
+	atLanguage       = 0x13
+	atCompDir        = 0x1B
+	atStmtList       = 0x10
+	atLowPC          = 0x11
+	atHighPC         = 0x12
+	atFrameBase      = 0x40
+	atType           = 0x49
+	atLocation       = 0x02
+	atEncoding       = 0x3E
+	atByteSize       = 0x0B
+	atMemberLocation = 0x38
+	formAddr         = 0x01
+	formData1        = 0x0B
+	formData2        = 0x05
+	formData4        = 0x06
+	formString       = 0x08
+	formRef4         = 0x13
+	formSecOffset    = 0x17
+	formExprloc      = 0x18
+)
+
+type abbrev struct {
+	tag      int
+	children bool
+	attrs    [][2]int
+}
+
+var abbrevs = []abbrev{
+	1:  {tagCompileUnit, true, [][2]int{{atName, formString}, {atProducer, formString}, {atLanguage, formData2}, {atCompDir, formString}, {atStmtList, formSecOffset}, {atLowPC, formAddr}, {atHighPC, formData4}}},
+	2:  {tagBaseType, false, [][2]int{{atName, formString}, {atEncoding, formData1}, {atByteSize, formData1}}},
+	3:  {tagStructure, true, [][2]int{{atName, formString}, {atByteSize, formData1}}},
+	4:  {tagMember, false, [][2]int{{atName, formString}, {atType, formRef4}, {atMemberLocation, formData1}}},
+	5:  {tagNamespace, true, [][2]int{{atName, formString}}},
+	6:  {tagSubprogram, true, [][2]int{{atLowPC, formAddr}, {atHighPC, formData4}, {atName, formString}}},
+	7:  {tagSubprogram, true, [][2]int{{atLowPC, formAddr}, {atHighPC, formData4}, {atFrameBase, formExprloc}, {atName, formString}}},
+	8:  {tagFormalParam, false, [][2]int{{atName, formString}, {atType, formRef4}, {atLocation, formExprloc}}},
+	9:  {tagVariable, false, [][2]int{{atName, formString}, {atType, formRef4}, {atLocation, formExprloc}}},
+	10: {tagVariable, false, [][2]int{{atName, formString}, {atType, formRef4}, {atLocation, formSecOffset}}},
+	11: {tagVariable, false, [][2]int{{atName, formString}, {atType, formRef4}}},
+	12: {tagVariable, false, [][2]int{{atName, formString}, {atType, formRef4}, {atLocation, formData1}}},
+	13: {tagLexicalBlock, true, [][2]int{{atLowPC, formAddr}, {atHighPC, formData4}}},
+	14: {tagSubprogram, false, [][2]int{{atLowPC, formAddr}, {atHighPC, formData4}, {atName, formString}}},
+}
+
+func abbrevTable() []byte {
+	var b []byte
+	for code, a := range abbrevs {
+		if code == 0 {
+			continue
+		}
+		children := byte(0)
+		if a.children {
+			children = 1
+		}
+		b = cat(b, uleb(uint64(code)), uleb(uint64(a.tag)), []byte{children})
+		for _, attr := range a.attrs {
+			b = cat(b, uleb(uint64(attr[0])), uleb(uint64(attr[1])))
+		}
+		b = append(b, 0, 0)
+	}
+	return append(b, 0)
+}
+
+func wasmLocal(i int) []byte  { return []byte{0xED, 0, byte(i)} }
+func exprloc(e []byte) []byte { return cat(uleb(uint64(len(e))), e) }
+
+func main() {
+	code := layout()
+	ledgerSet := lineAddr(increment, 15) // After local.set 1 on line 14
+
+	// The only location list: ledger lives in local 1 once it is set
+	loc := cat(u32(ledgerSet), u32(increment.high), u16(3), wasmLocal(1), u32(0), u32(0))
+
+	const header = 11 // DWARF 4 unit header
+	var info []byte
+	at := func() int { return header + len(info) }
+	die := func(parts ...[]byte) { info = cat(info, cat(parts...)) }
+
+	die(uleb(1), str("src/lib.rs"), str("bedrock testdata"), u16(0x1C), str("/proj"), u32(0), u32(0), u32(crash.high))
+	i32 := at()
+	die(uleb(2), str("i32"), []byte{5, 4})
+	point := at()
+	die(uleb(3), str("Point"), []byte{8})
+	die(uleb(4), str("x"), u32(i32), []byte{0})
+	die(uleb(4), str("y"), u32(i32), []byte{4})
+	die([]byte{0})
+
+	die(uleb(5), str("counter"))
+	die(uleb(6), u32(add.low), u32(add.high-add.low), str("add"))
+	die(uleb(8), str("a"), u32(i32), exprloc(wasmLocal(0)))
+	die(uleb(8), str("b"), u32(i32), exprloc(wasmLocal(1)))
+	die(uleb(9), str("sum"), u32(i32), exprloc(wasmLocal(2)))
+	die([]byte{0})
+
+	die(uleb(7), u32(increment.low), u32(increment.high-increment.low), exprloc(wasmLocal(0)), str("increment"))
+	die(uleb(9), str("p"), u32(point), exprloc([]byte{0x91, 8})) // DW_OP_fbreg 8
+	die(uleb(10), str("ledger"), u32(i32), u32(0))
+	die(uleb(11), str("scratch"), u32(i32))
+	die(uleb(12), str("hidden"), u32(i32), []byte{0})
+	next := lineAddr(increment, 15)
+	die(uleb(13), u32(next), u32(increment.high-next))
+	die(uleb(9), str("next"), u32(i32), exprloc(wasmLocal(2)))
+	die([]byte{0})
+	die([]byte{0})
+
+	die(uleb(14), u32(crash.low), u32(crash.high-crash.low), str("crash"))
+	die([]byte{0}) // namespace
+	die([]byte{0}) // compile unit
+
+	unit := cat(u16(4), u32(0), []byte{4}, info)
+	debugInfo := cat(u32(len(unit)), unit)
+
+	i32t := []byte{0x7F}
+	types := cat(uleb(2),
+		[]byte{0x60, 0, 1}, i32t, // () -> i32
+		[]byte{0x60, 2}, i32t, i32t, []byte{1}, i32t, // (i32, i32) -> i32
+	)
+	wasm := cat(
+		[]byte("\x00asm\x01\x00\x00\x00"),
+		section(1, types),
+		section(2, cat(uleb(1), name("host_lib"), name("get_ledger_sqn"), []byte{0, 0})),
+		section(3, cat(uleb(3), []byte{1, 0, 0})),
+		section(5, cat(uleb(1), []byte{0, 1})),
+		section(6, cat(uleb(1), []byte{0x7F, 1}, i32c(4096), []byte{0x0B})),
+		section(7, cat(uleb(2), name("increment"), []byte{0, 2}, name("crash"), []byte{0, 3})),
+		section(10, code),
+		custom(".debug_abbrev", abbrevTable()),
+		custom(".debug_info", debugInfo),
+		custom(".debug_line", lineProgram()),
+		custom(".debug_loc", loc),
+	)
+	if err := os.WriteFile("counter.wasm", wasm, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
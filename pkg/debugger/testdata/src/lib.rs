@@ -0,0 +1,24 @@
+// The source counter.wasm's debug info describes; see gen.go. The scratch
+// and hidden locals of increment exist only in the debug info.
+#![no_std]
+extern "C" { fn get_ledger_sqn() -> i32; }
+
+fn add(a: i32, b: i32) -> i32 {
+    let sum = a + b;
+    sum
+}
+
+#[no_mangle]
+pub extern "C" fn increment() -> i32 {
+    let p = Point { x: 3, y: 4 };
+    let ledger = unsafe { get_ledger_sqn() };
+    let next = add(ledger, p.x);
+    next
+}
+
+#[no_mangle]
+pub extern "C" fn crash() -> i32 {
+    unreachable!()
+}
+
+struct Point { x: i32, y: i32 }
@@ -0,0 +1,397 @@
+package debugger
+
+import (
+	"debug/dwarf"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+// Limits on what is read when rendering values
+const (
+	maxStringLen = 256
+	maxElements  = 100
+	maxSummary   = 6 // Fields or elements shown inline
+)
+
+// Variable is a value shown in the debugger. Structs, arrays and pointers
+// have children that are read on demand.
+type Variable struct {
+	Name    string
+	Type    string
+	Value   string
+	Address uint64 // Memory address; 0 when the value is held in a local
+	InMem   bool
+
+	children func() []Variable
+}
+
+// HasChildren reports whether the variable can be expanded
+func (v Variable) HasChildren() bool {
+	return v.children != nil
+}
+
+// Children returns the fields, elements or pointee of a variable
+func (v Variable) Children() []Variable {
+	if v.children == nil {
+		return nil
+	}
+	return v.children()
+}
+
+// location is where an expression says a value lives
+type location struct {
+	addr  uint64 // Memory address
+	value uint64 // Value held in a local, global or computed
+	inMem bool
+}
+
+// WASM location kinds of DW_OP_WASM_location
+const (
+	wasmLocal       = 0
+	wasmGlobal      = 1
+	wasmStack       = 2
+	wasmGlobalFixed = 3
+)
+
+// evalLocation runs a DWARF location expression for a frame
+func evalLocation(expr []byte, in *emulator.Instance, f emulator.Frame, frameBase func() (uint64, error)) (location, error) {
+	if frameBase == nil {
+		frameBase = func() (uint64, error) {
+			return 0, fmt.Errorf("frame base refers to itself")
+		}
+	}
+	var stack []uint64
+	pop := func() (uint64, error) {
+		if len(stack) == 0 {
+			return 0, fmt.Errorf("invalid location expression")
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v, nil
+	}
+	uleb := func(pos *int) uint64 {
+		var v uint64
+		var shift uint
+		for *pos < len(expr) {
+			b := expr[*pos]
+			*pos++
+			v |= uint64(b&0x7F) << shift
+			shift += 7
+			if b&0x80 == 0 {
+				break
+			}
+		}
+		return v
+	}
+	sleb := func(pos *int) int64 {
+		var v int64
+		var shift uint
+		var b byte
+		for *pos < len(expr) {
+			b = expr[*pos]
+			*pos++
+			v |= int64(b&0x7F) << shift
+			shift += 7
+			if b&0x80 == 0 {
+				break
+			}
+		}
+		if shift < 64 && b&0x40 != 0 {
+			v |= -1 << shift
+		}
+		return v
+	}
+
+	for pos := 0; pos < len(expr); {
+		op := expr[pos]
+		pos++
+		switch op {
+		case 0x03: // DW_OP_addr
+			if pos+4 > len(expr) {
+				return location{}, fmt.Errorf("invalid location expression")
+			}
+			stack = append(stack, uint64(binary.LittleEndian.Uint32(expr[pos:])))
+			pos += 4
+		case 0x06: // DW_OP_deref
+			addr, err := pop()
+			if err != nil {
+				return location{}, err
+			}
+			b, ok := in.Read(uint32(addr), 4)
+			if !ok {
+				return location{}, fmt.Errorf("address 0x%x is out of bounds", addr)
+			}
+			stack = append(stack, uint64(binary.LittleEndian.Uint32(b)))
+		case 0x10: // DW_OP_constu
+			stack = append(stack, uleb(&pos))
+		case 0x11: // DW_OP_consts
+			stack = append(stack, uint64(sleb(&pos)))
+		case 0x1C, 0x22: // DW_OP_minus, DW_OP_plus
+			b, err1 := pop()
+			a, err2 := pop()
+			if err1 != nil || err2 != nil {
+				return location{}, fmt.Errorf("invalid location expression")
+			}
+			if op == 0x22 {
+				stack = append(stack, a+b)
+			} else {
+				stack = append(stack, a-b)
+			}
+		case 0x23: // DW_OP_plus_uconst
+			a, err := pop()
+			if err != nil {
+				return location{}, err
+			}
+			stack = append(stack, a+uleb(&pos))
+		case 0x91: // DW_OP_fbreg
+			base, err := frameBase()
+			if err != nil {
+				return location{}, err
+			}
+			stack = append(stack, uint64(uint32(int64(base)+sleb(&pos))))
+		case 0x9F: // DW_OP_stack_value
+			v, err := pop()
+			return location{value: v}, err
+		case 0xED: // DW_OP_WASM_location
+			if pos >= len(expr) {
+				return location{}, fmt.Errorf("invalid location expression")
+			}
+			kind := expr[pos]
+			pos++
+			var idx uint64
+			if kind == wasmGlobalFixed {
+				if pos+4 > len(expr) {
+					return location{}, fmt.Errorf("invalid location expression")
+				}
+				idx = uint64(binary.LittleEndian.Uint32(expr[pos:]))
+				pos += 4
+			} else {
+				idx = uleb(&pos)
+			}
+			switch kind {
+			case wasmLocal:
+				if int(idx) >= len(f.Locals) {
+					return location{}, fmt.Errorf("local %d out of range", idx)
+				}
+				return location{value: f.Locals[idx]}, nil
+			case wasmGlobal, wasmGlobalFixed:
+				v, ok := in.Global(uint32(idx))
+				if !ok {
+					return location{}, fmt.Errorf("global %d out of range", idx)
+				}
+				return location{value: v}, nil
+			default:
+				return location{}, fmt.Errorf("value is on the operand stack")
+			}
+		case 0x9C: // DW_OP_call_frame_cfa
+			base, err := frameBase()
+			if err != nil {
+				return location{}, err
+			}
+			stack = append(stack, base)
+		default:
+			return location{}, fmt.Errorf("unsupported location operation 0x%02X", op)
+		}
+	}
+
+	addr, err := pop()
+	return location{addr: addr, inMem: true}, err
+}
+
+// valueReader reads typed values from an instance's memory
+type valueReader struct {
+	in *emulator.Instance
+}
+
+// variable renders a value of a type, given its bytes
+func (r valueReader) variable(name string, t dwarf.Type, b []byte, addr uint64, inMem bool) Variable {
+	v := Variable{Name: name, Type: typeName(t), Address: addr, InMem: inMem}
+	t = underlying(t)
+
+	switch t := t.(type) {
+	case *dwarf.BoolType:
+		v.Value = strconv.FormatBool(len(b) > 0 && b[0] != 0)
+	case *dwarf.IntType, *dwarf.CharType:
+		v.Value = strconv.FormatInt(signed(b), 10)
+	case *dwarf.UintType, *dwarf.UcharType:
+		v.Value = strconv.FormatUint(unsigned(b), 10)
+	case *dwarf.FloatType:
+		switch len(b) {
+		case 4:
+			v.Value = strconv.FormatFloat(float64(math.Float32frombits(uint32(unsigned(b)))), 'g', -1, 32)
+		case 8:
+			v.Value = strconv.FormatFloat(math.Float64frombits(unsigned(b)), 'g', -1, 64)
+		default:
+			v.Value = hexValue(b)
+		}
+	case *dwarf.EnumType:
+		n := signed(b)
+		v.Value = strconv.FormatInt(n, 10)
+		for _, e := range t.Val {
+			if e.Val == n {
+				v.Value = e.Name
+				break
+			}
+		}
+	case *dwarf.PtrType:
+		ptr := unsigned(b)
+		v.Value = fmt.Sprintf("0x%08x", ptr)
+		if elem := underlying(t.Type); elem != nil && elem.Size() > 0 && ptr != 0 {
+			v.children = func() []Variable {
+				return []Variable{r.at("*"+name, t.Type, ptr)}
+			}
+		}
+	case *dwarf.StructType:
+		r.structValue(&v, t, b)
+	case *dwarf.ArrayType:
+		r.arrayValue(&v, t, addr, b)
+	default:
+		v.Value = hexValue(b)
+	}
+	return v
+}
+
+// at reads a value of a type from memory
+func (r valueReader) at(name string, t dwarf.Type, addr uint64) Variable {
+	size := t.Size()
+	if size < 0 {
+		size = 0
+	}
+	b, ok := r.in.Read(uint32(addr), uint32(size))
+	if !ok {
+		return Variable{Name: name, Type: typeName(t), Value: fmt.Sprintf("<out of bounds 0x%08x>", addr)}
+	}
+	return r.variable(name, t, b, addr, true)
+}
+
+func (r valueReader) structValue(v *Variable, t *dwarf.StructType, b []byte) {
+	fields := func() []Variable {
+		var out []Variable
+		for _, f := range t.Field {
+			size := f.Type.Size()
+			if f.ByteOffset < 0 || size < 0 || f.ByteOffset+size > int64(len(b)) {
+				continue
+			}
+			out = append(out, r.variable(f.Name, f.Type, b[f.ByteOffset:f.ByteOffset+size], v.Address+uint64(f.ByteOffset), v.InMem))
+		}
+		return out
+	}
+
+	// Rust string slices render as their text
+	if s, ok := r.str(t, b); ok {
+		v.Value = strconv.Quote(s)
+		v.children = fields
+		return
+	}
+
+	parts := make([]string, 0, maxSummary)
+	for i, f := range fields() {
+		if i == maxSummary {
+			parts = append(parts, "…")
+			break
+		}
+		parts = append(parts, f.Name+": "+f.Value)
+	}
+	v.Value = "{" + strings.Join(parts, ", ") + "}"
+	if len(t.Field) > 0 {
+		v.children = fields
+	}
+}
+
+// str reads a &str: a data_ptr and length pair
+func (r valueReader) str(t *dwarf.StructType, b []byte) (string, bool) {
+	if t.StructName != "&str" || len(t.Field) != 2 || len(b) < 8 {
+		return "", false
+	}
+	ptr := binary.LittleEndian.Uint32(b[t.Field[0].ByteOffset:])
+	n := binary.LittleEndian.Uint32(b[t.Field[1].ByteOffset:])
+	if n > maxStringLen {
+		n = maxStringLen
+	}
+	data, ok := r.in.Read(ptr, n)
+	return string(data), ok
+}
+
+func (r valueReader) arrayValue(v *Variable, t *dwarf.ArrayType, addr uint64, b []byte) {
+	elemSize := t.Type.Size()
+	count := t.Count
+	if elemSize <= 0 || count < 0 {
+		v.Value = hexValue(b)
+		return
+	}
+	if count > maxElements {
+		count = maxElements
+	}
+	elems := func() []Variable {
+		out := make([]Variable, 0, count)
+		for i := int64(0); i < count && (i+1)*elemSize <= int64(len(b)); i++ {
+			out = append(out, r.variable(fmt.Sprintf("[%d]", i), t.Type, b[i*elemSize:(i+1)*elemSize], addr+uint64(i*elemSize), v.InMem))
+		}
+		return out
+	}
+
+	// Byte arrays, such as account IDs and hashes, read best as hex
+	if elemSize == 1 {
+		v.Value = hexValue(b)
+	} else {
+		parts := make([]string, 0, maxSummary)
+		for i, e := range elems() {
+			if i == maxSummary {
+				parts = append(parts, "…")
+				break
+			}
+			parts = append(parts, e.Value)
+		}
+		v.Value = "[" + strings.Join(parts, ", ") + "]"
+	}
+	v.children = elems
+}
+
+// underlying strips typedefs and qualifiers
+func underlying(t dwarf.Type) dwarf.Type {
+	for {
+		switch u := t.(type) {
+		case *dwarf.TypedefType:
+			t = u.Type
+		case *dwarf.QualType:
+			t = u.Type
+		default:
+			return t
+		}
+	}
+}
+
+func typeName(t dwarf.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+func unsigned(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:], b)
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+func signed(b []byte) int64 {
+	n := len(b)
+	if n == 0 || n > 8 {
+		return int64(unsigned(b))
+	}
+	shift := uint(64 - 8*n)
+	return int64(unsigned(b)<<shift) >> shift
+}
+
+func hexValue(b []byte) string {
+	if len(b) == 0 {
+		return "<empty>"
+	}
+	return "0x" + hex.EncodeToString(b)
+}
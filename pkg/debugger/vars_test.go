@@ -0,0 +1,151 @@
+package debugger
+
+import (
+	"debug/dwarf"
+	"strings"
+	"testing"
+
+	"github.com/xrpl-commons/bedrock/pkg/emulator"
+)
+
+func newTestInstance(t *testing.T) *emulator.Instance {
+	t.Helper()
+	s := newTestSession(t)
+	in, err := emulator.Instantiate(s.Emulator.Module, func(emulator.Import) (emulator.HostFunc, bool) {
+		return emulator.HostFunc{}, false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
+func TestEvalLocation(t *testing.T) {
+	in := newTestInstance(t)
+	in.Write(0x200, u32(0x1234))
+	frame := emulator.Frame{Locals: []uint64{7, 0xFFFFFFFF}}
+	frameBase := func() (uint64, error) { return 100, nil }
+
+	tests := []struct {
+		name    string
+		expr    []byte
+		want    location
+		wantErr string
+	}{
+		{name: "local", expr: []byte{0xED, wasmLocal, 1}, want: location{value: 0xFFFFFFFF}},
+		{name: "global", expr: []byte{0xED, wasmGlobal, 0}, want: location{value: 4096}},
+		{name: "fixed global", expr: cat([]byte{0xED, wasmGlobalFixed}, u32(0)), want: location{value: 4096}},
+		{name: "local out of range", expr: []byte{0xED, wasmLocal, 2}, wantErr: "local 2 out of range"},
+		{name: "global out of range", expr: []byte{0xED, wasmGlobal, 5}, wantErr: "global 5 out of range"},
+		{name: "operand stack", expr: []byte{0xED, wasmStack, 0}, wantErr: "value is on the operand stack"},
+		{name: "truncated WASM location", expr: []byte{0xED}, wantErr: "invalid location expression"},
+		{name: "frame base offset", expr: []byte{0x91, 0x08}, want: location{addr: 108, inMem: true}},
+		{name: "negative frame base offset", expr: []byte{0x91, 0x78}, want: location{addr: 92, inMem: true}},
+		{name: "call frame CFA", expr: []byte{0x9C, 0x23, 0x04}, want: location{addr: 104, inMem: true}},
+		{name: "address", expr: cat([]byte{0x03}, u32(0x200)), want: location{addr: 0x200, inMem: true}},
+		{name: "dereference", expr: cat([]byte{0x03}, u32(0x200), []byte{0x06}), want: location{addr: 0x1234, inMem: true}},
+		{name: "dereference out of bounds", expr: cat([]byte{0x03}, u32(0x20000), []byte{0x06}), wantErr: "out of bounds"},
+		{name: "stack value", expr: []byte{0x10, 0x05, 0x10, 0x03, 0x1C, 0x9F}, want: location{value: 2}},
+		{name: "plus", expr: []byte{0x11, 0x7F, 0x10, 0x03, 0x22, 0x9F}, want: location{value: 2}},
+		{name: "empty", expr: nil, wantErr: "invalid location expression"},
+		{name: "stack underflow", expr: []byte{0x10, 0x01, 0x22}, wantErr: "invalid location expression"},
+		{name: "unsupported operation", expr: []byte{0x96}, wantErr: "unsupported location operation 0x96"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evalLocation(tt.expr, in, frame, frameBase)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("location = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := evalLocation([]byte{0x91, 0x00}, in, frame, nil); err == nil {
+		t.Error("frame base offset evaluated without a frame base")
+	}
+}
+
+func TestRenderValues(t *testing.T) {
+	in := newTestInstance(t)
+	in.Write(0x300, []byte("hello"))
+	in.Write(0x400, u32(42))
+	r := valueReader{in: in}
+
+	i32 := &dwarf.IntType{BasicType: dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: 4, Name: "i32"}}}
+	u8 := &dwarf.UintType{BasicType: dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: 1, Name: "u8"}}}
+	usize := &dwarf.UintType{BasicType: dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: 4, Name: "usize"}}}
+	f64 := &dwarf.FloatType{BasicType: dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: 8, Name: "f64"}}}
+	boolean := &dwarf.BoolType{BasicType: dwarf.BasicType{CommonType: dwarf.CommonType{ByteSize: 1, Name: "bool"}}}
+	ptr := &dwarf.PtrType{CommonType: dwarf.CommonType{ByteSize: 4, Name: "*const i32"}, Type: i32}
+	str := &dwarf.StructType{CommonType: dwarf.CommonType{ByteSize: 8}, StructName: "&str", Kind: "struct",
+		Field: []*dwarf.StructField{{Name: "data_ptr", Type: ptr, ByteOffset: 0}, {Name: "length", Type: usize, ByteOffset: 4}}}
+	point := &dwarf.StructType{CommonType: dwarf.CommonType{ByteSize: 8}, StructName: "Point", Kind: "struct",
+		Field: []*dwarf.StructField{{Name: "x", Type: i32, ByteOffset: 0}, {Name: "y", Type: i32, ByteOffset: 4}}}
+	ordering := &dwarf.EnumType{CommonType: dwarf.CommonType{ByteSize: 1}, EnumName: "Ordering",
+		Val: []*dwarf.EnumValue{{Name: "Less", Val: -1}, {Name: "Equal", Val: 0}}}
+	bytes := &dwarf.ArrayType{CommonType: dwarf.CommonType{ByteSize: 3}, Type: u8, Count: 3}
+	ints := &dwarf.ArrayType{CommonType: dwarf.CommonType{ByteSize: 8}, Type: i32, Count: 2}
+	alias := &dwarf.TypedefType{CommonType: dwarf.CommonType{ByteSize: 4, Name: "Amount"}, Type: i32}
+
+	tests := []struct {
+		name string
+		typ  dwarf.Type
+		b    []byte
+		want string
+	}{
+		{"negative i32", i32, u32(0xFFFFFFFE), "-2"},
+		{"u8", u8, []byte{200}, "200"},
+		{"f64", f64, []byte{0, 0, 0, 0, 0, 0, 0xF8, 0x3F}, "1.5"},
+		{"bool", boolean, []byte{1}, "true"},
+		{"pointer", ptr, u32(0x400), "0x00000400"},
+		{"string slice", str, cat(u32(0x300), u32(5)), `"hello"`},
+		{"struct", point, cat(u32(3), u32(4)), "{x: 3, y: 4}"},
+		{"enum", ordering, []byte{0xFF}, "Less"},
+		{"unknown enum value", ordering, []byte{5}, "5"},
+		{"byte array", bytes, []byte{0xAB, 0xCD, 0xEF}, "0xabcdef"},
+		{"array", ints, cat(u32(1), u32(2)), "[1, 2]"},
+		{"typedef", alias, u32(9), "9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := r.variable("v", tt.typ, tt.b, 0, false)
+			if v.Value != tt.want {
+				t.Errorf("value = %q, want %q", v.Value, tt.want)
+			}
+		})
+	}
+
+	v := r.variable("p", ptr, u32(0x400), 0, false)
+	if children := v.Children(); len(children) != 1 || children[0].Name != "*p" || children[0].Value != "42" || children[0].Address != 0x400 {
+		t.Errorf("pointee = %+v", children)
+	}
+	if v := r.variable("p", ptr, u32(0), 0, false); v.HasChildren() {
+		t.Error("null pointer can be expanded")
+	}
+	if v := r.at("p", point, 0x20000); !strings.HasPrefix(v.Value, "<out of bounds") {
+		t.Errorf("value past memory = %q", v.Value)
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	tests := map[string][]string{
+		"p":           {"p"},
+		"p.x":         {"p", "x"},
+		"a[2]":        {"a", "[2]"},
+		"a.b[1][0].c": {"a", "b", "[1]", "[0]", "c"},
+	}
+	for path, want := range tests {
+		if got := splitPath(path); strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("splitPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
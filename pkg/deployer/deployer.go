@@ -4,14 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/xrpl-commons/bedrock/pkg/adapter"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/faucet"
+	"github.com/xrpl-commons/bedrock/pkg/wallet"
 )
 
 // Deployer handles contract deployment via embedded Node.js module
 type Deployer struct {
 	executor *adapter.Executor
 	verbose  bool
+
+	// OnProgress receives progress events while a transaction is built,
+	// submitted and validated
+	OnProgress adapter.ProgressFunc
 }
 
 // NewDeployer creates a new deployer instance
@@ -29,27 +39,33 @@ func NewDeployer(verbose bool) (*Deployer, error) {
 
 // Deploy deploys a contract to the specified network
 func (d *Deployer) Deploy(ctx context.Context, config DeploymentConfig) (*DeploymentResult, error) {
+	fee, err := chain.ResolveFee(ctx, config.Network, config.Fee, "ContractCreate")
+	if err != nil {
+		return nil, err
+	}
+
 	// Build JSON config for deploy.js module
 	jsConfig := map[string]interface{}{
 		"wasm_path":   config.WasmPath,
 		"abi_path":    config.ABIPath,
-		"network_url": config.NetworkURL,
-		"network_id":  config.NetworkID,
+		"network_url": config.Network.URL,
+		"network_id":  config.Network.NetworkID,
 		"algorithm":   config.Algorithm,
 		"verbose":     d.verbose,
 	}
 
-	if config.WalletSeed != "" {
-		jsConfig["wallet_seed"] = config.WalletSeed
+	// Signed deployments need a funded wallet before the module runs
+	var signer string
+	if !config.Unsigned {
+		seed, address, err := d.prepareWallet(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		jsConfig["wallet_seed"] = seed
+		signer = address
 	}
 
-	if config.FaucetURL != "" {
-		jsConfig["faucet_url"] = config.FaucetURL
-	}
-
-	if config.Fee != "" {
-		jsConfig["fee"] = config.Fee
-	}
+	jsConfig["fee"] = fee
 
 	if config.Immutable {
 		jsConfig["immutable"] = true
@@ -73,17 +89,161 @@ func (d *Deployer) Deploy(ctx context.Context, config DeploymentConfig) (*Deploy
 		jsConfig["owner"] = config.Owner
 	}
 
+	if config.Unsigned {
+		jsConfig["unsigned"] = true
+		jsConfig["account"] = config.Account
+	}
+
 	// Execute deploy.js module
-	result, err := d.executor.ExecuteModule(ctx, "deploy.js", jsConfig)
+	data, submission, err := d.execute(ctx, "deploy.js", config.Network, config.Unsigned, signer, jsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("deployment failed: %w", err)
 	}
 
 	// Parse deployment result from module output
 	var deployResult DeploymentResult
-	if err := json.Unmarshal(result.Data, &deployResult); err != nil {
+	if err := json.Unmarshal(data, &deployResult); err != nil {
 		return nil, fmt.Errorf("failed to parse deployment result: %w", err)
 	}
 
+	if submission != nil {
+		deployResult.TxHash = submission.Hash
+		deployResult.Validated = submission.Validated
+		deployResult.Meta = submission.Meta
+		deployResult.Submission = submission
+		deployResult.ContractAccount, deployResult.ContractIndex = createdContract(submission.Meta)
+		deployResult.ContractHash = contractSourceHash(submission.Meta)
+	}
+
 	return &deployResult, nil
 }
+
+// prepareWallet returns the seed to deploy with and its address. A wallet is
+// generated when none is given, and funded from the network's faucet when it
+// does not exist; local funding is a genesis payment submitted by the
+// submission engine.
+func (d *Deployer) prepareWallet(ctx context.Context, config DeploymentConfig) (string, string, error) {
+	xw, err := wallet.NewXRPLWallet()
+	if err != nil {
+		return "", "", err
+	}
+
+	seed := config.WalletSeed
+	if seed == "" {
+		w, err := xw.GenerateWalletWithAlgorithm("", config.Algorithm)
+		if err != nil {
+			return "", "", err
+		}
+		seed = w.Seed
+	}
+	address, err := xw.SeedToAddressWithAlgorithm(seed, config.Algorithm)
+	if err != nil {
+		return "", "", err
+	}
+
+	client := chain.NewNetworkClient(config.Network)
+	if _, err := client.GetAccountInfo(ctx, address); err == nil {
+		return seed, address, nil
+	}
+	if config.FaucetURL == "" {
+		// The deployment will most likely fail; let the node say why
+		return seed, address, nil
+	}
+
+	f, err := faucet.NewFaucet(d.verbose)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create faucet: %w", err)
+	}
+	f.OnProgress = d.OnProgress
+
+	isLocal := strings.Contains(config.Network.URL, "localhost") || strings.Contains(config.Network.URL, "127.0.0.1")
+	if _, err := f.Request(ctx, faucet.FaucetConfig{
+		FaucetURL:  config.FaucetURL,
+		WalletSeed: seed,
+		Algorithm:  config.Algorithm,
+		Network:    config.Network,
+		IsLocal:    isLocal,
+	}); err != nil {
+		return "", "", fmt.Errorf("failed to fund wallet: %w", err)
+	}
+
+	// External faucets fund the account asynchronously
+	for i := 0; i < fundWaitAttempts; i++ {
+		if _, err := client.GetAccountInfo(ctx, address); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+	return seed, address, nil
+}
+
+// fundWaitAttempts is how many seconds to wait for a funded account to appear
+const fundWaitAttempts = 10
+
+// execute runs a transaction module. Unsigned requests return the module
+// output as is; otherwise the module only signs and the transaction is
+// submitted and tracked by the submission engine. The Sequence of signer,
+// when given, is autofilled so the module signs offline.
+func (d *Deployer) execute(ctx context.Context, module string, network config.NetworkConfig, unsigned bool, signer string, jsConfig map[string]interface{}) (json.RawMessage, *chain.SubmissionResult, error) {
+	if unsigned {
+		result, err := d.executor.ExecuteModule(ctx, module, jsConfig, d.OnProgress)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Data, nil, nil
+	}
+
+	result, submission, err := d.executor.ExecuteAndSubmit(ctx, module, network, signer, jsConfig, d.OnProgress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := submission.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return result.Data, submission, nil
+}
+
+// createdContract finds the Contract ledger entry created by a transaction
+func createdContract(meta map[string]interface{}) (account, index string) {
+	nodes, _ := meta["AffectedNodes"].([]interface{})
+	for _, n := range nodes {
+		node, _ := n.(map[string]interface{})
+		created, ok := node["CreatedNode"].(map[string]interface{})
+		if !ok || created["LedgerEntryType"] != "Contract" {
+			continue
+		}
+		index, _ = created["LedgerIndex"].(string)
+		if fields, ok := created["NewFields"].(map[string]interface{}); ok {
+			account, _ = fields["ContractAccount"].(string)
+		}
+	}
+	return account, index
+}
+
+// contractSourceHash finds the ContractSource a transaction created or
+// referenced and returns its code hash
+func contractSourceHash(meta map[string]interface{}) string {
+	nodes, _ := meta["AffectedNodes"].([]interface{})
+	for _, n := range nodes {
+		node, _ := n.(map[string]interface{})
+		for _, kind := range []string{"CreatedNode", "ModifiedNode"} {
+			entry, ok := node[kind].(map[string]interface{})
+			if !ok || entry["LedgerEntryType"] != "ContractSource" {
+				continue
+			}
+			for _, key := range []string{"NewFields", "FinalFields"} {
+				if fields, ok := entry[key].(map[string]interface{}); ok {
+					if hash, ok := fields["ContractHash"].(string); ok {
+						return hash
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -4,82 +4,108 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
 )
 
 // ModifyConfig holds configuration for modifying a contract
 type ModifyConfig struct {
 	ContractAccount string
-	NetworkURL      string
+	Network         config.NetworkConfig
 	WalletSeed      string
 	Algorithm       string
 	WasmPath        string // Optional: new WASM code
 	ABIPath         string // Optional: new ABI
-	Fee             string
+	Fee             string // Drops or "auto"
 	Owner           string // Optional: new contract owner
 	ContractHash    string // Optional: reference existing ContractSource by hash
 	Immutable       bool
 	CodeImmutable   bool
 	ABIImmutable    bool
 	Undeletable     bool
+	Unsigned        bool   // Build the autofilled transaction without signing or submitting
+	Account         string // Signing account address, required when Unsigned
+	SignerCount     int    // With Unsigned: number of multisig signers to prepare for
 }
 
 // ModifyResult represents the result of a contract modification
 type ModifyResult struct {
-	TxHash    string                 `json:"txHash"`
-	Validated bool                   `json:"validated"`
-	Meta      map[string]interface{} `json:"meta"`
+	TxHash     string                  `json:"txHash"`
+	Validated  bool                    `json:"validated"`
+	Meta       map[string]interface{}  `json:"meta"`
+	UnsignedTx map[string]interface{}  `json:"unsignedTx,omitempty"`
+	Submission *chain.SubmissionResult `json:"submission,omitempty"`
 }
 
 // DeleteConfig holds configuration for deleting a contract
 type DeleteConfig struct {
 	ContractAccount string
-	NetworkURL      string
+	Network         config.NetworkConfig
 	WalletSeed      string
 	Algorithm       string
-	Fee             string
+	Fee             string // Drops or "auto"
+	Unsigned        bool   // Build the autofilled transaction without signing or submitting
+	Account         string // Signing account address, required when Unsigned
+	SignerCount     int    // With Unsigned: number of multisig signers to prepare for
 }
 
 // DeleteResult represents the result of a contract deletion
 type DeleteResult struct {
-	TxHash    string                 `json:"txHash"`
-	Validated bool                   `json:"validated"`
-	Meta      map[string]interface{} `json:"meta"`
+	TxHash     string                  `json:"txHash"`
+	Validated  bool                    `json:"validated"`
+	Meta       map[string]interface{}  `json:"meta"`
+	UnsignedTx map[string]interface{}  `json:"unsignedTx,omitempty"`
+	Submission *chain.SubmissionResult `json:"submission,omitempty"`
 }
 
 // UserDeleteConfig holds configuration for deleting user data from a contract
 type UserDeleteConfig struct {
 	ContractAccount string
-	NetworkURL      string
+	Network         config.NetworkConfig
 	WalletSeed      string
 	Algorithm       string
-	Fee             string
+	Fee             string // Drops or "auto"
+	Unsigned        bool   // Build the autofilled transaction without signing or submitting
+	Account         string // Signing account address, required when Unsigned
+	SignerCount     int    // With Unsigned: number of multisig signers to prepare for
 }
 
 // ClawbackConfig holds configuration for clawing back tokens from a contract
 type ClawbackConfig struct {
 	ContractAccount string
 	Amount          string
-	NetworkURL      string
+	Network         config.NetworkConfig
 	WalletSeed      string
 	Algorithm       string
-	Fee             string
+	Fee             string // Drops or "auto"
+	Unsigned        bool   // Build the autofilled transaction without signing or submitting
+	Account         string // Signing account address, required when Unsigned
+	SignerCount     int    // With Unsigned: number of multisig signers to prepare for
 }
 
 // ClawbackResult represents the result of a contract clawback
 type ClawbackResult struct {
-	TxHash    string                 `json:"txHash"`
-	Validated bool                   `json:"validated"`
-	Meta      map[string]interface{} `json:"meta"`
+	TxHash     string                  `json:"txHash"`
+	Validated  bool                    `json:"validated"`
+	Meta       map[string]interface{}  `json:"meta"`
+	UnsignedTx map[string]interface{}  `json:"unsignedTx,omitempty"`
+	Submission *chain.SubmissionResult `json:"submission,omitempty"`
 }
 
 // Modify updates a deployed contract's code or ABI
 func (d *Deployer) Modify(ctx context.Context, config ModifyConfig) (*ModifyResult, error) {
+	fee, err := chain.ResolveFee(ctx, config.Network, config.Fee, "ContractModify")
+	if err != nil {
+		return nil, err
+	}
+
 	jsConfig := map[string]interface{}{
 		"contract_account": config.ContractAccount,
-		"network_url":      config.NetworkURL,
+		"network_url":      config.Network.URL,
 		"wallet_seed":      config.WalletSeed,
 		"algorithm":        config.Algorithm,
-		"fee":              config.Fee,
+		"fee":              fee,
 		"verbose":          d.verbose,
 	}
 
@@ -108,89 +134,155 @@ func (d *Deployer) Modify(ctx context.Context, config ModifyConfig) (*ModifyResu
 		jsConfig["undeletable"] = true
 	}
 
-	result, err := d.executor.ExecuteModule(ctx, "modify.js", jsConfig)
+	if config.Unsigned {
+		jsConfig["unsigned"] = true
+		jsConfig["account"] = config.Account
+		jsConfig["signers_count"] = config.SignerCount
+	}
+
+	data, submission, err := d.execute(ctx, "modify.js", config.Network, config.Unsigned, "", jsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("contract modification failed: %w", err)
 	}
 
 	var modifyResult ModifyResult
-	if err := json.Unmarshal(result.Data, &modifyResult); err != nil {
+	if err := json.Unmarshal(data, &modifyResult); err != nil {
 		return nil, fmt.Errorf("failed to parse modify result: %w", err)
 	}
 
+	if submission != nil {
+		modifyResult.TxHash = submission.Hash
+		modifyResult.Validated = submission.Validated
+		modifyResult.Meta = submission.Meta
+		modifyResult.Submission = submission
+	}
+
 	return &modifyResult, nil
 }
 
 // Delete removes a deployed contract from the ledger
 func (d *Deployer) Delete(ctx context.Context, config DeleteConfig) (*DeleteResult, error) {
+	fee, err := chain.ResolveFee(ctx, config.Network, config.Fee, "ContractDelete")
+	if err != nil {
+		return nil, err
+	}
+
 	jsConfig := map[string]interface{}{
 		"contract_account": config.ContractAccount,
-		"network_url":      config.NetworkURL,
+		"network_url":      config.Network.URL,
 		"wallet_seed":      config.WalletSeed,
 		"algorithm":        config.Algorithm,
-		"fee":              config.Fee,
+		"fee":              fee,
 		"verbose":          d.verbose,
 	}
 
-	result, err := d.executor.ExecuteModule(ctx, "delete.js", jsConfig)
+	if config.Unsigned {
+		jsConfig["unsigned"] = true
+		jsConfig["account"] = config.Account
+		jsConfig["signers_count"] = config.SignerCount
+	}
+
+	data, submission, err := d.execute(ctx, "delete.js", config.Network, config.Unsigned, "", jsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("contract deletion failed: %w", err)
 	}
 
 	var deleteResult DeleteResult
-	if err := json.Unmarshal(result.Data, &deleteResult); err != nil {
+	if err := json.Unmarshal(data, &deleteResult); err != nil {
 		return nil, fmt.Errorf("failed to parse delete result: %w", err)
 	}
 
+	if submission != nil {
+		deleteResult.TxHash = submission.Hash
+		deleteResult.Validated = submission.Validated
+		deleteResult.Meta = submission.Meta
+		deleteResult.Submission = submission
+	}
+
 	return &deleteResult, nil
 }
 
 // Clawback reclaims tokens from a contract (issuer only)
 func (d *Deployer) Clawback(ctx context.Context, config ClawbackConfig) (*ClawbackResult, error) {
+	fee, err := chain.ResolveFee(ctx, config.Network, config.Fee, "ContractClawback")
+	if err != nil {
+		return nil, err
+	}
+
 	jsConfig := map[string]interface{}{
 		"contract_account": config.ContractAccount,
 		"amount":           config.Amount,
-		"network_url":      config.NetworkURL,
+		"network_url":      config.Network.URL,
 		"wallet_seed":      config.WalletSeed,
 		"algorithm":        config.Algorithm,
-		"fee":              config.Fee,
+		"fee":              fee,
 		"verbose":          d.verbose,
 	}
 
-	result, err := d.executor.ExecuteModule(ctx, "clawback.js", jsConfig)
+	if config.Unsigned {
+		jsConfig["unsigned"] = true
+		jsConfig["account"] = config.Account
+		jsConfig["signers_count"] = config.SignerCount
+	}
+
+	data, submission, err := d.execute(ctx, "clawback.js", config.Network, config.Unsigned, "", jsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("contract clawback failed: %w", err)
 	}
 
 	var clawbackResult ClawbackResult
-	if err := json.Unmarshal(result.Data, &clawbackResult); err != nil {
+	if err := json.Unmarshal(data, &clawbackResult); err != nil {
 		return nil, fmt.Errorf("failed to parse clawback result: %w", err)
 	}
 
+	if submission != nil {
+		clawbackResult.TxHash = submission.Hash
+		clawbackResult.Validated = submission.Validated
+		clawbackResult.Meta = submission.Meta
+		clawbackResult.Submission = submission
+	}
+
 	return &clawbackResult, nil
 }
 
 // UserDelete removes user's data from a contract and recovers reserves
 func (d *Deployer) UserDelete(ctx context.Context, config UserDeleteConfig) (*DeleteResult, error) {
+	fee, err := chain.ResolveFee(ctx, config.Network, config.Fee, "ContractUserDelete")
+	if err != nil {
+		return nil, err
+	}
+
 	jsConfig := map[string]interface{}{
 		"contract_account": config.ContractAccount,
-		"network_url":      config.NetworkURL,
+		"network_url":      config.Network.URL,
 		"wallet_seed":      config.WalletSeed,
 		"algorithm":        config.Algorithm,
-		"fee":              config.Fee,
+		"fee":              fee,
 		"verbose":          d.verbose,
 	}
 
-	result, err := d.executor.ExecuteModule(ctx, "user_delete.js", jsConfig)
+	if config.Unsigned {
+		jsConfig["unsigned"] = true
+		jsConfig["account"] = config.Account
+		jsConfig["signers_count"] = config.SignerCount
+	}
+
+	data, submission, err := d.execute(ctx, "user_delete.js", config.Network, config.Unsigned, "", jsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("user data deletion failed: %w", err)
 	}
 
 	var deleteResult DeleteResult
-	if err := json.Unmarshal(result.Data, &deleteResult); err != nil {
+	if err := json.Unmarshal(data, &deleteResult); err != nil {
 		return nil, fmt.Errorf("failed to parse user delete result: %w", err)
 	}
 
+	if submission != nil {
+		deleteResult.TxHash = submission.Hash
+		deleteResult.Validated = submission.Validated
+		deleteResult.Meta = submission.Meta
+		deleteResult.Submission = submission
+	}
+
 	return &deleteResult, nil
 }
-
@@ -1,31 +1,40 @@
 package deployer
 
+import (
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
 // DeploymentResult represents the result of a contract deployment
 type DeploymentResult struct {
-	TxHash          string                 `json:"txHash"`
-	WalletAddress   string                 `json:"walletAddress"`
-	WalletSeed      string                 `json:"walletSeed"`
-	ContractAccount string                 `json:"contractAccount"`
-	ContractIndex   string                 `json:"contractIndex"`
-	Validated       bool                   `json:"validated"`
-	Meta            map[string]interface{} `json:"meta"`
+	TxHash          string                  `json:"txHash"`
+	WalletAddress   string                  `json:"walletAddress"`
+	WalletSeed      string                  `json:"walletSeed"`
+	ContractAccount string                  `json:"contractAccount"`
+	ContractIndex   string                  `json:"contractIndex"`
+	ContractHash    string                  `json:"contractHash,omitempty"` // Hash of the ContractSource holding the code
+	Validated       bool                    `json:"validated"`
+	Meta            map[string]interface{}  `json:"meta"`
+	UnsignedTx      map[string]interface{}  `json:"unsignedTx,omitempty"`
+	Submission      *chain.SubmissionResult `json:"submission,omitempty"`
 }
 
 // DeploymentConfig holds configuration for deploying a contract
 type DeploymentConfig struct {
-	WasmPath       string
-	ABIPath        string
-	NetworkURL     string
-	NetworkID      uint32
-	WalletSeed     string
-	Algorithm      string
-	FaucetURL      string
-	Fee            string
-	Immutable      bool
-	CodeImmutable  bool
-	ABIImmutable   bool
-	Undeletable    bool
-	ReuseCode      string // Existing ContractSource hash to reference
-	Params         string // Instance parameter values as JSON
-	Owner          string // Optional contract owner (defaults to Account)
+	WasmPath      string
+	ABIPath       string
+	Network       config.NetworkConfig
+	WalletSeed    string
+	Algorithm     string
+	FaucetURL     string
+	Fee           string // Drops or "auto"
+	Immutable     bool
+	CodeImmutable bool
+	ABIImmutable  bool
+	Undeletable   bool
+	ReuseCode     string // Existing ContractSource hash to reference
+	Params        string // Instance parameter values as JSON
+	Owner         string // Optional contract owner (defaults to Account)
+	Unsigned      bool   // Build the autofilled transaction without signing or submitting
+	Account       string // Signing account address, required when Unsigned
 }
@@ -0,0 +1,294 @@
+// Package emulator runs contract WASM in process over an in-memory ledger
+// state, so contract functions can be exercised without an xrpld node.
+package emulator
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	addresscodec "github.com/Peersyst/xrpl-go/address-codec"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+	"github.com/xrpl-commons/bedrock/pkg/codec"
+	"github.com/xrpl-commons/bedrock/pkg/inspector"
+)
+
+// DefaultCaller is the account calls are made from when none is given
+const DefaultCaller = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+
+// DefaultGasLimit bounds a call when no limit is given
+const DefaultGasLimit = 1000000
+
+// Emulator executes the functions of a contract module
+type Emulator struct {
+	Module *Module
+	ABI    *abi.ABI // Optional; needed to pass named parameters
+	State  *State
+
+	// Unresolved lists the imports, as module.name, that the emulator has
+	// no host function for; calling one traps
+	Unresolved []string
+
+	defs *codec.Definitions
+}
+
+// Param is a function parameter as the contract reads it
+type Param struct {
+	Name     string
+	Type     string
+	TypeCode uint16
+	Value    []byte
+}
+
+// CallOptions configures a call
+type CallOptions struct {
+	Caller   string                 // Defaults to DefaultCaller
+	Params   map[string]interface{} // By ABI parameter name; plain or {"type", "value"}
+	GasLimit uint64                 // Defaults to DefaultGasLimit
+
+	// Instrument, when set, is given the instance before the call starts,
+	// to install Step and HostCall hooks
+	Instrument func(in *Instance)
+}
+
+// Result is the outcome of a call
+type Result struct {
+	Function string
+	Returns  []uint64 // Raw values returned by the export
+	Code     int64    // First return value, sign extended; 0 when none
+	GasUsed  uint64
+	Traces   []string
+	Events   []Event
+	Changes  []StateChange
+
+	// Err is the trap or gas exhaustion that aborted the call
+	Err error
+
+	// Unresolved is the emulator's list of imports it cannot link
+	Unresolved []string
+}
+
+// Success reports whether the call ran to completion with a non-negative
+// return code. Only successful calls change the state.
+func (r *Result) Success() bool {
+	return r.Err == nil && r.Code >= 0
+}
+
+// OutOfGas reports whether the call exceeded its gas limit
+func (r *Result) OutOfGas() bool {
+	return errors.Is(r.Err, ErrOutOfGas)
+}
+
+// Load reads a WASM file and returns an emulator for it. A nil state starts
+// empty, with a contract address derived from the code.
+func Load(wasmPath string, contractABI *abi.ABI, state *State) (*Emulator, error) {
+	data, err := os.ReadFile(wasmPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WASM: %w", err)
+	}
+	return New(data, contractABI, state)
+}
+
+// New returns an emulator for WASM code
+func New(wasm []byte, contractABI *abi.ABI, state *State) (*Emulator, error) {
+	info, err := inspector.Inspect(wasm)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Decode(wasm)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = NewState("")
+	}
+	if state.Contract == "" {
+		state.Contract, err = addresscodec.EncodeAccountIDToClassicAddress(sha512Half(wasm)[:20])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Emulator{
+		Module:     m,
+		ABI:        contractABI,
+		State:      state,
+		Unresolved: unresolved(m, info.Imports),
+		defs:       codec.DefaultDefinitions(),
+	}, nil
+}
+
+// unresolved returns the imports listed by the inspector that have no host
+// function with their name and signature
+func unresolved(m *Module, imports []string) []string {
+	resolve := (&call{}).resolve((&call{}).hostFuncs())
+	var missing []string
+	for i, name := range imports {
+		if i < len(m.Imports) {
+			if _, ok := resolve(m.Imports[i]); ok {
+				continue
+			}
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+// Call runs a contract function. Errors are returned for calls that cannot
+// start; traps and gas exhaustion are reported in the result.
+func (e *Emulator) Call(function string, opts CallOptions) (*Result, error) {
+	exportType, ok := e.Module.ExportType(function)
+	if !ok {
+		return nil, fmt.Errorf("function %s is not exported by the contract", function)
+	}
+	if opts.Caller == "" {
+		opts.Caller = DefaultCaller
+	}
+	if !addresscodec.IsValidClassicAddress(opts.Caller) {
+		return nil, fmt.Errorf("invalid caller address: %s", opts.Caller)
+	}
+	if opts.GasLimit == 0 {
+		opts.GasLimit = DefaultGasLimit
+	}
+
+	params, err := e.params(function, opts.Params)
+	if err != nil {
+		return nil, err
+	}
+	args, err := wasmArgs(exportType, params)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", function, err)
+	}
+
+	// Writes are staged on a copy and kept only if the call succeeds
+	c := &call{
+		em:       e,
+		function: function,
+		params:   params,
+		state:    e.State.clone(),
+		tx: map[string]interface{}{
+			"TransactionType":      "ContractCall",
+			"Account":              opts.Caller,
+			"ContractAccount":      e.State.Contract,
+			"FunctionName":         strings.ToUpper(hex.EncodeToString([]byte(function))),
+			"Fee":                  "1",
+			"Sequence":             uint32(1),
+			"ComputationAllowance": uint32(min(opts.GasLimit, 0xFFFFFFFF)),
+		},
+	}
+
+	in, err := Instantiate(e.Module, c.resolve(c.hostFuncs()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate contract: %w", err)
+	}
+	in.GasLimit = opts.GasLimit
+	if opts.Instrument != nil {
+		opts.Instrument(in)
+	}
+
+	result := &Result{Function: function, Unresolved: e.Unresolved}
+	result.Returns, result.Err = in.Call(function, args...)
+	result.GasUsed = min(in.Gas, opts.GasLimit)
+	result.Traces = c.traces
+
+	if result.Err == nil && len(result.Returns) > 0 {
+		if exportType.Results[0] == I32 {
+			result.Code = int64(int32(result.Returns[0]))
+		} else {
+			result.Code = int64(result.Returns[0])
+		}
+	}
+
+	if result.Success() {
+		result.Changes = c.state.diff(e.State)
+		result.Events = c.events
+		c.state.Events = append(c.state.Events, c.events...)
+		e.State = c.state
+	}
+	return result, nil
+}
+
+// params encodes the call parameters in ABI order
+func (e *Emulator) params(function string, values map[string]interface{}) ([]Param, error) {
+	var fn *abi.Function
+	if e.ABI != nil {
+		for i := range e.ABI.Functions {
+			if e.ABI.Functions[i].Name == function {
+				fn = &e.ABI.Functions[i]
+				break
+			}
+		}
+	}
+	if fn == nil {
+		if len(values) > 0 {
+			return nil, fmt.Errorf("function %s is not in the ABI; parameters cannot be passed", function)
+		}
+		return nil, nil
+	}
+
+	params := make([]Param, 0, len(fn.Parameters))
+	for _, p := range fn.Parameters {
+		v, ok := values[p.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing parameter: %s", p.Name)
+		}
+		if _, typed := v.(map[string]interface{}); !typed {
+			v = map[string]interface{}{"type": p.Type, "value": plainValue(v)}
+		}
+		code, value, err := codec.EncodeParameterValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		params = append(params, Param{Name: p.Name, Type: p.Type, TypeCode: code, Value: value})
+	}
+	for name := range values {
+		if !hasParam(fn.Parameters, name) {
+			return nil, fmt.Errorf("unknown parameter: %s", name)
+		}
+	}
+	return params, nil
+}
+
+// plainValue renders Go numbers the way the codec reads JSON numbers
+func plainValue(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(n)
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return v
+}
+
+func hasParam(params []abi.Parameter, name string) bool {
+	for _, p := range params {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wasmArgs passes integer parameters directly to exports that take them.
+// Exports without parameters read theirs through function_param.
+func wasmArgs(t FuncType, params []Param) ([]uint64, error) {
+	if len(t.Params) == 0 {
+		return nil, nil
+	}
+	if len(params) != len(t.Params) {
+		return nil, fmt.Errorf("export takes %d arguments but the ABI lists %d parameters", len(t.Params), len(params))
+	}
+	args := make([]uint64, len(params))
+	for i, p := range params {
+		if len(p.Value) > 8 || t.Params[i] == F32 || t.Params[i] == F64 {
+			return nil, fmt.Errorf("parameter %s (%s) cannot be passed as a %s argument", p.Name, p.Type, valTypeName(t.Params[i]))
+		}
+		var buf [8]byte
+		copy(buf[8-len(p.Value):], p.Value)
+		args[i] = binary.BigEndian.Uint64(buf[:])
+	}
+	return args, nil
+}
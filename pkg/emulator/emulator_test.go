@@ -0,0 +1,258 @@
+package emulator
+
+import (
+	"reflect"
+	"testing"
+
+	addresscodec "github.com/Peersyst/xrpl-go/address-codec"
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+)
+
+const testContract = "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf"
+
+// Memory layout of the host function test module
+const (
+	ownerPtr = 0  // 20 byte account ID of the contract
+	keyPtr   = 20 // "cnt"
+	valuePtr = 23 // 0x0102
+	msgPtr   = 25 // "hi"
+	namePtr  = 27 // "ev"
+	outPtr   = 64
+)
+
+func args(vs ...int32) []byte {
+	var b []byte
+	for _, v := range vs {
+		b = append(b, i32c(v)...)
+	}
+	return b
+}
+
+// hostModule imports host functions and exports one function per test
+func hostModule(t *testing.T) []byte {
+	t.Helper()
+	_, id, err := addresscodec.DecodeClassicAddressToAccountID(testContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i32 := func(n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = I32
+		}
+		return b
+	}
+	ret := []byte{I32}
+	drop := []byte{opDrop}
+
+	m := testModule{
+		imports: []testImport{
+			{"host_lib", "set_data_object_field", i32(6), ret}, // 0
+			{"host_lib", "get_data_object_field", i32(6), ret}, // 1
+			{"host_lib", "trace", i32(5), ret},                 // 2
+			{"host_lib", "emit_event", i32(4), ret},            // 3
+			{"host_lib", "function_param", i32(4), ret},        // 4
+			{"host_lib", "not_a_host_function", nil, ret},      // 5
+			{"host_lib", "get_ledger_sqn", i32(1), ret},        // 6: wrong signature
+		},
+		funcs: []testFunc{
+			{results: ret, export: "store", body: cat(
+				args(ownerPtr, 20, keyPtr, 3, valuePtr, 2), callOp(0), drop,
+				args(msgPtr, 2, valuePtr, 2, 1), callOp(2), drop,
+				args(namePtr, 2, valuePtr, 2), callOp(3), drop,
+				i32c(0))},
+			{results: ret, export: "fail", body: cat(
+				args(ownerPtr, 20, keyPtr, 3, valuePtr, 2), callOp(0), drop,
+				args(namePtr, 2, valuePtr, 2), callOp(3), drop,
+				i32c(-1))},
+			{results: ret, export: "load", body: cat(
+				args(ownerPtr, 20, keyPtr, 3, outPtr, 8), callOp(1))},
+			{results: ret, export: "load_small", body: cat(
+				args(ownerPtr, 20, keyPtr, 3, outPtr, 1), callOp(1))},
+			{results: ret, export: "param", body: cat(
+				args(0, 2, outPtr, 8), callOp(4), drop,
+				i32c(outPtr+3), mem(0x2D, 0))},
+			{results: ret, export: "param_type", body: cat(args(0, 1, outPtr, 8), callOp(4))},
+			{results: ret, export: "missing", body: callOp(5)},
+		},
+		memory: []uint32{1},
+		data:   cat(id, []byte("cnt\x01\x02hiev")),
+	}
+	return m.encode()
+}
+
+func newHostEmulator(t *testing.T) *Emulator {
+	t.Helper()
+	contractABI := &abi.ABI{Functions: []abi.Function{
+		{Name: "param", Parameters: []abi.Parameter{{Name: "n", Type: "UINT32"}}},
+		{Name: "param_type", Parameters: []abi.Parameter{{Name: "n", Type: "UINT32"}}},
+	}}
+	em, err := New(hostModule(t), contractABI, NewState(testContract))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return em
+}
+
+func TestUnresolvedImports(t *testing.T) {
+	em := newHostEmulator(t)
+	want := []string{"host_lib.not_a_host_function", "host_lib.get_ledger_sqn"}
+	if !reflect.DeepEqual(em.Unresolved, want) {
+		t.Errorf("Unresolved = %v, want %v", em.Unresolved, want)
+	}
+
+	result, err := em.Call("missing", CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectTrap(t, result.Err, "host_lib.not_a_host_function")
+	if !reflect.DeepEqual(result.Unresolved, want) {
+		t.Errorf("result lists %v as unresolved", result.Unresolved)
+	}
+}
+
+func TestHostStateAndEvents(t *testing.T) {
+	em := newHostEmulator(t)
+
+	result, err := em.Call("load", CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != errFieldNotFound {
+		t.Errorf("load before store = %d, want %d", result.Code, errFieldNotFound)
+	}
+
+	result, err = em.Call("store", CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success() {
+		t.Fatalf("store failed: code %d, %v", result.Code, result.Err)
+	}
+	if v, ok := em.State.Get(testContract, []byte("cnt")); !ok || string(v) != "\x01\x02" {
+		t.Errorf("stored value = %x, %v", v, ok)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Key != "636e74" || result.Changes[0].New != "0102" {
+		t.Errorf("changes = %+v", result.Changes)
+	}
+	if !reflect.DeepEqual(result.Traces, []string{"hi 0102"}) {
+		t.Errorf("traces = %q", result.Traces)
+	}
+	if len(result.Events) != 1 || result.Events[0].Name != "ev" || result.Events[0].Data != "0102" || result.Events[0].Function != "store" {
+		t.Errorf("events = %+v", result.Events)
+	}
+	if len(em.State.Events) != 1 {
+		t.Errorf("state has %d events, want 1", len(em.State.Events))
+	}
+	if result.GasUsed <= 3*hostGas {
+		t.Errorf("gas used = %d, want more than the three host calls", result.GasUsed)
+	}
+
+	result, err = em.Call("load", CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 2 {
+		t.Errorf("load = %d, want the value length 2", result.Code)
+	}
+	result, err = em.Call("load_small", CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != errBufferTooSmall {
+		t.Errorf("load into a 1 byte buffer = %d, want %d", result.Code, errBufferTooSmall)
+	}
+}
+
+func TestFailedCallKeepsState(t *testing.T) {
+	em := newHostEmulator(t)
+	before := em.State
+
+	result, err := em.Call("fail", CallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Success() || result.Code != -1 {
+		t.Fatalf("fail returned %d, success %v", result.Code, result.Success())
+	}
+	if em.State != before {
+		t.Error("state replaced after a failed call")
+	}
+	if _, ok := em.State.Get(testContract, []byte("cnt")); ok {
+		t.Error("write kept after a failed call")
+	}
+	if len(result.Events) != 0 || len(result.Changes) != 0 || len(em.State.Events) != 0 {
+		t.Errorf("failed call reported events %v and changes %v", result.Events, result.Changes)
+	}
+}
+
+func TestFunctionParam(t *testing.T) {
+	em := newHostEmulator(t)
+
+	result, err := em.Call("param", CallOptions{Params: map[string]interface{}{"n": 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != 7 {
+		t.Errorf("param = %d, want 7", result.Code)
+	}
+
+	result, err = em.Call("param_type", CallOptions{Params: map[string]interface{}{"n": 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Code != errInvalidParams {
+		t.Errorf("param with the wrong type code = %d, want %d", result.Code, errInvalidParams)
+	}
+
+	if _, err := em.Call("param", CallOptions{}); err == nil {
+		t.Error("call without its parameter succeeded")
+	}
+	if _, err := em.Call("param", CallOptions{Params: map[string]interface{}{"n": 1, "m": 2}}); err == nil {
+		t.Error("call with an unknown parameter succeeded")
+	}
+	if _, err := em.Call("store", CallOptions{Params: map[string]interface{}{"n": 1}}); err == nil {
+		t.Error("parameters passed to a function missing from the ABI")
+	}
+}
+
+func TestCallOptions(t *testing.T) {
+	em := newHostEmulator(t)
+	if _, err := em.Call("nope", CallOptions{}); err == nil {
+		t.Error("called a function that is not exported")
+	}
+	if _, err := em.Call("store", CallOptions{Caller: "not an address"}); err == nil {
+		t.Error("called with an invalid caller")
+	}
+
+	result, err := em.Call("store", CallOptions{GasLimit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.OutOfGas() || result.GasUsed != 10 {
+		t.Errorf("out of gas = %v, gas used %d; want true and 10", result.OutOfGas(), result.GasUsed)
+	}
+	if _, ok := em.State.Get(testContract, []byte("cnt")); ok {
+		t.Error("write kept after running out of gas")
+	}
+}
+
+func TestNewDerivesContractAddress(t *testing.T) {
+	wasm := exported(nil, nil).encode()
+	em, err := New(wasm, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addresscodec.IsValidClassicAddress(em.State.Contract) {
+		t.Errorf("contract address %q is not valid", em.State.Contract)
+	}
+	again, _ := New(wasm, nil, nil)
+	if again.State.Contract != em.State.Contract {
+		t.Error("contract address is not derived from the code")
+	}
+
+	if _, err := New([]byte("\x00asm"), nil, nil); err == nil {
+		t.Error("accepted a truncated module")
+	}
+}
@@ -0,0 +1,385 @@
+package emulator
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// label is an entered block, loop or if
+type label struct {
+	cont   int // Where a branch continues: the loop start, or after the end
+	endAt  int
+	height int // Stack height below the block's parameters
+	arity  int // Values a branch carries
+}
+
+func (in *Instance) push(v uint64) {
+	in.stack = append(in.stack, v)
+}
+
+func (in *Instance) pop() uint64 {
+	v := in.stack[len(in.stack)-1]
+	in.stack = in.stack[:len(in.stack)-1]
+	return v
+}
+
+func (in *Instance) pop32() uint32 {
+	return uint32(in.pop())
+}
+
+// branch unwinds the stack to a label, keeping the values it carries
+func (in *Instance) branch(l label) {
+	if len(in.stack)-l.arity != l.height {
+		copy(in.stack[l.height:], in.stack[len(in.stack)-l.arity:])
+		in.stack = in.stack[:l.height+l.arity]
+	}
+}
+
+// run executes a function body, leaving its results on the stack
+func (in *Instance) run(fn *function, f *Frame) error {
+	m := in.Module
+	code := fn.code
+	locals := f.Locals
+	labels := []label{{
+		cont:   len(code),
+		endAt:  len(code) - 1,
+		height: len(in.stack),
+		arity:  len(fn.typ.Results),
+	}}
+
+	u32 := func(pc *int) uint32 {
+		v, n := readULEB(code[*pc:], 32)
+		*pc += n
+		return uint32(v)
+	}
+
+	// br takes the branch to the label depth levels out
+	br := func(depth uint32, pc *int) {
+		i := len(labels) - 1 - int(depth)
+		l := labels[i]
+		in.branch(l)
+		*pc = l.cont
+		if l.cont > l.endAt {
+			labels = labels[:i]
+		} else {
+			labels = labels[:i+1] // A loop keeps its label
+		}
+	}
+
+	for pc := 0; pc < len(code); {
+		at := pc
+		op := code[pc]
+		pc++
+
+		if in.Step != nil {
+			f.PC = fn.offset + at
+			if err := in.Step(in); err != nil {
+				return err
+			}
+		}
+		if err := in.charge(1); err != nil {
+			return err
+		}
+
+		switch op {
+		case opUnreachable:
+			return trap("unreachable executed")
+		case opNop:
+
+		case opBlock, opLoop, opIf:
+			params, results, next, err := m.blockType(code, pc)
+			if err != nil {
+				return err
+			}
+			pc = next
+			taken := op != opIf || in.pop32() != 0
+			b := fn.blocks[at]
+			l := label{cont: b.endAt + 1, endAt: b.endAt, height: len(in.stack) - params, arity: results}
+			if op == opLoop {
+				l.cont, l.arity = pc, params
+			}
+			if !taken {
+				if b.elseAt == 0 {
+					pc = b.endAt + 1
+					continue
+				}
+				pc = b.elseAt
+			}
+			labels = append(labels, l)
+
+		case opElse:
+			// End of the taken branch of an if
+			pc = labels[len(labels)-1].endAt
+
+		case opEnd:
+			labels = labels[:len(labels)-1]
+
+		case opBr:
+			br(u32(&pc), &pc)
+
+		case opBrIf:
+			depth := u32(&pc)
+			if in.pop32() != 0 {
+				br(depth, &pc)
+			}
+
+		case opBrTable:
+			n := u32(&pc)
+			targets := make([]uint32, n+1)
+			for i := range targets {
+				targets[i] = u32(&pc)
+			}
+			i := in.pop32()
+			if i > n {
+				i = n
+			}
+			br(targets[i], &pc)
+
+		case opReturn:
+			br(uint32(len(labels)-1), &pc)
+
+		case opCall:
+			if err := in.callFunc(u32(&pc)); err != nil {
+				return err
+			}
+
+		case opCallIndirect:
+			typeIdx := u32(&pc)
+			u32(&pc) // Table index
+			elem := in.pop32()
+			if int(elem) >= len(in.table) || in.table[elem] < 0 {
+				return trap("undefined table element %d", elem)
+			}
+			idx := uint32(in.table[elem])
+			if !m.funcType(idx).equal(m.Types[typeIdx]) {
+				return trap("indirect call type mismatch")
+			}
+			if err := in.callFunc(idx); err != nil {
+				return err
+			}
+
+		case opDrop:
+			in.pop()
+
+		case opSelect, opSelectT:
+			if op == opSelectT {
+				pc += int(u32(&pc))
+			}
+			c := in.pop32()
+			b := in.pop()
+			if c == 0 {
+				in.stack[len(in.stack)-1] = b
+			}
+
+		case opLocalGet:
+			in.push(locals[u32(&pc)])
+		case opLocalSet:
+			locals[u32(&pc)] = in.pop()
+		case opLocalTee:
+			locals[u32(&pc)] = in.stack[len(in.stack)-1]
+		case opGlobalGet:
+			in.push(in.globals[u32(&pc)])
+		case opGlobalSet:
+			in.globals[u32(&pc)] = in.pop()
+
+		case opMemorySize:
+			pc++
+			in.push(uint64(len(in.memory) / pageSize))
+		case opMemoryGrow:
+			pc++
+			delta := in.pop32()
+			pages := uint32(len(in.memory) / pageSize)
+			if uint64(pages)+uint64(delta) > uint64(in.memMax) {
+				in.push(uint64(math.MaxUint32)) // -1
+				break
+			}
+			in.memory = append(in.memory, make([]byte, int(delta)*pageSize)...)
+			in.push(uint64(pages))
+
+		case opI32Const:
+			v, n := readSLEB(code[pc:], 32)
+			pc += n
+			in.push(uint64(uint32(int32(v))))
+		case opI64Const:
+			v, n := readSLEB(code[pc:], 64)
+			pc += n
+			in.push(uint64(v))
+		case opF32Const:
+			in.push(uint64(binary.LittleEndian.Uint32(code[pc:])))
+			pc += 4
+		case opF64Const:
+			in.push(binary.LittleEndian.Uint64(code[pc:]))
+			pc += 8
+
+		case opPrefixFC:
+			if err := in.prefixFC(u32(&pc), code, &pc); err != nil {
+				return err
+			}
+
+		default:
+			switch {
+			case op >= 0x28 && op <= 0x3E:
+				u32(&pc) // Alignment hint
+				offset := u32(&pc)
+				if err := in.memoryOp(op, offset); err != nil {
+					return err
+				}
+			case op >= 0x45 && op <= 0xC4:
+				if err := in.numeric(op); err != nil {
+					return err
+				}
+			default:
+				return trap("unsupported instruction 0x%02X", op)
+			}
+		}
+	}
+	return nil
+}
+
+// callFunc pops a function's arguments, calls it and pushes its results
+func (in *Instance) callFunc(idx uint32) error {
+	t := in.Module.funcType(idx)
+	n := len(t.Params)
+	args := append([]uint64(nil), in.stack[len(in.stack)-n:]...)
+	in.stack = in.stack[:len(in.stack)-n]
+
+	results, err := in.invoke(idx, args)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(t.Results) {
+		return trap("function %d returned %d values, expected %d", idx, len(results), len(t.Results))
+	}
+	in.stack = append(in.stack, results...)
+	return nil
+}
+
+// address computes an effective address, checking size bytes are in bounds
+func (in *Instance) address(offset uint32, size int) (uint64, error) {
+	addr := uint64(in.pop32()) + uint64(offset)
+	if addr+uint64(size) > uint64(len(in.memory)) {
+		return 0, trap("out of bounds memory access")
+	}
+	return addr, nil
+}
+
+// accessSizes are the bytes read or written by the loads and stores,
+// 0x28 to 0x3E
+var accessSizes = [...]int{
+	4, 8, 4, 8, 1, 1, 2, 2, 1, 1, 2, 2, 4, 4, // Loads
+	4, 8, 4, 8, 1, 2, 1, 2, 4, // Stores
+}
+
+func (in *Instance) memoryOp(op byte, offset uint32) error {
+	mem := in.memory
+
+	// Stores
+	if op >= 0x36 {
+		v := in.pop()
+		size := accessSizes[op-0x28]
+		addr, err := in.address(offset, size)
+		if err != nil {
+			return err
+		}
+		switch size {
+		case 1:
+			mem[addr] = byte(v)
+		case 2:
+			binary.LittleEndian.PutUint16(mem[addr:], uint16(v))
+		case 4:
+			binary.LittleEndian.PutUint32(mem[addr:], uint32(v))
+		case 8:
+			binary.LittleEndian.PutUint64(mem[addr:], v)
+		}
+		return nil
+	}
+
+	// Loads
+	addr, err := in.address(offset, accessSizes[op-0x28])
+	if err != nil {
+		return err
+	}
+	var v uint64
+	switch op {
+	case 0x28, 0x2A:
+		v = uint64(binary.LittleEndian.Uint32(mem[addr:]))
+	case 0x29, 0x2B:
+		v = binary.LittleEndian.Uint64(mem[addr:])
+	case 0x2C:
+		v = uint64(uint32(int32(int8(mem[addr]))))
+	case 0x2D, 0x31:
+		v = uint64(mem[addr])
+	case 0x2E:
+		v = uint64(uint32(int32(int16(binary.LittleEndian.Uint16(mem[addr:])))))
+	case 0x2F, 0x33:
+		v = uint64(binary.LittleEndian.Uint16(mem[addr:]))
+	case 0x30:
+		v = uint64(int64(int8(mem[addr])))
+	case 0x32:
+		v = uint64(int64(int16(binary.LittleEndian.Uint16(mem[addr:]))))
+	case 0x34:
+		v = uint64(int64(int32(binary.LittleEndian.Uint32(mem[addr:]))))
+	case 0x35:
+		v = uint64(binary.LittleEndian.Uint32(mem[addr:]))
+	}
+	in.push(v)
+	return nil
+}
+
+// prefixFC runs the saturating truncations and bulk memory instructions
+func (in *Instance) prefixFC(sub uint32, code []byte, pc *int) error {
+	switch sub {
+	case 0, 1, 2, 3, 4, 5, 6, 7:
+		in.push(truncSat(sub, in.pop()))
+		return nil
+
+	case 8: // memory.init
+		seg, n := readULEB(code[*pc:], 32)
+		*pc += n + 1
+		size, src, dst := in.pop32(), in.pop32(), in.pop32()
+		if int(seg) >= len(in.data) {
+			return trap("data segment %d out of range", seg)
+		}
+		data := in.data[seg]
+		if uint64(src)+uint64(size) > uint64(len(data)) || uint64(dst)+uint64(size) > uint64(len(in.memory)) {
+			return trap("out of bounds memory access")
+		}
+		copy(in.memory[dst:], data[src:src+size])
+
+	case 9: // data.drop
+		seg, n := readULEB(code[*pc:], 32)
+		*pc += n
+		if int(seg) < len(in.data) {
+			in.data[seg] = nil
+		}
+
+	case 10: // memory.copy
+		*pc += 2
+		size, src, dst := in.pop32(), in.pop32(), in.pop32()
+		if uint64(src)+uint64(size) > uint64(len(in.memory)) || uint64(dst)+uint64(size) > uint64(len(in.memory)) {
+			return trap("out of bounds memory access")
+		}
+		if err := in.charge(uint64(size) / 8); err != nil {
+			return err
+		}
+		copy(in.memory[dst:dst+size], in.memory[src:src+size])
+
+	case 11: // memory.fill
+		*pc++
+		size, val, dst := in.pop32(), in.pop32(), in.pop32()
+		if uint64(dst)+uint64(size) > uint64(len(in.memory)) {
+			return trap("out of bounds memory access")
+		}
+		if err := in.charge(uint64(size) / 8); err != nil {
+			return err
+		}
+		region := in.memory[dst : dst+size]
+		for i := range region {
+			region[i] = byte(val)
+		}
+
+	default:
+		return trap("unsupported instruction 0xFC %d", sub)
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	blockEmpty = 0x40
+	blockI32   = 0x7F
+	blockI64   = 0x7E
+)
+
+// mem encodes a load or store with its alignment and offset
+func mem(op byte, offset uint32) []byte {
+	return cat([]byte{op, 0}, uleb(uint64(offset)))
+}
+
+// call1 instantiates m and calls its export "f"
+func call1(t *testing.T, m testModule, args ...uint64) (uint64, error) {
+	t.Helper()
+	got, err := instantiate(t, m).Call("f", args...)
+	if err != nil {
+		return 0, err
+	}
+	return got[0], nil
+}
+
+func TestControlFlow(t *testing.T) {
+	i32 := []byte{I32}
+
+	brIf := exported(i32, i32,
+		[]byte{opBlock, blockI32}, i32c(10), localGet(0), []byte{opBrIf, 0, opDrop}, i32c(20), []byte{opEnd})
+
+	// Sums n down to 1 in a loop
+	loop := exported(i32, i32,
+		[]byte{opBlock, blockEmpty, opLoop, blockEmpty},
+		localGet(0), []byte{0x45, opBrIf, 1}, // br_if out when n == 0
+		localGet(1), localGet(0), []byte{0x6A}, localSet(1),
+		localGet(0), i32c(1), []byte{0x6B}, localSet(0),
+		[]byte{opBr, 0, opEnd, opEnd},
+		localGet(1))
+	loop.funcs[0].locals = i32
+
+	ifElse := exported(i32, i32,
+		localGet(0), []byte{opIf, blockI32}, i32c(1), []byte{opElse}, i32c(2), []byte{opEnd})
+
+	ifNoElse := exported(i32, i32,
+		i32c(5), localSet(1),
+		localGet(0), []byte{opIf, blockEmpty}, i32c(6), localSet(1), []byte{opEnd},
+		localGet(1))
+	ifNoElse.funcs[0].locals = i32
+
+	brTable := exported(i32, i32,
+		[]byte{opBlock, blockEmpty, opBlock, blockEmpty, opBlock, blockEmpty},
+		localGet(0), []byte{opBrTable, 2, 0, 1, 2, opEnd},
+		i32c(100), []byte{opReturn, opEnd},
+		i32c(101), []byte{opReturn, opEnd},
+		i32c(102))
+
+	// A branch keeps only the values its label carries
+	brUnwinds := exported(nil, i32,
+		[]byte{opBlock, blockI32}, i32c(1), i32c(2), i32c(3), []byte{opBr, 0, opEnd})
+
+	nestedReturn := exported(nil, i32,
+		[]byte{opBlock, blockEmpty, opBlock, blockEmpty}, i32c(7), []byte{opReturn, opEnd, opEnd}, i32c(8))
+
+	selectOp := exported(i32, i32, i32c(10), i32c(20), localGet(0), []byte{opSelect})
+
+	tests := []struct {
+		name string
+		m    testModule
+		arg  uint64
+		want uint64
+	}{
+		{"br_if taken", brIf, 1, 10},
+		{"br_if not taken", brIf, 0, 20},
+		{"loop", loop, 10, 55},
+		{"if", ifElse, 1, 1},
+		{"else", ifElse, 0, 2},
+		{"if without else taken", ifNoElse, 1, 6},
+		{"if without else skipped", ifNoElse, 0, 5},
+		{"br_table first", brTable, 0, 100},
+		{"br_table second", brTable, 1, 101},
+		{"br_table default", brTable, 7, 102},
+		{"branch unwinds the stack", brUnwinds, 0, 3},
+		{"return from nested blocks", nestedReturn, 0, 7},
+		{"select first", selectOp, 1, 10},
+		{"select second", selectOp, 0, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []uint64
+			if len(tt.m.funcs[0].params) > 0 {
+				args = []uint64{tt.arg}
+			}
+			got, err := call1(t, tt.m, args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalls(t *testing.T) {
+	i64 := []byte{I64}
+	m := testModule{funcs: []testFunc{
+		// 0: factorial, recursively
+		{params: i64, results: i64, body: cat(
+			localGet(0), []byte{0x50, opIf, blockI64}, i64c(1), []byte{opElse},
+			localGet(0), localGet(0), i64c(1), []byte{0x7D}, callOp(0), []byte{0x7E}, []byte{opEnd})},
+		// 1: the export
+		{params: i64, results: i64, body: cat(localGet(0), callOp(0)), export: "f"},
+	}}
+
+	got, err := call1(t, m, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 2432902008176640000 {
+		t.Errorf("20! = %d", got)
+	}
+}
+
+func TestCallIndirect(t *testing.T) {
+	m := testModule{
+		funcs: []testFunc{
+			{results: []byte{I32}, body: i32c(10)},
+			{results: []byte{I64}, body: i64c(11)},
+			{params: []byte{I32}, results: []byte{I32}, body: cat(localGet(0), []byte{opCallIndirect, 0, 0}), export: "f"},
+		},
+		table: []uint32{0, 1},
+	}
+
+	got, err := call1(t, m, 0)
+	if err != nil || got != 10 {
+		t.Errorf("call_indirect = %d, %v; want 10", got, err)
+	}
+	_, err = call1(t, m, 1)
+	expectTrap(t, err, "indirect call type mismatch")
+	_, err = call1(t, m, 2)
+	expectTrap(t, err, "undefined table element 2")
+}
+
+func TestTraps(t *testing.T) {
+	t.Run("unreachable", func(t *testing.T) {
+		m := testModule{funcs: []testFunc{
+			{body: []byte{opNop, opUnreachable}},
+			{body: callOp(0), export: "f"},
+		}}
+		_, err := instantiate(t, m).Call("f")
+		expectTrap(t, err, "unreachable executed")
+
+		// The trap records where it was raised, innermost first
+		var tr *Trap
+		errors.As(err, &tr)
+		if len(tr.Frames) != 2 || tr.Frames[0].Func != 0 || tr.Frames[1].Func != 1 {
+			t.Errorf("frames = %+v", tr.Frames)
+		}
+	})
+
+	t.Run("call stack exhausted", func(t *testing.T) {
+		m := testModule{funcs: []testFunc{{body: callOp(0), export: "f"}}}
+		_, err := instantiate(t, m).Call("f")
+		expectTrap(t, err, "call stack exhausted")
+	})
+
+	t.Run("unresolved import", func(t *testing.T) {
+		m := testModule{
+			imports: []testImport{{module: "host_lib", name: "missing", results: []byte{I32}}},
+			funcs:   []testFunc{{results: []byte{I32}, body: callOp(0), export: "f"}},
+		}
+		_, err := instantiate(t, m).Call("f")
+		expectTrap(t, err, "host function host_lib.missing () -> (i32) is not available")
+	})
+
+	t.Run("instance is reusable after a trap", func(t *testing.T) {
+		m := exported([]byte{I32}, []byte{I32}, i32c(100), localGet(0), []byte{0x6D})
+		in := instantiate(t, m)
+		_, err := in.Call("f", 0)
+		expectTrap(t, err, "integer divide by zero")
+		got, err := in.Call("f", 4)
+		if err != nil || got[0] != 25 {
+			t.Errorf("second call = %v, %v; want 25", got, err)
+		}
+	})
+}
+
+func TestCallErrors(t *testing.T) {
+	in := instantiate(t, exported([]byte{I32}, nil))
+	if _, err := in.Call("g"); err == nil {
+		t.Error("calling a missing export succeeded")
+	}
+	if _, err := in.Call("f"); err == nil {
+		t.Error("calling with too few arguments succeeded")
+	}
+}
+
+func TestGas(t *testing.T) {
+	// const, const, add, end
+	in := instantiate(t, exported(nil, []byte{I32}, i32c(1), i32c(2), []byte{0x6A}))
+	if _, err := in.Call("f"); err != nil {
+		t.Fatal(err)
+	}
+	if in.Gas != 4 {
+		t.Errorf("gas = %d, want 4", in.Gas)
+	}
+
+	spin := instantiate(t, exported(nil, nil, []byte{opLoop, blockEmpty, opBr, 0, opEnd}))
+	spin.GasLimit = 1000
+	_, err := spin.Call("f")
+	if !errors.Is(err, ErrOutOfGas) {
+		t.Fatalf("error = %v, want out of gas", err)
+	}
+	if spin.Gas != 1001 {
+		t.Errorf("gas = %d, want the limit plus the failing instruction", spin.Gas)
+	}
+}
+
+func TestStep(t *testing.T) {
+	in := instantiate(t, exported(nil, []byte{I32}, i32c(1), i32c(2), []byte{0x6A}))
+	var pcs []int
+	in.Step = func(in *Instance) error {
+		pcs = append(pcs, in.PC())
+		return nil
+	}
+	if _, err := in.Call("f"); err != nil {
+		t.Fatal(err)
+	}
+	start, _, _ := in.Module.CodeRange(0)
+	if len(pcs) != 4 || pcs[0] != start || pcs[1] != start+2 || pcs[2] != start+4 {
+		t.Errorf("steps at %v, code starts at %d", pcs, start)
+	}
+
+	stop := errors.New("stop")
+	in.Step = func(*Instance) error { return stop }
+	if _, err := in.Call("f"); !errors.Is(err, stop) {
+		t.Errorf("error = %v, want the Step error", err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	i32 := []byte{I32}
+	tests := []struct {
+		name     string
+		body     []byte
+		want     uint64
+		wantTrap string
+	}{
+		{"data segment", cat(i32c(4), mem(0x2D, 0)), 'o', ""},
+		{"load with offset", cat(i32c(0), mem(0x2D, 1)), 'e', ""},
+		{"i32.load little endian", cat(i32c(0), mem(0x28, 0)), 0x6C6C6568, ""},
+		{"store and load8_s", cat(i32c(16), i32c(-128), mem(0x36, 0), i32c(16), mem(0x2C, 0)), i32v(-128), ""},
+		{"store and load8_u", cat(i32c(16), i32c(-128), mem(0x36, 0), i32c(16), mem(0x2D, 0)), 0x80, ""},
+		{"store8 truncates", cat(i32c(16), i32c(0x1FF), mem(0x3A, 0), i32c(16), mem(0x28, 0)), 0xFF, ""},
+		{"store16 and load16_s", cat(i32c(16), i32c(0x8001), mem(0x3B, 0), i32c(16), mem(0x2E, 0)), 0xFFFF8001, ""},
+		{"last byte", cat(i32c(65535), mem(0x2D, 0)), 0, ""},
+		{"load past the end", cat(i32c(65533), mem(0x28, 0)), 0, "out of bounds memory access"},
+		{"offset past the end", cat(i32c(-1), mem(0x2D, 1)), 0, "out of bounds memory access"},
+		{"store past the end", cat(i32c(65535), i32c(1), mem(0x3B, 0), i32c(0)), 0, "out of bounds memory access"},
+		{"memory.size", []byte{opMemorySize, 0}, 1, ""},
+		{"memory.grow", cat(i32c(1), []byte{opMemoryGrow, 0, opDrop, opMemorySize, 0}), 2, ""},
+		{"memory.grow returns the old size", cat(i32c(1), []byte{opMemoryGrow, 0}), 1, ""},
+		{"memory.grow past the maximum", cat(i32c(2), []byte{opMemoryGrow, 0}), 0xFFFFFFFF, ""},
+		{"grown memory is usable", cat(i32c(1), []byte{opMemoryGrow, 0, opDrop}, i32c(70000), i32c(9), mem(0x36, 0), i32c(70000), mem(0x28, 0)), 9, ""},
+		{"memory.fill and memory.copy", cat(
+			i32c(32), i32c(0xAB), i32c(4), []byte{opPrefixFC, 11, 0},
+			i32c(40), i32c(32), i32c(4), []byte{opPrefixFC, 10, 0, 0},
+			i32c(40), mem(0x28, 0)), 0xABABABAB, ""},
+		{"memory.fill out of bounds", cat(i32c(65530), i32c(0), i32c(7), []byte{opPrefixFC, 11, 0}, i32c(0)), 0, "out of bounds memory access"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := exported(nil, i32, tt.body)
+			m.memory = []uint32{1, 2}
+			m.data = []byte("hello")
+			got, err := call1(t, m)
+			if tt.wantTrap != "" {
+				expectTrap(t, err, tt.wantTrap)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartFunction(t *testing.T) {
+	m := testModule{
+		funcs: []testFunc{
+			{body: cat(i32c(0), i32c(42), mem(0x3A, 0))},
+			{results: []byte{I32}, body: cat(i32c(0), mem(0x2D, 0)), export: "f"},
+		},
+		memory: []uint32{1},
+		start:  1,
+	}
+	got, err := call1(t, m)
+	if err != nil || got != 42 {
+		t.Errorf("f = %d, %v; want 42 written by the start function", got, err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	if _, err := Decode([]byte("not wasm")); err == nil {
+		t.Error("decoded a non-WASM file")
+	}
+
+	// A truncated module must fail to decode rather than panic
+	wasm := exported(nil, []byte{I32}, i32c(1)).encode()
+	for n := 9; n < len(wasm); n++ {
+		Decode(wasm[:n])
+	}
+}
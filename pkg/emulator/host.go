@@ -0,0 +1,303 @@
+package emulator
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	addresscodec "github.com/Peersyst/xrpl-go/address-codec"
+)
+
+// Error codes returned by host functions, as defined by xrpl-wasm-std
+const (
+	errInternal           = -1
+	errFieldNotFound      = -2
+	errBufferTooSmall     = -3
+	errPointerOutOfBounds = -13
+	errInvalidParams      = -15
+	errInvalidAccount     = -16
+	errInvalidField       = -17
+	errIndexOutOfBounds   = -18
+)
+
+// hostGas is charged for every host function call
+const hostGas = 100
+
+// call is the context of one contract call, shared by its host functions
+type call struct {
+	em       *Emulator
+	function string
+	tx       map[string]interface{}
+	params   []Param
+	state    *State
+	traces   []string
+	events   []Event
+}
+
+// hostFuncs returns the host functions available to a call by name. A name
+// may have several signatures to cover different versions of
+// xrpl-wasm-std; the one matching the import is linked.
+func (c *call) hostFuncs() map[string][]HostFunc {
+	i32, i64 := I32, I64
+	sig := func(params ...byte) FuncType {
+		return FuncType{Params: params, Results: []byte{i32}}
+	}
+
+	return map[string][]HostFunc{
+		"trace":         {c.host(sig(i32, i32, i32, i32, i32), c.trace)},
+		"trace_num":     {c.host(sig(i32, i32, i64), c.traceNum)},
+		"trace_account": {c.host(sig(i32, i32, i32, i32), c.traceAccount)},
+
+		"get_ledger_sqn": {
+			c.host(sig(), func(in *Instance, args []uint64) int64 { return int64(c.state.LedgerIndex) }),
+			c.host(sig(i32, i32), func(in *Instance, args []uint64) int64 {
+				return c.writeUint32(in, args[0], args[1], c.state.LedgerIndex)
+			}),
+		},
+		"get_parent_ledger_time": {
+			c.host(sig(), func(in *Instance, args []uint64) int64 { return int64(c.state.CloseTime) }),
+			c.host(sig(i32, i32), func(in *Instance, args []uint64) int64 {
+				return c.writeUint32(in, args[0], args[1], c.state.CloseTime)
+			}),
+		},
+		"get_parent_ledger_hash": {c.host(sig(i32, i32), func(in *Instance, args []uint64) int64 {
+			return c.write(in, args[0], args[1], c.ledgerHash())
+		})},
+
+		"compute_sha512_half": {c.host(sig(i32, i32, i32, i32), c.sha512Half)},
+		"account_keylet":      {c.host(sig(i32, i32, i32, i32), c.accountKeylet)},
+
+		"get_tx_field":                 {c.host(sig(i32, i32, i32), c.txField)},
+		"get_current_ledger_obj_field": {c.host(sig(i32, i32, i32), c.contractField)},
+		"function_param":               {c.host(sig(i32, i32, i32, i32), c.functionParam)},
+
+		"get_data_object_field": {c.host(sig(i32, i32, i32, i32, i32, i32), c.getData)},
+		"set_data_object_field": {c.host(sig(i32, i32, i32, i32, i32, i32), c.setData)},
+		"emit_event":            {c.host(sig(i32, i32, i32, i32), c.emitEvent)},
+	}
+}
+
+// host wraps a function returning an i32 status or length
+func (c *call) host(t FuncType, fn func(in *Instance, args []uint64) int64) HostFunc {
+	return HostFunc{
+		Type: t,
+		Gas:  hostGas,
+		Call: func(in *Instance, args []uint64) ([]uint64, error) {
+			return []uint64{uint64(uint32(int32(fn(in, args))))}, nil
+		},
+	}
+}
+
+// resolve links an import to the host function with its name and signature
+func (c *call) resolve(funcs map[string][]HostFunc) Resolver {
+	return func(imp Import) (HostFunc, bool) {
+		for _, f := range funcs[imp.Name] {
+			if f.Type.equal(imp.Type) {
+				return f, true
+			}
+		}
+		return HostFunc{}, false
+	}
+}
+
+// read returns a memory range given as (ptr, len) arguments
+func read(in *Instance, ptr, n uint64) ([]byte, bool) {
+	return in.Read(uint32(ptr), uint32(n))
+}
+
+// write copies data to an output buffer, returning the length written or
+// an error code
+func (c *call) write(in *Instance, ptr, size uint64, data []byte) int64 {
+	if uint64(len(data)) > uint64(uint32(size)) {
+		return errBufferTooSmall
+	}
+	if !in.Write(uint32(ptr), data) {
+		return errPointerOutOfBounds
+	}
+	return int64(len(data))
+}
+
+func (c *call) writeUint32(in *Instance, ptr, size uint64, v uint32) int64 {
+	return c.write(in, ptr, size, binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (c *call) trace(in *Instance, args []uint64) int64 {
+	msg, ok1 := read(in, args[0], args[1])
+	data, ok2 := read(in, args[2], args[3])
+	if !ok1 || !ok2 {
+		return errPointerOutOfBounds
+	}
+	line := string(msg)
+	if len(data) > 0 {
+		if uint32(args[4]) != 0 {
+			line += " " + strings.ToUpper(hex.EncodeToString(data))
+		} else {
+			line += " " + string(data)
+		}
+	}
+	c.traces = append(c.traces, line)
+	return 0
+}
+
+func (c *call) traceNum(in *Instance, args []uint64) int64 {
+	msg, ok := read(in, args[0], args[1])
+	if !ok {
+		return errPointerOutOfBounds
+	}
+	c.traces = append(c.traces, fmt.Sprintf("%s %d", msg, int64(args[2])))
+	return 0
+}
+
+func (c *call) traceAccount(in *Instance, args []uint64) int64 {
+	msg, ok1 := read(in, args[0], args[1])
+	id, ok2 := read(in, args[2], args[3])
+	if !ok1 || !ok2 {
+		return errPointerOutOfBounds
+	}
+	address, err := addresscodec.EncodeAccountIDToClassicAddress(id)
+	if err != nil {
+		return errInvalidAccount
+	}
+	c.traces = append(c.traces, fmt.Sprintf("%s %s", msg, address))
+	return 0
+}
+
+func sha512Half(data []byte) []byte {
+	sum := sha512.Sum512(data)
+	return sum[:32]
+}
+
+func (c *call) ledgerHash() []byte {
+	return sha512Half(binary.BigEndian.AppendUint32([]byte("LWR\x00"), c.state.LedgerIndex-1))
+}
+
+func (c *call) sha512Half(in *Instance, args []uint64) int64 {
+	data, ok := read(in, args[0], args[1])
+	if !ok {
+		return errPointerOutOfBounds
+	}
+	return c.write(in, args[2], args[3], sha512Half(data))
+}
+
+// accountKeylet computes the AccountRoot ledger index of an account
+func (c *call) accountKeylet(in *Instance, args []uint64) int64 {
+	id, ok := read(in, args[0], args[1])
+	if !ok {
+		return errPointerOutOfBounds
+	}
+	if len(id) != 20 {
+		return errInvalidAccount
+	}
+	return c.write(in, args[2], args[3], sha512Half(append([]byte{0x00, 0x61}, id...)))
+}
+
+// fieldValue encodes the field with a (type << 16 | field) code from an
+// object
+func (c *call) fieldValue(obj map[string]interface{}, code uint64) ([]byte, int64) {
+	f, ok := c.em.defs.FieldByHeader(int32(uint32(code)>>16), int32(uint32(code)&0xFFFF))
+	if !ok {
+		return nil, errInvalidField
+	}
+	v, ok := obj[f.Name]
+	if !ok {
+		return nil, errFieldNotFound
+	}
+	b, err := c.em.defs.EncodeValue(f, v)
+	if err != nil {
+		return nil, errInternal
+	}
+	return b, 0
+}
+
+func (c *call) txField(in *Instance, args []uint64) int64 {
+	b, code := c.fieldValue(c.tx, args[0])
+	if code != 0 {
+		return code
+	}
+	return c.write(in, args[1], args[2], b)
+}
+
+// contractField reads a field of the contract's own ledger entry
+func (c *call) contractField(in *Instance, args []uint64) int64 {
+	entry := map[string]interface{}{
+		"LedgerEntryType": "Contract",
+		"Account":         c.state.Contract,
+	}
+	b, code := c.fieldValue(entry, args[0])
+	if code != 0 {
+		return code
+	}
+	return c.write(in, args[1], args[2], b)
+}
+
+func (c *call) functionParam(in *Instance, args []uint64) int64 {
+	index := int(uint32(args[0]))
+	if index >= len(c.params) {
+		return errIndexOutOfBounds
+	}
+	p := c.params[index]
+	if uint16(uint32(args[1])) != p.TypeCode {
+		return errInvalidParams
+	}
+	return c.write(in, args[2], args[3], p.Value)
+}
+
+// dataOwner reads the account a data access is for
+func dataOwner(in *Instance, ptr, n uint64) (string, int64) {
+	id, ok := read(in, ptr, n)
+	if !ok {
+		return "", errPointerOutOfBounds
+	}
+	address, err := addresscodec.EncodeAccountIDToClassicAddress(id)
+	if err != nil || len(id) != 20 {
+		return "", errInvalidAccount
+	}
+	return address, 0
+}
+
+func (c *call) getData(in *Instance, args []uint64) int64 {
+	owner, code := dataOwner(in, args[0], args[1])
+	if code != 0 {
+		return code
+	}
+	key, ok := read(in, args[2], args[3])
+	if !ok {
+		return errPointerOutOfBounds
+	}
+	value, ok := c.state.Get(owner, key)
+	if !ok {
+		return errFieldNotFound
+	}
+	return c.write(in, args[4], args[5], value)
+}
+
+func (c *call) setData(in *Instance, args []uint64) int64 {
+	owner, code := dataOwner(in, args[0], args[1])
+	if code != 0 {
+		return code
+	}
+	key, ok1 := read(in, args[2], args[3])
+	value, ok2 := read(in, args[4], args[5])
+	if !ok1 || !ok2 {
+		return errPointerOutOfBounds
+	}
+	c.state.Set(owner, key, value)
+	return int64(len(value))
+}
+
+func (c *call) emitEvent(in *Instance, args []uint64) int64 {
+	name, ok1 := read(in, args[0], args[1])
+	data, ok2 := read(in, args[2], args[3])
+	if !ok1 || !ok2 {
+		return errPointerOutOfBounds
+	}
+	c.events = append(c.events, Event{
+		Name:        string(name),
+		Data:        strings.ToUpper(hex.EncodeToString(data)),
+		LedgerIndex: c.state.LedgerIndex,
+		Function:    c.function,
+	})
+	return 0
+}
@@ -0,0 +1,266 @@
+package emulator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Trap is a runtime error raised by the contract
+type Trap struct {
+	Reason string
+	Frames []Frame // Call stack where it was raised, innermost first
+}
+
+func (t *Trap) Error() string {
+	return "wasm trap: " + t.Reason
+}
+
+// ErrOutOfGas is returned when a call exceeds its gas limit
+var ErrOutOfGas = errors.New("out of gas")
+
+func trap(format string, args ...interface{}) error {
+	return &Trap{Reason: fmt.Sprintf(format, args...)}
+}
+
+// maxCallDepth bounds recursion inside the contract
+const maxCallDepth = 1000
+
+// HostFunc implements an imported function
+type HostFunc struct {
+	Type FuncType
+	Gas  uint64 // Charged per call, on top of the instruction
+	Call func(in *Instance, args []uint64) ([]uint64, error)
+}
+
+// Frame is a function activation on the call stack
+type Frame struct {
+	Func   uint32
+	PC     int // Code section offset of the current instruction; kept while Step is set
+	Locals []uint64
+}
+
+// Resolver returns the host function for an import, if there is one
+type Resolver func(imp Import) (HostFunc, bool)
+
+// Instance is an instantiated module with its memory, globals and gas
+// meter
+type Instance struct {
+	Module *Module
+
+	// Gas counts one unit per instruction executed plus the host functions'
+	// costs. Limit 0 means unlimited.
+	Gas      uint64
+	GasLimit uint64
+
+	// Step, when set, is called before each instruction; an error aborts the
+	// call. HostCall, when set, is called after each host function returns.
+	Step     func(in *Instance) error
+	HostCall func(imp Import, args, results []uint64, err error)
+
+	memory  []byte
+	memMax  uint32
+	globals []uint64
+	table   []int64 // Function indexes; -1 for empty slots
+	data    [][]byte
+	hosts   []HostFunc
+	stack   []uint64
+	frames  []*Frame
+}
+
+// Instantiate links a module's imports, initializes its memory, globals and
+// table, and runs its start function
+func Instantiate(m *Module, resolve Resolver) (*Instance, error) {
+	in := &Instance{Module: m, memMax: m.memMax}
+
+	for _, imp := range m.Imports {
+		host, ok := resolve(imp)
+		if !ok || !host.Type.equal(imp.Type) {
+			host = unresolvedHost(imp)
+		}
+		in.hosts = append(in.hosts, host)
+	}
+
+	for _, g := range m.globals {
+		v, err := m.evalConst(g.init, in.globals)
+		if err != nil {
+			return nil, err
+		}
+		in.globals = append(in.globals, v)
+	}
+
+	if m.hasMemory {
+		if m.memMin > m.memMax {
+			return nil, fmt.Errorf("initial memory of %d pages exceeds the limit", m.memMin)
+		}
+		in.memory = make([]byte, int(m.memMin)*pageSize)
+	}
+
+	if m.hasTable {
+		in.table = make([]int64, m.tableMin)
+		for i := range in.table {
+			in.table[i] = -1
+		}
+	}
+	for _, seg := range m.elements {
+		if seg.passive {
+			continue
+		}
+		offset, err := m.evalConst(seg.offset, in.globals)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(uint32(offset))+uint64(len(seg.funcs)) > uint64(len(in.table)) {
+			return nil, fmt.Errorf("element segment does not fit in the table")
+		}
+		for i, f := range seg.funcs {
+			in.table[int(uint32(offset))+i] = int64(f)
+		}
+	}
+
+	for _, seg := range m.data {
+		in.data = append(in.data, seg.data)
+		if seg.passive {
+			continue
+		}
+		offset, err := m.evalConst(seg.offset, in.globals)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(uint32(offset))+uint64(len(seg.data)) > uint64(len(in.memory)) {
+			return nil, fmt.Errorf("data segment does not fit in memory")
+		}
+		copy(in.memory[uint32(offset):], seg.data)
+		in.data[len(in.data)-1] = nil
+	}
+
+	if m.start >= 0 {
+		if _, err := in.invoke(uint32(m.start), nil); err != nil {
+			return nil, fmt.Errorf("start function failed: %w", err)
+		}
+	}
+	return in, nil
+}
+
+func unresolvedHost(imp Import) HostFunc {
+	return HostFunc{
+		Type: imp.Type,
+		Call: func(*Instance, []uint64) ([]uint64, error) {
+			return nil, trap("host function %s.%s %s is not available in the emulator", imp.Module, imp.Name, imp.Type)
+		},
+	}
+}
+
+// Call runs an exported function
+func (in *Instance) Call(name string, args ...uint64) ([]uint64, error) {
+	idx, ok := in.Module.Exports[name]
+	if !ok {
+		return nil, fmt.Errorf("function %s is not exported", name)
+	}
+	if t := in.Module.funcType(idx); len(args) != len(t.Params) {
+		return nil, fmt.Errorf("function %s takes %d arguments, got %d", name, len(t.Params), len(args))
+	}
+	in.stack = in.stack[:0]
+	in.frames = in.frames[:0]
+	return in.invoke(idx, args)
+}
+
+// Frames returns the call stack, innermost first
+func (in *Instance) Frames() []Frame {
+	frames := make([]Frame, len(in.frames))
+	for i, f := range in.frames {
+		frames[len(frames)-1-i] = *f
+	}
+	return frames
+}
+
+// Depth returns the number of contract functions on the call stack
+func (in *Instance) Depth() int {
+	return len(in.frames)
+}
+
+// PC returns the code section offset of the current instruction. It is only
+// kept up to date while Step is set.
+func (in *Instance) PC() int {
+	if len(in.frames) == 0 {
+		return 0
+	}
+	return in.frames[len(in.frames)-1].PC
+}
+
+// Global returns the value of a global
+func (in *Instance) Global(idx uint32) (uint64, bool) {
+	if int(idx) >= len(in.globals) {
+		return 0, false
+	}
+	return in.globals[idx], true
+}
+
+// Memory returns the instance's linear memory
+func (in *Instance) Memory() []byte {
+	return in.memory
+}
+
+// Read returns a copy of n bytes of memory at ptr
+func (in *Instance) Read(ptr, n uint32) ([]byte, bool) {
+	if uint64(ptr)+uint64(n) > uint64(len(in.memory)) {
+		return nil, false
+	}
+	return append([]byte(nil), in.memory[ptr:ptr+n]...), true
+}
+
+// Write copies data to memory at ptr
+func (in *Instance) Write(ptr uint32, data []byte) bool {
+	if uint64(ptr)+uint64(len(data)) > uint64(len(in.memory)) {
+		return false
+	}
+	copy(in.memory[ptr:], data)
+	return true
+}
+
+// charge adds gas, failing once the limit is passed
+func (in *Instance) charge(n uint64) error {
+	in.Gas += n
+	if in.GasLimit > 0 && in.Gas > in.GasLimit {
+		return ErrOutOfGas
+	}
+	return nil
+}
+
+// invoke calls a function by index with its arguments
+func (in *Instance) invoke(idx uint32, args []uint64) ([]uint64, error) {
+	if int(idx) < len(in.hosts) {
+		host := in.hosts[idx]
+		if err := in.charge(host.Gas); err != nil {
+			return nil, err
+		}
+		results, err := host.Call(in, args)
+		if in.HostCall != nil {
+			in.HostCall(in.Module.Imports[idx], args, results, err)
+		}
+		return results, err
+	}
+
+	if len(in.frames) >= maxCallDepth {
+		return nil, trap("call stack exhausted")
+	}
+
+	fn := in.Module.funcs[int(idx)-len(in.hosts)]
+	f := &Frame{Func: idx, PC: fn.offset, Locals: make([]uint64, len(args)+len(fn.locals))}
+	copy(f.Locals, args)
+	in.frames = append(in.frames, f)
+	defer func() { in.frames = in.frames[:len(in.frames)-1] }()
+
+	base := len(in.stack)
+	if err := in.run(fn, f); err != nil {
+		var t *Trap
+		if errors.As(err, &t) && t.Frames == nil {
+			t.Frames = in.Frames()
+		}
+		in.stack = in.stack[:base]
+		return nil, err
+	}
+	n := len(fn.typ.Results)
+	results := append([]uint64(nil), in.stack[len(in.stack)-n:]...)
+	in.stack = in.stack[:base]
+	return results, nil
+}
@@ -0,0 +1,855 @@
+package emulator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Value types
+const (
+	I32 byte = 0x7F
+	I64 byte = 0x7E
+	F32 byte = 0x7D
+	F64 byte = 0x7C
+)
+
+const pageSize = 65536
+
+// maxPages bounds memory growth (64 MiB)
+const maxPages = 1024
+
+// FuncType is a function signature
+type FuncType struct {
+	Params  []byte
+	Results []byte
+}
+
+func (t FuncType) equal(o FuncType) bool {
+	return string(t.Params) == string(o.Params) && string(t.Results) == string(o.Results)
+}
+
+func (t FuncType) String() string {
+	name := func(types []byte) string {
+		s := "("
+		for i, v := range types {
+			if i > 0 {
+				s += ", "
+			}
+			s += valTypeName(v)
+		}
+		return s + ")"
+	}
+	return name(t.Params) + " -> " + name(t.Results)
+}
+
+func valTypeName(t byte) string {
+	switch t {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	case F32:
+		return "f32"
+	case F64:
+		return "f64"
+	}
+	return fmt.Sprintf("0x%02x", t)
+}
+
+// Import is an imported function
+type Import struct {
+	Module string
+	Name   string
+	Type   FuncType
+}
+
+type function struct {
+	typ    FuncType
+	locals []byte // Declared locals, after the parameters
+	code   []byte
+	blocks map[int]block // Block structure by the position of its opcode
+	name   string
+	offset int // Position of the first instruction in the code section
+}
+
+// block records where a block's else and end are
+type block struct {
+	elseAt int // Position after the else opcode; 0 when there is none
+	endAt  int // Position of the end opcode
+}
+
+type global struct {
+	typ     byte
+	mutable bool
+	init    []byte
+}
+
+type segment struct {
+	passive bool
+	offset  []byte // Constant expression
+	data    []byte
+	funcs   []uint32
+}
+
+// Module is a decoded WASM module
+type Module struct {
+	Types   []FuncType
+	Imports []Import
+	Exports map[string]uint32 // Exported functions by name
+
+	funcs        []*function // Defined functions, indexed after the imports
+	tableMin     uint32
+	hasTable     bool
+	memMin       uint32
+	memMax       uint32
+	hasMemory    bool
+	globals      []global
+	elements     []segment
+	data         []segment
+	start        int64
+	memoryExport string
+	custom       map[string][]byte
+}
+
+// ExportType returns the signature of an exported function
+func (m *Module) ExportType(name string) (FuncType, bool) {
+	idx, ok := m.Exports[name]
+	if !ok {
+		return FuncType{}, false
+	}
+	return m.funcType(idx), true
+}
+
+// FuncName returns a function's name from the exports or the name section,
+// or the import name
+func (m *Module) FuncName(idx uint32) string {
+	if int(idx) < len(m.Imports) {
+		return m.Imports[idx].Module + "." + m.Imports[idx].Name
+	}
+	if i := int(idx) - len(m.Imports); i < len(m.funcs) && m.funcs[i].name != "" {
+		return m.funcs[i].name
+	}
+	return fmt.Sprintf("func[%d]", idx)
+}
+
+// CodeRange returns the code section offsets of a defined function's
+// instructions, the addresses DWARF line tables refer to
+func (m *Module) CodeRange(idx uint32) (start, end int, ok bool) {
+	i := int(idx) - len(m.Imports)
+	if int(idx) < len(m.Imports) || i >= len(m.funcs) {
+		return 0, 0, false
+	}
+	fn := m.funcs[i]
+	return fn.offset, fn.offset + len(fn.code), true
+}
+
+// CustomSection returns the contents of a custom section, such as
+// .debug_info
+func (m *Module) CustomSection(name string) ([]byte, bool) {
+	b, ok := m.custom[name]
+	return b, ok
+}
+
+func (m *Module) funcType(idx uint32) FuncType {
+	if int(idx) < len(m.Imports) {
+		return m.Imports[idx].Type
+	}
+	return m.funcs[int(idx)-len(m.Imports)].typ
+}
+
+type reader struct {
+	data []byte
+	pos  int
+}
+
+var errEOF = errors.New("unexpected end of module")
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errEOF
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, errEOF
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, n := readULEB(r.data[r.pos:], 32)
+	if n == 0 {
+		return 0, errEOF
+	}
+	r.pos += n
+	return uint32(v), nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(n))
+	return string(b), err
+}
+
+// constExpr reads a constant expression up to and including its end
+func (r *reader) constExpr() ([]byte, error) {
+	start := r.pos
+	for {
+		op, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if op == opEnd {
+			return r.data[start:r.pos], nil
+		}
+		next, err := skipImmediates(r.data, r.pos, op)
+		if err != nil {
+			return nil, err
+		}
+		r.pos = next
+	}
+}
+
+// readULEB decodes an unsigned LEB128 value, returning the bytes read (0 on
+// error)
+func readULEB(b []byte, bits int) (uint64, int) {
+	var v uint64
+	var shift uint
+	for i, c := range b {
+		if i >= (bits+6)/7 {
+			return 0, 0
+		}
+		v |= uint64(c&0x7F) << shift
+		if c&0x80 == 0 {
+			return v, i + 1
+		}
+		shift += 7
+	}
+	return 0, 0
+}
+
+// readSLEB decodes a signed LEB128 value
+func readSLEB(b []byte, bits int) (int64, int) {
+	var v int64
+	var shift uint
+	for i, c := range b {
+		if i >= (bits+6)/7 {
+			return 0, 0
+		}
+		v |= int64(c&0x7F) << shift
+		shift += 7
+		if c&0x80 == 0 {
+			if shift < 64 && c&0x40 != 0 {
+				v |= -1 << shift
+			}
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// Decode parses a WASM binary
+func Decode(data []byte) (*Module, error) {
+	if len(data) < 8 || string(data[:4]) != "\x00asm" {
+		return nil, fmt.Errorf("invalid WASM file: bad magic number")
+	}
+	if binary.LittleEndian.Uint32(data[4:8]) != 1 {
+		return nil, fmt.Errorf("unsupported WASM version %d", binary.LittleEndian.Uint32(data[4:8]))
+	}
+
+	m := &Module{Exports: make(map[string]uint32), start: -1, custom: make(map[string][]byte)}
+	var funcTypes []uint32
+	r := &reader{data: data, pos: 8}
+	for r.pos < len(data) {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		s := &reader{data: body}
+
+		switch id {
+		case 0:
+			var name string
+			if name, err = s.name(); err == nil {
+				m.custom[name] = body[s.pos:]
+			}
+		case 1:
+			err = m.readTypes(s)
+		case 2:
+			err = m.readImports(s)
+		case 3:
+			funcTypes, err = readVector(s, s.u32)
+		case 4:
+			err = m.readTables(s)
+		case 5:
+			err = m.readMemory(s)
+		case 6:
+			err = m.readGlobals(s)
+		case 7:
+			err = m.readExports(s)
+		case 8:
+			var idx uint32
+			idx, err = s.u32()
+			m.start = int64(idx)
+		case 9:
+			err = m.readElements(s)
+		case 10:
+			err = m.readCode(s, funcTypes)
+		case 11:
+			err = m.readData(s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid WASM section %d: %w", id, err)
+		}
+	}
+
+	if len(m.funcs) != len(funcTypes) {
+		return nil, fmt.Errorf("invalid WASM file: %d functions declared but %d defined", len(funcTypes), len(m.funcs))
+	}
+	if names, ok := m.custom["name"]; ok {
+		m.readNames(&reader{data: names}) // Names are optional; ignore a bad section
+	}
+	return m, nil
+}
+
+func readVector[T any](r *reader, read func() (T, error)) ([]T, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, 0, n)
+	for i := uint32(0); i < n; i++ {
+		v, err := read()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func (r *reader) valTypes() ([]byte, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	return r.bytes(int(n))
+}
+
+func (m *Module) readTypes(r *reader) error {
+	var err error
+	m.Types, err = readVector(r, func() (FuncType, error) {
+		form, err := r.byte()
+		if err != nil {
+			return FuncType{}, err
+		}
+		if form != 0x60 {
+			return FuncType{}, fmt.Errorf("unexpected type form 0x%02x", form)
+		}
+		params, err := r.valTypes()
+		if err != nil {
+			return FuncType{}, err
+		}
+		results, err := r.valTypes()
+		return FuncType{Params: params, Results: results}, err
+	})
+	return err
+}
+
+func (m *Module) readImports(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		module, err := r.name()
+		if err != nil {
+			return err
+		}
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind != 0 {
+			return fmt.Errorf("import %s.%s: only function imports are supported", module, name)
+		}
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if int(idx) >= len(m.Types) {
+			return fmt.Errorf("import %s.%s: type %d out of range", module, name, idx)
+		}
+		m.Imports = append(m.Imports, Import{Module: module, Name: name, Type: m.Types[idx]})
+	}
+	return nil
+}
+
+func (r *reader) limits() (uint32, uint32, bool, error) {
+	flag, err := r.byte()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	min, err := r.u32()
+	if err != nil || flag&1 == 0 {
+		return min, 0, false, err
+	}
+	max, err := r.u32()
+	return min, max, true, err
+}
+
+func (m *Module) readTables(r *reader) error {
+	n, err := r.u32()
+	if err != nil || n == 0 {
+		return err
+	}
+	if n > 1 {
+		return fmt.Errorf("only one table is supported")
+	}
+	if _, err := r.byte(); err != nil {
+		return err
+	}
+	m.tableMin, _, _, err = r.limits()
+	m.hasTable = true
+	return err
+}
+
+func (m *Module) readMemory(r *reader) error {
+	n, err := r.u32()
+	if err != nil || n == 0 {
+		return err
+	}
+	min, max, hasMax, err := r.limits()
+	if err != nil {
+		return err
+	}
+	m.memMin, m.memMax, m.hasMemory = min, maxPages, true
+	if hasMax && max < maxPages {
+		m.memMax = max
+	}
+	return nil
+}
+
+func (m *Module) readGlobals(r *reader) error {
+	var err error
+	m.globals, err = readVector(r, func() (global, error) {
+		typ, err := r.byte()
+		if err != nil {
+			return global{}, err
+		}
+		mut, err := r.byte()
+		if err != nil {
+			return global{}, err
+		}
+		init, err := r.constExpr()
+		return global{typ: typ, mutable: mut == 1, init: init}, err
+	})
+	return err
+}
+
+func (m *Module) readExports(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case 0:
+			m.Exports[name] = idx
+		case 2:
+			m.memoryExport = name
+		}
+	}
+	return nil
+}
+
+func (m *Module) readElements(r *reader) error {
+	var err error
+	m.elements, err = readVector(r, func() (segment, error) {
+		flags, err := r.u32()
+		if err != nil {
+			return segment{}, err
+		}
+		var seg segment
+		switch flags {
+		case 0:
+			if seg.offset, err = r.constExpr(); err != nil {
+				return seg, err
+			}
+		case 1:
+			seg.passive = true
+			if _, err = r.byte(); err != nil { // elemkind
+				return seg, err
+			}
+		case 2:
+			if _, err = r.u32(); err != nil { // table index
+				return seg, err
+			}
+			if seg.offset, err = r.constExpr(); err != nil {
+				return seg, err
+			}
+			if _, err = r.byte(); err != nil {
+				return seg, err
+			}
+		default:
+			return seg, fmt.Errorf("element segment kind %d is not supported", flags)
+		}
+		seg.funcs, err = readVector(r, r.u32)
+		return seg, err
+	})
+	return err
+}
+
+func (m *Module) readCode(r *reader, funcTypes []uint32) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if int(n) != len(funcTypes) {
+		return fmt.Errorf("%d function bodies for %d functions", n, len(funcTypes))
+	}
+	for i := uint32(0); i < n; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		start := r.pos
+		body, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		b := &reader{data: body}
+
+		groups, err := b.u32()
+		if err != nil {
+			return err
+		}
+		var locals []byte
+		for g := uint32(0); g < groups; g++ {
+			count, err := b.u32()
+			if err != nil {
+				return err
+			}
+			typ, err := b.byte()
+			if err != nil {
+				return err
+			}
+			if len(locals)+int(count) > 50000 {
+				return fmt.Errorf("function %d declares too many locals", i)
+			}
+			for c := uint32(0); c < count; c++ {
+				locals = append(locals, typ)
+			}
+		}
+
+		if int(funcTypes[i]) >= len(m.Types) {
+			return fmt.Errorf("function %d: type %d out of range", i, funcTypes[i])
+		}
+		fn := &function{
+			typ:    m.Types[funcTypes[i]],
+			locals: locals,
+			code:   body[b.pos:],
+			offset: start + b.pos,
+		}
+		if fn.blocks, err = scanBlocks(fn.code); err != nil {
+			return fmt.Errorf("function %d: %w", i, err)
+		}
+		m.funcs = append(m.funcs, fn)
+	}
+
+	for name, idx := range m.Exports {
+		if int(idx) >= len(m.Imports) && int(idx)-len(m.Imports) < len(m.funcs) {
+			m.funcs[int(idx)-len(m.Imports)].name = name
+		}
+	}
+	return nil
+}
+
+// readNames names the functions without an export from the name section
+func (m *Module) readNames(r *reader) {
+	for r.pos < len(r.data) {
+		id, err := r.byte()
+		if err != nil {
+			return
+		}
+		size, err := r.u32()
+		if err != nil {
+			return
+		}
+		body, err := r.bytes(int(size))
+		if err != nil || id != 1 { // Function names
+			continue
+		}
+		s := &reader{data: body}
+		n, err := s.u32()
+		for ; err == nil && n > 0; n-- {
+			var idx uint32
+			var name string
+			if idx, err = s.u32(); err != nil {
+				return
+			}
+			if name, err = s.name(); err != nil {
+				return
+			}
+			i := int(idx) - len(m.Imports)
+			if i >= 0 && i < len(m.funcs) && m.funcs[i].name == "" {
+				m.funcs[i].name = name
+			}
+		}
+	}
+}
+
+func (m *Module) readData(r *reader) error {
+	var err error
+	m.data, err = readVector(r, func() (segment, error) {
+		flags, err := r.u32()
+		if err != nil {
+			return segment{}, err
+		}
+		var seg segment
+		switch flags {
+		case 0:
+			seg.offset, err = r.constExpr()
+		case 1:
+			seg.passive = true
+		case 2:
+			if _, err = r.u32(); err == nil {
+				seg.offset, err = r.constExpr()
+			}
+		default:
+			return seg, fmt.Errorf("data segment kind %d is not supported", flags)
+		}
+		if err != nil {
+			return seg, err
+		}
+		n, err := r.u32()
+		if err != nil {
+			return seg, err
+		}
+		seg.data, err = r.bytes(int(n))
+		return seg, err
+	})
+	return err
+}
+
+// blockType returns the parameter and result counts of a block type
+func (m *Module) blockType(code []byte, pos int) (params, results int, next int, err error) {
+	if pos >= len(code) {
+		return 0, 0, pos, errEOF
+	}
+	switch b := code[pos]; {
+	case b == 0x40:
+		return 0, 0, pos + 1, nil
+	case b == I32 || b == I64 || b == F32 || b == F64:
+		return 0, 1, pos + 1, nil
+	}
+	idx, n := readSLEB(code[pos:], 33)
+	if n == 0 || idx < 0 || int(idx) >= len(m.Types) {
+		return 0, 0, pos, fmt.Errorf("invalid block type")
+	}
+	t := m.Types[idx]
+	return len(t.Params), len(t.Results), pos + n, nil
+}
+
+// scanBlocks records the else and end positions of every block, loop and if
+func scanBlocks(code []byte) (map[int]block, error) {
+	blocks := make(map[int]block)
+	var open []int
+	for pos := 0; pos < len(code); {
+		at := pos
+		op := code[pos]
+		pos++
+		switch op {
+		case opBlock, opLoop, opIf:
+			open = append(open, at)
+		case opElse:
+			if len(open) == 0 {
+				return nil, fmt.Errorf("else outside a block")
+			}
+			b := blocks[open[len(open)-1]]
+			b.elseAt = pos
+			blocks[open[len(open)-1]] = b
+		case opEnd:
+			if len(open) == 0 {
+				if pos != len(code) {
+					return nil, fmt.Errorf("code after the function end")
+				}
+				return blocks, nil
+			}
+			b := blocks[open[len(open)-1]]
+			b.endAt = at
+			blocks[open[len(open)-1]] = b
+			open = open[:len(open)-1]
+		}
+		next, err := skipImmediates(code, pos, op)
+		if err != nil {
+			return nil, err
+		}
+		pos = next
+	}
+	return nil, fmt.Errorf("function body is not terminated")
+}
+
+// skipImmediates returns the position after the immediates of op
+func skipImmediates(code []byte, pos int, op byte) (int, error) {
+	uleb := func(p int) (int, error) {
+		_, n := readULEB(code[p:], 64)
+		if n == 0 {
+			return 0, errEOF
+		}
+		return p + n, nil
+	}
+
+	switch {
+	case op == opBlock || op == opLoop || op == opIf:
+		if pos >= len(code) {
+			return 0, errEOF
+		}
+		if b := code[pos]; b == 0x40 || b == I32 || b == I64 || b == F32 || b == F64 {
+			return pos + 1, nil
+		}
+		_, n := readSLEB(code[pos:], 33)
+		if n == 0 {
+			return 0, errEOF
+		}
+		return pos + n, nil
+	case op == opBr || op == opBrIf || op == opCall ||
+		(op >= opLocalGet && op <= opGlobalSet) || op == opMemorySize || op == opMemoryGrow:
+		return uleb(pos)
+	case op == opBrTable:
+		n, size := readULEB(code[pos:], 32)
+		if size == 0 {
+			return 0, errEOF
+		}
+		pos += size
+		for i := uint64(0); i <= n; i++ {
+			var err error
+			if pos, err = uleb(pos); err != nil {
+				return 0, err
+			}
+		}
+		return pos, nil
+	case op == opCallIndirect:
+		p, err := uleb(pos)
+		if err != nil {
+			return 0, err
+		}
+		return uleb(p)
+	case op == opSelectT:
+		n, size := readULEB(code[pos:], 32)
+		if size == 0 {
+			return 0, errEOF
+		}
+		return pos + size + int(n), nil
+	case op >= 0x28 && op <= 0x3E:
+		p, err := uleb(pos)
+		if err != nil {
+			return 0, err
+		}
+		return uleb(p)
+	case op == opI32Const:
+		_, n := readSLEB(code[pos:], 32)
+		if n == 0 {
+			return 0, errEOF
+		}
+		return pos + n, nil
+	case op == opI64Const:
+		_, n := readSLEB(code[pos:], 64)
+		if n == 0 {
+			return 0, errEOF
+		}
+		return pos + n, nil
+	case op == opF32Const:
+		return pos + 4, nil
+	case op == opF64Const:
+		return pos + 8, nil
+	case op == opPrefixFC:
+		sub, n := readULEB(code[pos:], 32)
+		if n == 0 {
+			return 0, errEOF
+		}
+		pos += n
+		switch sub {
+		case 8: // memory.init
+			p, err := uleb(pos)
+			if err != nil {
+				return 0, err
+			}
+			return p + 1, nil
+		case 9: // data.drop
+			return uleb(pos)
+		case 10: // memory.copy
+			return pos + 2, nil
+		case 11: // memory.fill
+			return pos + 1, nil
+		}
+		if sub <= 7 {
+			return pos, nil
+		}
+		return 0, fmt.Errorf("unsupported instruction 0xFC %d", sub)
+	case op <= 0x01 || op == opElse || op == opEnd || op == opReturn ||
+		op == opDrop || op == opSelect || (op >= 0x45 && op <= 0xC4):
+		return pos, nil
+	}
+	return 0, fmt.Errorf("unsupported instruction 0x%02X", op)
+}
+
+// evalConst evaluates a constant expression
+func (m *Module) evalConst(expr []byte, globals []uint64) (uint64, error) {
+	if len(expr) < 2 {
+		return 0, fmt.Errorf("empty constant expression")
+	}
+	switch expr[0] {
+	case opI32Const:
+		v, _ := readSLEB(expr[1:], 32)
+		return uint64(uint32(int32(v))), nil
+	case opI64Const:
+		v, _ := readSLEB(expr[1:], 64)
+		return uint64(v), nil
+	case opF32Const:
+		return uint64(binary.LittleEndian.Uint32(expr[1:])), nil
+	case opF64Const:
+		return binary.LittleEndian.Uint64(expr[1:]), nil
+	case opGlobalGet:
+		idx, _ := readULEB(expr[1:], 32)
+		if int(idx) >= len(globals) {
+			return 0, fmt.Errorf("global %d out of range", idx)
+		}
+		return globals[idx], nil
+	}
+	return 0, fmt.Errorf("unsupported constant expression 0x%02X", expr[0])
+}
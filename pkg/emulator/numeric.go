@@ -0,0 +1,449 @@
+package emulator
+
+import (
+	"math"
+	"math/bits"
+)
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func f32(v uint64) float32  { return math.Float32frombits(uint32(v)) }
+func f64(v uint64) float64  { return math.Float64frombits(v) }
+func uf32(f float32) uint64 { return uint64(math.Float32bits(f)) }
+func uf64(f float64) uint64 { return math.Float64bits(f) }
+
+// numeric runs the comparison, arithmetic and conversion instructions,
+// 0x45 to 0xC4
+func (in *Instance) numeric(op byte) error {
+	// Unary operations and conversions
+	if isUnary(op) {
+		v := in.pop()
+		r, err := unary(op, v)
+		if err != nil {
+			return err
+		}
+		in.push(r)
+		return nil
+	}
+
+	b := in.pop()
+	a := in.pop()
+	r, err := binaryOp(op, a, b)
+	if err != nil {
+		return err
+	}
+	in.push(r)
+	return nil
+}
+
+func isUnary(op byte) bool {
+	switch {
+	case op == 0x45, op == 0x50: // eqz
+		return true
+	case op >= 0x67 && op <= 0x69, op >= 0x79 && op <= 0x7B: // clz, ctz, popcnt
+		return true
+	case op >= 0x8B && op <= 0x91, op >= 0x99 && op <= 0x9F: // abs ... sqrt
+		return true
+	case op >= 0xA7: // Conversions and sign extension
+		return true
+	}
+	return false
+}
+
+func unary(op byte, v uint64) (uint64, error) {
+	x32 := uint32(v)
+	switch op {
+	case 0x45:
+		return b2u(x32 == 0), nil
+	case 0x50:
+		return b2u(v == 0), nil
+	case 0x67:
+		return uint64(bits.LeadingZeros32(x32)), nil
+	case 0x68:
+		return uint64(bits.TrailingZeros32(x32)), nil
+	case 0x69:
+		return uint64(bits.OnesCount32(x32)), nil
+	case 0x79:
+		return uint64(bits.LeadingZeros64(v)), nil
+	case 0x7A:
+		return uint64(bits.TrailingZeros64(v)), nil
+	case 0x7B:
+		return uint64(bits.OnesCount64(v)), nil
+
+	case 0x8B:
+		return v & 0x7FFFFFFF, nil
+	case 0x8C:
+		return uint64(x32 ^ 0x80000000), nil
+	case 0x8D:
+		return uf32(float32(math.Ceil(float64(f32(v))))), nil
+	case 0x8E:
+		return uf32(float32(math.Floor(float64(f32(v))))), nil
+	case 0x8F:
+		return uf32(float32(math.Trunc(float64(f32(v))))), nil
+	case 0x90:
+		return uf32(float32(math.RoundToEven(float64(f32(v))))), nil
+	case 0x91:
+		return uf32(float32(math.Sqrt(float64(f32(v))))), nil
+
+	case 0x99:
+		return v &^ (1 << 63), nil
+	case 0x9A:
+		return v ^ (1 << 63), nil
+	case 0x9B:
+		return uf64(math.Ceil(f64(v))), nil
+	case 0x9C:
+		return uf64(math.Floor(f64(v))), nil
+	case 0x9D:
+		return uf64(math.Trunc(f64(v))), nil
+	case 0x9E:
+		return uf64(math.RoundToEven(f64(v))), nil
+	case 0x9F:
+		return uf64(math.Sqrt(f64(v))), nil
+
+	case 0xA7: // i32.wrap_i64
+		return uint64(x32), nil
+	case 0xA8, 0xA9, 0xAA, 0xAB, 0xAE, 0xAF, 0xB0, 0xB1:
+		return truncate(op, v)
+	case 0xAC: // i64.extend_i32_s
+		return uint64(int64(int32(x32))), nil
+	case 0xAD: // i64.extend_i32_u
+		return uint64(x32), nil
+	case 0xB2:
+		return uf32(float32(int32(x32))), nil
+	case 0xB3:
+		return uf32(float32(x32)), nil
+	case 0xB4:
+		return uf32(float32(int64(v))), nil
+	case 0xB5:
+		return uf32(float32(v)), nil
+	case 0xB6: // f32.demote_f64
+		return uf32(float32(f64(v))), nil
+	case 0xB7:
+		return uf64(float64(int32(x32))), nil
+	case 0xB8:
+		return uf64(float64(x32)), nil
+	case 0xB9:
+		return uf64(float64(int64(v))), nil
+	case 0xBA:
+		return uf64(float64(v)), nil
+	case 0xBB: // f64.promote_f32
+		return uf64(float64(f32(v))), nil
+	case 0xBC, 0xBE: // i32/f32 reinterpret
+		return uint64(x32), nil
+	case 0xBD, 0xBF: // i64/f64 reinterpret
+		return v, nil
+	case 0xC0:
+		return uint64(uint32(int32(int8(v)))), nil
+	case 0xC1:
+		return uint64(uint32(int32(int16(v)))), nil
+	case 0xC2:
+		return uint64(int64(int8(v))), nil
+	case 0xC3:
+		return uint64(int64(int16(v))), nil
+	case 0xC4:
+		return uint64(int64(int32(v))), nil
+	}
+	return 0, trap("unsupported instruction 0x%02X", op)
+}
+
+func binaryOp(op byte, a, b uint64) (uint64, error) {
+	a32, b32 := uint32(a), uint32(b)
+	switch op {
+	// i32 comparisons
+	case 0x46:
+		return b2u(a32 == b32), nil
+	case 0x47:
+		return b2u(a32 != b32), nil
+	case 0x48:
+		return b2u(int32(a32) < int32(b32)), nil
+	case 0x49:
+		return b2u(a32 < b32), nil
+	case 0x4A:
+		return b2u(int32(a32) > int32(b32)), nil
+	case 0x4B:
+		return b2u(a32 > b32), nil
+	case 0x4C:
+		return b2u(int32(a32) <= int32(b32)), nil
+	case 0x4D:
+		return b2u(a32 <= b32), nil
+	case 0x4E:
+		return b2u(int32(a32) >= int32(b32)), nil
+	case 0x4F:
+		return b2u(a32 >= b32), nil
+
+	// i64 comparisons
+	case 0x51:
+		return b2u(a == b), nil
+	case 0x52:
+		return b2u(a != b), nil
+	case 0x53:
+		return b2u(int64(a) < int64(b)), nil
+	case 0x54:
+		return b2u(a < b), nil
+	case 0x55:
+		return b2u(int64(a) > int64(b)), nil
+	case 0x56:
+		return b2u(a > b), nil
+	case 0x57:
+		return b2u(int64(a) <= int64(b)), nil
+	case 0x58:
+		return b2u(a <= b), nil
+	case 0x59:
+		return b2u(int64(a) >= int64(b)), nil
+	case 0x5A:
+		return b2u(a >= b), nil
+
+	// f32 comparisons
+	case 0x5B:
+		return b2u(f32(a) == f32(b)), nil
+	case 0x5C:
+		return b2u(f32(a) != f32(b)), nil
+	case 0x5D:
+		return b2u(f32(a) < f32(b)), nil
+	case 0x5E:
+		return b2u(f32(a) > f32(b)), nil
+	case 0x5F:
+		return b2u(f32(a) <= f32(b)), nil
+	case 0x60:
+		return b2u(f32(a) >= f32(b)), nil
+
+	// f64 comparisons
+	case 0x61:
+		return b2u(f64(a) == f64(b)), nil
+	case 0x62:
+		return b2u(f64(a) != f64(b)), nil
+	case 0x63:
+		return b2u(f64(a) < f64(b)), nil
+	case 0x64:
+		return b2u(f64(a) > f64(b)), nil
+	case 0x65:
+		return b2u(f64(a) <= f64(b)), nil
+	case 0x66:
+		return b2u(f64(a) >= f64(b)), nil
+
+	// i32 arithmetic
+	case 0x6A:
+		return uint64(a32 + b32), nil
+	case 0x6B:
+		return uint64(a32 - b32), nil
+	case 0x6C:
+		return uint64(a32 * b32), nil
+	case 0x6D:
+		if b32 == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		if int32(a32) == math.MinInt32 && int32(b32) == -1 {
+			return 0, trap("integer overflow")
+		}
+		return uint64(uint32(int32(a32) / int32(b32))), nil
+	case 0x6E:
+		if b32 == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		return uint64(a32 / b32), nil
+	case 0x6F:
+		if b32 == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		if int32(b32) == -1 {
+			return 0, nil
+		}
+		return uint64(uint32(int32(a32) % int32(b32))), nil
+	case 0x70:
+		if b32 == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		return uint64(a32 % b32), nil
+	case 0x71:
+		return uint64(a32 & b32), nil
+	case 0x72:
+		return uint64(a32 | b32), nil
+	case 0x73:
+		return uint64(a32 ^ b32), nil
+	case 0x74:
+		return uint64(a32 << (b32 & 31)), nil
+	case 0x75:
+		return uint64(uint32(int32(a32) >> (b32 & 31))), nil
+	case 0x76:
+		return uint64(a32 >> (b32 & 31)), nil
+	case 0x77:
+		return uint64(bits.RotateLeft32(a32, int(b32&31))), nil
+	case 0x78:
+		return uint64(bits.RotateLeft32(a32, -int(b32&31))), nil
+
+	// i64 arithmetic
+	case 0x7C:
+		return a + b, nil
+	case 0x7D:
+		return a - b, nil
+	case 0x7E:
+		return a * b, nil
+	case 0x7F:
+		if b == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			return 0, trap("integer overflow")
+		}
+		return uint64(int64(a) / int64(b)), nil
+	case 0x80:
+		if b == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		return a / b, nil
+	case 0x81:
+		if b == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		if int64(b) == -1 {
+			return 0, nil
+		}
+		return uint64(int64(a) % int64(b)), nil
+	case 0x82:
+		if b == 0 {
+			return 0, trap("integer divide by zero")
+		}
+		return a % b, nil
+	case 0x83:
+		return a & b, nil
+	case 0x84:
+		return a | b, nil
+	case 0x85:
+		return a ^ b, nil
+	case 0x86:
+		return a << (b & 63), nil
+	case 0x87:
+		return uint64(int64(a) >> (b & 63)), nil
+	case 0x88:
+		return a >> (b & 63), nil
+	case 0x89:
+		return bits.RotateLeft64(a, int(b&63)), nil
+	case 0x8A:
+		return bits.RotateLeft64(a, -int(b&63)), nil
+
+	// f32 arithmetic
+	case 0x92:
+		return uf32(f32(a) + f32(b)), nil
+	case 0x93:
+		return uf32(f32(a) - f32(b)), nil
+	case 0x94:
+		return uf32(f32(a) * f32(b)), nil
+	case 0x95:
+		return uf32(f32(a) / f32(b)), nil
+	case 0x96:
+		return uf32(float32(math.Min(float64(f32(a)), float64(f32(b))))), nil
+	case 0x97:
+		return uf32(float32(math.Max(float64(f32(a)), float64(f32(b))))), nil
+	case 0x98:
+		return uint64(a32&0x7FFFFFFF | b32&0x80000000), nil
+
+	// f64 arithmetic
+	case 0xA0:
+		return uf64(f64(a) + f64(b)), nil
+	case 0xA1:
+		return uf64(f64(a) - f64(b)), nil
+	case 0xA2:
+		return uf64(f64(a) * f64(b)), nil
+	case 0xA3:
+		return uf64(f64(a) / f64(b)), nil
+	case 0xA4:
+		return uf64(math.Min(f64(a), f64(b))), nil
+	case 0xA5:
+		return uf64(math.Max(f64(a), f64(b))), nil
+	case 0xA6:
+		return a&^(1<<63) | b&(1<<63), nil
+	}
+	return 0, trap("unsupported instruction 0x%02X", op)
+}
+
+// truncate converts a float to an integer, trapping when it does not fit
+func truncate(op byte, v uint64) (uint64, error) {
+	var x float64
+	switch op {
+	case 0xA8, 0xA9, 0xAE, 0xAF:
+		x = float64(f32(v))
+	default:
+		x = f64(v)
+	}
+	if math.IsNaN(x) {
+		return 0, trap("invalid conversion to integer")
+	}
+	t := math.Trunc(x)
+
+	switch op {
+	case 0xA8, 0xAA: // i32 signed
+		if t < -2147483648 || t >= 2147483648 {
+			return 0, trap("integer overflow")
+		}
+		return uint64(uint32(int32(t))), nil
+	case 0xA9, 0xAB: // i32 unsigned
+		if t <= -1 || t >= 4294967296 {
+			return 0, trap("integer overflow")
+		}
+		return uint64(uint32(t)), nil
+	case 0xAE, 0xB0: // i64 signed
+		if t < -9223372036854775808 || t >= 9223372036854775808 {
+			return 0, trap("integer overflow")
+		}
+		return uint64(int64(t)), nil
+	default: // i64 unsigned
+		if t <= -1 || t >= 18446744073709551616 {
+			return 0, trap("integer overflow")
+		}
+		return uint64(t), nil
+	}
+}
+
+// truncSat converts a float to an integer, saturating at the bounds
+func truncSat(sub uint32, v uint64) uint64 {
+	var x float64
+	if sub == 0 || sub == 1 || sub == 4 || sub == 5 {
+		x = float64(f32(v))
+	} else {
+		x = f64(v)
+	}
+	if math.IsNaN(x) {
+		return 0
+	}
+	t := math.Trunc(x)
+
+	switch sub {
+	case 0, 2: // i32 signed
+		switch {
+		case t < math.MinInt32:
+			return uint64(uint32(math.MaxInt32 + 1))
+		case t > math.MaxInt32:
+			return math.MaxInt32
+		}
+		return uint64(uint32(int32(t)))
+	case 1, 3: // i32 unsigned
+		switch {
+		case t < 0:
+			return 0
+		case t > math.MaxUint32:
+			return math.MaxUint32
+		}
+		return uint64(uint32(t))
+	case 4, 6: // i64 signed
+		switch {
+		case t < math.MinInt64:
+			return 1 << 63
+		case t >= 9223372036854775808:
+			return math.MaxInt64
+		}
+		return uint64(int64(t))
+	default: // i64 unsigned
+		switch {
+		case t < 0:
+			return 0
+		case t >= 18446744073709551616:
+			return math.MaxUint64
+		}
+		return uint64(t)
+	}
+}
@@ -0,0 +1,287 @@
+package emulator
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func i32v(v int32) uint64   { return uint64(uint32(v)) }
+func f32v(v float32) uint64 { return uint64(math.Float32bits(v)) }
+func f64v(v float64) uint64 { return math.Float64bits(v) }
+
+var (
+	nan32 = f32v(float32(math.NaN()))
+	nan64 = f64v(math.NaN())
+	neg0  = math.Copysign(0, -1)
+)
+
+// sameValue compares results bit for bit, except that any NaN matches a
+// NaN; the spec leaves the payload open
+func sameValue(typ byte, got, want uint64) bool {
+	switch typ {
+	case F32:
+		if math.IsNaN(float64(f32(want))) {
+			return math.IsNaN(float64(f32(got)))
+		}
+	case F64:
+		if math.IsNaN(f64(want)) {
+			return math.IsNaN(f64(got))
+		}
+	}
+	return got == want
+}
+
+// expectTrap checks err is a trap whose reason contains want
+func expectTrap(t *testing.T, err error, want string) {
+	t.Helper()
+	var tr *Trap
+	if !errors.As(err, &tr) {
+		t.Fatalf("error = %v, want trap %q", err, want)
+	}
+	if !strings.Contains(tr.Reason, want) {
+		t.Errorf("trap = %q, want %q", tr.Reason, want)
+	}
+}
+
+type numericCase struct {
+	name     string
+	code     []byte
+	in, out  byte
+	args     []uint64
+	want     uint64
+	wantTrap string
+}
+
+// runNumeric runs each case's instruction over its arguments in a function
+// of its own
+func runNumeric(t *testing.T, cases []numericCase) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			params := make([]byte, len(tt.args))
+			var body []byte
+			for i := range tt.args {
+				params[i] = tt.in
+				body = append(body, localGet(uint32(i))...)
+			}
+			in := instantiate(t, exported(params, []byte{tt.out}, body, tt.code))
+
+			got, err := in.Call("f", tt.args...)
+			if tt.wantTrap != "" {
+				expectTrap(t, err, tt.wantTrap)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sameValue(tt.out, got[0], tt.want) {
+				t.Errorf("got %#x, want %#x", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegerArithmetic(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"i32.add wraps", []byte{0x6A}, I32, I32, []uint64{0x7FFFFFFF, 1}, 0x80000000, ""},
+		{"i32.sub wraps", []byte{0x6B}, I32, I32, []uint64{0, 1}, 0xFFFFFFFF, ""},
+		{"i32.mul wraps", []byte{0x6C}, I32, I32, []uint64{0x10000, 0x10000}, 0, ""},
+		{"i32.div_s truncates", []byte{0x6D}, I32, I32, []uint64{i32v(-7), 2}, i32v(-3), ""},
+		{"i32.div_s by zero", []byte{0x6D}, I32, I32, []uint64{1, 0}, 0, "integer divide by zero"},
+		{"i32.div_s overflow", []byte{0x6D}, I32, I32, []uint64{0x80000000, i32v(-1)}, 0, "integer overflow"},
+		{"i32.div_u", []byte{0x6E}, I32, I32, []uint64{0xFFFFFFFE, 2}, 0x7FFFFFFF, ""},
+		{"i32.div_u by zero", []byte{0x6E}, I32, I32, []uint64{1, 0}, 0, "integer divide by zero"},
+		{"i32.rem_s sign of dividend", []byte{0x6F}, I32, I32, []uint64{i32v(-7), 2}, i32v(-1), ""},
+		{"i32.rem_s min by -1", []byte{0x6F}, I32, I32, []uint64{0x80000000, i32v(-1)}, 0, ""},
+		{"i32.rem_s by zero", []byte{0x6F}, I32, I32, []uint64{1, 0}, 0, "integer divide by zero"},
+		{"i32.rem_u", []byte{0x70}, I32, I32, []uint64{0xFFFFFFFF, 10}, 5, ""},
+		{"i32.and", []byte{0x71}, I32, I32, []uint64{0xF0F0, 0xFF00}, 0xF000, ""},
+		{"i32.or", []byte{0x72}, I32, I32, []uint64{0xF0F0, 0xFF00}, 0xFFF0, ""},
+		{"i32.xor", []byte{0x73}, I32, I32, []uint64{0xF0F0, 0xFF00}, 0x0FF0, ""},
+		{"i32.shl masks count", []byte{0x74}, I32, I32, []uint64{1, 33}, 2, ""},
+		{"i32.shr_s", []byte{0x75}, I32, I32, []uint64{i32v(-8), 1}, i32v(-4), ""},
+		{"i32.shr_u", []byte{0x76}, I32, I32, []uint64{0x80000000, 31}, 1, ""},
+		{"i32.rotl", []byte{0x77}, I32, I32, []uint64{0x80000001, 1}, 3, ""},
+		{"i32.rotr", []byte{0x78}, I32, I32, []uint64{1, 33}, 0x80000000, ""},
+
+		{"i64.add wraps", []byte{0x7C}, I64, I64, []uint64{math.MaxUint64, 1}, 0, ""},
+		{"i64.mul", []byte{0x7E}, I64, I64, []uint64{1 << 32, 1 << 31}, 1 << 63, ""},
+		{"i64.div_s", []byte{0x7F}, I64, I64, []uint64{uint64(1<<64 - 9), 4}, uint64(1<<64 - 2), ""},
+		{"i64.div_s overflow", []byte{0x7F}, I64, I64, []uint64{1 << 63, math.MaxUint64}, 0, "integer overflow"},
+		{"i64.div_u by zero", []byte{0x80}, I64, I64, []uint64{1, 0}, 0, "integer divide by zero"},
+		{"i64.rem_s min by -1", []byte{0x81}, I64, I64, []uint64{1 << 63, math.MaxUint64}, 0, ""},
+		{"i64.rem_u", []byte{0x82}, I64, I64, []uint64{math.MaxUint64, 10}, 5, ""},
+		{"i64.shl masks count", []byte{0x86}, I64, I64, []uint64{1, 65}, 2, ""},
+		{"i64.shr_s", []byte{0x87}, I64, I64, []uint64{1 << 63, 63}, math.MaxUint64, ""},
+		{"i64.shr_u", []byte{0x88}, I64, I64, []uint64{1 << 63, 63}, 1, ""},
+		{"i64.rotl", []byte{0x89}, I64, I64, []uint64{1<<63 | 1, 1}, 3, ""},
+		{"i64.rotr", []byte{0x8A}, I64, I64, []uint64{1, 1}, 1 << 63, ""},
+	})
+}
+
+func TestIntegerComparisons(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"i32.eq", []byte{0x46}, I32, I32, []uint64{5, 5}, 1, ""},
+		{"i32.ne", []byte{0x47}, I32, I32, []uint64{5, 5}, 0, ""},
+		{"i32.lt_s", []byte{0x48}, I32, I32, []uint64{i32v(-1), 0}, 1, ""},
+		{"i32.lt_u", []byte{0x49}, I32, I32, []uint64{i32v(-1), 0}, 0, ""},
+		{"i32.gt_s", []byte{0x4A}, I32, I32, []uint64{0, i32v(-1)}, 1, ""},
+		{"i32.gt_u", []byte{0x4B}, I32, I32, []uint64{0, i32v(-1)}, 0, ""},
+		{"i32.le_s", []byte{0x4C}, I32, I32, []uint64{i32v(-2), i32v(-2)}, 1, ""},
+		{"i32.ge_u", []byte{0x4F}, I32, I32, []uint64{1, 2}, 0, ""},
+		{"i64.eq", []byte{0x51}, I64, I32, []uint64{1 << 40, 1 << 40}, 1, ""},
+		{"i64.lt_s", []byte{0x53}, I64, I32, []uint64{math.MaxUint64, 0}, 1, ""},
+		{"i64.lt_u", []byte{0x54}, I64, I32, []uint64{math.MaxUint64, 0}, 0, ""},
+		{"i64.ge_s", []byte{0x59}, I64, I32, []uint64{0, math.MaxUint64}, 1, ""},
+	})
+}
+
+func TestIntegerUnary(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"i32.eqz", []byte{0x45}, I32, I32, []uint64{0}, 1, ""},
+		{"i32.clz", []byte{0x67}, I32, I32, []uint64{1}, 31, ""},
+		{"i32.clz of zero", []byte{0x67}, I32, I32, []uint64{0}, 32, ""},
+		{"i32.ctz", []byte{0x68}, I32, I32, []uint64{0x80000000}, 31, ""},
+		{"i32.popcnt", []byte{0x69}, I32, I32, []uint64{0xFFFFFFFF}, 32, ""},
+		{"i64.eqz", []byte{0x50}, I64, I32, []uint64{1 << 40}, 0, ""},
+		{"i64.clz of zero", []byte{0x79}, I64, I64, []uint64{0}, 64, ""},
+		{"i64.ctz", []byte{0x7A}, I64, I64, []uint64{1 << 40}, 40, ""},
+		{"i64.popcnt", []byte{0x7B}, I64, I64, []uint64{math.MaxUint64}, 64, ""},
+		{"i32.extend8_s", []byte{0xC0}, I32, I32, []uint64{0x80}, 0xFFFFFF80, ""},
+		{"i32.extend16_s", []byte{0xC1}, I32, I32, []uint64{0x17FFF}, 0x7FFF, ""},
+		{"i64.extend8_s", []byte{0xC2}, I64, I64, []uint64{0xFF}, math.MaxUint64, ""},
+		{"i64.extend16_s", []byte{0xC3}, I64, I64, []uint64{0x8000}, 0xFFFFFFFFFFFF8000, ""},
+		{"i64.extend32_s", []byte{0xC4}, I64, I64, []uint64{0x80000000}, 0xFFFFFFFF80000000, ""},
+	})
+}
+
+func TestFloatArithmetic(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"f32.add", []byte{0x92}, F32, F32, []uint64{f32v(1.5), f32v(2.25)}, f32v(3.75), ""},
+		{"f32.add rounds to single precision", []byte{0x92}, F32, F32, []uint64{f32v(16777216), f32v(1)}, f32v(16777216), ""},
+		{"f32.div by zero", []byte{0x95}, F32, F32, []uint64{f32v(1), f32v(0)}, f32v(float32(math.Inf(1))), ""},
+		{"f32.min of zeros", []byte{0x96}, F32, F32, []uint64{f32v(0), f32v(float32(neg0))}, f32v(float32(neg0)), ""},
+		{"f32.min with NaN", []byte{0x96}, F32, F32, []uint64{f32v(1), nan32}, nan32, ""},
+		{"f32.max of zeros", []byte{0x97}, F32, F32, []uint64{f32v(float32(neg0)), f32v(0)}, f32v(0), ""},
+		{"f32.copysign", []byte{0x98}, F32, F32, []uint64{f32v(1.5), f32v(float32(neg0))}, f32v(-1.5), ""},
+		{"f64.sub", []byte{0xA1}, F64, F64, []uint64{f64v(0.5), f64v(2)}, f64v(-1.5), ""},
+		{"f64.mul", []byte{0xA2}, F64, F64, []uint64{f64v(1e200), f64v(1e200)}, f64v(math.Inf(1)), ""},
+		{"f64.div zero by zero", []byte{0xA3}, F64, F64, []uint64{f64v(0), f64v(0)}, nan64, ""},
+		{"f64.min", []byte{0xA4}, F64, F64, []uint64{f64v(-2), f64v(3)}, f64v(-2), ""},
+		{"f64.max with NaN", []byte{0xA5}, F64, F64, []uint64{nan64, f64v(3)}, nan64, ""},
+		{"f64.copysign", []byte{0xA6}, F64, F64, []uint64{f64v(-4), f64v(1)}, f64v(4), ""},
+	})
+}
+
+func TestFloatComparisons(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"f32.eq", []byte{0x5B}, F32, I32, []uint64{f32v(0), f32v(float32(neg0))}, 1, ""},
+		{"f32.ne NaN", []byte{0x5C}, F32, I32, []uint64{nan32, nan32}, 1, ""},
+		{"f32.lt NaN", []byte{0x5D}, F32, I32, []uint64{nan32, f32v(1)}, 0, ""},
+		{"f32.ge", []byte{0x60}, F32, I32, []uint64{f32v(2), f32v(2)}, 1, ""},
+		{"f64.eq NaN", []byte{0x61}, F64, I32, []uint64{nan64, nan64}, 0, ""},
+		{"f64.gt", []byte{0x64}, F64, I32, []uint64{f64v(2), f64v(-2)}, 1, ""},
+		{"f64.le NaN", []byte{0x65}, F64, I32, []uint64{f64v(1), nan64}, 0, ""},
+	})
+}
+
+func TestFloatUnary(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"f32.abs", []byte{0x8B}, F32, F32, []uint64{f32v(-2)}, f32v(2), ""},
+		{"f32.neg of zero", []byte{0x8C}, F32, F32, []uint64{f32v(0)}, f32v(float32(neg0)), ""},
+		{"f32.ceil", []byte{0x8D}, F32, F32, []uint64{f32v(-1.5)}, f32v(-1), ""},
+		{"f32.floor", []byte{0x8E}, F32, F32, []uint64{f32v(-1.5)}, f32v(-2), ""},
+		{"f32.trunc", []byte{0x8F}, F32, F32, []uint64{f32v(-1.5)}, f32v(-1), ""},
+		{"f32.nearest ties to even", []byte{0x90}, F32, F32, []uint64{f32v(2.5)}, f32v(2), ""},
+		{"f32.nearest negative", []byte{0x90}, F32, F32, []uint64{f32v(-3.5)}, f32v(-4), ""},
+		{"f32.sqrt", []byte{0x91}, F32, F32, []uint64{f32v(4)}, f32v(2), ""},
+		{"f64.abs of negative zero", []byte{0x99}, F64, F64, []uint64{f64v(neg0)}, f64v(0), ""},
+		{"f64.neg", []byte{0x9A}, F64, F64, []uint64{f64v(3)}, f64v(-3), ""},
+		{"f64.nearest", []byte{0x9E}, F64, F64, []uint64{f64v(0.5)}, f64v(0), ""},
+		{"f64.sqrt of negative", []byte{0x9F}, F64, F64, []uint64{f64v(-1)}, nan64, ""},
+	})
+}
+
+func TestConversions(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"i32.wrap_i64", []byte{0xA7}, I64, I32, []uint64{0x100000005}, 5, ""},
+		{"i64.extend_i32_s", []byte{0xAC}, I32, I64, []uint64{i32v(-1)}, math.MaxUint64, ""},
+		{"i64.extend_i32_u", []byte{0xAD}, I32, I64, []uint64{i32v(-1)}, 0xFFFFFFFF, ""},
+		{"f32.convert_i32_s", []byte{0xB2}, I32, F32, []uint64{i32v(-3)}, f32v(-3), ""},
+		{"f32.convert_i32_u", []byte{0xB3}, I32, F32, []uint64{0xFFFFFFFF}, f32v(4294967296), ""},
+		{"f32.convert_i64_u", []byte{0xB5}, I64, F32, []uint64{math.MaxUint64}, f32v(18446744073709551616), ""},
+		{"f32.demote_f64", []byte{0xB6}, F64, F32, []uint64{f64v(0.1)}, f32v(0.1), ""},
+		{"f64.convert_i32_s", []byte{0xB7}, I32, F64, []uint64{i32v(-1)}, f64v(-1), ""},
+		{"f64.convert_i64_s", []byte{0xB9}, I64, F64, []uint64{1 << 63}, f64v(-9223372036854775808), ""},
+		{"f64.promote_f32", []byte{0xBB}, F32, F64, []uint64{f32v(1.5)}, f64v(1.5), ""},
+		{"i32.reinterpret_f32", []byte{0xBC}, F32, I32, []uint64{f32v(-0.0)}, 0, ""},
+		{"f64.reinterpret_i64", []byte{0xBF}, I64, F64, []uint64{1 << 63}, f64v(neg0), ""},
+	})
+}
+
+func TestTruncation(t *testing.T) {
+	runNumeric(t, []numericCase{
+		{"i32.trunc_f32_s", []byte{0xA8}, F32, I32, []uint64{f32v(-1.9)}, i32v(-1), ""},
+		{"i32.trunc_f32_s min", []byte{0xA8}, F32, I32, []uint64{f32v(-2147483648)}, 0x80000000, ""},
+		{"i32.trunc_f32_s overflow", []byte{0xA8}, F32, I32, []uint64{f32v(2147483648)}, 0, "integer overflow"},
+		{"i32.trunc_f32_s NaN", []byte{0xA8}, F32, I32, []uint64{nan32}, 0, "invalid conversion to integer"},
+		{"i32.trunc_f32_u", []byte{0xA9}, F32, I32, []uint64{f32v(3e9)}, 3000000000, ""},
+		{"i32.trunc_f64_s below min", []byte{0xAA}, F64, I32, []uint64{f64v(-2147483649)}, 0, "integer overflow"},
+		{"i32.trunc_f64_s just above min", []byte{0xAA}, F64, I32, []uint64{f64v(-2147483648.9)}, 0x80000000, ""},
+		{"i32.trunc_f64_u small negative", []byte{0xAB}, F64, I32, []uint64{f64v(-0.9)}, 0, ""},
+		{"i32.trunc_f64_u negative", []byte{0xAB}, F64, I32, []uint64{f64v(-1)}, 0, "integer overflow"},
+		{"i32.trunc_f64_u max", []byte{0xAB}, F64, I32, []uint64{f64v(4294967295.9)}, 0xFFFFFFFF, ""},
+		{"i64.trunc_f32_s", []byte{0xAE}, F32, I64, []uint64{f32v(-5.5)}, uint64(1<<64 - 5), ""},
+		{"i64.trunc_f64_s overflow", []byte{0xB0}, F64, I64, []uint64{f64v(9223372036854775808)}, 0, "integer overflow"},
+		{"i64.trunc_f64_u overflow", []byte{0xB1}, F64, I64, []uint64{f64v(18446744073709551616)}, 0, "integer overflow"},
+		{"i64.trunc_f64_u NaN", []byte{0xB1}, F64, I64, []uint64{nan64}, 0, "invalid conversion to integer"},
+	})
+}
+
+func TestSaturatingTruncation(t *testing.T) {
+	sat := func(sub byte) []byte { return []byte{opPrefixFC, sub} }
+	runNumeric(t, []numericCase{
+		{"i32.trunc_sat_f32_s NaN", sat(0), F32, I32, []uint64{nan32}, 0, ""},
+		{"i32.trunc_sat_f32_s high", sat(0), F32, I32, []uint64{f32v(3e9)}, 0x7FFFFFFF, ""},
+		{"i32.trunc_sat_f32_s low", sat(0), F32, I32, []uint64{f32v(-3e9)}, 0x80000000, ""},
+		{"i32.trunc_sat_f32_u negative", sat(1), F32, I32, []uint64{f32v(-5)}, 0, ""},
+		{"i32.trunc_sat_f32_u high", sat(1), F32, I32, []uint64{f32v(5e9)}, 0xFFFFFFFF, ""},
+		{"i32.trunc_sat_f64_s", sat(2), F64, I32, []uint64{f64v(-7.9)}, i32v(-7), ""},
+		{"i32.trunc_sat_f64_u infinity", sat(3), F64, I32, []uint64{f64v(math.Inf(1))}, 0xFFFFFFFF, ""},
+		{"i64.trunc_sat_f32_s low", sat(4), F32, I64, []uint64{f32v(float32(math.Inf(-1)))}, 1 << 63, ""},
+		{"i64.trunc_sat_f32_u", sat(5), F32, I64, []uint64{f32v(65536.5)}, 65536, ""},
+		{"i64.trunc_sat_f64_s high", sat(6), F64, I64, []uint64{f64v(1e19)}, math.MaxInt64, ""},
+		{"i64.trunc_sat_f64_s low", sat(6), F64, I64, []uint64{f64v(-1e19)}, 1 << 63, ""},
+		{"i64.trunc_sat_f64_u high", sat(7), F64, I64, []uint64{f64v(1e20)}, math.MaxUint64, ""},
+		{"i64.trunc_sat_f64_u negative", sat(7), F64, I64, []uint64{f64v(-1)}, 0, ""},
+	})
+}
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		name string
+		out  byte
+		code []byte
+		want uint64
+	}{
+		{"i32.const negative", I32, i32c(-123456), i32v(-123456)},
+		{"i32.const min", I32, i32c(math.MinInt32), 0x80000000},
+		{"i64.const min", I64, i64c(math.MinInt64), 1 << 63},
+		{"i64.const large", I64, i64c(0x123456789ABCDEF), 0x123456789ABCDEF},
+		{"f32.const", F32, f32c(-0.25), f32v(-0.25)},
+		{"f64.const", F64, f64c(math.Pi), f64v(math.Pi)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := instantiate(t, exported(nil, []byte{tt.out}, tt.code)).Call("f")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got[0] != tt.want {
+				t.Errorf("got %#x, want %#x", got[0], tt.want)
+			}
+		})
+	}
+}
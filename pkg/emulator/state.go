@@ -0,0 +1,156 @@
+package emulator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"unicode/utf8"
+)
+
+// Default ledger position of a new state
+const (
+	DefaultLedgerIndex = 1000
+	DefaultCloseTime   = 800000000 // Seconds since the XRPL epoch
+)
+
+// State is the in-memory ledger an emulated contract reads and writes. It
+// can be saved between runs to chain calls together.
+type State struct {
+	Contract    string `json:"contract"`
+	LedgerIndex uint32 `json:"ledger_index"`
+	CloseTime   uint32 `json:"close_time"`
+
+	// Data holds contract storage by owner (the contract for global state,
+	// or a user) and key. Keys and values are hex.
+	Data map[string]map[string]string `json:"data"`
+
+	Events []Event `json:"events,omitempty"`
+}
+
+// Event is an event emitted by a contract
+type Event struct {
+	Name        string `json:"name"`
+	Data        string `json:"data"` // Hex
+	LedgerIndex uint32 `json:"ledger_index"`
+	Function    string `json:"function"`
+}
+
+// StateChange is a storage value written by a call
+type StateChange struct {
+	Owner string
+	Key   string // Hex
+	Old   string // Hex; empty when the key was new
+	New   string
+}
+
+// NewState returns an empty state for a contract account
+func NewState(contract string) *State {
+	return &State{
+		Contract:    contract,
+		LedgerIndex: DefaultLedgerIndex,
+		CloseTime:   DefaultCloseTime,
+		Data:        make(map[string]map[string]string),
+	}
+}
+
+// LoadState reads a state file
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	if s.Data == nil {
+		s.Data = make(map[string]map[string]string)
+	}
+	if s.LedgerIndex == 0 {
+		s.LedgerIndex = DefaultLedgerIndex
+	}
+	if s.CloseTime == 0 {
+		s.CloseTime = DefaultCloseTime
+	}
+	return &s, nil
+}
+
+// Save writes the state to a file
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// Get returns a stored value
+func (s *State) Get(owner string, key []byte) ([]byte, bool) {
+	v, ok := s.Data[owner][hex.EncodeToString(key)]
+	if !ok {
+		return nil, false
+	}
+	b, err := hex.DecodeString(v)
+	return b, err == nil
+}
+
+// Set stores a value
+func (s *State) Set(owner string, key, value []byte) {
+	if s.Data[owner] == nil {
+		s.Data[owner] = make(map[string]string)
+	}
+	s.Data[owner][hex.EncodeToString(key)] = hex.EncodeToString(value)
+}
+
+// clone copies the state so a failed call can be discarded
+func (s *State) clone() *State {
+	c := *s
+	c.Data = make(map[string]map[string]string, len(s.Data))
+	for owner, values := range s.Data {
+		c.Data[owner] = make(map[string]string, len(values))
+		for k, v := range values {
+			c.Data[owner][k] = v
+		}
+	}
+	c.Events = append([]Event(nil), s.Events...)
+	return &c
+}
+
+// diff lists the values that differ from a previous state, sorted by owner
+// and key
+func (s *State) diff(prev *State) []StateChange {
+	var changes []StateChange
+	for owner, values := range s.Data {
+		for k, v := range values {
+			if old, ok := prev.Data[owner][k]; !ok || old != v {
+				changes = append(changes, StateChange{Owner: owner, Key: k, Old: old, New: v})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Owner != changes[j].Owner {
+			return changes[i].Owner < changes[j].Owner
+		}
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// KeyText renders a hex key as text when it is printable
+func KeyText(key string) string {
+	b, err := hex.DecodeString(key)
+	if err != nil || len(b) == 0 || !utf8.Valid(b) {
+		return key
+	}
+	for _, r := range string(b) {
+		if r < 0x20 || r == 0x7F {
+			return key
+		}
+	}
+	return string(b)
+}
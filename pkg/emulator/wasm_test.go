@@ -0,0 +1,168 @@
+package emulator
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// The tests assemble their modules from these pieces rather than checking in
+// binaries, so each case shows the instructions it runs.
+
+type testImport struct {
+	module, name    string
+	params, results []byte
+}
+
+type testFunc struct {
+	params, results []byte
+	locals          []byte // One entry per local
+	body            []byte // Without the final end
+	export          string
+}
+
+type testModule struct {
+	imports []testImport
+	funcs   []testFunc
+	memory  []uint32 // min, and optionally max pages; nil for no memory
+	table   []uint32 // Function indexes placed at table offset 0
+	data    []byte   // Placed at memory offset 0
+	start   int      // Function index plus one; 0 for no start function
+}
+
+func uleb(v uint64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7F)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func sleb(v int64) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7F)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func cat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func vec(n int, items ...[]byte) []byte {
+	return cat(uleb(uint64(n)), cat(items...))
+}
+
+func name(s string) []byte {
+	return cat(uleb(uint64(len(s))), []byte(s))
+}
+
+func section(id byte, body []byte) []byte {
+	return cat([]byte{id}, uleb(uint64(len(body))), body)
+}
+
+// Instruction helpers
+func i32c(v int32) []byte      { return cat([]byte{opI32Const}, sleb(int64(v))) }
+func i64c(v int64) []byte      { return cat([]byte{opI64Const}, sleb(v)) }
+func localGet(i uint32) []byte { return cat([]byte{opLocalGet}, uleb(uint64(i))) }
+func localSet(i uint32) []byte { return cat([]byte{opLocalSet}, uleb(uint64(i))) }
+func callOp(i uint32) []byte   { return cat([]byte{opCall}, uleb(uint64(i))) }
+
+func f32c(v float32) []byte {
+	return binary.LittleEndian.AppendUint32([]byte{opF32Const}, math.Float32bits(v))
+}
+
+func f64c(v float64) []byte {
+	return binary.LittleEndian.AppendUint64([]byte{opF64Const}, math.Float64bits(v))
+}
+
+func (m testModule) encode() []byte {
+	var types, imports, funcs, exports, code [][]byte
+	funcType := func(params, results []byte) []byte {
+		types = append(types, cat([]byte{0x60}, vec(len(params), params), vec(len(results), results)))
+		return uleb(uint64(len(types) - 1))
+	}
+
+	for _, imp := range m.imports {
+		imports = append(imports, cat(name(imp.module), name(imp.name), []byte{0}, funcType(imp.params, imp.results)))
+	}
+	for i, f := range m.funcs {
+		funcs = append(funcs, funcType(f.params, f.results))
+		if f.export != "" {
+			exports = append(exports, cat(name(f.export), []byte{0}, uleb(uint64(len(m.imports)+i))))
+		}
+		var locals [][]byte
+		for _, t := range f.locals {
+			locals = append(locals, []byte{1, t})
+		}
+		body := cat(vec(len(locals), locals...), f.body, []byte{opEnd})
+		code = append(code, cat(uleb(uint64(len(body))), body))
+	}
+
+	out := []byte("\x00asm\x01\x00\x00\x00")
+	out = append(out, section(1, vec(len(types), types...))...)
+	if len(imports) > 0 {
+		out = append(out, section(2, vec(len(imports), imports...))...)
+	}
+	out = append(out, section(3, vec(len(funcs), funcs...))...)
+	if m.table != nil {
+		out = append(out, section(4, vec(1, []byte{0x70, 0}, uleb(uint64(len(m.table)))))...)
+	}
+	switch len(m.memory) {
+	case 1:
+		out = append(out, section(5, vec(1, []byte{0}, uleb(uint64(m.memory[0]))))...)
+	case 2:
+		out = append(out, section(5, vec(1, []byte{1}, uleb(uint64(m.memory[0])), uleb(uint64(m.memory[1]))))...)
+	}
+	out = append(out, section(7, vec(len(exports), exports...))...)
+	if m.start > 0 {
+		out = append(out, section(8, uleb(uint64(m.start-1)))...)
+	}
+	if m.table != nil {
+		var idxs [][]byte
+		for _, f := range m.table {
+			idxs = append(idxs, uleb(uint64(f)))
+		}
+		out = append(out, section(9, vec(1, []byte{0}, i32c(0), []byte{opEnd}, vec(len(idxs), idxs...)))...)
+	}
+	out = append(out, section(10, vec(len(code), code...))...)
+	if m.data != nil {
+		out = append(out, section(11, vec(1, []byte{0}, i32c(0), []byte{opEnd}, vec(len(m.data), m.data)))...)
+	}
+	return out
+}
+
+// instantiate decodes a test module and links it with no host functions
+func instantiate(t *testing.T, m testModule) *Instance {
+	t.Helper()
+	mod, err := Decode(m.encode())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	in, err := Instantiate(mod, func(Import) (HostFunc, bool) { return HostFunc{}, false })
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	return in
+}
+
+// exported returns a module with a single exported function "f"
+func exported(params, results []byte, body ...[]byte) testModule {
+	return testModule{funcs: []testFunc{{params: params, results: results, body: cat(body...), export: "f"}}}
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 
 	"github.com/xrpl-commons/bedrock/pkg/adapter"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 )
 
 // Faucet handles requesting funds from XRPL faucets
 type Faucet struct {
 	executor *adapter.Executor
 	verbose  bool
+
+	// OnProgress receives progress events while a request runs
+	OnProgress adapter.ProgressFunc
 }
 
 // NewFaucet creates a new Faucet instance
@@ -35,13 +39,19 @@ func (f *Faucet) Request(ctx context.Context, config FaucetConfig) (*FaucetResul
 		"wallet_seed":    config.WalletSeed,
 		"wallet_address": config.WalletAddress,
 		"algorithm":      config.Algorithm,
-		"network_url":    config.NetworkURL,
+		"network_url":    config.Network.URL,
 		"is_local":       config.IsLocal,
 		"verbose":        f.verbose,
 	}
 
+	// Local funding is a genesis payment: faucet.js signs it and the
+	// submission engine submits and tracks it
+	if config.IsLocal {
+		return f.fundLocal(ctx, config, jsConfig)
+	}
+
 	// Execute faucet module
-	result, err := f.executor.ExecuteModule(ctx, "faucet.js", jsConfig)
+	result, err := f.executor.ExecuteModule(ctx, "faucet.js", jsConfig, f.OnProgress)
 	if err != nil {
 		return nil, err
 	}
@@ -54,3 +64,26 @@ func (f *Faucet) Request(ctx context.Context, config FaucetConfig) (*FaucetResul
 
 	return &faucetResult, nil
 }
+
+func (f *Faucet) fundLocal(ctx context.Context, config FaucetConfig, jsConfig map[string]interface{}) (*FaucetResult, error) {
+	result, submission, err := f.executor.ExecuteAndSubmit(ctx, "faucet.js", config.Network, "", jsConfig, f.OnProgress)
+	if err != nil {
+		return nil, fmt.Errorf("local funding failed: %w", err)
+	}
+	if err := submission.Err(); err != nil {
+		return nil, fmt.Errorf("local funding failed: %w", err)
+	}
+
+	var faucetResult FaucetResult
+	if err := json.Unmarshal(result.Data, &faucetResult); err != nil {
+		return nil, fmt.Errorf("failed to parse faucet result: %w", err)
+	}
+	faucetResult.TxHash = submission.Hash
+	faucetResult.Submission = submission
+
+	if balance, err := chain.NewNetworkClient(config.Network).GetAccountBalance(ctx, faucetResult.WalletAddress); err == nil {
+		faucetResult.Balance = balance
+	}
+
+	return &faucetResult, nil
+}
@@ -1,12 +1,18 @@
 package faucet
 
+import (
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
 // FaucetResult represents the result of a faucet request
 type FaucetResult struct {
-	TxHash        string `json:"txHash"`
-	WalletAddress string `json:"walletAddress"`
-	WalletSeed    string `json:"walletSeed"`
-	Balance       string `json:"balance"`
-	FaucetAmount  string `json:"faucetAmount"`
+	TxHash        string                  `json:"txHash"`
+	WalletAddress string                  `json:"walletAddress"`
+	WalletSeed    string                  `json:"walletSeed"`
+	Balance       string                  `json:"balance"`
+	FaucetAmount  string                  `json:"faucetAmount"`
+	Submission    *chain.SubmissionResult `json:"submission,omitempty"`
 }
 
 // FaucetConfig holds configuration for requesting from faucet
@@ -15,6 +21,6 @@ type FaucetConfig struct {
 	WalletSeed    string
 	WalletAddress string
 	Algorithm     string
-	NetworkURL    string
+	Network       config.NetworkConfig
 	IsLocal       bool
 }
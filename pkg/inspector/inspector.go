@@ -22,7 +22,11 @@ func InspectWasm(wasmPath string) (*WasmInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read WASM file: %w", err)
 	}
+	return Inspect(data)
+}
 
+// Inspect analyzes WASM code held in memory
+func Inspect(data []byte) (*WasmInfo, error) {
 	info := &WasmInfo{
 		Size: int64(len(data)),
 	}
@@ -2,12 +2,14 @@ package jade
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
 	"strings"
 
+	ledger "github.com/Peersyst/xrpl-go/xrpl/ledger-entry-types"
 	"github.com/Peersyst/xrpl-go/xrpl/queries/account"
 	"github.com/Peersyst/xrpl-go/xrpl/queries/common"
 	"github.com/Peersyst/xrpl-go/xrpl/queries/server"
@@ -16,6 +18,8 @@ import (
 	"github.com/Peersyst/xrpl-go/xrpl/transaction"
 	"github.com/Peersyst/xrpl-go/xrpl/transaction/types"
 	"github.com/Peersyst/xrpl-go/xrpl/wallet"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
 )
 
 // Operations handles XRPL network operations using pure Go
@@ -49,6 +53,24 @@ type SendResult struct {
 	Fee         string `json:"fee"`
 	Sequence    int    `json:"sequence"`
 	Validated   bool   `json:"validated"`
+
+	Submission *chain.SubmissionResult `json:"submission,omitempty"`
+}
+
+// SignerEntry is one member of a multisig signer list
+type SignerEntry struct {
+	Account string `json:"account"`
+	Weight  uint16 `json:"weight"`
+}
+
+// SignerListResult represents the result of a SignerListSet transaction
+type SignerListResult struct {
+	TxHash    string        `json:"tx_hash"`
+	Account   string        `json:"account"`
+	Quorum    uint32        `json:"quorum"`
+	Signers   []SignerEntry `json:"signers"`
+	Result    string        `json:"result"`
+	Validated bool          `json:"validated"`
 }
 
 // TxResult represents the result of a transaction query
@@ -222,8 +244,8 @@ func (o *Operations) GetBalance(networkURL string, address string) (*BalanceResu
 }
 
 // Send transfers XRP to a destination address
-func (o *Operations) Send(networkURL string, walletSeed, destination, amount, algorithm string) (*SendResult, error) {
-	client, err := createRPCClient(networkURL)
+func (o *Operations) Send(network config.NetworkConfig, walletSeed, destination, amount, algorithm string) (*SendResult, error) {
+	client, err := createRPCClient(network.URL)
 	if err != nil {
 		return nil, err
 	}
@@ -252,19 +274,91 @@ func (o *Operations) Send(networkURL string, walletSeed, destination, amount, al
 		"Amount":          amountDrops,
 	}
 
-	// Submit and wait for validation
+	if err := client.Autofill(&payment); err != nil {
+		return nil, fmt.Errorf("failed to autofill transaction: %w", err)
+	}
+
+	txBlob, txHash, err := w.Sign(payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	signed := chain.SignedTx{TxBlob: txBlob, Hash: txHash}
+	if lls, ok := payment["LastLedgerSequence"].(uint32); ok {
+		signed.LastLedgerSequence = lls
+	}
+
+	// Submit and track until validated or expired
+	submission, err := chain.NewNetworkClient(network).SubmitAndTrack(context.Background(), signed, chain.SubmitOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction: %w", err)
+	}
+
+	fee, _ := payment["Fee"].(string)
+	sequence, _ := payment["Sequence"].(uint32)
+
+	return &SendResult{
+		TxHash:      submission.Hash,
+		From:        string(w.ClassicAddress),
+		To:          destination,
+		Amount:      amount,
+		AmountDrops: amountDrops,
+		Result:      submission.TransactionResult,
+		Fee:         fee,
+		Sequence:    int(sequence),
+		Validated:   submission.Validated,
+		Submission:  submission,
+	}, nil
+}
+
+// SetSignerList submits a SignerListSet transaction making the wallet's account
+// a multisig account. A quorum of 0 with no signers removes the signer list.
+func (o *Operations) SetSignerList(networkURL string, walletSeed, algorithm string, quorum uint32, signers []SignerEntry) (*SignerListResult, error) {
+	client, err := createRPCClient(networkURL)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := wallet.FromSeed(walletSeed, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet from seed: %w", err)
+	}
+
+	tx := transaction.SignerListSet{
+		BaseTx: transaction.BaseTx{
+			Account:         w.ClassicAddress,
+			TransactionType: transaction.SignerListSetTx,
+		},
+		SignerQuorum: quorum,
+	}
+	for _, signer := range signers {
+		tx.SignerEntries = append(tx.SignerEntries, ledger.SignerEntryWrapper{
+			SignerEntry: ledger.SignerEntry{
+				Account:      types.Address(signer.Account),
+				SignerWeight: signer.Weight,
+			},
+		})
+	}
+
+	if ok, err := tx.Validate(); !ok {
+		return nil, fmt.Errorf("invalid signer list: %w", err)
+	}
+
+	if o.verbose {
+		fmt.Printf("Setting signer list on %s (quorum %d, %d signers)...\n", w.ClassicAddress, quorum, len(signers))
+	}
+
 	opts := &rpctypes.SubmitOptions{
 		Autofill: true,
 		Wallet:   &w,
 		FailHard: false,
 	}
 
-	txResp, err := client.SubmitTxAndWait(payment, opts)
+	txResp, err := client.SubmitTxAndWait(tx.Flatten(), opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit transaction: %w", err)
 	}
 
-	// Extract result from meta
 	result := "tesSUCCESS"
 	if meta, ok := txResp.Meta.(map[string]interface{}); ok {
 		if txResult, ok := meta["TransactionResult"].(string); ok {
@@ -272,34 +366,44 @@ func (o *Operations) Send(networkURL string, walletSeed, destination, amount, al
 		}
 	}
 
-	// Get fee from transaction
-	fee := ""
-	if feeVal, ok := txResp.Tx["Fee"]; ok {
-		fee = fmt.Sprintf("%v", feeVal)
+	return &SignerListResult{
+		TxHash:    string(txResp.Hash),
+		Account:   string(w.ClassicAddress),
+		Quorum:    quorum,
+		Signers:   signers,
+		Result:    result,
+		Validated: txResp.Validated,
+	}, nil
+}
+
+// BuildPayment autofills an unsigned XRP payment from account to destination
+// for offline signing. LastLedgerSequence is omitted since offline signing can
+// take longer than it would allow.
+func (o *Operations) BuildPayment(networkURL string, account, destination, amount string) (map[string]interface{}, error) {
+	client, err := createRPCClient(networkURL)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get sequence from transaction
-	sequence := 0
-	if seqVal, ok := txResp.Tx["Sequence"]; ok {
-		switch v := seqVal.(type) {
-		case float64:
-			sequence = int(v)
-		case int:
-			sequence = v
-		}
+	amountDrops, err := xrpToDrops(amount)
+	if err != nil {
+		return nil, err
 	}
 
-	return &SendResult{
-		TxHash:      string(txResp.Hash),
-		From:        string(w.ClassicAddress),
-		To:          destination,
-		Amount:      amount,
-		AmountDrops: amountDrops,
-		Result:      result,
-		Fee:         fee,
-		Sequence:    sequence,
-		Validated:   txResp.Validated,
-	}, nil
+	payment := transaction.FlatTransaction{
+		"TransactionType": "Payment",
+		"Account":         account,
+		"Destination":     destination,
+		"Amount":          amountDrops,
+	}
+
+	if err := client.Autofill(&payment); err != nil {
+		return nil, fmt.Errorf("failed to autofill transaction: %w", err)
+	}
+
+	delete(payment, "LastLedgerSequence")
+
+	return payment, nil
 }
 
 // GetTransaction retrieves transaction details by hash.
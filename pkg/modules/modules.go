@@ -0,0 +1,144 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xrpl-commons/bedrock/pkg/adapter"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
+// Dir is where a project keeps its own modules, relative to the project root
+const Dir = ".bedrock/modules"
+
+// Module is a user-defined JavaScript module
+type Module struct {
+	Name string // File name without the .js extension
+	Path string
+}
+
+// Find locates a module by name (with or without .js) in the project's
+// module directory
+func Find(projectRoot, name string) (*Module, error) {
+	name = strings.TrimSuffix(name, ".js")
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid module name '%s'", name)
+	}
+
+	path := filepath.Join(projectRoot, Dir, name+".js")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("module '%s' not found in %s", name, Dir)
+		}
+		return nil, fmt.Errorf("failed to read module '%s': %w", name, err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve module path: %w", err)
+	}
+
+	return &Module{Name: name, Path: abs}, nil
+}
+
+// List returns the project's modules sorted by name
+func List(projectRoot string) ([]Module, error) {
+	dir := filepath.Join(projectRoot, Dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", Dir, err)
+	}
+
+	var modules []Module
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".js" {
+			continue
+		}
+		path, _ := filepath.Abs(filepath.Join(dir, entry.Name()))
+		modules = append(modules, Module{
+			Name: strings.TrimSuffix(entry.Name(), ".js"),
+			Path: path,
+		})
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return modules, nil
+}
+
+// Context is the project context Bedrock injects into a module's config
+type Context struct {
+	Network    string
+	NetworkCfg config.NetworkConfig
+	WalletSeed string
+	ABIPath    string
+	Algorithm  string
+	Verbose    bool
+}
+
+// BuildConfig merges the injected context into the user's config. Keys
+// use the same names as the embedded modules; values the user set win.
+func BuildConfig(user map[string]interface{}, ctx Context) map[string]interface{} {
+	cfg := map[string]interface{}{
+		"network":     ctx.Network,
+		"network_url": ctx.NetworkCfg.URL,
+		"network_id":  ctx.NetworkCfg.NetworkID,
+		"verbose":     ctx.Verbose,
+	}
+	if ctx.NetworkCfg.FaucetURL != "" {
+		cfg["faucet_url"] = ctx.NetworkCfg.FaucetURL
+	}
+	if ctx.WalletSeed != "" {
+		cfg["wallet_seed"] = ctx.WalletSeed
+	}
+	if ctx.ABIPath != "" {
+		cfg["abi_path"] = ctx.ABIPath
+	}
+	if ctx.Algorithm != "" {
+		cfg["algorithm"] = ctx.Algorithm
+	}
+
+	for k, v := range user {
+		cfg[k] = v
+	}
+	return cfg
+}
+
+// ProjectABI returns the ABI path configured for the main contract, or
+// abi.json when it exists
+func ProjectABI(cfg *config.Config) string {
+	if main, ok := cfg.Contracts["main"]; ok && main.ABI != "" {
+		return main.ABI
+	}
+	if _, err := os.Stat("abi.json"); err == nil {
+		return "abi.json"
+	}
+	return ""
+}
+
+// Runner runs user modules through the adapter
+type Runner struct {
+	executor *adapter.Executor
+}
+
+// NewRunner creates a module runner
+func NewRunner(verbose bool) (*Runner, error) {
+	executor, err := adapter.NewExecutor(verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+	return &Runner{executor: executor}, nil
+}
+
+// Run executes a module with the given config. The module follows the
+// same contract as the embedded ones: it is started as
+// `node <module> <config.json>` and prints {"success", "data"} JSON.
+func (r *Runner) Run(ctx context.Context, module *Module, cfg map[string]interface{}, onProgress adapter.ProgressFunc) (*adapter.Result, error) {
+	return r.executor.ExecuteFile(ctx, module.Path, cfg, onProgress)
+}
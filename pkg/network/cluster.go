@@ -0,0 +1,388 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	addresscodec "github.com/Peersyst/xrpl-go/address-codec"
+	"github.com/Peersyst/xrpl-go/pkg/crypto"
+	"github.com/Peersyst/xrpl-go/xrpl/queries/server"
+	"github.com/Peersyst/xrpl-go/xrpl/rpc"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	dockernet "github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	ClusterNetworkName = "bedrock-xrpl-cluster"
+	MaxValidators      = 8
+
+	// Host ports of node 1; node n uses these plus n-1
+	ClusterRPCPort = 5005
+	ClusterWSPort  = 6006
+
+	clusterNodeLabel      = "bedrock.cluster.node"
+	clusterValidatorLabel = "bedrock.cluster.validator"
+	clusterStatusTimeout  = 3 * time.Second
+)
+
+// clusterEntrypoint loads the genesis ledger on first boot and syncs from the
+// other validators after a restart
+const clusterEntrypoint = `marker=/var/lib/rippled/db/.bedrock-started
+if [ -e "$marker" ]; then
+  exec /app/xrpld --net --quorum "$QUORUM" --conf /opt/ripple/config/xrpld.cfg
+fi
+mkdir -p /var/lib/rippled/db && touch "$marker"
+exec /app/xrpld --ledgerfile /genesis.json --quorum "$QUORUM" --conf /opt/ripple/config/xrpld.cfg`
+
+// ValidatorKey is a validator's signing seed and node public key
+type ValidatorKey struct {
+	Seed      string
+	PublicKey string
+}
+
+// GenerateValidatorKey creates a random secp256k1 validation key
+func GenerateValidatorKey() (*ValidatorKey, error) {
+	entropy := make([]byte, addresscodec.FamilySeedLength)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, fmt.Errorf("failed to generate entropy: %w", err)
+	}
+
+	seed, err := addresscodec.EncodeSeed(entropy, crypto.SECP256K1())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode seed: %w", err)
+	}
+	return validatorKeyFromSeed(seed)
+}
+
+// validatorKeyFromSeed derives the key xrpld signs validations with for a
+// [validation_seed]. Unlike account keys, this is the seed's root key.
+func validatorKeyFromSeed(seed string) (*ValidatorKey, error) {
+	entropy, _, err := addresscodec.DecodeSeed(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed: %w", err)
+	}
+
+	buf := make([]byte, len(entropy)+4)
+	copy(buf, entropy)
+	for seq := uint32(0); ; seq++ {
+		binary.BigEndian.PutUint32(buf[len(entropy):], seq)
+		sum := sha512.Sum512(buf)
+		k := new(big.Int).SetBytes(sum[:32])
+		if k.Sign() == 0 || k.Cmp(btcec.S256().N) >= 0 {
+			continue
+		}
+
+		_, public := btcec.PrivKeyFromBytes(sum[:32])
+		nodeKey, err := addresscodec.EncodeNodePublicKey(public.SerializeCompressed())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode node public key: %w", err)
+		}
+		return &ValidatorKey{Seed: seed, PublicKey: nodeKey}, nil
+	}
+}
+
+// ClusterNodeName returns the container name of cluster node n (1-based)
+func ClusterNodeName(n int) string {
+	return fmt.Sprintf("%s-%d", ContainerName, n)
+}
+
+// ClusterQuorum is the number of validations a ledger needs in a cluster of
+// n validators: a majority, so with three or more validators one node can
+// restart without stalling closes
+func ClusterQuorum(n int) int {
+	return n/2 + 1
+}
+
+// startCluster runs opts.Validators validators on a private Docker network
+func (m *Manager) startCluster(ctx context.Context, opts StartOptions) error {
+	if opts.Validators > MaxValidators {
+		return fmt.Errorf("at most %d validators are supported", MaxValidators)
+	}
+
+	if err := m.pullImage(ctx, opts.DockerImage); err != nil {
+		return fmt.Errorf("failed to pull image: %w", err)
+	}
+
+	configDir, err := filepath.Abs(opts.ConfigDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config directory: %w", err)
+	}
+
+	genesisPath := filepath.Join(configDir, "genesis.json")
+	if _, err := os.Stat(genesisPath); os.IsNotExist(err) {
+		return fmt.Errorf("genesis.json not found in %s", configDir)
+	}
+
+	baseCfg, err := os.ReadFile(filepath.Join(configDir, "xrpld.cfg"))
+	if err != nil {
+		return fmt.Errorf("xrpld.cfg not found in %s (run 'bedrock init' to generate)", configDir)
+	}
+
+	// Every node trusts every validator
+	keys := make([]*ValidatorKey, opts.Validators)
+	trusted := make([]string, opts.Validators)
+	for i := range keys {
+		if keys[i], err = GenerateValidatorKey(); err != nil {
+			return err
+		}
+		trusted[i] = keys[i].PublicKey
+	}
+	validatorsTxt := "[validators]\n" + strings.Join(trusted, "\n") + "\n"
+
+	clusterDir := filepath.Join(configDir, "cluster")
+	if err := os.RemoveAll(clusterDir); err != nil {
+		return fmt.Errorf("failed to clear cluster config: %w", err)
+	}
+
+	if _, err := m.docker.NetworkCreate(ctx, ClusterNetworkName, dockernet.CreateOptions{Driver: "bridge"}); err != nil {
+		return fmt.Errorf("failed to create Docker network: %w", err)
+	}
+
+	for i, key := range keys {
+		n := i + 1
+		nodeDir := filepath.Join(clusterDir, fmt.Sprintf("node-%d", n))
+		if err := os.MkdirAll(nodeDir, 0755); err != nil {
+			m.stopCluster(ctx)
+			return fmt.Errorf("failed to create node config directory: %w", err)
+		}
+
+		var peers []string
+		for p := 1; p <= opts.Validators; p++ {
+			if p != n {
+				peers = append(peers, ClusterNodeName(p)+" 51235")
+			}
+		}
+		cfg := setConfigSection(string(baseCfg), "validation_seed", []string{key.Seed})
+		cfg = setConfigSection(cfg, "ips_fixed", peers)
+
+		cfgPath := filepath.Join(nodeDir, "xrpld.cfg")
+		validatorsPath := filepath.Join(nodeDir, "validators.txt")
+		if err := os.WriteFile(cfgPath, []byte(cfg), 0644); err != nil {
+			m.stopCluster(ctx)
+			return fmt.Errorf("failed to write xrpld.cfg: %w", err)
+		}
+		if err := os.WriteFile(validatorsPath, []byte(validatorsTxt), 0644); err != nil {
+			m.stopCluster(ctx)
+			return fmt.Errorf("failed to write validators.txt: %w", err)
+		}
+
+		if err := m.startClusterNode(ctx, opts, n, key, genesisPath, cfgPath, validatorsPath); err != nil {
+			m.stopCluster(ctx)
+			return fmt.Errorf("failed to start node %d: %w", n, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) startClusterNode(ctx context.Context, opts StartOptions, n int, key *ValidatorKey, genesisPath, cfgPath, validatorsPath string) error {
+	rpcPort := strconv.Itoa(ClusterRPCPort + n - 1)
+	wsPort := strconv.Itoa(ClusterWSPort + n - 1)
+
+	resp, err := m.docker.ContainerCreate(ctx,
+		&container.Config{
+			Image:      opts.DockerImage,
+			Entrypoint: []string{"/bin/sh", "-c", clusterEntrypoint},
+			Env:        []string{fmt.Sprintf("QUORUM=%d", ClusterQuorum(opts.Validators))},
+			ExposedPorts: nat.PortSet{
+				"6006/tcp":  struct{}{},
+				"5005/tcp":  struct{}{},
+				"51235/tcp": struct{}{},
+			},
+			Labels: map[string]string{
+				clusterNodeLabel:      strconv.Itoa(n),
+				clusterValidatorLabel: key.PublicKey,
+			},
+		},
+		&container.HostConfig{
+			PortBindings: nat.PortMap{
+				"6006/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: wsPort}},
+				"5005/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: rpcPort}},
+			},
+			Binds: []string{
+				fmt.Sprintf("%s:/genesis.json:ro", genesisPath),
+				fmt.Sprintf("%s:/opt/ripple/config/xrpld.cfg:ro", cfgPath),
+				fmt.Sprintf("%s:/opt/ripple/config/validators.txt:ro", validatorsPath),
+			},
+		},
+		&dockernet.NetworkingConfig{
+			EndpointsConfig: map[string]*dockernet.EndpointSettings{
+				ClusterNetworkName: {},
+			},
+		},
+		nil,
+		ClusterNodeName(n),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+
+	if err := m.docker.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+	return nil
+}
+
+// stopCluster removes the cluster's containers and network
+func (m *Manager) stopCluster(ctx context.Context) error {
+	nodes, err := m.clusterContainers(ctx)
+	if err != nil {
+		return err
+	}
+
+	timeout := 10
+	for _, c := range nodes {
+		if err := m.docker.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+			return fmt.Errorf("failed to stop container: %w", err)
+		}
+		if err := m.docker.ContainerRemove(ctx, c.ID, container.RemoveOptions{}); err != nil {
+			return fmt.Errorf("failed to remove container: %w", err)
+		}
+	}
+
+	if _, err := m.docker.NetworkInspect(ctx, ClusterNetworkName, dockernet.InspectOptions{}); err == nil {
+		if err := m.docker.NetworkRemove(ctx, ClusterNetworkName); err != nil {
+			return fmt.Errorf("failed to remove Docker network: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RestartNode restarts validator n of a running cluster
+func (m *Manager) RestartNode(ctx context.Context, n int) error {
+	nodes, err := m.clusterContainers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no cluster is running (start one with 'bedrock node start --validators N')")
+	}
+
+	for _, c := range nodes {
+		if c.Labels[clusterNodeLabel] == strconv.Itoa(n) {
+			timeout := 10
+			if err := m.docker.ContainerRestart(ctx, c.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+				return fmt.Errorf("failed to restart container: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster has no node %d (nodes are 1-%d)", n, len(nodes))
+}
+
+// clusterStatus reports every cluster node, querying server_info on the
+// running ones
+func (m *Manager) clusterStatus(nodes []types.Container) *NodeStatus {
+	status := &NodeStatus{Image: nodes[0].Image}
+
+	for _, c := range nodes {
+		n, _ := strconv.Atoi(c.Labels[clusterNodeLabel])
+		node := ClusterNode{
+			Index:       n,
+			Name:        ClusterNodeName(n),
+			ContainerID: c.ID[:12],
+			Running:     c.State == "running",
+			Validator:   c.Labels[clusterValidatorLabel],
+			RPCURL:      fmt.Sprintf("http://localhost:%d", ClusterRPCPort+n-1),
+			WSURL:       fmt.Sprintf("ws://localhost:%d", ClusterWSPort+n-1),
+		}
+		if node.Running {
+			status.Running = true
+			if err := node.fetchServerInfo(); err != nil {
+				node.Error = err.Error()
+			}
+		}
+		status.Nodes = append(status.Nodes, node)
+	}
+
+	// Report node 1 as the node's endpoint
+	status.ContainerID = status.Nodes[0].ContainerID
+	status.Ports = []string{
+		fmt.Sprintf("%d->5005/tcp", ClusterRPCPort),
+		fmt.Sprintf("%d->6006/tcp", ClusterWSPort),
+	}
+	return status
+}
+
+func (node *ClusterNode) fetchServerInfo() error {
+	cfg, err := rpc.NewClientConfig(node.RPCURL, rpc.WithTimeout(clusterStatusTimeout))
+	if err != nil {
+		return err
+	}
+
+	resp, err := rpc.NewClient(cfg).Request(&server.InfoRequest{})
+	if err != nil {
+		return fmt.Errorf("server_info failed: %w", err)
+	}
+
+	var info server.InfoResponse
+	if err := resp.GetResult(&info); err != nil {
+		return fmt.Errorf("failed to parse server info: %w", err)
+	}
+
+	node.ServerState = info.Info.ServerState
+	node.Peers = int(info.Info.Peers)
+	node.ValidatedLedger = uint64(info.Info.ValidatedLedger.Seq)
+	return nil
+}
+
+// clusterContainers lists the cluster's containers in node order
+func (m *Manager) clusterContainers(ctx context.Context) ([]types.Container, error) {
+	nodes, err := m.docker.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", clusterNodeLabel)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		a, _ := strconv.Atoi(nodes[i].Labels[clusterNodeLabel])
+		b, _ := strconv.Atoi(nodes[j].Labels[clusterNodeLabel])
+		return a < b
+	})
+	return nodes, nil
+}
+
+// setConfigSection replaces the body of an xrpld.cfg [section], appending
+// the section if it is missing
+func setConfigSection(cfg, name string, lines []string) string {
+	header := "[" + name + "]"
+	body := strings.Join(lines, "\n") + "\n"
+
+	src := strings.Split(cfg, "\n")
+	for i, line := range src {
+		if strings.TrimSpace(line) != header {
+			continue
+		}
+		end := i + 1
+		for end < len(src) && !strings.HasPrefix(strings.TrimSpace(src[end]), "[") {
+			end++
+		}
+		out := strings.Join(src[:i+1], "\n") + "\n" + body
+		if end < len(src) {
+			out += "\n" + strings.Join(src[end:], "\n")
+		}
+		return out
+	}
+
+	if !strings.HasSuffix(cfg, "\n") {
+		cfg += "\n"
+	}
+	return cfg + "\n" + header + "\n" + body
+}
@@ -0,0 +1,51 @@
+package network
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatorKeyFromSeed(t *testing.T) {
+	// validation_create example from the xrpld documentation
+	key, err := validatorKeyFromSeed("ssZkdwURFMBXenJPbrpE14b6noJSu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "n9Mxf6qD4J55XeLSCEpqaePW4GjoCR5U1ZeGZGJUCNe3bQa4yQbG"; key.PublicKey != want {
+		t.Errorf("public key = %s, want %s", key.PublicKey, want)
+	}
+}
+
+func TestGenerateValidatorKey(t *testing.T) {
+	key, err := GenerateValidatorKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key.Seed, "s") || !strings.HasPrefix(key.PublicKey, "n") {
+		t.Errorf("unexpected key encoding: %+v", key)
+	}
+}
+
+func TestClusterQuorum(t *testing.T) {
+	for n, want := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 5: 3} {
+		if got := ClusterQuorum(n); got != want {
+			t.Errorf("ClusterQuorum(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestSetConfigSection(t *testing.T) {
+	cfg := "[server]\nport_rpc\n\n[node_size]\nsmall\n\n[ssl_verify]\n0\n"
+
+	got := setConfigSection(cfg, "node_size", []string{"tiny"})
+	want := "[server]\nport_rpc\n\n[node_size]\ntiny\n\n[ssl_verify]\n0\n"
+	if got != want {
+		t.Errorf("replace:\ngot  %q\nwant %q", got, want)
+	}
+
+	got = setConfigSection(cfg, "ips_fixed", []string{"a 51235", "b 51235"})
+	want = cfg + "\n[ips_fixed]\na 51235\nb 51235\n"
+	if got != want {
+		t.Errorf("append:\ngot  %q\nwant %q", got, want)
+	}
+}
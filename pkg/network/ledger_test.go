@@ -0,0 +1,45 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xrpl-commons/bedrock/pkg/chain/mockserver"
+)
+
+func TestLedgerServiceAdvancesLedgers(t *testing.T) {
+	srv := mockserver.New()
+	defer srv.Close()
+
+	service, err := NewLedgerService(srv.URL, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.WaitForReady(context.Background(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for service.GetStatus().LedgersAdvanced < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	service.Stop()
+
+	status := service.GetStatus()
+	if status.Running {
+		t.Error("service still running after Stop")
+	}
+	if status.LedgersAdvanced < 3 || status.LastError != "" {
+		t.Fatalf("status = %+v", status)
+	}
+	if calls := srv.Calls("ledger_accept"); calls < int(status.LedgersAdvanced) {
+		t.Errorf("ledger_accept called %d times for %d ledgers", calls, status.LedgersAdvanced)
+	}
+	if got := uint64(srv.LedgerIndex()); status.LastLedgerIndex > got || status.LastLedgerIndex < got-1 {
+		t.Errorf("last ledger = %d, server open ledger = %d", status.LastLedgerIndex, got)
+	}
+}
@@ -43,6 +43,13 @@ func (m *Manager) Start(ctx context.Context, opts StartOptions) error {
 	if err == nil && existing != nil {
 		return fmt.Errorf("node is already running (container: %s)", existing.ID[:12])
 	}
+	if nodes, err := m.clusterContainers(ctx); err == nil && len(nodes) > 0 {
+		return fmt.Errorf("a %d-node cluster is already running", len(nodes))
+	}
+
+	if opts.Validators > 0 {
+		return m.startCluster(ctx, opts)
+	}
 
 	// Pull the Docker image
 	if err := m.pullImage(ctx, opts.DockerImage); err != nil {
@@ -150,6 +157,10 @@ func (m *Manager) Stop(ctx context.Context) error {
 		m.ledgerService = nil
 	}
 
+	if nodes, err := m.clusterContainers(ctx); err == nil && len(nodes) > 0 {
+		return m.stopCluster(ctx)
+	}
+
 	containerInfo, err := m.getContainer(ctx)
 	if err != nil {
 		return fmt.Errorf("node is not running")
@@ -171,6 +182,10 @@ func (m *Manager) Stop(ctx context.Context) error {
 
 // Status returns the status of the local node
 func (m *Manager) Status(ctx context.Context) (*NodeStatus, error) {
+	if nodes, err := m.clusterContainers(ctx); err == nil && len(nodes) > 0 {
+		return m.clusterStatus(nodes), nil
+	}
+
 	containerInfo, err := m.getContainer(ctx)
 	if err != nil {
 		return &NodeStatus{Running: false}, nil
@@ -8,6 +8,9 @@ type StartOptions struct {
 	ConfigDir      string
 	LedgerInterval time.Duration
 	RPCURL         string
+	// Validators > 0 runs a cluster of validators reaching real consensus
+	// instead of a standalone node
+	Validators int
 }
 
 // NodeStatus represents the current status of the node
@@ -20,4 +23,21 @@ type NodeStatus struct {
 	LedgerServiceRunning bool
 	LedgersAdvanced      uint64
 	LastLedgerIndex      uint64
+	// Cluster nodes, empty for a standalone node
+	Nodes []ClusterNode
+}
+
+// ClusterNode is the status of one validator in a cluster
+type ClusterNode struct {
+	Index           int
+	Name            string
+	ContainerID     string
+	Running         bool
+	Validator       string // Node public key
+	RPCURL          string
+	WSURL           string
+	ServerState     string
+	Peers           int
+	ValidatedLedger uint64
+	Error           string // Why server_info could not be read
 }
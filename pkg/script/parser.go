@@ -101,6 +101,7 @@ func validateScript(s *Script) error {
 		"fund":   true,
 		"wait":   true,
 		"assert": true,
+		"module": true,
 	}
 
 	for i, step := range s.Steps {
@@ -108,7 +109,7 @@ func validateScript(s *Script) error {
 			return fmt.Errorf("step %d: action is required", i)
 		}
 		if !validActions[step.Action] {
-			return fmt.Errorf("step %d: unknown action '%s' (valid: deploy, call, fund, wait, assert)", i, step.Action)
+			return fmt.Errorf("step %d: unknown action '%s' (valid: deploy, call, fund, wait, assert, module)", i, step.Action)
 		}
 	}
 
@@ -2,15 +2,18 @@ package script
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 	"github.com/xrpl-commons/bedrock/pkg/config"
 	"github.com/xrpl-commons/bedrock/pkg/deployer"
 	"github.com/xrpl-commons/bedrock/pkg/faucet"
+	"github.com/xrpl-commons/bedrock/pkg/modules"
 )
 
 // Runner executes scripts step-by-step
@@ -105,6 +108,8 @@ func (r *Runner) executeStep(ctx context.Context, step Step, networkName string)
 		r.executeWait(step, &result)
 	case "assert":
 		r.executeAssert(step, &result)
+	case "module":
+		r.executeModule(ctx, step, networkName, &result)
 	}
 
 	result.Duration = time.Since(startTime)
@@ -134,8 +139,7 @@ func (r *Runner) executeDeploy(ctx context.Context, step Step, networkName strin
 	deployResult, err := d.Deploy(ctx, deployer.DeploymentConfig{
 		WasmPath:   wasmPath,
 		ABIPath:    abiPath,
-		NetworkURL: networkCfg.URL,
-		NetworkID:  networkCfg.NetworkID,
+		Network:    networkCfg,
 		WalletSeed: walletSeed,
 		Algorithm:  getStringConfig(step.Config, "algorithm", "secp256k1"),
 		FaucetURL:  networkCfg.FaucetURL,
@@ -173,14 +177,14 @@ func (r *Runner) executeCall(ctx context.Context, step Step, networkName string,
 	callResult, err := c.Call(ctx, caller.CallConfig{
 		ContractAccount:      contractAccount,
 		FunctionName:         functionName,
-		NetworkURL:           networkCfg.URL,
-		NetworkID:            networkCfg.NetworkID,
+		Network:              networkCfg,
 		WalletSeed:           walletSeed,
 		Algorithm:            getStringConfig(step.Config, "algorithm", "secp256k1"),
 		ABIPath:              getStringConfig(step.Config, "abi_path", "abi.json"),
 		Parameters:           params,
-		ComputationAllowance: getStringConfig(step.Config, "gas", "1000000"),
-		Fee:                  getStringConfig(step.Config, "fee", "1000000"),
+		Amount:               r.resolveVar(getStringConfig(step.Config, "amount", "")),
+		ComputationAllowance: getStringConfig(step.Config, "gas", caller.AllowanceAuto),
+		Fee:                  getStringConfig(step.Config, "fee", chain.FeeAuto),
 	})
 
 	if err != nil {
@@ -213,7 +217,7 @@ func (r *Runner) executeFund(ctx context.Context, step Step, networkName string,
 		FaucetURL:  networkCfg.FaucetURL,
 		WalletSeed: walletSeed,
 		Algorithm:  getStringConfig(step.Config, "algorithm", "secp256k1"),
-		NetworkURL: networkCfg.URL,
+		Network:    networkCfg,
 		IsLocal:    isLocal,
 	})
 
@@ -230,6 +234,68 @@ func (r *Runner) executeFund(ctx context.Context, step Step, networkName string,
 	}
 }
 
+// executeModule runs a project module from .bedrock/modules. The "module"
+// key names it; every other config key is passed to the module, with
+// ${var} references resolved and the network and ABI injected.
+func (r *Runner) executeModule(ctx context.Context, step Step, networkName string, result *StepResult) {
+	name := r.resolveVar(getStringConfig(step.Config, "module", ""))
+	if name == "" {
+		result.Error = "module step requires a 'module' name"
+		return
+	}
+
+	module, err := modules.Find(".", name)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	userConfig := make(map[string]interface{}, len(step.Config))
+	for k, v := range step.Config {
+		if k == "module" {
+			continue
+		}
+		if s, ok := v.(string); ok {
+			v = r.resolveVar(s)
+		}
+		userConfig[k] = v
+	}
+
+	runner, err := modules.NewRunner(r.verbose)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create module runner: %v", err)
+		return
+	}
+
+	moduleResult, err := runner.Run(ctx, module, modules.BuildConfig(userConfig, modules.Context{
+		Network:    networkName,
+		NetworkCfg: r.resolveNetwork(networkName),
+		ABIPath:    modules.ProjectABI(r.cfg),
+		Verbose:    r.verbose,
+	}), nil)
+	if err != nil {
+		result.Error = fmt.Sprintf("module failed: %v", err)
+		return
+	}
+
+	// Top-level fields of the module's data become step outputs
+	result.Success = true
+	result.Output = make(map[string]string)
+	var data map[string]interface{}
+	if err := json.Unmarshal(moduleResult.Data, &data); err == nil {
+		for k, v := range data {
+			switch val := v.(type) {
+			case string:
+				result.Output[k] = val
+			case nil:
+			default:
+				encoded, _ := json.Marshal(val)
+				result.Output[k] = string(encoded)
+			}
+		}
+	}
+}
+
 func (r *Runner) executeWait(step Step, result *StepResult) {
 	seconds := getIntConfig(step.Config, "seconds", 5)
 	time.Sleep(time.Duration(seconds) * time.Second)
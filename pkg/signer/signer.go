@@ -0,0 +1,111 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/xrpl-commons/bedrock/pkg/adapter"
+)
+
+// Signer signs transactions offline via embedded Node.js module
+type Signer struct {
+	executor *adapter.Executor
+	verbose  bool
+}
+
+// NewSigner creates a new signer instance
+func NewSigner(verbose bool) (*Signer, error) {
+	executor, err := adapter.NewExecutor(verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	return &Signer{
+		executor: executor,
+		verbose:  verbose,
+	}, nil
+}
+
+// Sign signs a transaction without contacting the network
+func (s *Signer) Sign(ctx context.Context, config SignConfig) (*SignResult, error) {
+	mode := ModeSign
+	if config.Multisign {
+		mode = ModeMultisign
+	}
+
+	jsConfig := map[string]interface{}{
+		"mode":        mode,
+		"tx_json":     config.TxJSON,
+		"wallet_seed": config.WalletSeed,
+		"algorithm":   config.Algorithm,
+		"verbose":     s.verbose,
+	}
+
+	result, err := s.executor.ExecuteModule(ctx, "sign.js", jsConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("signing failed: %w", err)
+	}
+
+	var signResult SignResult
+	if err := json.Unmarshal(result.Data, &signResult); err != nil {
+		return nil, fmt.Errorf("failed to parse sign result: %w", err)
+	}
+
+	return &signResult, nil
+}
+
+// Combine merges the Signers of several multi-signed blobs of the same transaction
+func (s *Signer) Combine(ctx context.Context, txBlobs []string) (*SignResult, error) {
+	jsConfig := map[string]interface{}{
+		"mode":     ModeCombine,
+		"tx_blobs": txBlobs,
+		"verbose":  s.verbose,
+	}
+
+	result, err := s.executor.ExecuteModule(ctx, "sign.js", jsConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("combining signatures failed: %w", err)
+	}
+
+	var combineResult SignResult
+	if err := json.Unmarshal(result.Data, &combineResult); err != nil {
+		return nil, fmt.Errorf("failed to parse combine result: %w", err)
+	}
+
+	return &combineResult, nil
+}
+
+// ReadTxFile loads a transaction file from disk
+func ReadTxFile(path string) (*TxFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction file: %w", err)
+	}
+
+	var file TxFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse transaction file: %w", err)
+	}
+
+	if file.TxJSON == nil && file.TxBlob == "" {
+		return nil, fmt.Errorf("transaction file %s contains neither tx_json nor tx_blob", path)
+	}
+
+	return &file, nil
+}
+
+// WriteTxFile writes a transaction file to disk
+func WriteTxFile(path string, file *TxFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal transaction file: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write transaction file: %w", err)
+	}
+
+	return nil
+}
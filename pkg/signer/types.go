@@ -0,0 +1,37 @@
+package signer
+
+// TxFile is the on-disk format exchanged between the online host that
+// builds a transaction and the offline host that signs it
+type TxFile struct {
+	Network string                 `json:"network,omitempty"`
+	TxJSON  map[string]interface{} `json:"tx_json"`
+	TxBlob  string                 `json:"tx_blob,omitempty"`
+	Hash    string                 `json:"hash,omitempty"`
+}
+
+// IsSigned reports whether the file carries a signed blob
+func (f *TxFile) IsSigned() bool {
+	return f.TxBlob != ""
+}
+
+// Signing modes understood by sign.js
+const (
+	ModeSign      = "sign"
+	ModeMultisign = "multisign"
+	ModeCombine   = "combine"
+)
+
+// SignConfig holds configuration for signing a transaction
+type SignConfig struct {
+	TxJSON     map[string]interface{}
+	WalletSeed string
+	Algorithm  string
+	Multisign  bool // Add a Signer entry instead of a single signature
+}
+
+// SignResult represents a signed transaction
+type SignResult struct {
+	TxBlob string                 `json:"txBlob"`
+	Hash   string                 `json:"hash"`
+	TxJSON map[string]interface{} `json:"txJson"`
+}
@@ -85,6 +85,7 @@ fn transfer(to: &[u8], amount: u64) -> u64 {
 /// @xrpl-function balance
 /// @param account ACCOUNT - Account to check
 /// @return UINT64 - Token balance
+/// @view
 #[wasm_export]
 fn balance(account: &[u8]) -> u64 {
     let _ = trace("Checking balance");
@@ -125,6 +126,7 @@ fn transfer_nft(token_id: u64, to: &[u8]) -> u64 {
 /// @xrpl-function owner_of
 /// @param token_id UINT64 - Token ID to check
 /// @return UINT64 - Owner info
+/// @view
 #[wasm_export]
 fn owner_of(token_id: u64) -> u64 {
     let _ = trace("Checking NFT owner");
@@ -199,6 +201,7 @@ fn decrement() -> u64 {
 
 /// @xrpl-function get_count
 /// @return UINT64 - Current counter value
+/// @view
 #[wasm_export]
 fn get_count() -> u64 {
     let _ = trace("Getting counter value");
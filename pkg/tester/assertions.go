@@ -3,9 +3,12 @@ package tester
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/xrpl-commons/bedrock/pkg/abi"
 	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 )
 
 // AssertionResult holds the outcome of an assertion check
@@ -16,16 +19,17 @@ type AssertionResult struct {
 	Actual   string
 }
 
-// RunAssertions validates a call result against a set of assertions
-func RunAssertions(result *caller.CallResult, assertions []Assertion) []AssertionResult {
+// RunAssertions validates a call result against a set of assertions. State
+// values are decoded with the contract's state schema when one is given.
+func RunAssertions(result *caller.CallResult, assertions []Assertion, schema *abi.StateSchema) []AssertionResult {
 	var results []AssertionResult
 	for _, a := range assertions {
-		results = append(results, runAssertion(result, a))
+		results = append(results, runAssertion(result, a, schema))
 	}
 	return results
 }
 
-func runAssertion(result *caller.CallResult, a Assertion) AssertionResult {
+func runAssertion(result *caller.CallResult, a Assertion, schema *abi.StateSchema) AssertionResult {
 	switch a.Type {
 	case AssertReturnCode:
 		return assertReturnCode(result, a)
@@ -38,9 +42,17 @@ func runAssertion(result *caller.CallResult, a Assertion) AssertionResult {
 	case AssertEvent:
 		return assertEvent(result, a)
 	case AssertState:
-		return assertState(result, a)
+		return assertState(result, a, schema)
 	case AssertGasBelow:
 		return assertGasBelow(result, a)
+	case AssertStateChange:
+		return assertStateChange(result, a, schema)
+	case AssertBalanceChange:
+		return assertBalanceChange(result, a)
+	case AssertOwnerCountChange:
+		return assertOwnerCountChange(result, a)
+	case AssertAmountReceived:
+		return assertAmountReceived(result)
 	default:
 		return AssertionResult{
 			Passed:  false,
@@ -127,12 +139,12 @@ func assertEvent(result *caller.CallResult, a Assertion) AssertionResult {
 	}
 
 	// Look for ContractEvent entries in metadata
-	events := extractEvents(result.Meta)
+	events := chain.ContractEventsFromMeta(result.Meta, result.TxHash, 0)
 	eventType, _ := expectedEvent["type"].(string)
 
 	for _, event := range events {
 		if eventType != "" {
-			if et, ok := event["type"].(string); ok && et == eventType {
+			if event.Type == eventType {
 				return AssertionResult{
 					Passed:  true,
 					Message: fmt.Sprintf("event '%s' found", eventType),
@@ -149,7 +161,7 @@ func assertEvent(result *caller.CallResult, a Assertion) AssertionResult {
 	}
 }
 
-func assertState(result *caller.CallResult, a Assertion) AssertionResult {
+func assertState(result *caller.CallResult, a Assertion, schema *abi.StateSchema) AssertionResult {
 	// State assertions check ContractData in metadata
 	if result.Meta == nil {
 		return AssertionResult{
@@ -166,11 +178,14 @@ func assertState(result *caller.CallResult, a Assertion) AssertionResult {
 	}
 
 	// Look through AffectedNodes in metadata for state changes
-	expected := fmt.Sprintf("%v", a.Expected)
-	actual := findStateValue(result.Meta, a.Field)
+	expected := formatValue(a.Expected)
+	actual := ""
+	if raw, ok := findStateValue(result.Meta, a.Field); ok {
+		actual = schema.DecodeValue(a.Field, raw, false).Value
+	}
 
 	return AssertionResult{
-		Passed:   actual == expected,
+		Passed:   stateValueMatches(a.Expected, actual),
 		Message:  fmt.Sprintf("state field '%s'", a.Field),
 		Expected: expected,
 		Actual:   actual,
@@ -194,57 +209,166 @@ func assertGasBelow(result *caller.CallResult, a Assertion) AssertionResult {
 	}
 }
 
-// extractEvents pulls ContractEvent entries from transaction metadata
-func extractEvents(meta map[string]interface{}) []map[string]interface{} {
-	var events []map[string]interface{}
+func assertStateChange(result *caller.CallResult, a Assertion, schema *abi.StateSchema) AssertionResult {
+	expected, ok := a.Expected.(map[string]interface{})
+	if !ok || expected["field"] == nil {
+		return AssertionResult{
+			Passed:  false,
+			Message: fmt.Sprintf("invalid state change assertion (needs 'field'): %v", a.Expected),
+		}
+	}
+
+	field := fmt.Sprintf("%v", expected["field"])
+	diff := DiffState(result.Meta)
+	diff.DecodeData(schema)
 
-	// Navigate metadata structure: AffectedNodes or similar
-	affectedNodes, ok := meta["AffectedNodes"]
-	if !ok {
-		return events
+	var found []string
+	for _, c := range diff.Data {
+		if c.Field != field {
+			continue
+		}
+		found = append(found, fmt.Sprintf("%s %s -> %s", c.Kind, c.Before, c.After))
+		if matchesExpected(expected, "owner", c.Owner) &&
+			matchesExpected(expected, "kind", string(c.Kind)) &&
+			matchesStateValue(expected, "before", c.Before) &&
+			matchesStateValue(expected, "after", c.After) {
+			return AssertionResult{
+				Passed:  true,
+				Message: fmt.Sprintf("state field '%s' changed", field),
+			}
+		}
 	}
 
-	nodes, ok := affectedNodes.([]interface{})
-	if !ok {
-		return events
+	actual := "unchanged"
+	if len(found) > 0 {
+		actual = strings.Join(found, "; ")
+	}
+	return AssertionResult{
+		Passed:   false,
+		Message:  fmt.Sprintf("state field '%s' changed", field),
+		Expected: fmt.Sprintf("%v", expected),
+		Actual:   actual,
+	}
+}
+
+func assertBalanceChange(result *caller.CallResult, a Assertion) AssertionResult {
+	expected, ok := a.Expected.(map[string]interface{})
+	if !ok || expected["account"] == nil || expected["delta"] == nil {
+		return AssertionResult{
+			Passed:  false,
+			Message: fmt.Sprintf("invalid balance change assertion (needs 'account' and 'delta'): %v", a.Expected),
+		}
 	}
 
-	for _, node := range nodes {
-		nodeMap, ok := node.(map[string]interface{})
-		if !ok {
+	account := fmt.Sprintf("%v", expected["account"])
+	currency := "XRP"
+	if c, ok := expected["currency"]; ok {
+		currency = fmt.Sprintf("%v", c)
+	}
+	wantDelta := fmt.Sprintf("%v", expected["delta"])
+
+	actual := "0"
+	for _, b := range DiffState(result.Meta).Balances {
+		if b.Account != account || b.Currency != currency || !matchesExpected(expected, "issuer", b.Issuer) {
 			continue
 		}
+		actual = b.Delta
+		break
+	}
 
-		for _, v := range nodeMap {
-			innerMap, ok := v.(map[string]interface{})
-			if !ok {
-				continue
-			}
+	return AssertionResult{
+		Passed:   parseDecimal(actual).Cmp(parseDecimal(wantDelta)) == 0,
+		Message:  fmt.Sprintf("%s balance of %s changed", currency, account),
+		Expected: wantDelta,
+		Actual:   actual,
+	}
+}
 
-			if ledgerEntryType, ok := innerMap["LedgerEntryType"].(string); ok {
-				if ledgerEntryType == "ContractEvent" {
-					events = append(events, innerMap)
-				}
-			}
+func assertOwnerCountChange(result *caller.CallResult, a Assertion) AssertionResult {
+	expected, ok := a.Expected.(map[string]interface{})
+	if !ok || expected["account"] == nil {
+		return AssertionResult{
+			Passed:  false,
+			Message: fmt.Sprintf("invalid owner count assertion (needs 'account' and 'delta'): %v", a.Expected),
+		}
+	}
+	wantDelta, ok := toInt64(expected["delta"])
+	if !ok {
+		return AssertionResult{
+			Passed:  false,
+			Message: fmt.Sprintf("invalid owner count delta: %v", expected["delta"]),
 		}
 	}
 
-	return events
+	account := fmt.Sprintf("%v", expected["account"])
+	var actual int64
+	for _, o := range DiffState(result.Meta).OwnerCounts {
+		if o.Account == account {
+			actual = o.Delta()
+			break
+		}
+	}
+
+	return AssertionResult{
+		Passed:   actual == wantDelta,
+		Message:  fmt.Sprintf("owner count of %s changed", account),
+		Expected: fmt.Sprintf("%+d", wantDelta),
+		Actual:   fmt.Sprintf("%+d", actual),
+	}
 }
 
-// findStateValue searches metadata for a specific state field value
-func findStateValue(meta map[string]interface{}, field string) string {
-	// Search through AffectedNodes for ContractData modifications
-	affectedNodes, ok := meta["AffectedNodes"]
+func assertAmountReceived(result *caller.CallResult) AssertionResult {
+	transfer := DiffCall(result).Transfer
+	if transfer == nil {
+		return AssertionResult{
+			Passed:  false,
+			Message: "no amount was sent with the call",
+		}
+	}
+
+	return AssertionResult{
+		Passed:   transfer.Complete(),
+		Message:  fmt.Sprintf("contract received %s", transfer.Amount),
+		Expected: transfer.Amount,
+		Actual:   transfer.Received,
+	}
+}
+
+// matchesExpected reports whether an optional expected key is absent or equal to actual
+func matchesExpected(expected map[string]interface{}, key, actual string) bool {
+	want, ok := expected[key]
 	if !ok {
-		return ""
+		return true
 	}
+	return formatValue(want) == actual
+}
 
-	nodes, ok := affectedNodes.([]interface{})
+// matchesStateValue is matchesExpected for decoded state values
+func matchesStateValue(expected map[string]interface{}, key, actual string) bool {
+	want, ok := expected[key]
 	if !ok {
-		return ""
+		return true
 	}
+	return stateValueMatches(want, actual)
+}
+
+// stateValueMatches compares an expected value to a decoded state value.
+// Text values are rendered quoted, so they also match unquoted.
+func stateValueMatches(want interface{}, actual string) bool {
+	expected := formatValue(want)
+	if expected == actual {
+		return true
+	}
+	if text, err := strconv.Unquote(actual); err == nil {
+		return expected == text
+	}
+	return false
+}
 
+// findStateValue searches metadata for a specific state field value
+func findStateValue(meta map[string]interface{}, field string) (interface{}, bool) {
+	// Search through AffectedNodes for ContractData modifications
+	nodes, _ := meta["AffectedNodes"].([]interface{})
 	for _, node := range nodes {
 		nodeMap, ok := node.(map[string]interface{})
 		if !ok {
@@ -259,14 +383,11 @@ func findStateValue(meta map[string]interface{}, field string) string {
 
 			if ledgerEntryType, ok := innerMap["LedgerEntryType"].(string); ok {
 				if ledgerEntryType == "ContractData" {
-					if finalFields, ok := innerMap["FinalFields"].(map[string]interface{}); ok {
-						if val, ok := finalFields[field]; ok {
-							return fmt.Sprintf("%v", val)
-						}
-					}
-					if newFields, ok := innerMap["NewFields"].(map[string]interface{}); ok {
-						if val, ok := newFields[field]; ok {
-							return fmt.Sprintf("%v", val)
+					for _, key := range []string{"FinalFields", "NewFields"} {
+						if fields, ok := innerMap[key].(map[string]interface{}); ok {
+							if val, ok := abi.StateEntries(fields)[field]; ok {
+								return val, true
+							}
 						}
 					}
 				}
@@ -274,7 +395,7 @@ func findStateValue(meta map[string]interface{}, field string) string {
 		}
 	}
 
-	return ""
+	return nil, false
 }
 
 // toInt64 converts an interface{} to int64
@@ -0,0 +1,42 @@
+package tester
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/tickets"
+)
+
+// newTicketManager returns a ticket manager when calls should run in
+// parallel, or nil to run them one at a time. Parallel calls need a known
+// wallet: without one every call generates its own account.
+func newTicketManager(networkCfg config.NetworkConfig, walletSeed string, opts TestOptions) (*tickets.Manager, error) {
+	if opts.Concurrency <= 1 || walletSeed == "" {
+		return nil, nil
+	}
+	return tickets.NewManager(networkCfg, walletSeed, opts.Concurrency*2)
+}
+
+// forEach calls fn for 0..n-1 with up to concurrency calls in flight
+func forEach(ctx context.Context, n, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
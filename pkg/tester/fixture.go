@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/pelletier/go-toml/v2"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 )
 
 // FixtureFile represents a test fixture file (TOML or JSON)
@@ -31,6 +32,7 @@ type FixtureTest struct {
 	Name       string                 `toml:"name" json:"name"`
 	Function   string                 `toml:"function" json:"function"`
 	Parameters map[string]interface{} `toml:"parameters" json:"parameters"`
+	Amount     string                 `toml:"amount" json:"amount,omitempty"`
 	Expect     FixtureExpect          `toml:"expect" json:"expect"`
 }
 
@@ -41,6 +43,12 @@ type FixtureExpect struct {
 	TxResult    *string `toml:"tx_result" json:"tx_result,omitempty"`
 	GasBelow    *int64  `toml:"gas_below" json:"gas_below,omitempty"`
 	Events      []map[string]interface{} `toml:"events" json:"events,omitempty"`
+
+	// State diff expectations, matched against the transaction's AffectedNodes
+	StateChanges      []map[string]interface{} `toml:"state_changes" json:"state_changes,omitempty"`             // field, after, before, owner, kind
+	BalanceChanges    []map[string]interface{} `toml:"balance_changes" json:"balance_changes,omitempty"`         // account, delta, currency, issuer
+	OwnerCountChanges []map[string]interface{} `toml:"owner_count_changes" json:"owner_count_changes,omitempty"` // account, delta
+	AmountReceived    bool                     `toml:"amount_received" json:"amount_received,omitempty"`         // Contract received the test's amount
 }
 
 // LoadFixtures loads all test fixtures from a directory
@@ -129,6 +137,7 @@ func ConvertFixtureToSuite(f *FixtureFile) *IntegrationTestSuite {
 			Name:       ft.Name,
 			Function:   ft.Function,
 			Parameters: ft.Parameters,
+			Amount:     ft.Amount,
 			Assertions: convertExpectToAssertions(ft.Expect),
 		}
 		suite.Tests = append(suite.Tests, test)
@@ -181,6 +190,33 @@ func convertExpectToAssertions(expect FixtureExpect) []Assertion {
 		})
 	}
 
+	for _, change := range expect.StateChanges {
+		assertions = append(assertions, Assertion{
+			Type:     AssertStateChange,
+			Expected: change,
+		})
+	}
+
+	for _, change := range expect.BalanceChanges {
+		assertions = append(assertions, Assertion{
+			Type:     AssertBalanceChange,
+			Expected: change,
+		})
+	}
+
+	for _, change := range expect.OwnerCountChanges {
+		assertions = append(assertions, Assertion{
+			Type:     AssertOwnerCountChange,
+			Expected: change,
+		})
+	}
+
+	if expect.AmountReceived {
+		assertions = append(assertions, Assertion{
+			Type: AssertAmountReceived,
+		})
+	}
+
 	return assertions
 }
 
@@ -193,6 +229,14 @@ func validateFixture(f *FixtureFile) error {
 		if t.Function == "" {
 			return fmt.Errorf("test %d (%s): function is required", i, t.Name)
 		}
+		if t.Amount != "" {
+			if _, err := chain.ParseAmount(t.Amount); err != nil {
+				return fmt.Errorf("test %d (%s): %w", i, t.Name, err)
+			}
+		}
+		if t.Expect.AmountReceived && t.Amount == "" {
+			return fmt.Errorf("test %d (%s): amount_received requires an amount", i, t.Name)
+		}
 	}
 
 	return nil
@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/xrpl-commons/bedrock/pkg/abi"
 	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 	"github.com/xrpl-commons/bedrock/pkg/config"
+	"github.com/xrpl-commons/bedrock/pkg/tickets"
 )
 
 // Fuzzer performs fuzz testing on contract functions
@@ -27,13 +30,19 @@ type FuzzResult struct {
 	FailInputs []map[string]interface{} // Inputs that caused failures
 }
 
+// FuzzReport contains the outcome of a fuzz run over a contract's functions
+type FuzzReport struct {
+	Results            []FuzzResult
+	TicketCleanupError string // Set when the run's unused Tickets could not be cleaned up
+}
+
 // NewFuzzer creates a new fuzz tester
 func NewFuzzer(cfg *config.Config, verbose bool) *Fuzzer {
 	return &Fuzzer{cfg: cfg, verbose: verbose}
 }
 
 // Run executes fuzz testing for all contract functions
-func (f *Fuzzer) Run(ctx context.Context, contractAccount string, walletSeed string, opts TestOptions) ([]FuzzResult, error) {
+func (f *Fuzzer) Run(ctx context.Context, contractAccount string, walletSeed string, opts TestOptions) (*FuzzReport, error) {
 	// Load ABI
 	abiPath := "abi.json"
 	if contracts := f.cfg.Contracts; contracts != nil {
@@ -70,21 +79,40 @@ func (f *Fuzzer) Run(ctx context.Context, contractAccount string, walletSeed str
 	}
 
 	gen := NewValueGenerator(seed)
-	var results []FuzzResult
+	report := &FuzzReport{}
+
+	// Every run goes through one Node worker with a warm connection
+	c, err := caller.NewWorkerCaller(f.verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create caller: %w", err)
+	}
+	defer c.Close()
+
+	// With --concurrency, runs share the wallet through Tickets
+	mgr, err := newTicketManager(networkCfg, walletSeed, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tickets: %w", err)
+	}
 
 	for _, fn := range abiData.Functions {
 		if opts.Match != "" && fn.Name != opts.Match {
 			continue
 		}
 
-		result := f.fuzzFunction(ctx, fn, contractAccount, walletSeed, networkCfg, gen, opts, abiPath)
-		results = append(results, result)
+		result := f.fuzzFunction(ctx, c, mgr, fn, contractAccount, walletSeed, networkCfg, gen, opts, abiPath)
+		report.Results = append(report.Results, result)
+	}
+
+	if mgr != nil {
+		if err := mgr.Close(ctx); err != nil {
+			report.TicketCleanupError = err.Error()
+		}
 	}
 
-	return results, nil
+	return report, nil
 }
 
-func (f *Fuzzer) fuzzFunction(ctx context.Context, fn abi.Function, contractAccount string, walletSeed string, networkCfg config.NetworkConfig, gen *ValueGenerator, opts TestOptions, abiPath string) FuzzResult {
+func (f *Fuzzer) fuzzFunction(ctx context.Context, c *caller.Caller, mgr *tickets.Manager, fn abi.Function, contractAccount string, walletSeed string, networkCfg config.NetworkConfig, gen *ValueGenerator, opts TestOptions, abiPath string) FuzzResult {
 	startTime := time.Now()
 	result := FuzzResult{
 		Function: fn.Name,
@@ -95,55 +123,45 @@ func (f *Fuzzer) fuzzFunction(ctx context.Context, fn abi.Function, contractAcco
 		runs = 256
 	}
 
-	c, err := caller.NewCaller(f.verbose)
-	if err != nil {
-		result.Failures = runs
-		return result
-	}
-
-	for i := 0; i < runs; i++ {
-		select {
-		case <-ctx.Done():
-			result.Duration = time.Since(startTime)
-			result.Runs = i
-			return result
-		default:
-		}
-
-		// Generate random parameters
+	// Generate every input up front so a seed reproduces the same runs
+	// whatever the concurrency
+	inputs := make([]map[string]interface{}, runs)
+	for i := range inputs {
 		params := make(map[string]interface{})
 		for _, p := range fn.Parameters {
 			params[p.Name] = gen.Generate(p.Type)
 		}
+		inputs[i] = params
+	}
 
-		result.Runs++
+	var mu sync.Mutex
+	forEach(ctx, runs, opts.Concurrency, func(i int) {
+		params := inputs[i]
 
 		// Call the function with generated params
-		callResult, err := c.Call(ctx, caller.CallConfig{
+		callResult, err := c.CallWithTicket(ctx, mgr, caller.CallConfig{
 			ContractAccount:      contractAccount,
 			FunctionName:         fn.Name,
-			NetworkURL:           networkCfg.URL,
-			NetworkID:            networkCfg.NetworkID,
+			Network:              networkCfg,
 			WalletSeed:           walletSeed,
 			Algorithm:            "secp256k1",
 			ABIPath:              abiPath,
 			Parameters:           params,
-			ComputationAllowance: "1000000",
-			Fee:                  "1000000",
+			ComputationAllowance: caller.AllowanceAuto,
+			Fee:                  chain.FeeAuto,
 		})
 
-		if err != nil {
-			result.Failures++
-			result.FailInputs = append(result.FailInputs, params)
-			continue
-		}
+		mu.Lock()
+		defer mu.Unlock()
+
+		result.Runs++
 
 		// Check for unexpected failures (non-success transaction result)
-		if callResult.TransactionResult != "tesSUCCESS" && callResult.TransactionResult != "" {
+		if err != nil || (callResult.TransactionResult != "tesSUCCESS" && callResult.TransactionResult != "") {
 			result.Failures++
 			result.FailInputs = append(result.FailInputs, params)
 		}
-	}
+	})
 
 	result.Duration = time.Since(startTime)
 	return result
@@ -9,10 +9,12 @@ import (
 	"github.com/xrpl-commons/bedrock/pkg/abi"
 	"github.com/xrpl-commons/bedrock/pkg/builder"
 	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 	"github.com/xrpl-commons/bedrock/pkg/config"
 	"github.com/xrpl-commons/bedrock/pkg/deployer"
 	"github.com/xrpl-commons/bedrock/pkg/faucet"
 	"github.com/xrpl-commons/bedrock/pkg/network"
+	"github.com/xrpl-commons/bedrock/pkg/tickets"
 )
 
 // IntegrationRunner executes integration tests against a live node
@@ -39,6 +41,8 @@ type IntegrationResult struct {
 	Duration        time.Duration
 	Tests           []IntegrationTestResult
 	ContractAccount string
+
+	TicketCleanupError string // Set when the suite's unused Tickets could not be cleaned up
 }
 
 // IntegrationTestResult holds the result of a single integration test
@@ -73,6 +77,13 @@ func (r *IntegrationRunner) Run(ctx context.Context, opts TestOptions) ([]Integr
 		return nil, fmt.Errorf("local node not available: %w", err)
 	}
 
+	// Every test goes through one Node worker with a warm connection
+	c, err := caller.NewWorkerCaller(r.verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create caller: %w", err)
+	}
+	defer c.Close()
+
 	var results []IntegrationResult
 	for _, fixture := range fixtures {
 		if opts.Match != "" && fixture.Name != opts.Match {
@@ -80,7 +91,7 @@ func (r *IntegrationRunner) Run(ctx context.Context, opts TestOptions) ([]Integr
 		}
 
 		suite := ConvertFixtureToSuite(&fixture)
-		result, err := r.runSuite(ctx, suite, opts)
+		result, err := r.runSuite(ctx, c, suite, opts)
 		if err != nil {
 			return nil, fmt.Errorf("suite '%s' failed: %w", suite.Name, err)
 		}
@@ -90,7 +101,7 @@ func (r *IntegrationRunner) Run(ctx context.Context, opts TestOptions) ([]Integr
 	return results, nil
 }
 
-func (r *IntegrationRunner) runSuite(ctx context.Context, suite *IntegrationTestSuite, opts TestOptions) (*IntegrationResult, error) {
+func (r *IntegrationRunner) runSuite(ctx context.Context, c *caller.Caller, suite *IntegrationTestSuite, opts TestOptions) (*IntegrationResult, error) {
 	startTime := time.Now()
 	result := &IntegrationResult{
 		SuiteName: suite.Name,
@@ -146,9 +157,25 @@ func (r *IntegrationRunner) runSuite(ctx context.Context, suite *IntegrationTest
 
 	result.ContractAccount = contractAccount
 
+	// With --concurrency, tests share the wallet through Tickets
+	mgr, err := newTicketManager(networkCfg, walletSeed, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tickets: %w", err)
+	}
+
 	// Run each test
-	for _, test := range suite.Tests {
-		testResult := r.runTest(ctx, test, contractAccount, walletSeed, networkCfg, opts)
+	testResults := make([]IntegrationTestResult, len(suite.Tests))
+	forEach(ctx, len(suite.Tests), opts.Concurrency, func(i int) {
+		testResults[i] = r.runTest(ctx, c, suite.Tests[i], contractAccount, walletSeed, networkCfg, mgr)
+	})
+
+	if mgr != nil {
+		if err := mgr.Close(ctx); err != nil {
+			result.TicketCleanupError = err.Error()
+		}
+	}
+
+	for _, testResult := range testResults {
 		if testResult.Passed {
 			result.Passed++
 		} else {
@@ -161,7 +188,7 @@ func (r *IntegrationRunner) runSuite(ctx context.Context, suite *IntegrationTest
 	return result, nil
 }
 
-func (r *IntegrationRunner) runTest(ctx context.Context, test IntegrationTest, contractAccount string, walletSeed string, networkCfg config.NetworkConfig, opts TestOptions) IntegrationTestResult {
+func (r *IntegrationRunner) runTest(ctx context.Context, c *caller.Caller, test IntegrationTest, contractAccount string, walletSeed string, networkCfg config.NetworkConfig, mgr *tickets.Manager) IntegrationTestResult {
 	startTime := time.Now()
 	result := IntegrationTestResult{
 		Name: test.Name,
@@ -175,24 +202,17 @@ func (r *IntegrationRunner) runTest(ctx context.Context, test IntegrationTest, c
 		}
 	}
 
-	c, err := caller.NewCaller(r.verbose)
-	if err != nil {
-		result.Error = fmt.Sprintf("failed to create caller: %v", err)
-		result.Duration = time.Since(startTime)
-		return result
-	}
-
-	callResult, err := c.Call(ctx, caller.CallConfig{
+	callResult, err := c.CallWithTicket(ctx, mgr, caller.CallConfig{
 		ContractAccount:      contractAccount,
 		FunctionName:         test.Function,
-		NetworkURL:           networkCfg.URL,
-		NetworkID:            networkCfg.NetworkID,
+		Network:              networkCfg,
 		WalletSeed:           walletSeed,
 		Algorithm:            "secp256k1",
 		ABIPath:              abiPath,
 		Parameters:           test.Parameters,
-		ComputationAllowance: "1000000",
-		Fee:                  "1000000",
+		Amount:               test.Amount,
+		ComputationAllowance: caller.AllowanceAuto,
+		Fee:                  chain.FeeAuto,
 	})
 
 	if err != nil {
@@ -204,7 +224,11 @@ func (r *IntegrationRunner) runTest(ctx context.Context, test IntegrationTest, c
 	result.GasUsed = callResult.GasUsed
 
 	// Run assertions
-	assertionResults := RunAssertions(callResult, test.Assertions)
+	var schema *abi.StateSchema
+	if contractABI, err := loadABI(abiPath); err == nil {
+		schema = contractABI.State
+	}
+	assertionResults := RunAssertions(callResult, test.Assertions, schema)
 	result.Assertions = assertionResults
 
 	allPassed := true
@@ -249,7 +273,7 @@ func (r *IntegrationRunner) fundWallet(ctx context.Context, networkCfg config.Ne
 		FaucetURL:  networkCfg.FaucetURL,
 		WalletSeed: walletSeed,
 		Algorithm:  "secp256k1",
-		NetworkURL: networkCfg.URL,
+		Network:    networkCfg,
 		IsLocal:    isLocal,
 	})
 	if err != nil {
@@ -290,7 +314,7 @@ func (r *IntegrationRunner) buildAndDeploy(ctx context.Context, networkCfg confi
 	deployResult, err := d.Deploy(ctx, deployer.DeploymentConfig{
 		WasmPath:   buildResult.WasmPath,
 		ABIPath:    abiPath,
-		NetworkURL: networkCfg.URL,
+		Network:    networkCfg,
 		WalletSeed: walletSeed,
 		Algorithm:  "secp256k1",
 		FaucetURL:  networkCfg.FaucetURL,
@@ -7,6 +7,7 @@ import (
 
 	"github.com/xrpl-commons/bedrock/pkg/abi"
 	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
 	"github.com/xrpl-commons/bedrock/pkg/config"
 )
 
@@ -75,10 +76,11 @@ func (r *InvariantRunner) Run(ctx context.Context, contractAccount string, walle
 		runs = 100
 	}
 
-	c, err := caller.NewCaller(r.verbose)
+	c, err := caller.NewWorkerCaller(r.verbose)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create caller: %w", err)
 	}
+	defer c.Close()
 
 	var results []InvariantResult
 
@@ -96,6 +98,11 @@ func (r *InvariantRunner) checkInvariant(ctx context.Context, inv Invariant, con
 		Name: inv.Name,
 	}
 
+	var schema *abi.StateSchema
+	if contractABI, err := loadABI(abiPath); err == nil {
+		schema = contractABI.State
+	}
+
 	for i := 0; i < runs; i++ {
 		select {
 		case <-ctx.Done():
@@ -119,14 +126,13 @@ func (r *InvariantRunner) checkInvariant(ctx context.Context, inv Invariant, con
 		callResult, err := c.Call(ctx, caller.CallConfig{
 			ContractAccount:      contractAccount,
 			FunctionName:         fn.Name,
-			NetworkURL:           networkCfg.URL,
-			NetworkID:            networkCfg.NetworkID,
+			Network:              networkCfg,
 			WalletSeed:           walletSeed,
 			Algorithm:            "secp256k1",
 			ABIPath:              abiPath,
 			Parameters:           params,
-			ComputationAllowance: "1000000",
-			Fee:                  "1000000",
+			ComputationAllowance: caller.AllowanceAuto,
+			Fee:                  chain.FeeAuto,
 		})
 
 		if err != nil {
@@ -138,13 +144,12 @@ func (r *InvariantRunner) checkInvariant(ctx context.Context, inv Invariant, con
 			invResult, err := c.Call(ctx, caller.CallConfig{
 				ContractAccount:      contractAccount,
 				FunctionName:         inv.Function,
-				NetworkURL:           networkCfg.URL,
-				NetworkID:            networkCfg.NetworkID,
+				Network:              networkCfg,
 				WalletSeed:           walletSeed,
 				Algorithm:            "secp256k1",
 				ABIPath:              abiPath,
-				ComputationAllowance: "1000000",
-				Fee:                  "1000000",
+				ComputationAllowance: caller.AllowanceAuto,
+				Fee:                  chain.FeeAuto,
 			})
 
 			if err != nil {
@@ -155,7 +160,7 @@ func (r *InvariantRunner) checkInvariant(ctx context.Context, inv Invariant, con
 
 			// Check assertions on invariant result
 			for _, assertion := range inv.Assertions {
-				ar := runAssertion(invResult, assertion)
+				ar := runAssertion(invResult, assertion, schema)
 				if !ar.Passed {
 					result.Violations++
 					result.Details = append(result.Details, fmt.Sprintf("invariant violated after %s: %s (expected: %s, got: %s)", fn.Name, ar.Message, ar.Expected, ar.Actual))
@@ -164,7 +169,7 @@ func (r *InvariantRunner) checkInvariant(ctx context.Context, inv Invariant, con
 		} else {
 			// Check assertions directly on the call result
 			for _, assertion := range inv.Assertions {
-				ar := runAssertion(callResult, assertion)
+				ar := runAssertion(callResult, assertion, schema)
 				if !ar.Passed {
 					result.Violations++
 					result.Details = append(result.Details, fmt.Sprintf("invariant violated after %s: %s", fn.Name, ar.Message))
@@ -153,3 +153,13 @@ func SnapshotFromEntries(entries []GasEntry) *Snapshot {
 	}
 	return snapshot
 }
+
+// PreviousGasUsed returns the gas recorded for name in a snapshot file,
+// or 0 when the file or the entry does not exist
+func PreviousGasUsed(path, name string) int64 {
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		return 0
+	}
+	return snapshot.Entries[name]
+}
@@ -0,0 +1,525 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xrpl-commons/bedrock/pkg/abi"
+	"github.com/xrpl-commons/bedrock/pkg/caller"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+)
+
+// ChangeKind describes how a ledger value changed
+type ChangeKind string
+
+const (
+	ChangeCreated  ChangeKind = "created"
+	ChangeModified ChangeKind = "modified"
+	ChangeDeleted  ChangeKind = "deleted"
+)
+
+// StateDiff is the readable form of a transaction's AffectedNodes
+type StateDiff struct {
+	Data        []DataChange          // ContractData fields, per user
+	Balances    []BalanceChange       // XRP, IOU and MPT deltas per account
+	Events      []chain.ContractEvent // Emitted contract events
+	OwnerCounts []OwnerCountChange    // Owned object count changes per account
+	Transfer    *Transfer             // Value sent with the call, if any
+}
+
+// Transfer is the value sent with a call and what the contract received
+type Transfer struct {
+	Amount   string `json:"amount"`   // As given to the call
+	To       string `json:"to"`       // Contract account
+	Received string `json:"received"` // Balance delta of the contract in the sent asset
+}
+
+// Complete reports whether the contract received the full amount
+func (t *Transfer) Complete() bool {
+	amount, err := chain.ParseAmount(t.Amount)
+	if err != nil {
+		return false
+	}
+	return parseDecimal(t.Received).Cmp(parseDecimal(amount.Value)) == 0
+}
+
+// DataChange is a single ContractData field change
+type DataChange struct {
+	Kind     ChangeKind `json:"kind"`
+	Contract string     `json:"contract,omitempty"`
+	Owner    string     `json:"owner,omitempty"` // User the data belongs to
+	Field    string     `json:"field"`
+	Type     string     `json:"type,omitempty"` // Declared state type, once decoded
+	Before   string     `json:"before,omitempty"`
+	After    string     `json:"after,omitempty"`
+}
+
+// label returns the field name, with its type once decoded
+func (c DataChange) label() string {
+	if c.Type != "" {
+		return fmt.Sprintf("%s (%s)", c.Field, c.Type)
+	}
+	return c.Field
+}
+
+// BalanceChange is the change of one account's balance in one asset
+type BalanceChange struct {
+	Account  string `json:"account"`
+	Currency string `json:"currency"`         // "XRP", a currency code or "MPT"
+	Issuer   string `json:"issuer,omitempty"` // IOU counterparty or MPT issuance ID
+	Before   string `json:"before"`
+	After    string `json:"after"`
+	Delta    string `json:"delta"`
+}
+
+// OwnerCountChange is the change of an account's owned object count
+type OwnerCountChange struct {
+	Account string `json:"account"`
+	Before  int64  `json:"before"`
+	After   int64  `json:"after"`
+}
+
+// Delta returns After - Before
+func (c OwnerCountChange) Delta() int64 {
+	return c.After - c.Before
+}
+
+// DiffState turns transaction metadata into a state diff
+func DiffState(meta map[string]interface{}) *StateDiff {
+	diff := &StateDiff{}
+	if meta == nil {
+		return diff
+	}
+
+	nodes, _ := meta["AffectedNodes"].([]interface{})
+	for _, n := range nodes {
+		nodeMap, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for kind, v := range nodeMap {
+			entry, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			var change ChangeKind
+			switch kind {
+			case "CreatedNode":
+				change = ChangeCreated
+			case "ModifiedNode":
+				change = ChangeModified
+			case "DeletedNode":
+				change = ChangeDeleted
+			default:
+				continue
+			}
+
+			before, after := nodeFields(entry, change)
+
+			switch entry["LedgerEntryType"] {
+			case "ContractData":
+				diff.Data = append(diff.Data, diffContractData(before, after)...)
+			case "AccountRoot":
+				diff.addAccountRoot(before, after)
+			case "RippleState":
+				diff.addRippleState(before, after)
+			case "MPToken":
+				diff.addMPToken(before, after)
+			}
+		}
+	}
+
+	diff.Events = chain.ContractEventsFromMeta(meta, "", 0)
+	diff.sort()
+	return diff
+}
+
+// DiffCall is DiffState for a call result, including the value the call sent
+func DiffCall(result *caller.CallResult) *StateDiff {
+	diff := DiffState(result.Meta)
+	if result.Amount == "" {
+		return diff
+	}
+
+	diff.Transfer = &Transfer{Amount: result.Amount, To: result.ContractAccount, Received: "0"}
+
+	amount, err := chain.ParseAmount(result.Amount)
+	if err != nil {
+		return diff
+	}
+	for _, b := range diff.Balances {
+		if b.Account == result.ContractAccount && sameAsset(b, amount) {
+			diff.Transfer.Received = b.Delta
+			break
+		}
+	}
+	return diff
+}
+
+// sameAsset reports whether a balance change is in the asset of amount
+func sameAsset(b BalanceChange, amount *chain.Amount) bool {
+	switch {
+	case amount.IsXRP():
+		return b.Currency == "XRP"
+	case amount.IsMPT():
+		return b.Currency == "MPT" && strings.EqualFold(b.Issuer, amount.MPTIssuanceID)
+	default:
+		return b.Currency == amount.Currency && b.Issuer == amount.Issuer
+	}
+}
+
+// nodeFields returns the entry's fields before and after the transaction
+func nodeFields(entry map[string]interface{}, kind ChangeKind) (before, after map[string]interface{}) {
+	newFields, _ := entry["NewFields"].(map[string]interface{})
+	finalFields, _ := entry["FinalFields"].(map[string]interface{})
+	previousFields, _ := entry["PreviousFields"].(map[string]interface{})
+
+	switch kind {
+	case ChangeCreated:
+		return nil, newFields
+	case ChangeDeleted:
+		before = make(map[string]interface{}, len(finalFields))
+		for k, v := range finalFields {
+			before[k] = v
+		}
+		for k, v := range previousFields {
+			before[k] = v
+		}
+		return before, nil
+	default:
+		// PreviousFields only lists what changed; everything else is as final
+		before = make(map[string]interface{}, len(finalFields))
+		for k, v := range finalFields {
+			before[k] = v
+		}
+		for k, v := range previousFields {
+			before[k] = v
+		}
+		return before, finalFields
+	}
+}
+
+// diffContractData lists the fields that changed in a ContractData entry.
+// Key/value contents held in a nested object are diffed key by key.
+func diffContractData(before, after map[string]interface{}) []DataChange {
+	ref := after
+	if ref == nil {
+		ref = before
+	}
+	owner := stringField(ref, "Owner")
+	if owner == "" {
+		owner = stringField(ref, "Account")
+	}
+	contract := stringField(ref, "ContractAccount")
+
+	beforeValues := flattenData(before)
+	afterValues := flattenData(after)
+
+	var changes []DataChange
+	for field, value := range afterValues {
+		old, existed := beforeValues[field]
+		switch {
+		case !existed:
+			changes = append(changes, DataChange{Kind: ChangeCreated, Field: field, After: value})
+		case old != value:
+			changes = append(changes, DataChange{Kind: ChangeModified, Field: field, Before: old, After: value})
+		}
+	}
+	for field, value := range beforeValues {
+		if _, ok := afterValues[field]; !ok {
+			changes = append(changes, DataChange{Kind: ChangeDeleted, Field: field, Before: value})
+		}
+	}
+
+	for i := range changes {
+		changes[i].Owner = owner
+		changes[i].Contract = contract
+	}
+	return changes
+}
+
+// flattenData returns the data fields of a ContractData entry as strings
+func flattenData(fields map[string]interface{}) map[string]string {
+	values := make(map[string]string)
+	for key, value := range abi.StateEntries(fields) {
+		values[key] = abi.FormatStateValue(value)
+	}
+	return values
+}
+
+// DecodeData renders the ContractData changes with a contract's state
+// schema, so values read as their declared types
+func (d *StateDiff) DecodeData(schema *abi.StateSchema) {
+	if schema == nil {
+		return
+	}
+	for i := range d.Data {
+		c := &d.Data[i]
+		user := c.Owner != "" && c.Owner != c.Contract
+		if c.Before != "" {
+			v := schema.DecodeValue(c.Field, c.Before, user)
+			c.Before, c.Type = v.Value, v.Type
+		}
+		if c.After != "" {
+			v := schema.DecodeValue(c.Field, c.After, user)
+			c.After, c.Type = v.Value, v.Type
+		}
+	}
+}
+
+func (d *StateDiff) addAccountRoot(before, after map[string]interface{}) {
+	ref := after
+	if ref == nil {
+		ref = before
+	}
+	account := stringField(ref, "Account")
+
+	oldBalance, newBalance := stringField(before, "Balance"), stringField(after, "Balance")
+	if oldBalance != newBalance {
+		d.addBalance(account, "XRP", "", orZero(oldBalance), orZero(newBalance))
+	}
+
+	oldCount, _ := toInt64(before["OwnerCount"])
+	newCount, _ := toInt64(after["OwnerCount"])
+	if oldCount != newCount {
+		d.OwnerCounts = append(d.OwnerCounts, OwnerCountChange{Account: account, Before: oldCount, After: newCount})
+	}
+}
+
+// addRippleState records a trust line balance change for both sides. A
+// positive balance means the low account holds IOUs issued by the high one.
+func (d *StateDiff) addRippleState(before, after map[string]interface{}) {
+	ref := after
+	if ref == nil {
+		ref = before
+	}
+	low := limitIssuer(ref, "LowLimit")
+	high := limitIssuer(ref, "HighLimit")
+
+	oldBalance, currency := amountValue(before["Balance"])
+	newBalance, newCurrency := amountValue(after["Balance"])
+	if currency == "" {
+		currency = newCurrency
+	}
+	// A created or deleted line has no balance on one side
+	oldBalance, newBalance = orZero(oldBalance), orZero(newBalance)
+	if oldBalance == newBalance {
+		return
+	}
+
+	d.addBalance(low, currency, high, oldBalance, newBalance)
+	d.addBalance(high, currency, low, negate(oldBalance), negate(newBalance))
+}
+
+func (d *StateDiff) addMPToken(before, after map[string]interface{}) {
+	ref := after
+	if ref == nil {
+		ref = before
+	}
+
+	oldAmount, newAmount := orZero(stringField(before, "MPTAmount")), orZero(stringField(after, "MPTAmount"))
+	if oldAmount == newAmount {
+		return
+	}
+	d.addBalance(stringField(ref, "Account"), "MPT", stringField(ref, "MPTokenIssuanceID"), oldAmount, newAmount)
+}
+
+func (d *StateDiff) addBalance(account, currency, issuer, before, after string) {
+	d.Balances = append(d.Balances, BalanceChange{
+		Account:  account,
+		Currency: currency,
+		Issuer:   issuer,
+		Before:   before,
+		After:    after,
+		Delta:    subtract(after, before),
+	})
+}
+
+func (d *StateDiff) sort() {
+	sort.SliceStable(d.Data, func(i, j int) bool {
+		if d.Data[i].Owner != d.Data[j].Owner {
+			return d.Data[i].Owner < d.Data[j].Owner
+		}
+		return d.Data[i].Field < d.Data[j].Field
+	})
+	sort.SliceStable(d.Balances, func(i, j int) bool {
+		if d.Balances[i].Account != d.Balances[j].Account {
+			return d.Balances[i].Account < d.Balances[j].Account
+		}
+		return d.Balances[i].Currency < d.Balances[j].Currency
+	})
+	sort.SliceStable(d.OwnerCounts, func(i, j int) bool {
+		return d.OwnerCounts[i].Account < d.OwnerCounts[j].Account
+	})
+}
+
+// HasChanges returns true if the transaction changed anything visible
+func (d *StateDiff) HasChanges() bool {
+	return len(d.Data) > 0 || len(d.Balances) > 0 || len(d.Events) > 0 || len(d.OwnerCounts) > 0 || d.Transfer != nil
+}
+
+// FormatDiff returns a human-readable representation of the diff
+func (d *StateDiff) FormatDiff() string {
+	if !d.HasChanges() {
+		return "No state changes"
+	}
+
+	var sb strings.Builder
+
+	if d.Transfer != nil {
+		sb.WriteString("Transfer:\n")
+		sb.WriteString(fmt.Sprintf("  %s -> %s (received %s)\n", d.Transfer.Amount, d.Transfer.To, d.Transfer.Received))
+	}
+
+	if len(d.Data) > 0 {
+		sb.WriteString("Contract data:\n")
+		owner := "\x00"
+		for _, c := range d.Data {
+			if c.Owner != owner {
+				owner = c.Owner
+				label := owner
+				if label == "" {
+					label = "(contract)"
+				}
+				sb.WriteString(fmt.Sprintf("  %s\n", label))
+			}
+			switch c.Kind {
+			case ChangeCreated:
+				sb.WriteString(fmt.Sprintf("    + %s: %s\n", c.label(), c.After))
+			case ChangeDeleted:
+				sb.WriteString(fmt.Sprintf("    - %s: %s\n", c.label(), c.Before))
+			default:
+				sb.WriteString(fmt.Sprintf("    ~ %s: %s -> %s\n", c.label(), c.Before, c.After))
+			}
+		}
+	}
+
+	if len(d.Balances) > 0 {
+		sb.WriteString("Balances:\n")
+		for _, b := range d.Balances {
+			asset := b.Currency
+			switch {
+			case b.Currency == "XRP":
+				asset = "drops"
+			case b.Issuer != "":
+				asset = b.Currency + "/" + b.Issuer
+			}
+			sign := ""
+			if !strings.HasPrefix(b.Delta, "-") {
+				sign = "+"
+			}
+			sb.WriteString(fmt.Sprintf("  %s: %s -> %s %s (%s%s)\n", b.Account, b.Before, b.After, asset, sign, b.Delta))
+		}
+	}
+
+	if len(d.OwnerCounts) > 0 {
+		sb.WriteString("Owner counts:\n")
+		for _, o := range d.OwnerCounts {
+			sb.WriteString(fmt.Sprintf("  %s: %d -> %d (%+d)\n", o.Account, o.Before, o.After, o.Delta()))
+		}
+	}
+
+	if len(d.Events) > 0 {
+		sb.WriteString("Events:\n")
+		for _, e := range d.Events {
+			sb.WriteString(fmt.Sprintf("  %s\n", formatEvent(e)))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatEvent renders an event's fields in a stable order
+func formatEvent(event chain.ContractEvent) string {
+	var fields map[string]interface{}
+	json.Unmarshal(event.Data, &fields)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k == "LedgerEntryType" || k == "EventType" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, fields[k]))
+	}
+
+	name := event.Type
+	if name == "" {
+		name = "event"
+	}
+	return fmt.Sprintf("%s {%s}", name, strings.Join(parts, ", "))
+}
+
+// formatValue renders a metadata value, keeping large numbers out of exponent form
+func formatValue(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func stringField(fields map[string]interface{}, key string) string {
+	if fields == nil {
+		return ""
+	}
+	if s, ok := fields[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// limitIssuer returns the account of a trust line limit
+func limitIssuer(fields map[string]interface{}, key string) string {
+	limit, _ := fields[key].(map[string]interface{})
+	return stringField(limit, "issuer")
+}
+
+// amountValue returns the value and currency of an amount field
+func amountValue(v interface{}) (value, currency string) {
+	switch amount := v.(type) {
+	case string:
+		return amount, "XRP"
+	case map[string]interface{}:
+		return stringField(amount, "value"), stringField(amount, "currency")
+	default:
+		return "", ""
+	}
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func parseDecimal(s string) *big.Float {
+	f, _, err := big.ParseFloat(s, 10, 128, big.ToNearestEven)
+	if err != nil {
+		return new(big.Float).SetPrec(128)
+	}
+	return f
+}
+
+func formatDecimal(f *big.Float) string {
+	return f.Text('f', -1)
+}
+
+func subtract(a, b string) string {
+	return formatDecimal(new(big.Float).SetPrec(128).Sub(parseDecimal(a), parseDecimal(b)))
+}
+
+func negate(s string) string {
+	return formatDecimal(new(big.Float).SetPrec(128).Neg(parseDecimal(s)))
+}
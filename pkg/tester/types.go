@@ -12,6 +12,7 @@ type TestOptions struct {
 	Fuzz        bool   // Enable fuzz testing
 	FuzzRuns    int    // Number of fuzz iterations
 	FuzzSeed    int64  // Seed for reproducible fuzzing
+	Concurrency int    // Parallel calls from one wallet, using Tickets when > 1
 }
 
 // TestResult contains the outcome of a test run
@@ -103,6 +104,7 @@ type IntegrationTest struct {
 	Name       string                 // Test name
 	Function   string                 // Contract function to call
 	Parameters map[string]interface{} // Call parameters
+	Amount     string                 // Value sent with the call
 	Assertions []Assertion            // Expected outcomes
 }
 
@@ -124,6 +126,10 @@ const (
 	AssertEvent
 	AssertState
 	AssertGasBelow
+	AssertStateChange
+	AssertBalanceChange
+	AssertOwnerCountChange
+	AssertAmountReceived
 )
 
 // String returns a human-readable assertion type
@@ -143,6 +149,14 @@ func (a AssertionType) String() string {
 		return "state"
 	case AssertGasBelow:
 		return "gas_below"
+	case AssertStateChange:
+		return "state_change"
+	case AssertBalanceChange:
+		return "balance_change"
+	case AssertOwnerCountChange:
+		return "owner_count_change"
+	case AssertAmountReceived:
+		return "amount_received"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,315 @@
+package tickets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Peersyst/xrpl-go/xrpl/wallet"
+	"github.com/xrpl-commons/bedrock/pkg/chain"
+	"github.com/xrpl-commons/bedrock/pkg/config"
+)
+
+// MaxTicketsPerAccount is the protocol limit on Tickets owned by one account
+const MaxTicketsPerAccount = 250
+
+// Manager hands out Tickets so that one account can submit many transactions
+// concurrently without Sequence collisions. It also tracks the account's
+// regular Sequence for the transactions it submits itself.
+type Manager struct {
+	client    *chain.Client
+	wallet    wallet.Wallet
+	network   config.NetworkConfig
+	batchSize int
+
+	mu        sync.Mutex
+	nextSeq   uint32          // Next regular Sequence, 0 until fetched
+	available []uint32        // Tickets ready to be handed out
+	owned     map[uint32]bool // Tickets created by this manager and not known to be consumed
+	refilling chan struct{}   // Closed when the TicketCreate in flight finishes, nil when none is
+}
+
+// NewManager creates a ticket manager for the account of walletSeed.
+// Tickets are created batchSize at a time when the pool runs dry.
+func NewManager(network config.NetworkConfig, walletSeed string, batchSize int) (*Manager, error) {
+	w, err := wallet.FromSeed(walletSeed, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet from seed: %w", err)
+	}
+
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if batchSize > MaxTicketsPerAccount {
+		batchSize = MaxTicketsPerAccount
+	}
+
+	return &Manager{
+		client:    chain.NewNetworkClient(network),
+		wallet:    w,
+		network:   network,
+		batchSize: batchSize,
+		owned:     make(map[uint32]bool),
+	}, nil
+}
+
+// Account returns the address of the managed account
+func (m *Manager) Account() string {
+	return string(m.wallet.ClassicAddress)
+}
+
+// Acquire returns a Ticket for exclusive use, creating a new batch via
+// TicketCreate when none are available. Callers that find the pool empty
+// while a batch is being created wait for it instead of creating their own.
+func (m *Manager) Acquire(ctx context.Context) (uint32, error) {
+	for {
+		m.mu.Lock()
+		if len(m.available) > 0 {
+			ticket := m.available[0]
+			m.available = m.available[1:]
+			m.mu.Unlock()
+			return ticket, nil
+		}
+
+		if wait := m.refilling; wait != nil {
+			m.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+
+		done := make(chan struct{})
+		m.refilling = done
+		m.mu.Unlock()
+
+		err := m.refill(ctx)
+
+		m.mu.Lock()
+		m.refilling = nil
+		m.mu.Unlock()
+		close(done)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Release returns a Ticket after use. A reusable Ticket goes back to the
+// pool; otherwise it is considered consumed. Use Reusable to decide from the
+// error of the transaction that used it.
+func (m *Manager) Release(ticket uint32, reusable bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if reusable {
+		m.available = append(m.available, ticket)
+		return
+	}
+	delete(m.owned, ticket)
+}
+
+// Reusable reports whether a Ticket is still unused after a transaction
+// finished with err: the transaction was rejected or expired, or failed
+// before it was submitted.
+func Reusable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var subErr *chain.SubmissionError
+	if errors.As(err, &subErr) {
+		switch subErr.Result.Status {
+		case chain.SubmissionRejected, chain.SubmissionExpired:
+			return true
+		default:
+			// Validated with a tec result, or outcome unknown
+			return false
+		}
+	}
+
+	// Failed before submission (e.g. while building or signing)
+	return true
+}
+
+// Close cleans up the Tickets this manager created but did not use, so the
+// account gets their owner reserve back. Each one is consumed by a no-op
+// AccountSet.
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.owned) == 0 {
+		return nil
+	}
+
+	// Only clean up tickets still on ledger: an unknown outcome may have consumed some
+	onLedger, err := m.ledgerTickets(ctx)
+	if err != nil {
+		return err
+	}
+
+	var unused []uint32
+	for ticket := range m.owned {
+		if onLedger[ticket] {
+			unused = append(unused, ticket)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i] < unused[j] })
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(unused))
+	for i, ticket := range unused {
+		wg.Add(1)
+		go func(i int, ticket uint32) {
+			defer wg.Done()
+			errs[i] = m.submit(ctx, map[string]interface{}{
+				"TransactionType": "AccountSet",
+				"Sequence":        uint32(0),
+				"TicketSequence":  ticket,
+			})
+		}(i, ticket)
+	}
+	wg.Wait()
+
+	m.available = nil
+	m.owned = make(map[uint32]bool)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to clean up tickets: %w", err)
+	}
+	return nil
+}
+
+// NextSequence reserves the account's next regular Sequence
+func (m *Manager) NextSequence(ctx context.Context) (uint32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.reserveSequence(ctx, 1)
+}
+
+// reserveSequence returns the next regular Sequence and advances it by n.
+// Callers must hold m.mu.
+func (m *Manager) reserveSequence(ctx context.Context, n uint32) (uint32, error) {
+	if m.nextSeq == 0 {
+		info, err := m.client.GetAccountInfo(ctx, m.Account())
+		if err != nil {
+			return 0, fmt.Errorf("failed to get account sequence: %w", err)
+		}
+		m.nextSeq = uint32(info.AccountData.Sequence)
+	}
+
+	seq := m.nextSeq
+	m.nextSeq += n
+	return seq, nil
+}
+
+// refill creates a batch of Tickets. m.mu is taken only around the
+// bookkeeping, not while the TicketCreate is submitted and tracked.
+func (m *Manager) refill(ctx context.Context) error {
+	m.mu.Lock()
+	count := m.batchSize
+	if room := MaxTicketsPerAccount - len(m.owned); count > room {
+		count = room
+	}
+	if count <= 0 {
+		owned := len(m.owned)
+		m.mu.Unlock()
+		return fmt.Errorf("account already owns %d tickets; release some before acquiring more", owned)
+	}
+
+	// TicketCreate consumes Sequence S and creates Tickets S+1 .. S+count
+	seq, err := m.reserveSequence(ctx, uint32(count)+1)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = m.submit(ctx, map[string]interface{}{
+		"TransactionType": "TicketCreate",
+		"Sequence":        seq,
+		"TicketCount":     uint32(count),
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil {
+		// The sequence may or may not have been used; fetch it again next time
+		m.nextSeq = 0
+		return fmt.Errorf("failed to create tickets: %w", err)
+	}
+
+	for i := 1; i <= count; i++ {
+		ticket := seq + uint32(i)
+		m.available = append(m.available, ticket)
+		m.owned[ticket] = true
+	}
+	return nil
+}
+
+// submit fills in the common fields, signs and submits a transaction
+func (m *Manager) submit(ctx context.Context, tx map[string]interface{}) error {
+	lastLedger, err := m.client.LastLedgerSequence(ctx, chain.DefaultLedgerOffset)
+	if err != nil {
+		return err
+	}
+
+	txType, _ := tx["TransactionType"].(string)
+	fee, err := chain.ResolveFee(ctx, m.network, chain.FeeAuto, txType)
+	if err != nil {
+		return err
+	}
+
+	tx["Account"] = m.Account()
+	tx["Fee"] = fee
+	tx["LastLedgerSequence"] = lastLedger
+	if m.network.NetworkID > 1024 {
+		tx["NetworkID"] = m.network.NetworkID
+	}
+
+	txBlob, hash, err := m.wallet.Sign(tx)
+	if err != nil {
+		return fmt.Errorf("failed to sign %s: %w", tx["TransactionType"], err)
+	}
+
+	result, err := m.client.SubmitAndTrack(ctx, chain.SignedTx{
+		TxBlob:             txBlob,
+		Hash:               hash,
+		LastLedgerSequence: lastLedger,
+	}, chain.SubmitOptions{})
+	if err != nil {
+		return err
+	}
+	return result.Err()
+}
+
+// ledgerTickets returns the TicketSequence of every Ticket the account owns
+func (m *Manager) ledgerTickets(ctx context.Context) (map[uint32]bool, error) {
+	var result struct {
+		Objects []struct {
+			TicketSequence uint32 `json:"TicketSequence"`
+		} `json:"account_objects"`
+	}
+	params := map[string]interface{}{
+		"account":      m.Account(),
+		"type":         "ticket",
+		"ledger_index": "validated",
+		"limit":        400,
+	}
+	if err := m.client.CallTyped(ctx, &result, "account_objects", params); err != nil {
+		return nil, fmt.Errorf("account_objects failed: %w", err)
+	}
+
+	tickets := make(map[uint32]bool, len(result.Objects))
+	for _, obj := range result.Objects {
+		tickets[obj.TicketSequence] = true
+	}
+	return tickets, nil
+}
@@ -59,6 +59,21 @@ func (wm *WalletManager) WalletExists(name string) bool {
 	return err == nil
 }
 
+// GetWalletInfo returns the public information of a stored wallet without decrypting it
+func (wm *WalletManager) GetWalletInfo(name string) (*WalletInfo, error) {
+	keystorePath := filepath.Join(wm.walletsDir, name+".json")
+	keystore, err := wm.loadKeystore(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keystore: %w", err)
+	}
+
+	return &WalletInfo{
+		Name:      keystore.Name,
+		Address:   keystore.Address,
+		CreatedAt: keystore.CreatedAt,
+	}, nil
+}
+
 // ListWallets returns information about all stored wallets
 func (wm *WalletManager) ListWallets() ([]WalletInfo, error) {
 	files, err := os.ReadDir(wm.walletsDir)
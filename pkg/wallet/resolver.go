@@ -69,4 +69,46 @@ func (wr *WalletResolver) resolveWalletName(walletName string) (string, error) {
 	}
 
 	return wallet.Seed, nil
-}
\ No newline at end of file
+}
+
+// ResolveAddress resolves a wallet input to its classic address without
+// requiring the seed to be decrypted. Accepts an address, a seed or a wallet name.
+func (wr *WalletResolver) ResolveAddress(walletInput, algorithm string) (string, error) {
+	if walletInput == "" {
+		return "", fmt.Errorf("wallet input cannot be empty")
+	}
+
+	xrplWallet, err := NewXRPLWallet()
+	if err != nil {
+		return "", fmt.Errorf("failed to create XRPL wallet: %w", err)
+	}
+
+	// Classic address
+	if strings.HasPrefix(walletInput, "r") {
+		if err := xrplWallet.ValidateAddress(walletInput); err != nil {
+			return "", fmt.Errorf("invalid address: %w", err)
+		}
+		return walletInput, nil
+	}
+
+	// Raw seed
+	if strings.HasPrefix(walletInput, "s") {
+		address, err := xrplWallet.SeedToAddressWithAlgorithm(walletInput, algorithm)
+		if err != nil {
+			return "", fmt.Errorf("invalid seed format: %w", err)
+		}
+		return address, nil
+	}
+
+	// Wallet name: the address is stored unencrypted in the keystore
+	if !wr.manager.WalletExists(walletInput) {
+		return "", fmt.Errorf("wallet '%s' not found. Use 'bedrock jade list' to see available wallets", walletInput)
+	}
+
+	info, err := wr.manager.GetWalletInfo(walletInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to load wallet '%s': %w", walletInput, err)
+	}
+
+	return info.Address, nil
+}